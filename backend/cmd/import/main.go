@@ -0,0 +1,88 @@
+// Package main provides a command line tool to bulk import properties from a CSV or JSON file
+// directly into the database, with the same validation and per-row report as the bulk import endpoint.
+//
+// Usage:
+//
+//	go run cmd/import/main.go -file properties.csv -lister <user id> [-images images.zip] [-format csv|json] [-dry-run]
+package main
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/importer"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/joho/godotenv/autoload"
+)
+
+func main() {
+	filePath := flag.String("file", "", "path to the CSV or JSON file of properties to import")
+	imagesPath := flag.String("images", "", "path to a zip archive of the images referenced by the properties")
+	format := flag.String("format", "", "format of the import file, csv or json (default: guessed from the file extension)")
+	listerUserID := flag.String("lister", "", "user id of the lister of properties that do not name one")
+	dryRun := flag.Bool("dry-run", false, "only validate the properties without creating them")
+	flag.Parse()
+
+	if *filePath == "" || *listerUserID == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		var err error
+		*format, err = importer.FormatFromFilename(*filePath)
+		if err != nil {
+			exit(err)
+		}
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		exit(err)
+	}
+	defer file.Close()
+
+	rows, err := importer.ParseRows(file, *format)
+	if err != nil {
+		exit(err)
+	}
+
+	images := map[string]database.FileInternal{}
+	if *imagesPath != "" {
+		archiveData, err := os.ReadFile(*imagesPath)
+		if err != nil {
+			exit(err)
+		}
+		images, err = importer.ReadImageArchive(archiveData, rows)
+		if err != nil {
+			exit(err)
+		}
+	}
+
+	config.InitConfig()
+	db := database.New()
+
+	// Whoever can run this has direct access to the database, so rows may name any lister
+	report, err := importer.New(db).Import(rows, images, *listerUserID, true, *dryRun)
+	if err != nil {
+		exit(err)
+	}
+
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		exit(err)
+	}
+	fmt.Println(string(output))
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func exit(err error) {
+	fmt.Fprintf(os.Stderr, "import failed: %s\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,111 @@
+// Package analytics aggregates the daily view and save counters of properties into
+// the listing analytics shown to listers.
+package analytics
+
+import (
+	"backend/internal/database"
+	"sort"
+	"time"
+)
+
+const dateFormat = "2006-01-02"
+
+type PropertySummary struct {
+	PropertyID string                          `json:"propertyId"`
+	Name       string                          `json:"name"`
+	Status     string                          `json:"status"`
+	Views      int64                           `json:"views"`
+	Saves      int64                           `json:"saves"`
+	SaveRate   float64                         `json:"saveRate"` // saves per view
+	Daily      []database.PropertyAnalyticsDay `json:"daily,omitempty"`
+}
+
+type Portfolio struct {
+	From       string                          `json:"from"`
+	To         string                          `json:"to"`
+	Views      int64                           `json:"views"`
+	Saves      int64                           `json:"saves"`
+	SaveRate   float64                         `json:"saveRate"`
+	Daily      []database.PropertyAnalyticsDay `json:"daily"`
+	Properties []PropertySummary               `json:"properties"`
+}
+
+// Range returns the first and last day of the given number of days ending today
+func Range(now time.Time, days int) (time.Time, time.Time) {
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return to.AddDate(0, 0, -(days - 1)), to
+}
+
+// FillDays returns one entry for every day from the first to the last day, taking the counters
+// from the given days and zero for the days that are missing.
+func FillDays(from, to time.Time, days []database.PropertyAnalyticsDay) []database.PropertyAnalyticsDay {
+	byDate := make(map[string]database.PropertyAnalyticsDay)
+	for _, day := range days {
+		existing := byDate[day.Date]
+		byDate[day.Date] = database.PropertyAnalyticsDay{
+			Date:  day.Date,
+			Views: existing.Views + day.Views,
+			Saves: existing.Saves + day.Saves,
+		}
+	}
+
+	filled := []database.PropertyAnalyticsDay{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day, exists := byDate[date.Format(dateFormat)]
+		if !exists {
+			day = database.PropertyAnalyticsDay{Date: date.Format(dateFormat)}
+		}
+		filled = append(filled, day)
+	}
+	return filled
+}
+
+// Summarize totals the daily counters of a property over the range from the first to the last day
+func Summarize(details database.PropertyDetails, from, to time.Time, days []database.PropertyAnalyticsDay) PropertySummary {
+	summary := PropertySummary{
+		PropertyID: details.PropertyID,
+		Name:       details.Name,
+		Status:     details.Status,
+		Daily:      FillDays(from, to, days),
+	}
+	for _, day := range summary.Daily {
+		summary.Views += int64(day.Views)
+		summary.Saves += int64(day.Saves)
+	}
+	summary.SaveRate = saveRate(summary.Views, summary.Saves)
+	return summary
+}
+
+// NewPortfolio combines the summaries of all properties of a lister, most viewed properties
+// first. The daily counters are totalled across properties and left out of each summary.
+func NewPortfolio(from, to time.Time, summaries []PropertySummary) Portfolio {
+	portfolio := Portfolio{
+		From:       from.Format(dateFormat),
+		To:         to.Format(dateFormat),
+		Properties: []PropertySummary{},
+	}
+
+	days := []database.PropertyAnalyticsDay{}
+	for _, summary := range summaries {
+		days = append(days, summary.Daily...)
+		portfolio.Views += summary.Views
+		portfolio.Saves += summary.Saves
+
+		summary.Daily = nil
+		portfolio.Properties = append(portfolio.Properties, summary)
+	}
+	portfolio.Daily = FillDays(from, to, days)
+	portfolio.SaveRate = saveRate(portfolio.Views, portfolio.Saves)
+
+	sort.SliceStable(portfolio.Properties, func(i, j int) bool {
+		return portfolio.Properties[i].Views > portfolio.Properties[j].Views
+	})
+	return portfolio
+}
+
+func saveRate(views, saves int64) float64 {
+	if views == 0 {
+		return 0
+	}
+	return float64(saves) / float64(views)
+}
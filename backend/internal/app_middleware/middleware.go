@@ -125,3 +125,27 @@ func AdminAuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// OptionalAuthMiddleware is a middleware for public endpoints whose response depends on who is asking.
+// If the request carries a valid JWT the user id and email are added to the context just like in
+// AuthMiddleware, otherwise the request is passed through unauthenticated instead of being rejected.
+func OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := auth.AuthCheckAndGetClaims(r, config.GlobalConfig.JWT_SIGN_SECRET)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		userID, _ := claims["user_id"].(string)
+		userEmail, _ := claims["email"].(string)
+		if userID == "" || userEmail == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), UserIDKey, userID)
+		ctx = context.WithValue(ctx, UserEmailKey, userEmail)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,138 @@
+// Package compare puts properties side by side on normalized dimensions, e.g. price per
+// square foot, and flags which property is best on each of them.
+package compare
+
+import (
+	"backend/internal/database"
+	"backend/internal/utils"
+	"errors"
+	"math"
+)
+
+// Dimensions properties are compared on
+const (
+	DIMENSION_PRICE                 = "price"
+	DIMENSION_PRICE_PER_SQUARE_FOOT = "pricePerSquareFoot"
+	DIMENSION_SQUARE_FEET           = "squareFeet"
+	DIMENSION_BEDROOMS              = "bedrooms"
+	DIMENSION_BATHROOMS             = "bathrooms"
+	DIMENSION_AMENITIES             = "amenities"
+	DIMENSION_DISTANCE              = "distance"
+)
+
+// Point is a location to measure the distance of each property to, e.g. a workplace
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type Entry struct {
+	PropertyID         string   `json:"propertyId"`
+	Name               string   `json:"name"`
+	Status             string   `json:"status"`
+	PriceType          string   `json:"priceType"`
+	Price              float64  `json:"price"`
+	PricePerSquareFoot float64  `json:"pricePerSquareFoot"`
+	SquareFeet         int32    `json:"squareFeet"`
+	Bedrooms           int16    `json:"bedrooms"`
+	Bathrooms          int16    `json:"bathrooms"`
+	Toilets            int16    `json:"toilets"`
+	Amenities          []string `json:"amenities"`
+	DistanceKm         *float64 `json:"distanceKm"` // nil without a point or the property's coordinates
+	BestOn             []string `json:"bestOn"`     // dimensions this property is best on
+}
+
+type Comparison struct {
+	Point      *Point              `json:"point,omitempty"`
+	Properties []Entry             `json:"properties"`
+	Best       map[string][]string `json:"best"` // ids of the best properties on each dimension, several on a tie
+}
+
+// Compare puts the properties side by side in the given order. Properties can only be compared
+// if they share a price type, as monthly rents cannot be compared with sale prices.
+func Compare(properties []database.PropertyDetails, point *Point) (Comparison, error) {
+	comparison := Comparison{
+		Point:      point,
+		Properties: []Entry{},
+		Best:       map[string][]string{},
+	}
+	if len(properties) == 0 {
+		return comparison, nil
+	}
+
+	for _, details := range properties {
+		if details.Price_type != properties[0].Price_type {
+			return Comparison{}, errors.New("cannot compare properties for rent with properties for sale")
+		}
+
+		price := float64(details.Cost_dollars) + float64(details.Cost_cents)/100
+		entry := Entry{
+			PropertyID: details.PropertyID,
+			Name:       details.Name,
+			Status:     details.Status,
+			PriceType:  details.Price_type,
+			Price:      price,
+			SquareFeet: details.Square_feet,
+			Bedrooms:   details.Num_bedrooms,
+			Bathrooms:  details.Num_showers_baths,
+			Toilets:    details.Num_toilets,
+			Amenities:  details.Amenities,
+			BestOn:     []string{},
+		}
+		if details.Square_feet > 0 {
+			entry.PricePerSquareFoot = math.Round(price/float64(details.Square_feet)*100) / 100
+		}
+		if entry.Amenities == nil {
+			entry.Amenities = []string{}
+		}
+		if point != nil && details.Latitude != nil && details.Longitude != nil {
+			distance := math.Round(utils.DistanceKm(point.Latitude, point.Longitude, *details.Latitude, *details.Longitude)*100) / 100
+			entry.DistanceKm = &distance
+		}
+		comparison.Properties = append(comparison.Properties, entry)
+	}
+
+	flagBest(&comparison, DIMENSION_PRICE, false, func(entry Entry) (float64, bool) { return entry.Price, true })
+	flagBest(&comparison, DIMENSION_PRICE_PER_SQUARE_FOOT, false, func(entry Entry) (float64, bool) {
+		return entry.PricePerSquareFoot, entry.SquareFeet > 0
+	})
+	flagBest(&comparison, DIMENSION_SQUARE_FEET, true, func(entry Entry) (float64, bool) { return float64(entry.SquareFeet), true })
+	flagBest(&comparison, DIMENSION_BEDROOMS, true, func(entry Entry) (float64, bool) { return float64(entry.Bedrooms), true })
+	flagBest(&comparison, DIMENSION_BATHROOMS, true, func(entry Entry) (float64, bool) { return float64(entry.Bathrooms), true })
+	flagBest(&comparison, DIMENSION_AMENITIES, true, func(entry Entry) (float64, bool) { return float64(len(entry.Amenities)), true })
+	flagBest(&comparison, DIMENSION_DISTANCE, false, func(entry Entry) (float64, bool) {
+		if entry.DistanceKm == nil {
+			return 0, false
+		}
+		return *entry.DistanceKm, true
+	})
+
+	return comparison, nil
+}
+
+// flagBest marks the properties with the highest (or lowest) value on a dimension as the best on it.
+// Properties without a value on the dimension are skipped, the dimension is left out if none have one.
+func flagBest(comparison *Comparison, dimension string, higherIsBetter bool, value func(entry Entry) (float64, bool)) {
+	var best float64
+	found := false
+	for _, entry := range comparison.Properties {
+		v, ok := value(entry)
+		if !ok {
+			continue
+		}
+		if !found || (higherIsBetter && v > best) || (!higherIsBetter && v < best) {
+			best = v
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+
+	for i, entry := range comparison.Properties {
+		if v, ok := value(entry); ok && v == best {
+			comparison.Properties[i].BestOn = append(comparison.Properties[i].BestOn, dimension)
+			comparison.Best[dimension] = append(comparison.Best[dimension], entry.PropertyID)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 )
 
 var GlobalConfig *Config
@@ -21,6 +22,232 @@ const USER_STATUS_NORMAL = "normal"
 const USER_STATUS_PRIVATE = "private"
 const USER_STATUS_FLAGGED = "flagged"
 
+const PROPERTY_STATUS_DRAFT = "draft"
+const PROPERTY_STATUS_PUBLISHED = "published"
+const PROPERTY_STATUS_PAUSED = "paused"
+const PROPERTY_STATUS_RENTED = "rented"
+const PROPERTY_STATUS_ARCHIVED = "archived"
+const PROPERTY_STATUS_EXPIRED = "expired" // published listing that was not renewed, only set by the listing expiry job
+
+const PROPERTY_PRICE_TYPE_MONTHLY = "monthly"
+const PROPERTY_PRICE_TYPE_SALE = "sale"
+
+const PROPERTY_MAX_LEASE_LENGTH_MONTHS = 60
+
+// Number of days a published listing stays up before it expires unless renewed,
+// and how many days before expiry its lister is reminded to renew it
+const PROPERTY_LISTING_DURATION_DAYS = 60
+const PROPERTY_EXPIRY_REMINDER_DAYS = 7
+
+// Trigram similarity of normalized addresses at or above which a new listing is
+// flagged as a suspected duplicate of an existing one for admin review.
+const PROPERTY_DUPLICATE_SIMILARITY_THRESHOLD = 0.6
+
+// Separator of the values of list columns (e.g. leaseLengthMonths, amenities, images) in CSV
+// files, both imported and exported
+const CSV_LIST_SEPARATOR = ";"
+
+// Limits of a single bulk property import
+const PROPERTY_IMPORT_MAX_ROWS = 500
+const PROPERTY_IMPORT_MAX_IMAGES = 10             // per property
+const PROPERTY_IMPORT_MAX_IMAGE_SIZE = 10 << 20   // 10 MiB decompressed
+const PROPERTY_IMPORT_MAX_IMAGES_SIZE = 200 << 20 // 200 MiB decompressed, of all images of an import
+const PROPERTY_IMPORT_MAX_ARCHIVE_FILES = PROPERTY_IMPORT_MAX_ROWS * PROPERTY_IMPORT_MAX_IMAGES
+
+// Limits of similar property and personalized recommendation results
+const RECOMMENDATION_DEFAULT_LIMIT = 10
+const RECOMMENDATION_MAX_LIMIT = 50
+const RECOMMENDATION_MAX_CANDIDATES = 200 // closest in price to a property, ranked by similarity
+const RECOMMENDATION_MAX_SAVED_PROPERTIES = 20
+
+// Limits of a side by side property comparison
+const PROPERTY_COMPARE_MIN_PROPERTIES = 2
+const PROPERTY_COMPARE_MAX_PROPERTIES = 4
+
+const PROPERTY_ANALYTICS_EVENT_VIEW = "view"
+const PROPERTY_ANALYTICS_EVENT_SAVE = "save"
+
+// Number of days of listing analytics returned by default and at most
+const PROPERTY_ANALYTICS_DEFAULT_DAYS = 30
+const PROPERTY_ANALYTICS_MAX_DAYS = 365
+
+// Number of days viewer events are kept to de-duplicate views and saves
+const PROPERTY_ANALYTICS_EVENTS_RETENTION_DAYS = 2
+
+// Number of hours a file blob without references is kept, e.g. while the upload referencing it
+// is still in progress
+const FILE_BLOBS_UNREFERENCED_RETENTION_HOURS = 24
+
+// How often the background jobs run
+const JOB_INTERVAL_LISTING_EXPIRY = time.Hour
+const JOB_INTERVAL_FILE_BLOBS_PRUNING = 24 * time.Hour
+const JOB_INTERVAL_ANALYTICS_PRUNING = 24 * time.Hour
+const JOB_INTERVAL_COMMUNITY_INVITES_PRUNING = 24 * time.Hour
+const JOB_INTERVAL_COMMUNITY_CHORE_TASKS = time.Hour
+
+// Limits of the filters of a search of communities
+const COMMUNITY_SEARCH_MAX_RADIUS_KM = 500
+const COMMUNITY_SEARCH_MAX_INTERESTS = 20
+
+// Pending community memberships are either requested by a user or an invite of the community admin
+const COMMUNITY_MEMBERSHIP_KIND_REQUEST = "request"
+const COMMUNITY_MEMBERSHIP_KIND_INVITE = "invite"
+
+const COMMUNITY_MEMBERSHIP_DECISION_ACCEPT = "accept"
+const COMMUNITY_MEMBERSHIP_DECISION_DECLINE = "decline"
+
+// Number of days an invited user has to accept an invite to a community
+const COMMUNITY_INVITE_EXPIRY_DAYS = 14
+
+// Decisions of a lister on a request of a community to link their property
+const COMMUNITY_PROPERTY_LINK_DECISION_APPROVE = "approve"
+const COMMUNITY_PROPERTY_LINK_DECISION_REJECT = "reject"
+
+// Roles of the members of a community. The owner is the community admin.
+const COMMUNITY_ROLE_OWNER = "owner"
+const COMMUNITY_ROLE_MODERATOR = "moderator"
+const COMMUNITY_ROLE_MEMBER = "member"
+
+// Actions on a community that require a permission of the member's role
+const COMMUNITY_PERMISSION_EDIT_DETAILS = "edit_details"
+const COMMUNITY_PERMISSION_MANAGE_MEMBERS = "manage_members"
+const COMMUNITY_PERMISSION_MANAGE_ROLES = "manage_roles"
+const COMMUNITY_PERMISSION_LINK_PROPERTIES = "link_properties"
+const COMMUNITY_PERMISSION_TRANSFER_OWNERSHIP = "transfer_ownership"
+const COMMUNITY_PERMISSION_DELETE_COMMUNITY = "delete_community"
+const COMMUNITY_PERMISSION_POST = "post"                     // read and write on the discussion board
+const COMMUNITY_PERMISSION_MODERATE_POSTS = "moderate_posts" // pin threads and delete posts of others
+
+// Limits of a post on the discussion board of a community
+const COMMUNITY_POST_MAX_TITLE_LENGTH = 200
+const COMMUNITY_POST_MAX_BODY_LENGTH = 10000
+const COMMUNITY_POSTS_MAX_LIMIT = 100 // per page
+
+const COMMUNITY_PERMISSION_EVENTS = "events"               // see, organize and RSVP to events
+const COMMUNITY_PERMISSION_MANAGE_EVENTS = "manage_events" // cancel events organized by others
+
+const COMMUNITY_EVENT_RSVP_YES = "yes"
+const COMMUNITY_EVENT_RSVP_NO = "no"
+const COMMUNITY_EVENT_RSVP_MAYBE = "maybe"
+
+// Limits of an event of a community
+const COMMUNITY_EVENT_MAX_TITLE_LENGTH = 200
+const COMMUNITY_EVENT_MAX_DESCRIPTION_LENGTH = 5000
+const COMMUNITY_EVENT_MAX_LOCATION_LENGTH = 500
+const COMMUNITY_EVENT_MAX_DURATION_DAYS = 7
+const COMMUNITY_EVENT_MAX_CAPACITY = 1000
+
+// Number of days past events stay in the calendar feed of a community
+const COMMUNITY_EVENTS_FEED_PAST_DAYS = 30
+
+const COMMUNITY_PERMISSION_EXPENSES = "expenses"               // see and record shared expenses
+const COMMUNITY_PERMISSION_MANAGE_EXPENSES = "manage_expenses" // delete expenses recorded by others
+
+// How the amount of a shared expense is split between the members sharing it
+const COMMUNITY_EXPENSE_SPLIT_EQUAL = "equal"   // in equal parts
+const COMMUNITY_EXPENSE_SPLIT_SHARES = "shares" // in proportion to the shares of each member
+const COMMUNITY_EXPENSE_SPLIT_EXACT = "exact"   // in the exact amounts given for each member
+
+const COMMUNITY_EXPENSE_CATEGORY_RENT = "rent"
+const COMMUNITY_EXPENSE_CATEGORY_UTILITIES = "utilities"
+const COMMUNITY_EXPENSE_CATEGORY_INTERNET = "internet"
+const COMMUNITY_EXPENSE_CATEGORY_GROCERIES = "groceries"
+const COMMUNITY_EXPENSE_CATEGORY_HOUSEHOLD = "household"
+const COMMUNITY_EXPENSE_CATEGORY_REPAIRS = "repairs"
+const COMMUNITY_EXPENSE_CATEGORY_OTHER = "other"
+const COMMUNITY_EXPENSE_CATEGORY_SETTLEMENT = "settlement" // a member paying back another one
+
+// Limits of a shared expense of a community
+const COMMUNITY_EXPENSE_MAX_DESCRIPTION_LENGTH = 200
+const COMMUNITY_EXPENSE_MAX_AMOUNT_CENTS = 10000000
+const COMMUNITY_EXPENSE_MAX_SPLITS = 100
+const COMMUNITY_EXPENSE_MAX_SHARES = 100
+
+const COMMUNITY_PERMISSION_POLLS = "polls"               // see, create and vote in polls
+const COMMUNITY_PERMISSION_MANAGE_POLLS = "manage_polls" // delete polls created by others
+
+// When the results of a poll are visible to its voters, everyone sees them once the poll closes
+const COMMUNITY_POLL_RESULTS_AFTER_VOTE = "after_vote"
+const COMMUNITY_POLL_RESULTS_AFTER_CLOSE = "after_close"
+
+// Limits of a poll of a community
+const COMMUNITY_POLL_MAX_QUESTION_LENGTH = 500
+const COMMUNITY_POLL_MAX_OPTION_LENGTH = 200
+const COMMUNITY_POLL_MIN_OPTIONS = 2
+const COMMUNITY_POLL_MAX_OPTIONS = 20
+const COMMUNITY_POLL_MAX_DURATION_DAYS = 90
+
+const COMMUNITY_PERMISSION_DOCUMENTS = "documents"               // see member documents, upload documents and add folders
+const COMMUNITY_PERMISSION_MANAGE_DOCUMENTS = "manage_documents" // see moderator documents, edit and delete documents of others
+
+// Who can see a document of a community
+const COMMUNITY_DOCUMENT_VISIBILITY_MEMBERS = "members"
+const COMMUNITY_DOCUMENT_VISIBILITY_MODERATORS = "moderators" // members allowed to manage documents
+const COMMUNITY_DOCUMENT_VISIBILITY_PUBLIC = "public"
+
+// Limits of a document of a community
+const COMMUNITY_DOCUMENT_MAX_TITLE_LENGTH = 200
+const COMMUNITY_DOCUMENT_MAX_NOTE_LENGTH = 500
+const COMMUNITY_DOCUMENT_MAX_FOLDER_NAME_LENGTH = 100
+const COMMUNITY_DOCUMENT_MAX_SIZE = 25 << 20 // 25 MiB
+
+const COMMUNITY_PERMISSION_CHORES = "chores"               // see and create chores, complete own tasks and swap them with other members who accept
+const COMMUNITY_PERMISSION_MANAGE_CHORES = "manage_chores" // delete chores created by others, complete tasks of others and swap tasks of two members right away
+
+// How often a chore of a community recurs, every interval of days, weeks or months
+const COMMUNITY_CHORE_FREQUENCY_DAILY = "daily"
+const COMMUNITY_CHORE_FREQUENCY_WEEKLY = "weekly"
+const COMMUNITY_CHORE_FREQUENCY_MONTHLY = "monthly"
+
+// Limits of a chore of a community
+const COMMUNITY_CHORE_MAX_TITLE_LENGTH = 200
+const COMMUNITY_CHORE_MAX_DESCRIPTION_LENGTH = 2000
+const COMMUNITY_CHORE_MAX_INTERVAL = 52
+const COMMUNITY_CHORE_MAX_ROTATION = 100
+
+// Number of days ahead the tasks of chores are scheduled
+const COMMUNITY_CHORE_SCHEDULE_DAYS = 14
+
+// Limits of a viewing time slot of a property
+const VIEWING_SLOT_MAX_CAPACITY = 20
+const VIEWING_SLOT_MAX_DURATION_MINUTES = 240
+const VIEWING_SLOT_MAX_NOTE_LENGTH = 500
+
+const NOTIFICATION_TYPE_VIEWING_BOOKED = "viewing_booked"
+const NOTIFICATION_TYPE_VIEWING_CANCELLED = "viewing_cancelled"
+const NOTIFICATION_TYPE_APPLICATION_SUBMITTED = "application_submitted"
+const NOTIFICATION_TYPE_APPLICATION_STATUS_CHANGED = "application_status_changed"
+const NOTIFICATION_TYPE_APPLICATION_WITHDRAWN = "application_withdrawn"
+const NOTIFICATION_TYPE_LISTING_EXPIRING = "listing_expiring"
+const NOTIFICATION_TYPE_LISTING_EXPIRED = "listing_expired"
+const NOTIFICATION_TYPE_COMMUNITY_JOIN_REQUESTED = "community_join_requested"
+const NOTIFICATION_TYPE_COMMUNITY_INVITED = "community_invited"
+const NOTIFICATION_TYPE_COMMUNITY_MEMBERSHIP_ACCEPTED = "community_membership_accepted"
+const NOTIFICATION_TYPE_COMMUNITY_MEMBERSHIP_DECLINED = "community_membership_declined"
+const NOTIFICATION_TYPE_COMMUNITY_POST_REPLIED = "community_post_replied"
+const NOTIFICATION_TYPE_COMMUNITY_EVENT_CANCELLED = "community_event_cancelled"
+const NOTIFICATION_TYPE_COMMUNITY_PROPERTY_LINK_REQUESTED = "community_property_link_requested"
+const NOTIFICATION_TYPE_COMMUNITY_PROPERTY_LINK_APPROVED = "community_property_link_approved"
+const NOTIFICATION_TYPE_COMMUNITY_PROPERTY_LINK_REJECTED = "community_property_link_rejected"
+const NOTIFICATION_TYPE_COMMUNITY_PROPERTY_DETACHED = "community_property_detached"
+const NOTIFICATION_TYPE_COMMUNITY_CHORE_TASK_SWAPPED = "community_chore_task_swapped"
+const NOTIFICATION_TYPE_COMMUNITY_CHORE_TASK_SWAP_PROPOSED = "community_chore_task_swap_proposed"
+
+const APPLICATION_STATUS_SUBMITTED = "submitted"
+const APPLICATION_STATUS_UNDER_REVIEW = "under_review"
+const APPLICATION_STATUS_ACCEPTED = "accepted"
+const APPLICATION_STATUS_REJECTED = "rejected"
+
+// Limits of a rental application to a property
+const APPLICATION_MAX_MESSAGE_LENGTH = 2000
+const APPLICATION_MAX_DECISION_NOTE_LENGTH = 1000
+const APPLICATION_MAX_CO_APPLICANTS = 5
+const APPLICATION_MAX_DOCUMENTS = 5
+
+const PROPERTY_DUPLICATE_REVIEW_PENDING = "pending"
+const PROPERTY_DUPLICATE_REVIEW_CONFIRMED = "confirmed"
+const PROPERTY_DUPLICATE_REVIEW_DISMISSED = "dismissed"
+
 var USER_ROLE_OPTIONS = map[string]struct{}{
 	USER_ROLE_REGULAR: {},
 	USER_ROLE_LISTER:  {},
@@ -33,6 +260,212 @@ var USER_STATUS_OPTIONS = map[string]struct{}{
 	USER_STATUS_FLAGGED: {},
 }
 
+var PROPERTY_STATUS_OPTIONS = map[string]struct{}{
+	PROPERTY_STATUS_DRAFT:     {},
+	PROPERTY_STATUS_PUBLISHED: {},
+	PROPERTY_STATUS_PAUSED:    {},
+	PROPERTY_STATUS_RENTED:    {},
+	PROPERTY_STATUS_ARCHIVED:  {},
+	PROPERTY_STATUS_EXPIRED:   {},
+}
+
+var COMMUNITY_ROLE_OPTIONS = map[string]struct{}{
+	COMMUNITY_ROLE_OWNER:     {},
+	COMMUNITY_ROLE_MODERATOR: {},
+	COMMUNITY_ROLE_MEMBER:    {},
+}
+
+var COMMUNITY_EVENT_RSVP_OPTIONS = map[string]struct{}{
+	COMMUNITY_EVENT_RSVP_YES:   {},
+	COMMUNITY_EVENT_RSVP_NO:    {},
+	COMMUNITY_EVENT_RSVP_MAYBE: {},
+}
+
+var COMMUNITY_EXPENSE_SPLIT_OPTIONS = map[string]struct{}{
+	COMMUNITY_EXPENSE_SPLIT_EQUAL:  {},
+	COMMUNITY_EXPENSE_SPLIT_SHARES: {},
+	COMMUNITY_EXPENSE_SPLIT_EXACT:  {},
+}
+
+var COMMUNITY_EXPENSE_CATEGORY_OPTIONS = map[string]struct{}{
+	COMMUNITY_EXPENSE_CATEGORY_RENT:       {},
+	COMMUNITY_EXPENSE_CATEGORY_UTILITIES:  {},
+	COMMUNITY_EXPENSE_CATEGORY_INTERNET:   {},
+	COMMUNITY_EXPENSE_CATEGORY_GROCERIES:  {},
+	COMMUNITY_EXPENSE_CATEGORY_HOUSEHOLD:  {},
+	COMMUNITY_EXPENSE_CATEGORY_REPAIRS:    {},
+	COMMUNITY_EXPENSE_CATEGORY_OTHER:      {},
+	COMMUNITY_EXPENSE_CATEGORY_SETTLEMENT: {},
+}
+
+var COMMUNITY_POLL_RESULTS_OPTIONS = map[string]struct{}{
+	COMMUNITY_POLL_RESULTS_AFTER_VOTE:  {},
+	COMMUNITY_POLL_RESULTS_AFTER_CLOSE: {},
+}
+
+var COMMUNITY_DOCUMENT_VISIBILITY_OPTIONS = map[string]struct{}{
+	COMMUNITY_DOCUMENT_VISIBILITY_MEMBERS:    {},
+	COMMUNITY_DOCUMENT_VISIBILITY_MODERATORS: {},
+	COMMUNITY_DOCUMENT_VISIBILITY_PUBLIC:     {},
+}
+
+// Types of the images of properties, as detected from their content
+var PROPERTY_IMAGE_MIME_TYPE_OPTIONS = map[string]struct{}{
+	"image/gif":  {},
+	"image/jpeg": {},
+	"image/png":  {},
+	"image/webp": {},
+}
+
+// Types of the files of community documents, as detected from their content
+var COMMUNITY_DOCUMENT_MIME_TYPE_OPTIONS = map[string]struct{}{
+	"application/pdf": {},
+	"application/zip": {}, // also office documents
+	"image/gif":       {},
+	"image/jpeg":      {},
+	"image/png":       {},
+	"image/webp":      {},
+	"text/plain":      {},
+}
+
+var COMMUNITY_CHORE_FREQUENCY_OPTIONS = map[string]struct{}{
+	COMMUNITY_CHORE_FREQUENCY_DAILY:   {},
+	COMMUNITY_CHORE_FREQUENCY_WEEKLY:  {},
+	COMMUNITY_CHORE_FREQUENCY_MONTHLY: {},
+}
+
+// COMMUNITY_ROLE_PERMISSIONS maps a role of a community member to the actions it permits
+var COMMUNITY_ROLE_PERMISSIONS = map[string]map[string]struct{}{
+	COMMUNITY_ROLE_OWNER: {
+		COMMUNITY_PERMISSION_EDIT_DETAILS:       {},
+		COMMUNITY_PERMISSION_MANAGE_MEMBERS:     {},
+		COMMUNITY_PERMISSION_MANAGE_ROLES:       {},
+		COMMUNITY_PERMISSION_LINK_PROPERTIES:    {},
+		COMMUNITY_PERMISSION_TRANSFER_OWNERSHIP: {},
+		COMMUNITY_PERMISSION_DELETE_COMMUNITY:   {},
+		COMMUNITY_PERMISSION_POST:               {},
+		COMMUNITY_PERMISSION_MODERATE_POSTS:     {},
+		COMMUNITY_PERMISSION_EVENTS:             {},
+		COMMUNITY_PERMISSION_MANAGE_EVENTS:      {},
+		COMMUNITY_PERMISSION_EXPENSES:           {},
+		COMMUNITY_PERMISSION_MANAGE_EXPENSES:    {},
+		COMMUNITY_PERMISSION_POLLS:              {},
+		COMMUNITY_PERMISSION_MANAGE_POLLS:       {},
+		COMMUNITY_PERMISSION_DOCUMENTS:          {},
+		COMMUNITY_PERMISSION_MANAGE_DOCUMENTS:   {},
+		COMMUNITY_PERMISSION_CHORES:             {},
+		COMMUNITY_PERMISSION_MANAGE_CHORES:      {},
+	},
+	COMMUNITY_ROLE_MODERATOR: {
+		COMMUNITY_PERMISSION_EDIT_DETAILS:     {},
+		COMMUNITY_PERMISSION_MANAGE_MEMBERS:   {},
+		COMMUNITY_PERMISSION_LINK_PROPERTIES:  {},
+		COMMUNITY_PERMISSION_POST:             {},
+		COMMUNITY_PERMISSION_MODERATE_POSTS:   {},
+		COMMUNITY_PERMISSION_EVENTS:           {},
+		COMMUNITY_PERMISSION_MANAGE_EVENTS:    {},
+		COMMUNITY_PERMISSION_EXPENSES:         {},
+		COMMUNITY_PERMISSION_MANAGE_EXPENSES:  {},
+		COMMUNITY_PERMISSION_POLLS:            {},
+		COMMUNITY_PERMISSION_MANAGE_POLLS:     {},
+		COMMUNITY_PERMISSION_DOCUMENTS:        {},
+		COMMUNITY_PERMISSION_MANAGE_DOCUMENTS: {},
+		COMMUNITY_PERMISSION_CHORES:           {},
+		COMMUNITY_PERMISSION_MANAGE_CHORES:    {},
+	},
+	COMMUNITY_ROLE_MEMBER: {
+		COMMUNITY_PERMISSION_POST:      {},
+		COMMUNITY_PERMISSION_EVENTS:    {},
+		COMMUNITY_PERMISSION_EXPENSES:  {},
+		COMMUNITY_PERMISSION_POLLS:     {},
+		COMMUNITY_PERMISSION_DOCUMENTS: {},
+		COMMUNITY_PERMISSION_CHORES:    {},
+	},
+}
+
+// PROPERTY_STATUS_TRANSITIONS maps a property's current listing status to the
+// statuses a lister is allowed to move it to.
+var PROPERTY_STATUS_TRANSITIONS = map[string]map[string]struct{}{
+	PROPERTY_STATUS_DRAFT: {
+		PROPERTY_STATUS_PUBLISHED: {},
+		PROPERTY_STATUS_ARCHIVED:  {},
+	},
+	PROPERTY_STATUS_PUBLISHED: {
+		PROPERTY_STATUS_PAUSED:   {},
+		PROPERTY_STATUS_RENTED:   {},
+		PROPERTY_STATUS_ARCHIVED: {},
+	},
+	PROPERTY_STATUS_PAUSED: {
+		PROPERTY_STATUS_PUBLISHED: {},
+		PROPERTY_STATUS_RENTED:    {},
+		PROPERTY_STATUS_ARCHIVED:  {},
+	},
+	PROPERTY_STATUS_RENTED: {
+		PROPERTY_STATUS_PUBLISHED: {},
+		PROPERTY_STATUS_ARCHIVED:  {},
+	},
+	PROPERTY_STATUS_EXPIRED: {
+		PROPERTY_STATUS_PUBLISHED: {},
+		PROPERTY_STATUS_ARCHIVED:  {},
+	},
+	PROPERTY_STATUS_ARCHIVED: {
+		PROPERTY_STATUS_DRAFT: {},
+	},
+}
+
+var APPLICATION_STATUS_OPTIONS = map[string]struct{}{
+	APPLICATION_STATUS_SUBMITTED:    {},
+	APPLICATION_STATUS_UNDER_REVIEW: {},
+	APPLICATION_STATUS_ACCEPTED:     {},
+	APPLICATION_STATUS_REJECTED:     {},
+}
+
+// APPLICATION_STATUS_TRANSITIONS maps a rental application's current status to the
+// statuses the property's lister may move it to. Decided applications are final.
+var APPLICATION_STATUS_TRANSITIONS = map[string]map[string]struct{}{
+	APPLICATION_STATUS_SUBMITTED: {
+		APPLICATION_STATUS_UNDER_REVIEW: {},
+		APPLICATION_STATUS_ACCEPTED:     {},
+		APPLICATION_STATUS_REJECTED:     {},
+	},
+	APPLICATION_STATUS_UNDER_REVIEW: {
+		APPLICATION_STATUS_ACCEPTED: {},
+		APPLICATION_STATUS_REJECTED: {},
+	},
+}
+
+var PROPERTY_DUPLICATE_REVIEW_STATUS_OPTIONS = map[string]struct{}{
+	PROPERTY_DUPLICATE_REVIEW_PENDING:   {},
+	PROPERTY_DUPLICATE_REVIEW_CONFIRMED: {},
+	PROPERTY_DUPLICATE_REVIEW_DISMISSED: {},
+}
+
+var PROPERTY_PRICE_TYPE_OPTIONS = map[string]struct{}{
+	PROPERTY_PRICE_TYPE_MONTHLY: {},
+	PROPERTY_PRICE_TYPE_SALE:    {},
+}
+
+var PROPERTY_PETS_POLICY_OPTIONS = map[string]struct{}{
+	"allowed":     {},
+	"not_allowed": {},
+	"negotiable":  {},
+}
+
+var PROPERTY_SMOKING_POLICY_OPTIONS = map[string]struct{}{
+	"allowed":      {},
+	"not_allowed":  {},
+	"outside_only": {},
+}
+
+var AMENITY_CATEGORY_OPTIONS = map[string]struct{}{
+	"appliances":    {},
+	"building":      {},
+	"services":      {},
+	"accessibility": {},
+	"outdoor":       {},
+	"other":         {},
+}
+
 var GENDER_OPTIONS = map[string]struct{}{
 	"Man":                 {},
 	"Woman":               {},
@@ -13,12 +13,23 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/joho/godotenv/autoload"
 )
 
+// ErrAlreadyCommunityMember is returned when a user is added to a community they are a member of
+var ErrAlreadyCommunityMember = errors.New("user is already a member of the community")
+
+// isUniqueViolation reports whether the error is a violation of a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 type service struct {
 	db             *sql.DB
 	db_queries     *sqlc.Queries
@@ -39,6 +50,9 @@ type Service interface {
 	// Lister functions
 	GetManyListersDetails(limit, offset int32, nameFilter string) ([]ListerDetails, error)
 
+	// File Blobs
+	DeleteUnreferencedFileBlobs(createdBefore time.Time) (int64, error)
+
 	// Users Account
 	CreateUser(userId, email string) error
 	GetUserDetails(userId string) (UserDetails, error)
@@ -77,21 +91,92 @@ type Service interface {
 	UpdateUserRole(userId, role string) error
 	// DeleteUserRole(userId string) error
 
+	// Amenities
+	GetAmenities() ([]Amenity, error)
+	CreateAmenity(amenity Amenity) error
+	DeleteAmenity(name string) error
+
 	// Properties
 	CreateProperty(propertyDetails PropertyDetails, images []OrderedFileInternal) error
 	GetPropertyDetails(propertyId string) (PropertyDetails, error)
 	GetPropertyImages(propertyId string) ([]OrderedFileInternal, error)
-	GetNextPageProperties(limit, offset int32, addressFilter string) ([]string, error)
+	GetPropertyImage(propertyID string, orderNum int16) (FileInternal, error)
+	GetPropertyImageOrderNums(propertyID string) ([]int16, error)
+	GetNextPageProperties(limit, offset int32, filters PropertySearchFilters) ([]string, error)
 	GetListerOwnedProperties(userID string) ([]string, error)
 	CheckDuplicateProperty(propertyDetails PropertyDetails) error
+	GetPropertyAddresses() ([]PropertyAddress, error)
+	UpdatePropertyNormalizedAddress(propertyID, normalizedAddress string) error
+	FindSimilarProperties(propertyDetails PropertyDetails, threshold float32) ([]SimilarProperty, error)
+	FlagSimilarProperties(propertyDetails PropertyDetails, threshold float32) error
+	GetRecommendationCandidates(propertyDetails PropertyDetails, excludedPropertyIDs []string, excludedListerUserID string, limit int32) ([]PropertyDetails, error)
 	UpdatePropertyDetails(details PropertyDetails) error
 	UpdatePropertyImages(propertyID string, images []OrderedFileInternal) error
 	UpdatePropertyLister(propertyID string, userID string) error
+	UpdatePropertyStatus(propertyID string, status string) error
+	UpdatePropertyExpiry(propertyID string, expiresAt time.Time) error
+	RemindExpiringProperties(expiresBy time.Time) ([]ExpiringProperty, error)
+	ExpireProperties(now time.Time) ([]ExpiringProperty, error)
 	TransferAllPropertiesToOtherUser(fromUserID, toUserID string) error
 	DeleteProperty(propertyId string) error
 	DeletePropertyImage(propertyId string, imageOrderNum int16) error
 	DeleteUserOwnedProperties(userID string) error
 
+	// Property Duplicate Reviews
+	CreatePropertyDuplicateReview(propertyID, duplicateOfPropertyID string, similarity float32) error
+	GetPropertyDuplicateReview(reviewID int32) (PropertyDuplicateReview, error)
+	GetPropertyDuplicateReviews(status string, limit, offset int32) ([]PropertyDuplicateReview, error)
+	UpdatePropertyDuplicateReviewStatus(reviewID int32, status, reviewerUserID string) error
+
+	// Property History
+	CreatePropertyHistory(propertyID, changedByUserID string, changes []PropertyChange) error
+	GetPropertyHistory(propertyID string) ([]PropertyHistoryEntry, error)
+
+	// Property Rooms
+	CreatePropertyRoom(details RoomDetails, images []OrderedFileInternal) error
+	GetPropertyRoomDetails(roomID string) (RoomDetails, error)
+	GetPropertyRoomImages(roomID string) ([]OrderedFileInternal, error)
+	GetPropertyRooms(propertyID string) ([]RoomDetails, error)
+	CountPropertyRooms(propertyID string) (int64, error)
+	UpdatePropertyRoomDetails(details RoomDetails) error
+	UpdatePropertyRoomImages(roomID string, images []OrderedFileInternal) error
+	DeletePropertyRoom(roomID string) error
+
+	// Property Analytics
+	RecordPropertyEvent(propertyID, eventType, viewer string) error
+	GetPropertyAnalytics(propertyID string, from time.Time) ([]PropertyAnalyticsDay, error)
+	DeletePropertyAnalyticsEventsBefore(day time.Time) (int64, error)
+
+	// Property Viewings
+	CreateViewingSlot(slot ViewingSlot) error
+	GetViewingSlot(slotID string) (ViewingSlot, error)
+	GetUpcomingViewingSlots(propertyID string) ([]ViewingSlot, error)
+	GetUserUpcomingViewingSlots(userID string) ([]ViewingSlot, error)
+	DeleteViewingSlot(slotID string) error
+	BookViewingSlot(slotID, userID string) (bool, error)
+	CancelViewingBooking(slotID, userID string) error
+	GetViewingSlotBookers(slotID string) ([]string, error)
+
+	// Property Applications
+	CreateRentalApplication(application RentalApplication, documents []OrderedFileInternal) error
+	GetRentalApplication(applicationID string) (RentalApplication, error)
+	GetRentalApplicationDocuments(applicationID string) ([]OrderedFileInternal, error)
+	GetPropertyRentalApplications(propertyID string) ([]RentalApplication, error)
+	GetUserRentalApplications(userID string) ([]RentalApplication, error)
+	UpdateRentalApplicationStatus(applicationID, currentStatus, newStatus, decisionNote string) (bool, error)
+	DeleteRentalApplication(applicationID string) error
+
+	// Notifications
+	CreateNotification(userID, notificationType, message, link string) error
+	GetNotifications(userID string, limit, offset int32) ([]Notification, error)
+	CountUnreadNotifications(userID string) (int64, error)
+	MarkNotificationRead(userID string, notificationID int32) error
+	MarkAllNotificationsRead(userID string) error
+
+	// Calendar Tokens
+	SetUserCalendarToken(userID, token string) error
+	GetUserIDByCalendarToken(token string) (string, error)
+
 	// Communities
 	CreateCommunity(details CommunityDetails, images []FileInternal) error
 	CreateCommunityUser(communityId, userId string) error
@@ -100,17 +185,102 @@ type Service interface {
 	GetCommunityImages(communityId string) ([]FileInternal, error)
 	GetCommunityUsers(communityId string) ([]string, error)
 	GetCommunityProperties(communityId string) ([]string, error)
-	GetNextPageCommunities(limit, offset int32, filterName, filterDescription string) ([]string, error)
+	GetNextPageCommunities(limit, offset int32, filters CommunitySearchFilters) ([]string, error)
 	GetUserOwnedCommunities(userId string) ([]string, error)
 	UpdateCommunityDetails(details CommunityDetails) error
 	UpdateCommunityImages(communityId string, images []FileInternal) error
 	UpdateCommunityUsers(communityID string, userIDs []string) error
 	UpdateCommunityProperties(communityID string, propertyIDs []string) error
-	UpdateCommunityAdmin(communityID string, userID string) error
 	DeleteCommunity(communityId string) error
 	DeleteCommunityUser(communityId, userId string) error
 	DeleteCommunityProperty(communityId, propertyId string) error
 	DeleteUserOwnedCommunities(userID string) error
+	IsCommunityUser(communityID, userID string) (bool, error)
+	GetCommunityUserRole(communityID, userID string) (string, error)
+	GetCommunityMembers(communityID string) ([]CommunityMember, error)
+	UpdateCommunityUserRole(communityID, userID, role string) error
+	TransferCommunityOwnership(communityID, fromUserID, toUserID string) error
+	GetPropertyCommunities(propertyID string) ([]string, error)
+
+	// Community Membership Requests
+	CreateCommunityMembershipRequest(request CommunityMembershipRequest) error
+	GetCommunityMembershipRequest(requestID string) (CommunityMembershipRequest, error)
+	GetCommunityMembershipRequestOfUser(communityID, userID string) (CommunityMembershipRequest, error)
+	GetCommunityMembershipRequests(communityID string) ([]CommunityMembershipRequest, error)
+	GetUserCommunityMembershipRequests(userID string) ([]CommunityMembershipRequest, error)
+	AcceptCommunityMembershipRequest(request CommunityMembershipRequest) error
+	DeleteCommunityMembershipRequest(requestID string) error
+	DeleteExpiredCommunityMembershipRequests(now time.Time) (int64, error)
+
+	// Community Property Link Requests
+	CreateCommunityPropertyLinkRequest(request CommunityPropertyLinkRequest) error
+	GetCommunityPropertyLinkRequest(requestID string) (CommunityPropertyLinkRequest, error)
+	GetCommunityPropertyLinkRequestOfProperty(communityID, propertyID string) (CommunityPropertyLinkRequest, error)
+	GetCommunityPropertyLinkRequests(communityID string) ([]CommunityPropertyLinkRequest, error)
+	GetListerCommunityPropertyLinkRequests(listerUserID string) ([]CommunityPropertyLinkRequest, error)
+	AcceptCommunityPropertyLinkRequest(request CommunityPropertyLinkRequest) error
+	DeleteCommunityPropertyLinkRequest(requestID string) error
+
+	// Community Posts
+	CreateCommunityPost(post CommunityPost) error
+	GetCommunityPost(postID string) (CommunityPost, error)
+	GetCommunityThreads(communityID string, limit, offset int32) ([]CommunityPost, error)
+	GetCommunityThreadReplies(threadID string, limit, offset int32) ([]CommunityPost, error)
+	UpdateCommunityPostPinned(postID string, pinned bool) error
+	DeleteCommunityPost(postID string) error
+
+	// Community Events
+	CreateCommunityEvent(event CommunityEvent) error
+	GetCommunityEvent(eventID string) (CommunityEvent, error)
+	GetCommunityEvents(communityID string, endingAfter time.Time) ([]CommunityEvent, error)
+	GetCommunityEventRSVPs(eventID string) ([]CommunityEventRSVP, error)
+	SetCommunityEventRSVP(eventID, userID, response string) (bool, error)
+	DeleteCommunityEventRSVP(eventID, userID string) error
+	DeleteCommunityEvent(eventID string) error
+
+	// Community Expenses
+	CreateCommunityExpense(expense CommunityExpense) error
+	GetCommunityExpense(expenseID string) (CommunityExpense, error)
+	GetCommunityExpenses(communityID string) ([]CommunityExpense, error)
+	DeleteCommunityExpense(expenseID string) error
+
+	// Community Polls
+	CreateCommunityPoll(poll CommunityPoll) error
+	GetCommunityPoll(pollID string) (CommunityPoll, error)
+	GetCommunityPolls(communityID string) ([]CommunityPoll, error)
+	GetCommunityPollResults(poll CommunityPoll) ([]CommunityPollResult, error)
+	CheckIsCommunityPollVoter(pollID, userID string) (bool, error)
+	CastCommunityPollBallot(pollID, userID string, optionIDs []string) (bool, error)
+	DeleteCommunityPoll(pollID string) error
+
+	// Community Documents
+	CreateCommunityDocumentFolder(folder CommunityDocumentFolder) error
+	GetCommunityDocumentFolder(folderID string) (CommunityDocumentFolder, error)
+	GetCommunityDocumentFolders(communityID string) ([]CommunityDocumentFolder, error)
+	DeleteCommunityDocumentFolder(folderID string) error
+	CreateCommunityDocument(document CommunityDocument, version CommunityDocumentVersion, file FileInternal) error
+	CreateCommunityDocumentVersion(version CommunityDocumentVersion, file FileInternal) (int32, error)
+	GetCommunityDocument(documentID string) (CommunityDocument, error)
+	GetCommunityDocuments(communityID string) ([]CommunityDocument, error)
+	GetCommunityDocumentVersions(documentID string) ([]CommunityDocumentVersion, error)
+	GetCommunityDocumentFile(documentID string, version int32) (FileInternal, error)
+	UpdateCommunityDocument(document CommunityDocument) error
+	DeleteCommunityDocument(documentID string) error
+
+	// Community Chores
+	CreateCommunityChore(chore CommunityChore) error
+	GetCommunityChore(choreID string) (CommunityChore, error)
+	GetCommunityChores(communityID string) ([]CommunityChore, error)
+	GetAllCommunityChores() ([]CommunityChore, error)
+	DeleteCommunityChore(choreID string) error
+	GetCommunityChoreLastOccurrence(choreID string) (int32, error)
+	CreateCommunityChoreTask(task CommunityChoreTask) error
+	GetCommunityChoreTask(taskID string) (CommunityChoreTask, error)
+	GetCommunityChoreTasks(communityID string, since time.Time) ([]CommunityChoreTask, error)
+	GetUserChoreTasks(userID string, since time.Time) ([]CommunityChoreTask, error)
+	UpdateCommunityChoreTaskCompletion(taskID, completedByUserID string, completedAt time.Time) error
+	SwapCommunityChoreTaskAssignees(taskID, otherTaskID string) (bool, error)
+	UpdateCommunityChoreTaskSwap(taskID, swapTaskID string) (bool, error)
 
 	// Public User Discovery API
 	GetNextPagePublicUserIDs(limit, offset int32, firstName, lastName string) ([]string, error)
@@ -132,6 +302,22 @@ func (s *service) Health() map[string]string {
 	}
 }
 
+// withTx runs fn with queries bound to a new transaction, committing it if fn succeeds and
+// rolling it back otherwise
+func (s *service) withTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = fn(s.db_queries.WithTx(tx))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // -------------- ADMIN FUNCTIONS ------------------
 // Admin functions
 func (s *service) AdminGetUsers(limit, offset int32, name string) ([]UserDetails, error) {
@@ -507,6 +693,7 @@ func (s *service) DeleteUser(userId string) error {
 	}
 
 	// Delete the user with the matching encrypyted id
+	// (owned properties and communities cascade, releasing their image blobs as well)
 	err = s.db_queries.DeleteUserDetails(ctx, userIDEncrypted)
 	return err
 }
@@ -1041,6 +1228,98 @@ func (s *service) UpdateUserRole(userId, role string) error {
 // 	return err
 // }
 
+// -------------- FILE BLOBS ------------------
+
+// createFileBlob stores the given file data in the content-addressed blob store
+// and returns the content hash to reference it by. Identical data uploaded more
+// than once (e.g. the same photo for several properties) is only stored once.
+// The reference must be inserted with the same transaction's queries, the blob is
+// otherwise not held until it is referenced.
+func createFileBlob(ctx context.Context, q *sqlc.Queries, data []byte) (string, error) {
+	contentHash := utils.ContentHash(data)
+	err := q.CreateFileBlob(ctx, sqlc.CreateFileBlobParams{
+		ContentHash: contentHash,
+		Size:        int64(len(data)),
+		Data:        data,
+	})
+	if err != nil {
+		return "", err
+	}
+	return contentHash, nil
+}
+
+// Deletes the blobs without any reference created before the given time
+func (s *service) DeleteUnreferencedFileBlobs(createdBefore time.Time) (int64, error) {
+	ctx := context.Background()
+	return s.db_queries.DeleteUnreferencedFileBlobs(ctx, createdBefore)
+}
+
+// -------------- AMENITIES ------------------
+
+func (s *service) GetAmenities() ([]Amenity, error) {
+	ctx := context.Background()
+
+	amenitiesDB, err := s.db_queries.GetAmenities(ctx)
+	if err != nil {
+		return []Amenity{}, err
+	}
+
+	amenities := []Amenity{}
+	for _, amenity := range amenitiesDB {
+		amenities = append(amenities, Amenity{
+			Name:     amenity.Name,
+			Category: amenity.Category,
+		})
+	}
+	return amenities, nil
+}
+
+// AmenityCatalog returns the names of all amenities in the catalog of the database as a set,
+// in the same shape as the option maps in config for validation.
+func AmenityCatalog(db Service) (map[string]struct{}, error) {
+	amenities, err := db.GetAmenities()
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string]struct{}, len(amenities))
+	for _, amenity := range amenities {
+		catalog[amenity.Name] = struct{}{}
+	}
+	return catalog, nil
+}
+
+func (s *service) CreateAmenity(amenity Amenity) error {
+	ctx := context.Background()
+	return s.db_queries.CreateAmenity(ctx, sqlc.CreateAmenityParams{
+		Name:     amenity.Name,
+		Category: amenity.Category,
+	})
+}
+
+// Delete an amenity from the catalog, which also untags every property that had it
+func (s *service) DeleteAmenity(name string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteAmenity(ctx, name)
+}
+
+// setPropertyAmenities replaces the amenities a property is tagged with.
+func setPropertyAmenities(ctx context.Context, q *sqlc.Queries, propertyID string, amenities []string) error {
+	err := q.DeletePropertyAmenities(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	for _, amenity := range amenities {
+		err = q.CreatePropertyAmenity(ctx, sqlc.CreatePropertyAmenityParams{
+			PropertyID:  propertyID,
+			AmenityName: amenity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Properties
 func (s *service) CreateProperty(propertyDetails PropertyDetails, images []OrderedFileInternal) error {
 	ctx := context.Background()
@@ -1051,46 +1330,82 @@ func (s *service) CreateProperty(propertyDetails PropertyDetails, images []Order
 		return err
 	}
 
-	// Insert property data into db
-	err = s.db_queries.CreatePropertyDetails(ctx, sqlc.CreatePropertyDetailsParams{
-		PropertyID:      propertyDetails.PropertyID,
-		ListerUserID:    encryptedListerUserID,
-		Name:            propertyDetails.Name,
-		Description:     utils.CreateSQLNullString(propertyDetails.Description),
-		Address1:        propertyDetails.Address_1,
-		Address2:        utils.CreateSQLNullString(propertyDetails.Address_2),
-		City:            propertyDetails.City,
-		State:           propertyDetails.State,
-		Zipcode:         propertyDetails.Zipcode,
-		Country:         propertyDetails.Country,
-		SquareFeet:      propertyDetails.Square_feet,
-		NumBedrooms:     propertyDetails.Num_bedrooms,
-		NumToilets:      propertyDetails.Num_toilets,
-		NumShowersBaths: propertyDetails.Num_showers_baths,
-		CostDollars:     propertyDetails.Cost_dollars,
-		CostCents:       propertyDetails.Cost_cents,
-		MiscNote:        utils.CreateSQLNullString(propertyDetails.Misc_note),
-	})
+	availableFrom, err := utils.CreateSQLNullDate(propertyDetails.Available_from)
 	if err != nil {
 		return err
 	}
-
-	// Create the property images
-	for _, image := range images {
-		err = s.db_queries.CreatePropertyImage(ctx, sqlc.CreatePropertyImageParams{
-			PropertyID: propertyDetails.PropertyID,
-			OrderNum:   image.OrderNum,
-			FileName:   image.File.Filename,
-			MimeType:   image.File.Mimetype,
-			Size:       image.File.Size,
-			Data:       image.File.Data,
+	// lease_length_months is not nullable, store no options as an empty array
+	if propertyDetails.Lease_length_months == nil {
+		propertyDetails.Lease_length_months = []int32{}
+	}
+
+	// Insert the property with its amenities and images at once, so that a failed image does not
+	// leave a property without images behind
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		// Insert property data into db
+		err := q.CreatePropertyDetails(ctx, sqlc.CreatePropertyDetailsParams{
+			PropertyID:        propertyDetails.PropertyID,
+			ListerUserID:      encryptedListerUserID,
+			Name:              propertyDetails.Name,
+			Description:       utils.CreateSQLNullString(propertyDetails.Description),
+			Address1:          propertyDetails.Address_1,
+			Address2:          utils.CreateSQLNullString(propertyDetails.Address_2),
+			City:              propertyDetails.City,
+			State:             propertyDetails.State,
+			Zipcode:           propertyDetails.Zipcode,
+			Country:           propertyDetails.Country,
+			SquareFeet:        propertyDetails.Square_feet,
+			NumBedrooms:       propertyDetails.Num_bedrooms,
+			NumToilets:        propertyDetails.Num_toilets,
+			NumShowersBaths:   propertyDetails.Num_showers_baths,
+			CostDollars:       propertyDetails.Cost_dollars,
+			CostCents:         propertyDetails.Cost_cents,
+			MiscNote:          utils.CreateSQLNullString(propertyDetails.Misc_note),
+			Status:            propertyDetails.Status,
+			PriceType:         propertyDetails.Price_type,
+			DepositDollars:    propertyDetails.Deposit_dollars,
+			DepositCents:      propertyDetails.Deposit_cents,
+			UtilitiesIncluded: propertyDetails.Utilities_included,
+			LeaseLengthMonths: propertyDetails.Lease_length_months,
+			AvailableFrom:     availableFrom,
+			RoomsAvailable:    propertyDetails.Rooms_available,
+			PetsPolicy:        propertyDetails.Pets_policy,
+			SmokingPolicy:     propertyDetails.Smoking_policy,
+			NormalizedAddress: normalizePropertyAddress(propertyDetails),
+			Latitude:          utils.CreateSQLNullFloat64(propertyDetails.Latitude),
+			Longitude:         utils.CreateSQLNullFloat64(propertyDetails.Longitude),
+			ExpiresAt:         listingExpiry(propertyDetails.Status, time.Now()),
 		})
 		if err != nil {
 			return err
 		}
-	}
 
-	return nil
+		// Tag the property with its amenities
+		err = setPropertyAmenities(ctx, q, propertyDetails.PropertyID, propertyDetails.Amenities)
+		if err != nil {
+			return err
+		}
+
+		// Create the property images
+		for _, image := range images {
+			contentHash, err := createFileBlob(ctx, q, image.File.Data)
+			if err != nil {
+				return err
+			}
+			err = q.CreatePropertyImage(ctx, sqlc.CreatePropertyImageParams{
+				PropertyID:  propertyDetails.PropertyID,
+				OrderNum:    image.OrderNum,
+				FileName:    image.File.Filename,
+				MimeType:    image.File.Mimetype,
+				Size:        image.File.Size,
+				ContentHash: contentHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // Find and return the property details given the property's id
@@ -1110,24 +1425,48 @@ func (s *service) GetPropertyDetails(propertyId string) (PropertyDetails, error)
 	}
 
 	propertyDetails := PropertyDetails{
-		PropertyID:        propertyId,
-		ListerUserID:      decryptedListerUserID,
-		Name:              property.Name,
-		Description:       property.Description.String,
-		Address_1:         property.Address1,
-		Address_2:         property.Address2.String,
-		City:              property.City,
-		State:             property.State,
-		Zipcode:           property.Zipcode,
-		Country:           property.Country,
-		Square_feet:       property.SquareFeet,
-		Num_bedrooms:      property.NumBedrooms,
-		Num_toilets:       property.NumToilets,
-		Num_showers_baths: property.NumShowersBaths,
-		Cost_dollars:      property.CostDollars,
-		Cost_cents:        property.CostCents,
-		Misc_note:         property.MiscNote.String,
+		PropertyID:          propertyId,
+		ListerUserID:        decryptedListerUserID,
+		Name:                property.Name,
+		Description:         property.Description.String,
+		Address_1:           property.Address1,
+		Address_2:           property.Address2.String,
+		City:                property.City,
+		State:               property.State,
+		Zipcode:             property.Zipcode,
+		Country:             property.Country,
+		Square_feet:         property.SquareFeet,
+		Num_bedrooms:        property.NumBedrooms,
+		Num_toilets:         property.NumToilets,
+		Num_showers_baths:   property.NumShowersBaths,
+		Cost_dollars:        property.CostDollars,
+		Cost_cents:          property.CostCents,
+		Misc_note:           property.MiscNote.String,
+		Status:              property.Status,
+		Price_type:          property.PriceType,
+		Deposit_dollars:     property.DepositDollars,
+		Deposit_cents:       property.DepositCents,
+		Utilities_included:  property.UtilitiesIncluded,
+		Lease_length_months: property.LeaseLengthMonths,
+		Available_from:      utils.FormatSQLNullDate(property.AvailableFrom),
+		Rooms_available:     property.RoomsAvailable,
+		Pets_policy:         property.PetsPolicy,
+		Smoking_policy:      property.SmokingPolicy,
+		Latitude:            utils.FormatSQLNullFloat64(property.Latitude),
+		Longitude:           utils.FormatSQLNullFloat64(property.Longitude),
+	}
+	if property.ExpiresAt.Valid {
+		propertyDetails.ExpiresAt = &property.ExpiresAt.Time
+	}
+
+	amenities, err := s.db_queries.GetPropertyAmenities(ctx, propertyId)
+	if err != nil {
+		return PropertyDetails{}, err
+	}
+	if amenities == nil {
+		amenities = []string{}
 	}
+	propertyDetails.Amenities = amenities
 
 	return propertyDetails, nil
 }
@@ -1156,15 +1495,64 @@ func (s *service) GetPropertyImages(propertyId string) ([]OrderedFileInternal, e
 	return propertyImages, nil
 }
 
+func (s *service) GetPropertyImage(propertyID string, orderNum int16) (FileInternal, error) {
+	ctx := context.Background()
+
+	image, err := s.db_queries.GetPropertyImage(ctx, sqlc.GetPropertyImageParams{
+		PropertyID: propertyID,
+		OrderNum:   orderNum,
+	})
+	if err != nil {
+		return FileInternal{}, err
+	}
+
+	return FileInternal{
+		Filename: image.FileName,
+		Mimetype: image.MimeType,
+		Size:     image.Size,
+		Data:     image.Data,
+	}, nil
+}
+
+// Returns the order numbers of a property's images without loading the images themselves
+func (s *service) GetPropertyImageOrderNums(propertyID string) ([]int16, error) {
+	ctx := context.Background()
+
+	orderNums, err := s.db_queries.GetPropertyImageOrderNums(ctx, propertyID)
+	if err != nil {
+		return []int16{}, err
+	}
+	if orderNums == nil {
+		orderNums = []int16{}
+	}
+	return orderNums, nil
+}
+
 // Allow a public function to search for the available properties on app
-func (s *service) GetNextPageProperties(limit, offset int32, addressFilter string) ([]string, error) {
+// Only published properties are returned, drafts and other non-public listings are never searchable.
+func (s *service) GetNextPageProperties(limit, offset int32, filters PropertySearchFilters) ([]string, error) {
 	ctx := context.Background()
 
-	// There is an addressFilter
+	// A NULL array would filter out every property, no amenities means no amenity filter
+	if filters.Amenities == nil {
+		filters.Amenities = []string{}
+	}
+
 	propertyIDs, err := s.db_queries.GetNextPageProperties(ctx, sqlc.GetNextPagePropertiesParams{
-		Limit:   limit,
-		Offset:  offset,
-		Column3: addressFilter,
+		Limit:          limit,
+		Offset:         offset,
+		Column3:        filters.Address,
+		Status:         config.PROPERTY_STATUS_PUBLISHED,
+		Column5:        filters.PriceType,
+		Column6:        filters.MaxCostDollars,
+		Column7:        filters.AvailableBy,
+		Column8:        filters.LeaseLengthMonths,
+		Column9:        filters.UtilitiesIncluded,
+		Column10:       filters.PetsAllowed,
+		Column11:       filters.SmokingAllowed,
+		RoomsAvailable: filters.MinRoomsAvailable,
+		Column13:       filters.Amenities,
+		Column14:       filters.MatchAllAmenities,
 	})
 	if err != nil {
 		return []string{}, err
@@ -1183,57 +1571,99 @@ func (s *service) UpdatePropertyDetails(details PropertyDetails) error {
 		return err
 	}
 
+	availableFrom, err := utils.CreateSQLNullDate(details.Available_from)
+	if err != nil {
+		return err
+	}
+	// lease_length_months is not nullable, store no options as an empty array
+	if details.Lease_length_months == nil {
+		details.Lease_length_months = []int32{}
+	}
+
 	// Construct the new details struct to insert into db
 	err = s.db_queries.UpdatePropertyDetails(ctx, sqlc.UpdatePropertyDetailsParams{
-		PropertyID:      details.PropertyID,
-		ListerUserID:    encryptedListerUserID,
-		Name:            details.Name,
-		Description:     utils.CreateSQLNullString(details.Description),
-		Address1:        details.Address_1,
-		Address2:        utils.CreateSQLNullString(details.Address_2),
-		City:            details.City,
-		State:           details.State,
-		Zipcode:         details.Zipcode,
-		Country:         details.Country,
-		SquareFeet:      details.Square_feet,
-		NumBedrooms:     details.Num_bedrooms,
-		NumToilets:      details.Num_toilets,
-		NumShowersBaths: details.Num_showers_baths,
-		CostDollars:     details.Cost_dollars,
-		CostCents:       details.Cost_cents,
-		MiscNote:        utils.CreateSQLNullString(details.Misc_note),
+		PropertyID:        details.PropertyID,
+		ListerUserID:      encryptedListerUserID,
+		Name:              details.Name,
+		Description:       utils.CreateSQLNullString(details.Description),
+		Address1:          details.Address_1,
+		Address2:          utils.CreateSQLNullString(details.Address_2),
+		City:              details.City,
+		State:             details.State,
+		Zipcode:           details.Zipcode,
+		Country:           details.Country,
+		SquareFeet:        details.Square_feet,
+		NumBedrooms:       details.Num_bedrooms,
+		NumToilets:        details.Num_toilets,
+		NumShowersBaths:   details.Num_showers_baths,
+		CostDollars:       details.Cost_dollars,
+		CostCents:         details.Cost_cents,
+		MiscNote:          utils.CreateSQLNullString(details.Misc_note),
+		PriceType:         details.Price_type,
+		DepositDollars:    details.Deposit_dollars,
+		DepositCents:      details.Deposit_cents,
+		UtilitiesIncluded: details.Utilities_included,
+		LeaseLengthMonths: details.Lease_length_months,
+		AvailableFrom:     availableFrom,
+		RoomsAvailable:    details.Rooms_available,
+		PetsPolicy:        details.Pets_policy,
+		SmokingPolicy:     details.Smoking_policy,
+		NormalizedAddress: normalizePropertyAddress(details),
+		Latitude:          utils.CreateSQLNullFloat64(details.Latitude),
+		Longitude:         utils.CreateSQLNullFloat64(details.Longitude),
 	})
 	if err != nil {
 		return err
 	}
-	return nil
-}
 
-func (s *service) UpdatePropertyImages(propertyID string, images []OrderedFileInternal) error {
-	ctx := context.Background()
+	err = setPropertyAmenities(ctx, s.db_queries, details.PropertyID, details.Amenities)
+	if err != nil {
+		return err
+	}
 
-	// Delete all old property images
-	err := s.db_queries.DeletePropertyImages(ctx, propertyID)
+	// Properties that are rented by the room derive their availability from their rooms
+	roomCount, err := s.db_queries.CountPropertyRooms(ctx, details.PropertyID)
 	if err != nil {
 		return err
 	}
+	if roomCount > 0 {
+		return s.db_queries.UpdatePropertyRoomsAvailable(ctx, details.PropertyID)
+	}
 
-	// Upload new ones
-	for _, image := range images {
-		err = s.db_queries.CreatePropertyImage(ctx, sqlc.CreatePropertyImageParams{
-			PropertyID: propertyID,
-			OrderNum:   image.OrderNum,
-			FileName:   image.File.Filename,
-			MimeType:   image.File.Mimetype,
-			Size:       image.File.Size,
-			Data:       image.File.Data,
-		})
+	return nil
+}
+
+func (s *service) UpdatePropertyImages(propertyID string, images []OrderedFileInternal) error {
+	ctx := context.Background()
+
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		// Delete all old property images, blobs that are not referenced elsewhere are
+		// released by the database once their last reference is removed.
+		err := q.DeletePropertyImages(ctx, propertyID)
 		if err != nil {
 			return err
 		}
-	}
 
-	return nil
+		// Upload new ones
+		for _, image := range images {
+			contentHash, err := createFileBlob(ctx, q, image.File.Data)
+			if err != nil {
+				return err
+			}
+			err = q.CreatePropertyImage(ctx, sqlc.CreatePropertyImageParams{
+				PropertyID:  propertyID,
+				OrderNum:    image.OrderNum,
+				FileName:    image.File.Filename,
+				MimeType:    image.File.Mimetype,
+				Size:        image.File.Size,
+				ContentHash: contentHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (s *service) UpdatePropertyLister(propertyID string, userID string) error {
@@ -1251,6 +1681,83 @@ func (s *service) UpdatePropertyLister(propertyID string, userID string) error {
 	return err
 }
 
+// listingExpiry returns when a listing given the status now expires, only published listings expire
+func listingExpiry(status string, now time.Time) sql.NullTime {
+	if status != config.PROPERTY_STATUS_PUBLISHED {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: now.AddDate(0, 0, config.PROPERTY_LISTING_DURATION_DAYS), Valid: true}
+}
+
+// Updates the status of a property, publishing a property starts a new listing period
+func (s *service) UpdatePropertyStatus(propertyID string, status string) error {
+	ctx := context.Background()
+	return s.db_queries.UpdatePropertyStatus(ctx, sqlc.UpdatePropertyStatusParams{
+		PropertyID: propertyID,
+		Status:     status,
+		ExpiresAt:  listingExpiry(status, time.Now()),
+	})
+}
+
+func (s *service) UpdatePropertyExpiry(propertyID string, expiresAt time.Time) error {
+	ctx := context.Background()
+	return s.db_queries.UpdatePropertyExpiry(ctx, sqlc.UpdatePropertyExpiryParams{
+		PropertyID: propertyID,
+		ExpiresAt:  sql.NullTime{Time: expiresAt, Valid: true},
+	})
+}
+
+// Returns the published properties expiring by the given time whose listers were not reminded
+// yet, marking them as reminded so each listing period is only reminded of once.
+func (s *service) RemindExpiringProperties(expiresBy time.Time) ([]ExpiringProperty, error) {
+	ctx := context.Background()
+
+	rows, err := s.db_queries.RemindExpiringProperties(ctx, sql.NullTime{Time: expiresBy, Valid: true})
+	if err != nil {
+		return []ExpiringProperty{}, err
+	}
+
+	properties := []ExpiringProperty{}
+	for _, row := range rows {
+		listerUserID, err := utils.DecryptString(row.ListerUserID, s.db_encrypt_key)
+		if err != nil {
+			return []ExpiringProperty{}, err
+		}
+		properties = append(properties, ExpiringProperty{
+			PropertyID:   row.PropertyID,
+			ListerUserID: listerUserID,
+			Name:         row.Name,
+			ExpiresAt:    row.ExpiresAt.Time,
+		})
+	}
+	return properties, nil
+}
+
+// Moves the published properties whose listing expired by now to the expired status, hiding them,
+// and returns them.
+func (s *service) ExpireProperties(now time.Time) ([]ExpiringProperty, error) {
+	ctx := context.Background()
+
+	rows, err := s.db_queries.ExpireProperties(ctx, sql.NullTime{Time: now, Valid: true})
+	if err != nil {
+		return []ExpiringProperty{}, err
+	}
+
+	properties := []ExpiringProperty{}
+	for _, row := range rows {
+		listerUserID, err := utils.DecryptString(row.ListerUserID, s.db_encrypt_key)
+		if err != nil {
+			return []ExpiringProperty{}, err
+		}
+		properties = append(properties, ExpiringProperty{
+			PropertyID:   row.PropertyID,
+			ListerUserID: listerUserID,
+			Name:         row.Name,
+		})
+	}
+	return properties, nil
+}
+
 func (s *service) TransferAllPropertiesToOtherUser(fromUserID, toUserID string) error {
 	ctx := context.Background()
 
@@ -1273,7 +1780,8 @@ func (s *service) TransferAllPropertiesToOtherUser(fromUserID, toUserID string)
 func (s *service) DeleteProperty(propertyId string) error {
 	ctx := context.Background()
 
-	// Delete the property (image rows get deleted via cascade)
+	// Delete the property (image rows get deleted via cascade, and each image's
+	// file blob is only removed once no other property or community references it)
 	err := s.db_queries.DeletePropertyDetails(ctx, propertyId)
 	if err != nil {
 		return err
@@ -1316,24 +1824,25 @@ func (s *service) DeleteUserOwnedProperties(userID string) error {
 	return err
 }
 
+// normalizePropertyAddress returns the canonical address of a property used for duplicate detection
+func normalizePropertyAddress(details PropertyDetails) string {
+	return utils.NormalizeAddress(details.Address_1, details.Address_2, details.City, details.State, details.Zipcode, details.Country)
+}
+
+// Rejects properties whose address is the same as an existing property's, ignoring case and
+// surrounding spaces. Addresses that only match after normalization, such as "St" and "Street",
+// are left to the admin review queue by FlagSimilarProperties.
 func (s *service) CheckDuplicateProperty(propertyDetails PropertyDetails) error {
 	ctx := context.Background()
 
-	// No duplicate property addresses (basic search and check)
-	address1 := propertyDetails.Address_1
-	address2 := propertyDetails.Address_2
-	city := propertyDetails.City
-	state := propertyDetails.State
-	zipcode := propertyDetails.Zipcode
-	country := propertyDetails.Country
-
 	count, err := s.db_queries.CheckIsPropertyDuplicate(ctx, sqlc.CheckIsPropertyDuplicateParams{
-		Btrim:   address1,
-		Btrim_2: address2,
-		Btrim_3: city,
-		Btrim_4: state,
-		Btrim_5: zipcode,
-		Btrim_6: country,
+		Btrim:      propertyDetails.Address_1,
+		Btrim_2:    propertyDetails.Address_2,
+		Btrim_3:    propertyDetails.City,
+		Btrim_4:    propertyDetails.State,
+		Btrim_5:    propertyDetails.Zipcode,
+		Btrim_6:    propertyDetails.Country,
+		PropertyID: propertyDetails.PropertyID,
 	})
 	if err != nil {
 		return err
@@ -1346,119 +1855,642 @@ func (s *service) CheckDuplicateProperty(propertyDetails PropertyDetails) error
 	return nil
 }
 
-// ------------------- ADMIN -------------------
-// Admin - get multiple user ids
-func (s *service) AdminGetUsersRoles(userIds []string) ([]string, error) {
+// Returns the address and stored normalized address of every property
+func (s *service) GetPropertyAddresses() ([]PropertyAddress, error) {
 	ctx := context.Background()
 
-	// Encrypt the user ids
-	var userIdsEncrypted []string
-	for _, userId := range userIds {
-		userIdEncrypted, err := utils.EncryptString(userId, s.db_encrypt_key)
+	rows, err := s.db_queries.GetPropertyAddresses(ctx)
+	if err != nil {
+		return []PropertyAddress{}, err
+	}
+
+	addresses := []PropertyAddress{}
+	for _, row := range rows {
+		addresses = append(addresses, PropertyAddress{
+			PropertyID:        row.PropertyID,
+			Address_1:         row.Address1,
+			Address_2:         row.Address2.String,
+			City:              row.City,
+			State:             row.State,
+			Zipcode:           row.Zipcode,
+			Country:           row.Country,
+			NormalizedAddress: row.NormalizedAddress,
+		})
+	}
+	return addresses, nil
+}
+
+func (s *service) UpdatePropertyNormalizedAddress(propertyID, normalizedAddress string) error {
+	ctx := context.Background()
+	return s.db_queries.UpdatePropertyNormalizedAddress(ctx, sqlc.UpdatePropertyNormalizedAddressParams{
+		PropertyID:        propertyID,
+		NormalizedAddress: normalizedAddress,
+	})
+}
+
+// Find other properties whose address is similar enough to be a suspected duplicate
+func (s *service) FindSimilarProperties(propertyDetails PropertyDetails, threshold float32) ([]SimilarProperty, error) {
+	ctx := context.Background()
+
+	rows, err := s.db_queries.GetSimilarProperties(ctx, sqlc.GetSimilarPropertiesParams{
+		Column1:    normalizePropertyAddress(propertyDetails),
+		PropertyID: propertyDetails.PropertyID,
+		Column3:    threshold,
+	})
+	if err != nil {
+		return []SimilarProperty{}, err
+	}
+
+	similarProperties := []SimilarProperty{}
+	for _, row := range rows {
+		similarProperties = append(similarProperties, SimilarProperty{
+			PropertyID: row.PropertyID,
+			Similarity: row.Similarity,
+		})
+	}
+	return similarProperties, nil
+}
+
+// Adds the property to the admin duplicate review queue once for every existing property
+// whose address is similar to it, including the same address after normalization. Addresses
+// typed identically are rejected outright by CheckDuplicateProperty, this catches the rest.
+func (s *service) FlagSimilarProperties(propertyDetails PropertyDetails, threshold float32) error {
+	similarProperties, err := s.FindSimilarProperties(propertyDetails, threshold)
+	if err != nil {
+		return err
+	}
+	for _, similarProperty := range similarProperties {
+		err = s.CreatePropertyDuplicateReview(propertyDetails.PropertyID, similarProperty.PropertyID, similarProperty.Similarity)
 		if err != nil {
-			return []string{}, err
+			return err
 		}
-		userIdsEncrypted = append(userIdsEncrypted, userIdEncrypted)
 	}
+	return nil
+}
 
-	// Get the roles for the users
-	var userRolesEncrypted []sqlc.Role
-	for _, userId := range userIdsEncrypted {
-		role, err := s.db_queries.GetUserRole(ctx, userId)
+// Returns published properties of the same price type as the given property that are closest
+// to it in price, as candidates to rank by similarity. Only the fields used for ranking are filled
+// in. The excluded properties and the properties of the excluded lister, if any, are left out.
+func (s *service) GetRecommendationCandidates(propertyDetails PropertyDetails, excludedPropertyIDs []string, excludedListerUserID string, limit int32) ([]PropertyDetails, error) {
+	ctx := context.Background()
+
+	encryptedListerUserID := ""
+	if excludedListerUserID != "" {
+		var err error
+		encryptedListerUserID, err = utils.EncryptString(excludedListerUserID, s.db_encrypt_key)
 		if err != nil {
-			return []string{}, err
+			return []PropertyDetails{}, err
 		}
+	}
+	if excludedPropertyIDs == nil {
+		excludedPropertyIDs = []string{}
+	}
 
-		userRolesEncrypted = append(userRolesEncrypted, role)
+	rows, err := s.db_queries.GetRecommendationCandidates(ctx, sqlc.GetRecommendationCandidatesParams{
+		Status:       config.PROPERTY_STATUS_PUBLISHED,
+		PriceType:    propertyDetails.Price_type,
+		Column3:      excludedPropertyIDs,
+		ListerUserID: encryptedListerUserID,
+		Column5:      propertyDetails.Cost_dollars,
+		Limit:        limit,
+	})
+	if err != nil {
+		return []PropertyDetails{}, err
+	}
+
+	candidates := []PropertyDetails{}
+	for _, row := range rows {
+		candidates = append(candidates, PropertyDetails{
+			PropertyID:        row.PropertyID,
+			Price_type:        row.PriceType,
+			Cost_dollars:      row.CostDollars,
+			Cost_cents:        row.CostCents,
+			Square_feet:       row.SquareFeet,
+			Num_bedrooms:      row.NumBedrooms,
+			Num_showers_baths: row.NumShowersBaths,
+			City:              row.City,
+			State:             row.State,
+			Zipcode:           row.Zipcode,
+			Latitude:          utils.FormatSQLNullFloat64(row.Latitude),
+			Longitude:         utils.FormatSQLNullFloat64(row.Longitude),
+		})
 	}
+	return candidates, nil
+}
 
-	// Decrypt the roles
-	var userRoles []string
-	for _, role := range userRolesEncrypted {
-		roleDecrypted, err := utils.DecryptString(role.Role, s.db_encrypt_key)
+// -------------- PROPERTY DUPLICATE REVIEWS ------------------
+
+func (s *service) propertyDuplicateReviewFromDB(review sqlc.PropertiesDuplicateReview) (PropertyDuplicateReview, error) {
+	var reviewedByUserID string
+	if review.ReviewedByUserID.Valid {
+		decryptedUserID, err := utils.DecryptString(review.ReviewedByUserID.String, s.db_encrypt_key)
 		if err != nil {
-			return []string{}, err
+			return PropertyDuplicateReview{}, err
 		}
-		userRoles = append(userRoles, roleDecrypted)
-	}
+		reviewedByUserID = decryptedUserID
+	}
+	var reviewedAt *time.Time
+	if review.ReviewedAt.Valid {
+		reviewedAt = &review.ReviewedAt.Time
+	}
+
+	return PropertyDuplicateReview{
+		ID:                    review.ID,
+		PropertyID:            review.PropertyID,
+		DuplicateOfPropertyID: review.DuplicateOfPropertyID,
+		Similarity:            review.Similarity,
+		Status:                review.Status,
+		ReviewedByUserID:      reviewedByUserID,
+		ReviewedAt:            reviewedAt,
+		CreatedAt:             review.CreatedAt,
+	}, nil
+}
 
-	return userRoles, nil
+// Add a suspected duplicate to the admin review queue, a pair is only queued once
+func (s *service) CreatePropertyDuplicateReview(propertyID, duplicateOfPropertyID string, similarity float32) error {
+	ctx := context.Background()
+	return s.db_queries.CreatePropertyDuplicateReview(ctx, sqlc.CreatePropertyDuplicateReviewParams{
+		PropertyID:            propertyID,
+		DuplicateOfPropertyID: duplicateOfPropertyID,
+		Similarity:            similarity,
+	})
 }
 
-func (s *service) GetTotalCountProperties() (int64, error) {
+func (s *service) GetPropertyDuplicateReview(reviewID int32) (PropertyDuplicateReview, error) {
 	ctx := context.Background()
-	num, err := s.db_queries.GetTotalCountProperties(ctx)
+
+	review, err := s.db_queries.GetPropertyDuplicateReview(ctx, reviewID)
 	if err != nil {
-		return -1, err
+		return PropertyDuplicateReview{}, err
 	}
-	return num, nil
+	return s.propertyDuplicateReviewFromDB(review)
 }
 
-func (s *service) GetTotalCountCommunities() (int64, error) {
+func (s *service) GetPropertyDuplicateReviews(status string, limit, offset int32) ([]PropertyDuplicateReview, error) {
 	ctx := context.Background()
-	num, err := s.db_queries.GetTotalCountCommunities(ctx)
+
+	reviewsDB, err := s.db_queries.GetPropertyDuplicateReviews(ctx, sqlc.GetPropertyDuplicateReviewsParams{
+		Status: status,
+		Limit:  limit,
+		Offset: offset,
+	})
 	if err != nil {
-		return -1, err
+		return []PropertyDuplicateReview{}, err
 	}
-	return num, nil
+
+	reviews := []PropertyDuplicateReview{}
+	for _, reviewDB := range reviewsDB {
+		review, err := s.propertyDuplicateReviewFromDB(reviewDB)
+		if err != nil {
+			return []PropertyDuplicateReview{}, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
 }
 
-func (s *service) GetTotalCountUsers() (int64, error) {
+func (s *service) UpdatePropertyDuplicateReviewStatus(reviewID int32, status, reviewerUserID string) error {
 	ctx := context.Background()
-	num, err := s.db_queries.GetTotalCountUsers(ctx)
+
+	encryptedUserID, err := s.encryptOptionalUserID(reviewerUserID)
 	if err != nil {
-		return -1, err
+		return err
 	}
-	return num, nil
+
+	return s.db_queries.UpdatePropertyDuplicateReviewStatus(ctx, sqlc.UpdatePropertyDuplicateReviewStatusParams{
+		ID:               reviewID,
+		Status:           status,
+		ReviewedByUserID: encryptedUserID,
+	})
 }
 
-// ---------------- Communities --------------------
-func (s *service) CreateCommunity(details CommunityDetails, images []FileInternal) error {
+// -------------- PROPERTY HISTORY ------------------
+
+// Record the changes a user made to a property
+func (s *service) CreatePropertyHistory(propertyID, changedByUserID string, changes []PropertyChange) error {
 	ctx := context.Background()
 
-	// Encrypt the community's admin user id
-	encryptedAdminUserID, err := utils.EncryptString(details.AdminUserID, s.db_encrypt_key)
+	encryptedUserID, err := s.encryptOptionalUserID(changedByUserID)
 	if err != nil {
 		return err
 	}
 
-	// Create community details with all plain text details except admin user id
-	err = s.db_queries.CreateCommunityDetails(ctx, sqlc.CreateCommunityDetailsParams{
-		CommunityID: details.CommunityID,
-		AdminUserID: encryptedAdminUserID,
-		Name:        details.Name,
-		Description: utils.CreateSQLNullString(details.Description),
+	for _, change := range changes {
+		// Lister ids are user ids and are stored encrypted
+		if change.Field == PROPERTY_HISTORY_FIELD_LISTER {
+			change.OldValue, err = utils.EncryptString(change.OldValue, s.db_encrypt_key)
+			if err != nil {
+				return err
+			}
+			change.NewValue, err = utils.EncryptString(change.NewValue, s.db_encrypt_key)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.db_queries.CreatePropertyHistory(ctx, sqlc.CreatePropertyHistoryParams{
+			PropertyID:      propertyID,
+			ChangedByUserID: encryptedUserID,
+			Field:           change.Field,
+			OldValue:        change.OldValue,
+			NewValue:        change.NewValue,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get the history of a property, most recent changes first
+func (s *service) GetPropertyHistory(propertyID string) ([]PropertyHistoryEntry, error) {
+	ctx := context.Background()
+
+	historyDB, err := s.db_queries.GetPropertyHistory(ctx, propertyID)
+	if err != nil {
+		return []PropertyHistoryEntry{}, err
+	}
+
+	history := []PropertyHistoryEntry{}
+	for _, entry := range historyDB {
+		var changedByUserID string
+		if entry.ChangedByUserID.Valid {
+			changedByUserID, err = utils.DecryptString(entry.ChangedByUserID.String, s.db_encrypt_key)
+			if err != nil {
+				return []PropertyHistoryEntry{}, err
+			}
+		}
+
+		oldValue, newValue := entry.OldValue, entry.NewValue
+		if entry.Field == PROPERTY_HISTORY_FIELD_LISTER {
+			oldValue, err = utils.DecryptString(oldValue, s.db_encrypt_key)
+			if err != nil {
+				return []PropertyHistoryEntry{}, err
+			}
+			newValue, err = utils.DecryptString(newValue, s.db_encrypt_key)
+			if err != nil {
+				return []PropertyHistoryEntry{}, err
+			}
+		}
+
+		history = append(history, PropertyHistoryEntry{
+			PropertyChange: PropertyChange{
+				Field:    entry.Field,
+				OldValue: oldValue,
+				NewValue: newValue,
+			},
+			ChangedByUserID: changedByUserID,
+			CreatedAt:       entry.CreatedAt,
+		})
+	}
+
+	return history, nil
+}
+
+// -------------- PROPERTY ROOMS ------------------
+
+// encryptOptionalUserID encrypts a user id that may be empty, in which case a NULL is stored.
+func (s *service) encryptOptionalUserID(userID string) (sql.NullString, error) {
+	if userID == "" {
+		return sql.NullString{Valid: false}, nil
+	}
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return sql.NullString{Valid: false}, err
+	}
+	return utils.CreateSQLNullString(encryptedUserID), nil
+}
+
+// decryptOptionalUserID decrypts a user id that may be NULL, in which case it is empty.
+func (s *service) decryptOptionalUserID(userID sql.NullString) (string, error) {
+	if !userID.Valid {
+		return "", nil
+	}
+	return utils.DecryptString(userID.String, s.db_encrypt_key)
+}
+
+// roomDetailsFromDB converts a room row into its external representation.
+func (s *service) roomDetailsFromDB(room sqlc.PropertiesRoom) (RoomDetails, error) {
+	var occupantUserID string
+	if room.OccupantUserID.Valid {
+		decryptedUserID, err := utils.DecryptString(room.OccupantUserID.String, s.db_encrypt_key)
+		if err != nil {
+			return RoomDetails{}, err
+		}
+		occupantUserID = decryptedUserID
+	}
+
+	return RoomDetails{
+		RoomID:           room.RoomID,
+		PropertyID:       room.PropertyID,
+		Name:             room.Name,
+		Description:      room.Description.String,
+		Square_feet:      room.SquareFeet,
+		Cost_dollars:     room.CostDollars,
+		Cost_cents:       room.CostCents,
+		Is_available:     room.IsAvailable,
+		Available_from:   utils.FormatSQLNullDate(room.AvailableFrom),
+		Occupant_user_id: occupantUserID,
+	}, nil
+}
+
+func (s *service) CreatePropertyRoom(details RoomDetails, images []OrderedFileInternal) error {
+	ctx := context.Background()
+
+	occupantUserID, err := s.encryptOptionalUserID(details.Occupant_user_id)
+	if err != nil {
+		return err
+	}
+	availableFrom, err := utils.CreateSQLNullDate(details.Available_from)
+	if err != nil {
+		return err
+	}
+
+	err = s.db_queries.CreatePropertyRoom(ctx, sqlc.CreatePropertyRoomParams{
+		RoomID:         details.RoomID,
+		PropertyID:     details.PropertyID,
+		Name:           details.Name,
+		Description:    utils.CreateSQLNullString(details.Description),
+		SquareFeet:     details.Square_feet,
+		CostDollars:    details.Cost_dollars,
+		CostCents:      details.Cost_cents,
+		IsAvailable:    details.Is_available,
+		AvailableFrom:  availableFrom,
+		OccupantUserID: occupantUserID,
 	})
 	if err != nil {
 		return err
 	}
 
-	// Add the community admin as the first user
-	err = s.db_queries.CreateCommunityUser(ctx, sqlc.CreateCommunityUserParams{
-		CommunityID: details.CommunityID,
-		UserID:      encryptedAdminUserID,
+	err = s.UpdatePropertyRoomImages(details.RoomID, images)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.UpdatePropertyRoomsAvailable(ctx, details.PropertyID)
+}
+
+func (s *service) GetPropertyRoomDetails(roomID string) (RoomDetails, error) {
+	ctx := context.Background()
+
+	room, err := s.db_queries.GetPropertyRoom(ctx, roomID)
+	if err != nil {
+		return RoomDetails{}, err
+	}
+
+	return s.roomDetailsFromDB(room)
+}
+
+func (s *service) GetPropertyRoomImages(roomID string) ([]OrderedFileInternal, error) {
+	ctx := context.Background()
+
+	var roomImages []OrderedFileInternal
+	roomImagesDB, err := s.db_queries.GetPropertyRoomImages(ctx, roomID)
+	if err != nil {
+		return []OrderedFileInternal{}, err
+	}
+
+	for _, image := range roomImagesDB {
+		roomImages = append(roomImages, OrderedFileInternal{
+			OrderNum: image.OrderNum,
+			File: FileInternal{
+				Filename: image.FileName,
+				Mimetype: image.MimeType,
+				Size:     image.Size,
+				Data:     image.Data,
+			},
+		})
+	}
+
+	return roomImages, nil
+}
+
+func (s *service) GetPropertyRooms(propertyID string) ([]RoomDetails, error) {
+	ctx := context.Background()
+
+	roomsDB, err := s.db_queries.GetPropertyRooms(ctx, propertyID)
+	if err != nil {
+		return []RoomDetails{}, err
+	}
+
+	rooms := []RoomDetails{}
+	for _, roomDB := range roomsDB {
+		room, err := s.roomDetailsFromDB(roomDB)
+		if err != nil {
+			return []RoomDetails{}, err
+		}
+		rooms = append(rooms, room)
+	}
+
+	return rooms, nil
+}
+
+func (s *service) CountPropertyRooms(propertyID string) (int64, error) {
+	ctx := context.Background()
+	return s.db_queries.CountPropertyRooms(ctx, propertyID)
+}
+
+// Update a room's details, the room cannot be moved to another property
+func (s *service) UpdatePropertyRoomDetails(details RoomDetails) error {
+	ctx := context.Background()
+
+	occupantUserID, err := s.encryptOptionalUserID(details.Occupant_user_id)
+	if err != nil {
+		return err
+	}
+	availableFrom, err := utils.CreateSQLNullDate(details.Available_from)
+	if err != nil {
+		return err
+	}
+
+	err = s.db_queries.UpdatePropertyRoom(ctx, sqlc.UpdatePropertyRoomParams{
+		RoomID:         details.RoomID,
+		Name:           details.Name,
+		Description:    utils.CreateSQLNullString(details.Description),
+		SquareFeet:     details.Square_feet,
+		CostDollars:    details.Cost_dollars,
+		CostCents:      details.Cost_cents,
+		IsAvailable:    details.Is_available,
+		AvailableFrom:  availableFrom,
+		OccupantUserID: occupantUserID,
 	})
 	if err != nil {
 		return err
 	}
 
-	// Insert all provided community images
-	for _, image := range images {
-		err = s.db_queries.CreateCommunityImage(ctx, sqlc.CreateCommunityImageParams{
+	return s.db_queries.UpdatePropertyRoomsAvailable(ctx, details.PropertyID)
+}
+
+func (s *service) UpdatePropertyRoomImages(roomID string, images []OrderedFileInternal) error {
+	ctx := context.Background()
+
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		// Delete all old room images, their blobs are released once unreferenced
+		err := q.DeletePropertyRoomImages(ctx, roomID)
+		if err != nil {
+			return err
+		}
+
+		for _, image := range images {
+			contentHash, err := createFileBlob(ctx, q, image.File.Data)
+			if err != nil {
+				return err
+			}
+			err = q.CreatePropertyRoomImage(ctx, sqlc.CreatePropertyRoomImageParams{
+				RoomID:      roomID,
+				OrderNum:    image.OrderNum,
+				FileName:    image.File.Filename,
+				MimeType:    image.File.Mimetype,
+				Size:        image.File.Size,
+				ContentHash: contentHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *service) DeletePropertyRoom(roomID string) error {
+	ctx := context.Background()
+
+	room, err := s.db_queries.GetPropertyRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	// Room images are deleted via cascade
+	err = s.db_queries.DeletePropertyRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.UpdatePropertyRoomsAvailable(ctx, room.PropertyID)
+}
+
+// ------------------- ADMIN -------------------
+// Admin - get multiple user ids
+func (s *service) AdminGetUsersRoles(userIds []string) ([]string, error) {
+	ctx := context.Background()
+
+	// Encrypt the user ids
+	var userIdsEncrypted []string
+	for _, userId := range userIds {
+		userIdEncrypted, err := utils.EncryptString(userId, s.db_encrypt_key)
+		if err != nil {
+			return []string{}, err
+		}
+		userIdsEncrypted = append(userIdsEncrypted, userIdEncrypted)
+	}
+
+	// Get the roles for the users
+	var userRolesEncrypted []sqlc.Role
+	for _, userId := range userIdsEncrypted {
+		role, err := s.db_queries.GetUserRole(ctx, userId)
+		if err != nil {
+			return []string{}, err
+		}
+
+		userRolesEncrypted = append(userRolesEncrypted, role)
+	}
+
+	// Decrypt the roles
+	var userRoles []string
+	for _, role := range userRolesEncrypted {
+		roleDecrypted, err := utils.DecryptString(role.Role, s.db_encrypt_key)
+		if err != nil {
+			return []string{}, err
+		}
+		userRoles = append(userRoles, roleDecrypted)
+	}
+
+	return userRoles, nil
+}
+
+func (s *service) GetTotalCountProperties() (int64, error) {
+	ctx := context.Background()
+	num, err := s.db_queries.GetTotalCountProperties(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return num, nil
+}
+
+func (s *service) GetTotalCountCommunities() (int64, error) {
+	ctx := context.Background()
+	num, err := s.db_queries.GetTotalCountCommunities(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return num, nil
+}
+
+func (s *service) GetTotalCountUsers() (int64, error) {
+	ctx := context.Background()
+	num, err := s.db_queries.GetTotalCountUsers(ctx)
+	if err != nil {
+		return -1, err
+	}
+	return num, nil
+}
+
+// ---------------- Communities --------------------
+func (s *service) CreateCommunity(details CommunityDetails, images []FileInternal) error {
+	ctx := context.Background()
+
+	// Encrypt the community's admin user id
+	encryptedAdminUserID, err := utils.EncryptString(details.AdminUserID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	// Insert the community with its owner and images at once
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		// Create community details with all plain text details except admin user id
+		err := q.CreateCommunityDetails(ctx, sqlc.CreateCommunityDetailsParams{
 			CommunityID: details.CommunityID,
-			FileName:    image.Filename,
-			MimeType:    image.Mimetype,
-			Size:        image.Size,
-			Data:        image.Data,
+			AdminUserID: encryptedAdminUserID,
+			Name:        details.Name,
+			Description: utils.CreateSQLNullString(details.Description),
 		})
 		if err != nil {
 			return err
 		}
-	}
 
-	return nil
+		// Add the community admin as the first user, owning the community
+		err = q.CreateCommunityUser(ctx, sqlc.CreateCommunityUserParams{
+			CommunityID: details.CommunityID,
+			UserID:      encryptedAdminUserID,
+			Role:        config.COMMUNITY_ROLE_OWNER,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Insert all provided community images
+		for _, image := range images {
+			contentHash, err := createFileBlob(ctx, q, image.Data)
+			if err != nil {
+				return err
+			}
+			err = q.CreateCommunityImage(ctx, sqlc.CreateCommunityImageParams{
+				CommunityID: details.CommunityID,
+				FileName:    image.Filename,
+				MimeType:    image.Mimetype,
+				Size:        image.Size,
+				ContentHash: contentHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
+// Adds a user to a community as a regular member
 func (s *service) CreateCommunityUser(communityId, userId string) error {
 	ctx := context.Background()
 
@@ -1471,6 +2503,7 @@ func (s *service) CreateCommunityUser(communityId, userId string) error {
 	err = s.db_queries.CreateCommunityUser(ctx, sqlc.CreateCommunityUserParams{
 		CommunityID: communityId,
 		UserID:      encryptedUserID,
+		Role:        config.COMMUNITY_ROLE_MEMBER,
 	})
 	return err
 }
@@ -1554,13 +2587,33 @@ func (s *service) GetCommunityProperties(communityId string) ([]string, error) {
 	return returnPropertyIds, nil
 }
 
-func (s *service) GetNextPageCommunities(limit, offset int32, filterName, filterDescription string) ([]string, error) {
+func (s *service) GetNextPageCommunities(limit, offset int32, filters CommunitySearchFilters) ([]string, error) {
 	ctx := context.Background()
+
+	// Interests of users are stored encrypted, a NULL array would filter out every community
+	encryptedInterests := []string{}
+	for _, interest := range filters.Interests {
+		encryptedInterest, err := utils.EncryptString(interest, s.db_encrypt_key)
+		if err != nil {
+			return []string{}, err
+		}
+		encryptedInterests = append(encryptedInterests, encryptedInterest)
+	}
+
 	communityIds, err := s.db_queries.GetNextPageCommunities(ctx, sqlc.GetNextPageCommunitiesParams{
-		Limit:   limit,
-		Offset:  offset,
-		Column3: filterName,
-		Column4: filterDescription,
+		Limit:    limit,
+		Offset:   offset,
+		Column3:  filters.Name,
+		Column4:  filters.Description,
+		Column5:  filters.City,
+		Column6:  filters.Latitude,
+		Column7:  filters.Longitude,
+		Column8:  filters.RadiusKm,
+		Column9:  filters.MinMembers,
+		Column10: filters.MaxMembers,
+		Column11: filters.HasVacancy,
+		Column12: encryptedInterests,
+		Status:   config.PROPERTY_STATUS_PUBLISHED,
 	})
 	if err != nil {
 		return []string{}, err
@@ -1586,41 +2639,74 @@ func (s *service) UpdateCommunityDetails(details CommunityDetails) error {
 
 func (s *service) UpdateCommunityImages(communityId string, images []FileInternal) error {
 	ctx := context.Background()
-	err := s.db_queries.DeleteCommunityImages(ctx, communityId)
-	if err != nil {
-		return err
-	}
-	for _, image := range images {
-		err = s.db_queries.CreateCommunityImage(ctx, sqlc.CreateCommunityImageParams{
-			CommunityID: communityId,
-			FileName:    image.Filename,
-			MimeType:    image.Mimetype,
-			Size:        image.Size,
-			Data:        image.Data,
-		})
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.DeleteCommunityImages(ctx, communityId)
 		if err != nil {
 			return err
 		}
-	}
-	return nil
-}
+		for _, image := range images {
+			contentHash, err := createFileBlob(ctx, q, image.Data)
+			if err != nil {
+				return err
+			}
+			err = q.CreateCommunityImage(ctx, sqlc.CreateCommunityImageParams{
+				CommunityID: communityId,
+				FileName:    image.Filename,
+				MimeType:    image.Mimetype,
+				Size:        image.Size,
+				ContentHash: contentHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
+// Sets the users of a community. Users no longer in the list are removed, users that remain keep
+// their role and new users join as regular members.
 func (s *service) UpdateCommunityUsers(communityID string, userIDs []string) error {
 	ctx := context.Background()
-	err := s.db_queries.DeleteCommunityUsers(ctx, communityID)
+
+	currentUsers, err := s.db_queries.GetCommunityUsers(ctx, communityID)
 	if err != nil {
 		return err
 	}
+	currentEncryptedUserIDs := map[string]struct{}{}
+	for _, user := range currentUsers {
+		currentEncryptedUserIDs[user.UserID] = struct{}{}
+	}
 
+	encryptedUserIDs := map[string]struct{}{}
 	for _, userID := range userIDs {
 		// Encrypt user id
 		encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
 		if err != nil {
 			return err
 		}
+		encryptedUserIDs[encryptedUserID] = struct{}{}
+
+		if _, exists := currentEncryptedUserIDs[encryptedUserID]; exists {
+			continue
+		}
 		err = s.db_queries.CreateCommunityUser(ctx, sqlc.CreateCommunityUserParams{
 			CommunityID: communityID,
 			UserID:      encryptedUserID,
+			Role:        config.COMMUNITY_ROLE_MEMBER,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for encryptedUserID := range currentEncryptedUserIDs {
+		if _, exists := encryptedUserIDs[encryptedUserID]; exists {
+			continue
+		}
+		err = s.db_queries.DeleteCommunityUser(ctx, sqlc.DeleteCommunityUserParams{
+			CommunityID: communityID,
+			UserID:      encryptedUserID,
 		})
 		if err != nil {
 			return err
@@ -1647,21 +2733,6 @@ func (s *service) UpdateCommunityProperties(communityID string, propertyIDs []st
 	return nil
 }
 
-func (s *service) UpdateCommunityAdmin(communityID string, userID string) error {
-	ctx := context.Background()
-
-	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
-	if err != nil {
-		return err
-	}
-
-	err = s.db_queries.UpdateCommunityAdmin(ctx, sqlc.UpdateCommunityAdminParams{
-		CommunityID: communityID,
-		AdminUserID: encryptedUserID,
-	})
-	return err
-}
-
 func (s *service) DeleteCommunity(communityId string) error {
 	ctx := context.Background()
 	err := s.db_queries.DeleteCommunity(ctx, communityId)
@@ -1714,6 +2785,132 @@ func (s *service) DeleteUserOwnedCommunities(userID string) error {
 	return err
 }
 
+func (s *service) IsCommunityUser(communityID, userID string) (bool, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := s.db_queries.CheckIsCommunityUser(ctx, sqlc.CheckIsCommunityUserParams{
+		CommunityID: communityID,
+		UserID:      encryptedUserID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Returns the role of a user in a community, empty if the user is not a member
+func (s *service) GetCommunityUserRole(communityID, userID string) (string, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return "", err
+	}
+
+	role, err := s.db_queries.GetCommunityUserRole(ctx, sqlc.GetCommunityUserRoleParams{
+		CommunityID: communityID,
+		UserID:      encryptedUserID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return role, err
+}
+
+func (s *service) GetCommunityMembers(communityID string) ([]CommunityMember, error) {
+	ctx := context.Background()
+
+	users, err := s.db_queries.GetCommunityUsers(ctx, communityID)
+	if err != nil {
+		return []CommunityMember{}, err
+	}
+
+	members := []CommunityMember{}
+	for _, user := range users {
+		userID, err := utils.DecryptString(user.UserID, s.db_encrypt_key)
+		if err != nil {
+			return []CommunityMember{}, err
+		}
+		members = append(members, CommunityMember{
+			UserID: userID,
+			Role:   user.Role,
+		})
+	}
+	return members, nil
+}
+
+func (s *service) UpdateCommunityUserRole(communityID, userID, role string) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.UpdateCommunityUserRole(ctx, sqlc.UpdateCommunityUserRoleParams{
+		CommunityID: communityID,
+		UserID:      encryptedUserID,
+		Role:        role,
+	})
+}
+
+// Makes a member the owner and admin of a community, the previous owner stays on as a moderator
+func (s *service) TransferCommunityOwnership(communityID, fromUserID, toUserID string) error {
+	ctx := context.Background()
+
+	encryptedFromUserID, err := utils.EncryptString(fromUserID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+	encryptedToUserID, err := utils.EncryptString(toUserID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	// The admin and the roles change together, a community never has two owners or none
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.UpdateCommunityAdmin(ctx, sqlc.UpdateCommunityAdminParams{
+			CommunityID: communityID,
+			AdminUserID: encryptedToUserID,
+		})
+		if err != nil {
+			return err
+		}
+		err = q.UpdateCommunityUserRole(ctx, sqlc.UpdateCommunityUserRoleParams{
+			CommunityID: communityID,
+			UserID:      encryptedToUserID,
+			Role:        config.COMMUNITY_ROLE_OWNER,
+		})
+		if err != nil {
+			return err
+		}
+		return q.UpdateCommunityUserRole(ctx, sqlc.UpdateCommunityUserRoleParams{
+			CommunityID: communityID,
+			UserID:      encryptedFromUserID,
+			Role:        config.COMMUNITY_ROLE_MODERATOR,
+		})
+	})
+}
+
+// Returns the ids of the communities a property is linked to
+func (s *service) GetPropertyCommunities(propertyID string) ([]string, error) {
+	ctx := context.Background()
+
+	communityIDs, err := s.db_queries.GetPropertyCommunities(ctx, propertyID)
+	if err != nil {
+		return []string{}, err
+	}
+	if communityIDs == nil {
+		communityIDs = []string{}
+	}
+	return communityIDs, nil
+}
+
 func (s *service) GetNextPagePublicUserIDs(limit, offset int32, firstName, lastName string) ([]string, error) {
 	ctx := context.Background()
 
@@ -1836,6 +3033,2003 @@ func (s *service) GetPublicUserProfile(userID string) (PublicUserProfile, error)
 	return userProfile, nil
 }
 
+// -------------- NOTIFICATIONS ------------------
+
+func (s *service) CreateNotification(userID, notificationType, message, link string) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.CreateNotification(ctx, sqlc.CreateNotificationParams{
+		UserID:  encryptedUserID,
+		Type:    notificationType,
+		Message: message,
+		Link:    link,
+	})
+}
+
+func (s *service) GetNotifications(userID string, limit, offset int32) ([]Notification, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return []Notification{}, err
+	}
+
+	notificationsDB, err := s.db_queries.GetNotifications(ctx, sqlc.GetNotificationsParams{
+		UserID: encryptedUserID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return []Notification{}, err
+	}
+
+	notifications := []Notification{}
+	for _, notification := range notificationsDB {
+		notifications = append(notifications, Notification{
+			ID:        notification.ID,
+			Type:      notification.Type,
+			Message:   notification.Message,
+			Link:      notification.Link,
+			IsRead:    notification.IsRead,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+	return notifications, nil
+}
+
+func (s *service) CountUnreadNotifications(userID string) (int64, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return 0, err
+	}
+	return s.db_queries.CountUnreadNotifications(ctx, encryptedUserID)
+}
+
+// Marks a notification of the user as read, notifications of other users are left untouched
+func (s *service) MarkNotificationRead(userID string, notificationID int32) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+	return s.db_queries.MarkNotificationRead(ctx, sqlc.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: encryptedUserID,
+	})
+}
+
+func (s *service) MarkAllNotificationsRead(userID string) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+	return s.db_queries.MarkAllNotificationsRead(ctx, encryptedUserID)
+}
+
+// -------------- PROPERTY ANALYTICS ------------------
+
+// Records a view or save of a property, counting each viewer at most once per day. The viewer
+// identifies who viewed the property and is only stored as a hash salted with the secret key.
+func (s *service) RecordPropertyEvent(propertyID, eventType, viewer string) error {
+	ctx := context.Background()
+
+	// The viewer is only marked as seen if the event is counted as well
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		recorded, err := q.CreatePropertyAnalyticsEvent(ctx, sqlc.CreatePropertyAnalyticsEventParams{
+			PropertyID: propertyID,
+			EventType:  eventType,
+			ViewerHash: utils.ContentHash([]byte(s.db_encrypt_key + viewer)),
+		})
+		if err != nil {
+			return err
+		}
+		if recorded == 0 {
+			return nil
+		}
+
+		switch eventType {
+		case config.PROPERTY_ANALYTICS_EVENT_VIEW:
+			return q.IncrementPropertyAnalyticsViews(ctx, propertyID)
+		case config.PROPERTY_ANALYTICS_EVENT_SAVE:
+			return q.IncrementPropertyAnalyticsSaves(ctx, propertyID)
+		}
+		return fmt.Errorf("unknown property analytics event %s", eventType)
+	})
+}
+
+// Returns the daily counters of a property since the given day, days without any views
+// or saves are left out.
+func (s *service) GetPropertyAnalytics(propertyID string, from time.Time) ([]PropertyAnalyticsDay, error) {
+	ctx := context.Background()
+
+	daysDB, err := s.db_queries.GetPropertyAnalyticsDaily(ctx, sqlc.GetPropertyAnalyticsDailyParams{
+		PropertyID: propertyID,
+		Day:        from,
+	})
+	if err != nil {
+		return []PropertyAnalyticsDay{}, err
+	}
+
+	days := []PropertyAnalyticsDay{}
+	for _, day := range daysDB {
+		days = append(days, PropertyAnalyticsDay{
+			Date:  day.Day.Format("2006-01-02"),
+			Views: day.Views,
+			Saves: day.Saves,
+		})
+	}
+	return days, nil
+}
+
+func (s *service) DeletePropertyAnalyticsEventsBefore(day time.Time) (int64, error) {
+	ctx := context.Background()
+	return s.db_queries.DeletePropertyAnalyticsEventsBefore(ctx, day)
+}
+
+// -------------- PROPERTY VIEWINGS ------------------
+
+func (s *service) CreateViewingSlot(slot ViewingSlot) error {
+	ctx := context.Background()
+	return s.db_queries.CreatePropertyViewingSlot(ctx, sqlc.CreatePropertyViewingSlotParams{
+		SlotID:     slot.SlotID,
+		PropertyID: slot.PropertyID,
+		StartTime:  slot.StartTime.UTC(),
+		EndTime:    slot.EndTime.UTC(),
+		Capacity:   slot.Capacity,
+		Note:       slot.Note,
+	})
+}
+
+func (s *service) GetViewingSlot(slotID string) (ViewingSlot, error) {
+	ctx := context.Background()
+
+	slot, err := s.db_queries.GetPropertyViewingSlot(ctx, slotID)
+	if err != nil {
+		return ViewingSlot{}, err
+	}
+	return ViewingSlot(slot), nil
+}
+
+// Returns the viewing slots of a property that have not ended yet, earliest first
+func (s *service) GetUpcomingViewingSlots(propertyID string) ([]ViewingSlot, error) {
+	ctx := context.Background()
+
+	slotsDB, err := s.db_queries.GetPropertyViewingSlots(ctx, propertyID)
+	if err != nil {
+		return []ViewingSlot{}, err
+	}
+
+	slots := []ViewingSlot{}
+	for _, slot := range slotsDB {
+		slots = append(slots, ViewingSlot(slot))
+	}
+	return slots, nil
+}
+
+// Returns the upcoming viewing slots the user booked, and the booked slots of properties they list
+func (s *service) GetUserUpcomingViewingSlots(userID string) ([]ViewingSlot, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return []ViewingSlot{}, err
+	}
+
+	slotsDB, err := s.db_queries.GetUserUpcomingViewingSlots(ctx, encryptedUserID)
+	if err != nil {
+		return []ViewingSlot{}, err
+	}
+
+	slots := []ViewingSlot{}
+	for _, slot := range slotsDB {
+		slots = append(slots, ViewingSlot(slot))
+	}
+	return slots, nil
+}
+
+// Bookings of the slot are deleted via cascade
+func (s *service) DeleteViewingSlot(slotID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeletePropertyViewingSlot(ctx, slotID)
+}
+
+// Books a viewing slot for the user. Returns false if the slot could not be booked
+// because it already started, is full or was already booked by the user.
+func (s *service) BookViewingSlot(slotID, userID string) (bool, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return false, err
+	}
+
+	// Bookings of the slot are serialized by locking it, so each sees the ones before it
+	var booked int64
+	err = s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.LockPropertyViewingSlot(ctx, slotID)
+		if err != nil {
+			return err
+		}
+		booked, err = q.CreatePropertyViewingBooking(ctx, sqlc.CreatePropertyViewingBookingParams{
+			SlotID: slotID,
+			UserID: encryptedUserID,
+		})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return booked > 0, nil
+}
+
+func (s *service) CancelViewingBooking(slotID, userID string) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.DeletePropertyViewingBooking(ctx, sqlc.DeletePropertyViewingBookingParams{
+		SlotID: slotID,
+		UserID: encryptedUserID,
+	})
+}
+
+// Returns the users that booked the slot in the order they booked it
+func (s *service) GetViewingSlotBookers(slotID string) ([]string, error) {
+	ctx := context.Background()
+
+	encryptedUserIDs, err := s.db_queries.GetPropertyViewingSlotBookers(ctx, slotID)
+	if err != nil {
+		return []string{}, err
+	}
+
+	userIDs := []string{}
+	for _, encryptedUserID := range encryptedUserIDs {
+		userID, err := utils.DecryptString(encryptedUserID, s.db_encrypt_key)
+		if err != nil {
+			return []string{}, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+// -------------- CALENDAR TOKENS ------------------
+
+// Replaces the user's calendar feed token, only its hash is stored
+func (s *service) SetUserCalendarToken(userID, token string) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.UpsertUserCalendarToken(ctx, sqlc.UpsertUserCalendarTokenParams{
+		UserID:    encryptedUserID,
+		TokenHash: utils.ContentHash([]byte(token)),
+	})
+}
+
+func (s *service) GetUserIDByCalendarToken(token string) (string, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := s.db_queries.GetUserIDByCalendarToken(ctx, utils.ContentHash([]byte(token)))
+	if err != nil {
+		return "", err
+	}
+	return utils.DecryptString(encryptedUserID, s.db_encrypt_key)
+}
+
+// -------------- PROPERTY APPLICATIONS ------------------
+// Applicants, messages and documents are personal and encrypted in the database
+
+func (s *service) CreateRentalApplication(application RentalApplication, documents []OrderedFileInternal) error {
+	ctx := context.Background()
+
+	encryptedApplicantUserID, err := utils.EncryptString(application.ApplicantUserID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+	encryptedMessage, err := utils.EncryptString(application.Message, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+	moveInDate, err := utils.CreateSQLNullDate(application.MoveInDate)
+	if err != nil {
+		return err
+	}
+
+	// Insert the application with its co-applicants and documents at once, so that a failed
+	// document does not leave an application without it behind
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.CreatePropertyApplication(ctx, sqlc.CreatePropertyApplicationParams{
+			ApplicationID:   application.ApplicationID,
+			PropertyID:      application.PropertyID,
+			ApplicantUserID: encryptedApplicantUserID,
+			Message:         encryptedMessage,
+			MoveInDate:      moveInDate,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range application.CoApplicantUserIDs {
+			encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+			if err != nil {
+				return err
+			}
+			err = q.CreatePropertyApplicationCoApplicant(ctx, sqlc.CreatePropertyApplicationCoApplicantParams{
+				ApplicationID: application.ApplicationID,
+				UserID:        encryptedUserID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, document := range documents {
+			encryptedFileName, err := utils.EncryptString(document.File.Filename, s.db_encrypt_key)
+			if err != nil {
+				return fmt.Errorf("couldn't encrypt filename for application document %d", document.OrderNum+1)
+			}
+			encryptedData, err := utils.EncryptBytes(document.File.Data, s.db_encrypt_key)
+			if err != nil {
+				return fmt.Errorf("couldn't encrypt data for application document %d", document.OrderNum+1)
+			}
+			contentHash, err := createFileBlob(ctx, q, encryptedData)
+			if err != nil {
+				return err
+			}
+			err = q.CreatePropertyApplicationDocument(ctx, sqlc.CreatePropertyApplicationDocumentParams{
+				ApplicationID: application.ApplicationID,
+				OrderNum:      document.OrderNum,
+				FileName:      encryptedFileName,
+				MimeType:      document.File.Mimetype,
+				Size:          document.File.Size,
+				ContentHash:   contentHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// decryptRentalApplication decrypts an application row and looks up its co-applicants
+func (s *service) decryptRentalApplication(ctx context.Context, application sqlc.PropertiesApplication) (RentalApplication, error) {
+	applicantUserID, err := utils.DecryptString(application.ApplicantUserID, s.db_encrypt_key)
+	if err != nil {
+		return RentalApplication{}, err
+	}
+	message, err := utils.DecryptString(application.Message, s.db_encrypt_key)
+	if err != nil {
+		return RentalApplication{}, err
+	}
+
+	encryptedCoApplicantUserIDs, err := s.db_queries.GetPropertyApplicationCoApplicants(ctx, application.ApplicationID)
+	if err != nil {
+		return RentalApplication{}, err
+	}
+	coApplicantUserIDs := []string{}
+	for _, encryptedUserID := range encryptedCoApplicantUserIDs {
+		userID, err := utils.DecryptString(encryptedUserID, s.db_encrypt_key)
+		if err != nil {
+			return RentalApplication{}, err
+		}
+		coApplicantUserIDs = append(coApplicantUserIDs, userID)
+	}
+
+	return RentalApplication{
+		ApplicationID:      application.ApplicationID,
+		PropertyID:         application.PropertyID,
+		ApplicantUserID:    applicantUserID,
+		CoApplicantUserIDs: coApplicantUserIDs,
+		Message:            message,
+		MoveInDate:         utils.FormatSQLNullDate(application.MoveInDate),
+		Status:             application.Status,
+		DecisionNote:       application.DecisionNote,
+		StatusUpdatedAt:    application.StatusUpdatedAt,
+		CreatedAt:          application.CreatedAt,
+	}, nil
+}
+
+func (s *service) GetRentalApplication(applicationID string) (RentalApplication, error) {
+	ctx := context.Background()
+
+	application, err := s.db_queries.GetPropertyApplication(ctx, applicationID)
+	if err != nil {
+		return RentalApplication{}, err
+	}
+	return s.decryptRentalApplication(ctx, application)
+}
+
+func (s *service) GetRentalApplicationDocuments(applicationID string) ([]OrderedFileInternal, error) {
+	ctx := context.Background()
+
+	documentsDB, err := s.db_queries.GetPropertyApplicationDocuments(ctx, applicationID)
+	if err != nil {
+		return []OrderedFileInternal{}, err
+	}
+
+	documents := []OrderedFileInternal{}
+	for i, document := range documentsDB {
+		fileName, err := utils.DecryptString(document.FileName, s.db_encrypt_key)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decrypt filename for application document %d", i+1)
+		}
+		data, err := utils.DecryptBytes(document.Data, s.db_encrypt_key)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decrypt data for application document %d", i+1)
+		}
+		documents = append(documents, OrderedFileInternal{
+			OrderNum: document.OrderNum,
+			File: FileInternal{
+				Filename: fileName,
+				Mimetype: document.MimeType,
+				Size:     document.Size,
+				Data:     data,
+			},
+		})
+	}
+	return documents, nil
+}
+
+// Returns the applications to a property, oldest first
+func (s *service) GetPropertyRentalApplications(propertyID string) ([]RentalApplication, error) {
+	ctx := context.Background()
+
+	applicationsDB, err := s.db_queries.GetPropertyApplications(ctx, propertyID)
+	if err != nil {
+		return []RentalApplication{}, err
+	}
+
+	applications := []RentalApplication{}
+	for _, applicationDB := range applicationsDB {
+		application, err := s.decryptRentalApplication(ctx, applicationDB)
+		if err != nil {
+			return []RentalApplication{}, err
+		}
+		applications = append(applications, application)
+	}
+	return applications, nil
+}
+
+// Returns the applications the user submitted or is a co-applicant of, most recent first
+func (s *service) GetUserRentalApplications(userID string) ([]RentalApplication, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return []RentalApplication{}, err
+	}
+
+	applicationsDB, err := s.db_queries.GetUserPropertyApplications(ctx, encryptedUserID)
+	if err != nil {
+		return []RentalApplication{}, err
+	}
+
+	applications := []RentalApplication{}
+	for _, applicationDB := range applicationsDB {
+		application, err := s.decryptRentalApplication(ctx, applicationDB)
+		if err != nil {
+			return []RentalApplication{}, err
+		}
+		applications = append(applications, application)
+	}
+	return applications, nil
+}
+
+// Moves an application from its current status to a new one. Returns false if the application
+// is no longer in the given current status, e.g. because it was changed concurrently.
+func (s *service) UpdateRentalApplicationStatus(applicationID, currentStatus, newStatus, decisionNote string) (bool, error) {
+	ctx := context.Background()
+
+	updated, err := s.db_queries.UpdatePropertyApplicationStatus(ctx, sqlc.UpdatePropertyApplicationStatusParams{
+		ApplicationID: applicationID,
+		Status:        newStatus,
+		DecisionNote:  decisionNote,
+		Status_2:      currentStatus,
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// Co-applicants and documents are deleted via cascade, their blobs are released once unreferenced
+func (s *service) DeleteRentalApplication(applicationID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeletePropertyApplication(ctx, applicationID)
+}
+
+// -------------- COMMUNITY MEMBERSHIP REQUESTS ------------------
+
+func (s *service) decryptCommunityMembershipRequest(request sqlc.CommunitiesMembershipRequest) (CommunityMembershipRequest, error) {
+	userID, err := utils.DecryptString(request.UserID, s.db_encrypt_key)
+	if err != nil {
+		return CommunityMembershipRequest{}, err
+	}
+	var invitedByUserID string
+	if request.InvitedByUserID.Valid {
+		invitedByUserID, err = utils.DecryptString(request.InvitedByUserID.String, s.db_encrypt_key)
+		if err != nil {
+			return CommunityMembershipRequest{}, err
+		}
+	}
+
+	membershipRequest := CommunityMembershipRequest{
+		RequestID:       request.RequestID,
+		CommunityID:     request.CommunityID,
+		UserID:          userID,
+		Kind:            request.Kind,
+		InvitedByUserID: invitedByUserID,
+		CreatedAt:       request.CreatedAt,
+	}
+	if request.ExpiresAt.Valid {
+		membershipRequest.ExpiresAt = &request.ExpiresAt.Time
+	}
+	return membershipRequest, nil
+}
+
+func (s *service) CreateCommunityMembershipRequest(request CommunityMembershipRequest) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(request.UserID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+	encryptedInvitedByUserID, err := s.encryptOptionalUserID(request.InvitedByUserID)
+	if err != nil {
+		return err
+	}
+	var expiresAt sql.NullTime
+	if request.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *request.ExpiresAt, Valid: true}
+	}
+
+	return s.db_queries.CreateCommunityMembershipRequest(ctx, sqlc.CreateCommunityMembershipRequestParams{
+		RequestID:       request.RequestID,
+		CommunityID:     request.CommunityID,
+		UserID:          encryptedUserID,
+		Kind:            request.Kind,
+		InvitedByUserID: encryptedInvitedByUserID,
+		ExpiresAt:       expiresAt,
+	})
+}
+
+// Returns a pending membership request, expired invites are not found
+func (s *service) GetCommunityMembershipRequest(requestID string) (CommunityMembershipRequest, error) {
+	ctx := context.Background()
+
+	request, err := s.db_queries.GetCommunityMembershipRequest(ctx, sqlc.GetCommunityMembershipRequestParams{
+		RequestID: requestID,
+		ExpiresAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return CommunityMembershipRequest{}, err
+	}
+	return s.decryptCommunityMembershipRequest(request)
+}
+
+// Returns the pending membership request or invite of a user to a community,
+// sql.ErrNoRows if there is none
+func (s *service) GetCommunityMembershipRequestOfUser(communityID, userID string) (CommunityMembershipRequest, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return CommunityMembershipRequest{}, err
+	}
+
+	request, err := s.db_queries.GetCommunityMembershipRequestOfUser(ctx, sqlc.GetCommunityMembershipRequestOfUserParams{
+		CommunityID: communityID,
+		UserID:      encryptedUserID,
+		ExpiresAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return CommunityMembershipRequest{}, err
+	}
+	return s.decryptCommunityMembershipRequest(request)
+}
+
+// Returns the pending membership requests and invites of a community, oldest first
+func (s *service) GetCommunityMembershipRequests(communityID string) ([]CommunityMembershipRequest, error) {
+	ctx := context.Background()
+
+	requestsDB, err := s.db_queries.GetCommunityMembershipRequests(ctx, sqlc.GetCommunityMembershipRequestsParams{
+		CommunityID: communityID,
+		ExpiresAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return []CommunityMembershipRequest{}, err
+	}
+
+	requests := []CommunityMembershipRequest{}
+	for _, requestDB := range requestsDB {
+		request, err := s.decryptCommunityMembershipRequest(requestDB)
+		if err != nil {
+			return []CommunityMembershipRequest{}, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// Returns the pending membership requests and invites of the user, as well as those of the
+// communities the user is the admin of, most recent first
+func (s *service) GetUserCommunityMembershipRequests(userID string) ([]CommunityMembershipRequest, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return []CommunityMembershipRequest{}, err
+	}
+
+	requestsDB, err := s.db_queries.GetUserCommunityMembershipRequests(ctx, sqlc.GetUserCommunityMembershipRequestsParams{
+		UserID:    encryptedUserID,
+		ExpiresAt: sql.NullTime{Time: time.Now(), Valid: true},
+		Column3:   communityRolesWithPermission(config.COMMUNITY_PERMISSION_MANAGE_MEMBERS),
+	})
+	if err != nil {
+		return []CommunityMembershipRequest{}, err
+	}
+
+	requests := []CommunityMembershipRequest{}
+	for _, requestDB := range requestsDB {
+		request, err := s.decryptCommunityMembershipRequest(requestDB)
+		if err != nil {
+			return []CommunityMembershipRequest{}, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// communityRolesWithPermission returns the community roles that hold the given permission
+func communityRolesWithPermission(permission string) []string {
+	roles := []string{}
+	for role, permissions := range config.COMMUNITY_ROLE_PERMISSIONS {
+		if _, exists := permissions[permission]; exists {
+			roles = append(roles, role)
+		}
+	}
+	slices.Sort(roles)
+	return roles
+}
+
+// Adds the user of a membership request to the community and removes the now decided request
+// Adds the user of a membership request to its community and deletes the request at once. Returns
+// ErrAlreadyCommunityMember if the user was added already, e.g. by a concurrent accept.
+func (s *service) AcceptCommunityMembershipRequest(request CommunityMembershipRequest) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(request.UserID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	err = s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.DeleteCommunityMembershipRequest(ctx, request.RequestID)
+		if err != nil {
+			return err
+		}
+		return q.CreateCommunityUser(ctx, sqlc.CreateCommunityUserParams{
+			CommunityID: request.CommunityID,
+			UserID:      encryptedUserID,
+			Role:        config.COMMUNITY_ROLE_MEMBER,
+		})
+	})
+	if isUniqueViolation(err) {
+		return ErrAlreadyCommunityMember
+	}
+	return err
+}
+
+func (s *service) DeleteCommunityMembershipRequest(requestID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityMembershipRequest(ctx, requestID)
+}
+
+func (s *service) DeleteExpiredCommunityMembershipRequests(now time.Time) (int64, error) {
+	ctx := context.Background()
+	return s.db_queries.DeleteExpiredCommunityMembershipRequests(ctx, sql.NullTime{Time: now, Valid: true})
+}
+
+// -------------- COMMUNITY PROPERTY LINK REQUESTS ------------------
+
+func (s *service) decryptCommunityPropertyLinkRequest(request sqlc.CommunitiesPropertyLinkRequest) (CommunityPropertyLinkRequest, error) {
+	var requestedByUserID string
+	var err error
+	if request.RequestedByUserID.Valid {
+		requestedByUserID, err = utils.DecryptString(request.RequestedByUserID.String, s.db_encrypt_key)
+		if err != nil {
+			return CommunityPropertyLinkRequest{}, err
+		}
+	}
+
+	return CommunityPropertyLinkRequest{
+		RequestID:         request.RequestID,
+		CommunityID:       request.CommunityID,
+		PropertyID:        request.PropertyID,
+		RequestedByUserID: requestedByUserID,
+		CreatedAt:         request.CreatedAt,
+	}, nil
+}
+
+func (s *service) decryptCommunityPropertyLinkRequests(requestsDB []sqlc.CommunitiesPropertyLinkRequest) ([]CommunityPropertyLinkRequest, error) {
+	requests := []CommunityPropertyLinkRequest{}
+	for _, requestDB := range requestsDB {
+		request, err := s.decryptCommunityPropertyLinkRequest(requestDB)
+		if err != nil {
+			return []CommunityPropertyLinkRequest{}, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+func (s *service) CreateCommunityPropertyLinkRequest(request CommunityPropertyLinkRequest) error {
+	ctx := context.Background()
+
+	encryptedRequestedByUserID, err := s.encryptOptionalUserID(request.RequestedByUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.CreateCommunityPropertyLinkRequest(ctx, sqlc.CreateCommunityPropertyLinkRequestParams{
+		RequestID:         request.RequestID,
+		CommunityID:       request.CommunityID,
+		PropertyID:        request.PropertyID,
+		RequestedByUserID: encryptedRequestedByUserID,
+	})
+}
+
+func (s *service) GetCommunityPropertyLinkRequest(requestID string) (CommunityPropertyLinkRequest, error) {
+	ctx := context.Background()
+
+	request, err := s.db_queries.GetCommunityPropertyLinkRequest(ctx, requestID)
+	if err != nil {
+		return CommunityPropertyLinkRequest{}, err
+	}
+	return s.decryptCommunityPropertyLinkRequest(request)
+}
+
+// Returns the pending request of a community to link a property, sql.ErrNoRows if there is none
+func (s *service) GetCommunityPropertyLinkRequestOfProperty(communityID, propertyID string) (CommunityPropertyLinkRequest, error) {
+	ctx := context.Background()
+
+	request, err := s.db_queries.GetCommunityPropertyLinkRequestOfProperty(ctx, sqlc.GetCommunityPropertyLinkRequestOfPropertyParams{
+		CommunityID: communityID,
+		PropertyID:  propertyID,
+	})
+	if err != nil {
+		return CommunityPropertyLinkRequest{}, err
+	}
+	return s.decryptCommunityPropertyLinkRequest(request)
+}
+
+// Returns the pending requests of a community to link properties, oldest first
+func (s *service) GetCommunityPropertyLinkRequests(communityID string) ([]CommunityPropertyLinkRequest, error) {
+	ctx := context.Background()
+
+	requests, err := s.db_queries.GetCommunityPropertyLinkRequests(ctx, communityID)
+	if err != nil {
+		return []CommunityPropertyLinkRequest{}, err
+	}
+	return s.decryptCommunityPropertyLinkRequests(requests)
+}
+
+// Returns the pending requests to link the properties listed by the user, most recent first
+func (s *service) GetListerCommunityPropertyLinkRequests(listerUserID string) ([]CommunityPropertyLinkRequest, error) {
+	ctx := context.Background()
+
+	encryptedListerUserID, err := utils.EncryptString(listerUserID, s.db_encrypt_key)
+	if err != nil {
+		return []CommunityPropertyLinkRequest{}, err
+	}
+
+	requests, err := s.db_queries.GetListerCommunityPropertyLinkRequests(ctx, encryptedListerUserID)
+	if err != nil {
+		return []CommunityPropertyLinkRequest{}, err
+	}
+	return s.decryptCommunityPropertyLinkRequests(requests)
+}
+
+// Links the property of a request to the community and removes the now decided request
+func (s *service) AcceptCommunityPropertyLinkRequest(request CommunityPropertyLinkRequest) error {
+	ctx := context.Background()
+
+	err := s.CreateCommunityProperty(request.CommunityID, request.PropertyID)
+	if err != nil {
+		return err
+	}
+	return s.db_queries.DeleteCommunityPropertyLinkRequest(ctx, request.RequestID)
+}
+
+func (s *service) DeleteCommunityPropertyLinkRequest(requestID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityPropertyLinkRequest(ctx, requestID)
+}
+
+// -------------- COMMUNITY POSTS ------------------
+
+func (s *service) decryptCommunityPost(post sqlc.CommunitiesPost) (CommunityPost, error) {
+	userID, err := utils.DecryptString(post.UserID, s.db_encrypt_key)
+	if err != nil {
+		return CommunityPost{}, err
+	}
+
+	return CommunityPost{
+		PostID:         post.PostID,
+		CommunityID:    post.CommunityID,
+		ThreadID:       post.ThreadID.String,
+		UserID:         userID,
+		Title:          post.Title,
+		Body:           post.Body,
+		Pinned:         post.Pinned,
+		LastActivityAt: post.LastActivityAt,
+		CreatedAt:      post.CreatedAt,
+	}, nil
+}
+
+func (s *service) decryptCommunityPosts(postsDB []sqlc.CommunitiesPost) ([]CommunityPost, error) {
+	posts := []CommunityPost{}
+	for _, postDB := range postsDB {
+		post, err := s.decryptCommunityPost(postDB)
+		if err != nil {
+			return []CommunityPost{}, err
+		}
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
+
+// Creates a thread or a reply, a reply bumps the latest activity of its thread
+func (s *service) CreateCommunityPost(post CommunityPost) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(post.UserID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	err = s.db_queries.CreateCommunityPost(ctx, sqlc.CreateCommunityPostParams{
+		PostID:      post.PostID,
+		CommunityID: post.CommunityID,
+		ThreadID:    sql.NullString{String: post.ThreadID, Valid: post.ThreadID != ""},
+		UserID:      encryptedUserID,
+		Title:       post.Title,
+		Body:        post.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	if post.ThreadID != "" {
+		return s.db_queries.UpdateCommunityThreadActivity(ctx, post.ThreadID)
+	}
+	return nil
+}
+
+func (s *service) GetCommunityPost(postID string) (CommunityPost, error) {
+	ctx := context.Background()
+
+	post, err := s.db_queries.GetCommunityPost(ctx, postID)
+	if err != nil {
+		return CommunityPost{}, err
+	}
+	return s.decryptCommunityPost(post)
+}
+
+// Returns a page of the threads of a community, pinned first and then most recently active first
+func (s *service) GetCommunityThreads(communityID string, limit, offset int32) ([]CommunityPost, error) {
+	ctx := context.Background()
+
+	threads, err := s.db_queries.GetCommunityThreads(ctx, sqlc.GetCommunityThreadsParams{
+		CommunityID: communityID,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return []CommunityPost{}, err
+	}
+	return s.decryptCommunityPosts(threads)
+}
+
+// Returns a page of the replies to a thread, oldest first
+func (s *service) GetCommunityThreadReplies(threadID string, limit, offset int32) ([]CommunityPost, error) {
+	ctx := context.Background()
+
+	replies, err := s.db_queries.GetCommunityThreadReplies(ctx, sqlc.GetCommunityThreadRepliesParams{
+		ThreadID: sql.NullString{String: threadID, Valid: true},
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return []CommunityPost{}, err
+	}
+	return s.decryptCommunityPosts(replies)
+}
+
+func (s *service) UpdateCommunityPostPinned(postID string, pinned bool) error {
+	ctx := context.Background()
+	return s.db_queries.UpdateCommunityPostPinned(ctx, sqlc.UpdateCommunityPostPinnedParams{
+		PostID: postID,
+		Pinned: pinned,
+	})
+}
+
+// Deletes a post, deleting a thread deletes its replies
+func (s *service) DeleteCommunityPost(postID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityPost(ctx, postID)
+}
+
+// -------------- COMMUNITY EVENTS ------------------
+
+func (s *service) decryptCommunityEvent(event sqlc.GetCommunityEventRow) (CommunityEvent, error) {
+	var createdByUserID string
+	var err error
+	if event.CreatedByUserID.Valid {
+		createdByUserID, err = utils.DecryptString(event.CreatedByUserID.String, s.db_encrypt_key)
+		if err != nil {
+			return CommunityEvent{}, err
+		}
+	}
+
+	return CommunityEvent{
+		EventID:         event.EventID,
+		CommunityID:     event.CommunityID,
+		PropertyID:      event.PropertyID.String,
+		CreatedByUserID: createdByUserID,
+		Title:           event.Title,
+		Description:     event.Description,
+		Location:        event.Location,
+		StartTime:       event.StartTime,
+		EndTime:         event.EndTime,
+		Capacity:        event.Capacity.Int16,
+		YesCount:        event.YesCount,
+		MaybeCount:      event.MaybeCount,
+	}, nil
+}
+
+func (s *service) CreateCommunityEvent(event CommunityEvent) error {
+	ctx := context.Background()
+
+	encryptedCreatedByUserID, err := s.encryptOptionalUserID(event.CreatedByUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.CreateCommunityEvent(ctx, sqlc.CreateCommunityEventParams{
+		EventID:         event.EventID,
+		CommunityID:     event.CommunityID,
+		PropertyID:      sql.NullString{String: event.PropertyID, Valid: event.PropertyID != ""},
+		CreatedByUserID: encryptedCreatedByUserID,
+		Title:           event.Title,
+		Description:     event.Description,
+		Location:        event.Location,
+		StartTime:       event.StartTime,
+		EndTime:         event.EndTime,
+		Capacity:        sql.NullInt16{Int16: event.Capacity, Valid: event.Capacity > 0},
+	})
+}
+
+func (s *service) GetCommunityEvent(eventID string) (CommunityEvent, error) {
+	ctx := context.Background()
+
+	event, err := s.db_queries.GetCommunityEvent(ctx, eventID)
+	if err != nil {
+		return CommunityEvent{}, err
+	}
+	return s.decryptCommunityEvent(event)
+}
+
+// Returns the events of a community that end after the given time, soonest first
+func (s *service) GetCommunityEvents(communityID string, endingAfter time.Time) ([]CommunityEvent, error) {
+	ctx := context.Background()
+
+	eventsDB, err := s.db_queries.GetCommunityEvents(ctx, sqlc.GetCommunityEventsParams{
+		CommunityID: communityID,
+		EndTime:     endingAfter,
+	})
+	if err != nil {
+		return []CommunityEvent{}, err
+	}
+
+	events := []CommunityEvent{}
+	for _, eventDB := range eventsDB {
+		event, err := s.decryptCommunityEvent(sqlc.GetCommunityEventRow(eventDB))
+		if err != nil {
+			return []CommunityEvent{}, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (s *service) GetCommunityEventRSVPs(eventID string) ([]CommunityEventRSVP, error) {
+	ctx := context.Background()
+
+	rsvpsDB, err := s.db_queries.GetCommunityEventRSVPs(ctx, eventID)
+	if err != nil {
+		return []CommunityEventRSVP{}, err
+	}
+
+	rsvps := []CommunityEventRSVP{}
+	for _, rsvpDB := range rsvpsDB {
+		userID, err := utils.DecryptString(rsvpDB.UserID, s.db_encrypt_key)
+		if err != nil {
+			return []CommunityEventRSVP{}, err
+		}
+		rsvps = append(rsvps, CommunityEventRSVP{
+			EventID:   rsvpDB.EventID,
+			UserID:    userID,
+			Response:  rsvpDB.Response,
+			UpdatedAt: rsvpDB.UpdatedAt,
+		})
+	}
+	return rsvps, nil
+}
+
+// Sets the RSVP of a user to an event. Returns false without an error if the event already
+// started or, for a yes, is full.
+func (s *service) SetCommunityEventRSVP(eventID, userID, response string) (bool, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return false, err
+	}
+
+	// RSVPs to the event are serialized by locking it, so each sees the ones before it
+	var rows int64
+	err = s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.LockCommunityEvent(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		rows, err = q.UpsertCommunityEventRSVP(ctx, sqlc.UpsertCommunityEventRSVPParams{
+			EventID:  eventID,
+			UserID:   encryptedUserID,
+			Response: response,
+		})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *service) DeleteCommunityEventRSVP(eventID, userID string) error {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.DeleteCommunityEventRSVP(ctx, sqlc.DeleteCommunityEventRSVPParams{
+		EventID: eventID,
+		UserID:  encryptedUserID,
+	})
+}
+
+func (s *service) DeleteCommunityEvent(eventID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityEvent(ctx, eventID)
+}
+
+// -------------- COMMUNITY EXPENSES ------------------
+
+func (s *service) decryptCommunityExpense(expense sqlc.CommunitiesExpense) (CommunityExpense, error) {
+	payerUserID, err := s.decryptOptionalUserID(expense.PayerUserID)
+	if err != nil {
+		return CommunityExpense{}, err
+	}
+	createdByUserID, err := s.decryptOptionalUserID(expense.CreatedByUserID)
+	if err != nil {
+		return CommunityExpense{}, err
+	}
+
+	return CommunityExpense{
+		ExpenseID:       expense.ExpenseID,
+		CommunityID:     expense.CommunityID,
+		PayerUserID:     payerUserID,
+		CreatedByUserID: createdByUserID,
+		Description:     expense.Description,
+		Category:        expense.Category,
+		AmountCents:     int64(expense.AmountCents),
+		SplitType:       expense.SplitType,
+		SpentOn:         expense.SpentOn.Format("2006-01-02"),
+		CreatedAt:       expense.CreatedAt,
+		Splits:          []CommunityExpenseSplit{},
+	}, nil
+}
+
+// Creates an expense with its splits, the amounts of the splits are expected to be computed already
+func (s *service) CreateCommunityExpense(expense CommunityExpense) error {
+	ctx := context.Background()
+
+	spentOn, err := time.Parse("2006-01-02", expense.SpentOn)
+	if err != nil {
+		return err
+	}
+	encryptedPayerUserID, err := s.encryptOptionalUserID(expense.PayerUserID)
+	if err != nil {
+		return err
+	}
+	encryptedCreatedByUserID, err := s.encryptOptionalUserID(expense.CreatedByUserID)
+	if err != nil {
+		return err
+	}
+
+	// The expense and its splits are created together, a partial expense would unbalance the ledger
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.CreateCommunityExpense(ctx, sqlc.CreateCommunityExpenseParams{
+			ExpenseID:       expense.ExpenseID,
+			CommunityID:     expense.CommunityID,
+			PayerUserID:     encryptedPayerUserID,
+			CreatedByUserID: encryptedCreatedByUserID,
+			Description:     expense.Description,
+			Category:        expense.Category,
+			AmountCents:     int32(expense.AmountCents),
+			SplitType:       expense.SplitType,
+			SpentOn:         spentOn,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, split := range expense.Splits {
+			encryptedUserID, err := s.encryptOptionalUserID(split.UserID)
+			if err != nil {
+				return err
+			}
+			err = q.CreateCommunityExpenseSplit(ctx, sqlc.CreateCommunityExpenseSplitParams{
+				ExpenseID:   expense.ExpenseID,
+				UserID:      encryptedUserID,
+				Shares:      sql.NullInt32{Int32: split.Shares, Valid: split.Shares > 0},
+				AmountCents: int32(split.AmountCents),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Returns an expense without its splits
+func (s *service) GetCommunityExpense(expenseID string) (CommunityExpense, error) {
+	ctx := context.Background()
+
+	expense, err := s.db_queries.GetCommunityExpense(ctx, expenseID)
+	if err != nil {
+		return CommunityExpense{}, err
+	}
+	return s.decryptCommunityExpense(expense)
+}
+
+// Returns the expenses of a community with their splits, in the order they were spent
+func (s *service) GetCommunityExpenses(communityID string) ([]CommunityExpense, error) {
+	ctx := context.Background()
+
+	expensesDB, err := s.db_queries.GetCommunityExpenses(ctx, communityID)
+	if err != nil {
+		return []CommunityExpense{}, err
+	}
+	splitsDB, err := s.db_queries.GetCommunityExpenseSplits(ctx, communityID)
+	if err != nil {
+		return []CommunityExpense{}, err
+	}
+
+	splits := map[string][]CommunityExpenseSplit{}
+	for _, splitDB := range splitsDB {
+		userID, err := s.decryptOptionalUserID(splitDB.UserID)
+		if err != nil {
+			return []CommunityExpense{}, err
+		}
+		splits[splitDB.ExpenseID] = append(splits[splitDB.ExpenseID], CommunityExpenseSplit{
+			UserID:      userID,
+			Shares:      splitDB.Shares.Int32,
+			AmountCents: int64(splitDB.AmountCents),
+		})
+	}
+
+	expenses := []CommunityExpense{}
+	for _, expenseDB := range expensesDB {
+		expense, err := s.decryptCommunityExpense(expenseDB)
+		if err != nil {
+			return []CommunityExpense{}, err
+		}
+		if expenseSplits, ok := splits[expense.ExpenseID]; ok {
+			expense.Splits = expenseSplits
+		}
+		expenses = append(expenses, expense)
+	}
+	return expenses, nil
+}
+
+func (s *service) DeleteCommunityExpense(expenseID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityExpense(ctx, expenseID)
+}
+
+// -------------- COMMUNITY POLLS ------------------
+
+// decryptCommunityPoll decrypts a poll and adds its options
+func (s *service) decryptCommunityPoll(ctx context.Context, poll sqlc.GetCommunityPollRow) (CommunityPoll, error) {
+	var createdByUserID string
+	var err error
+	if poll.CreatedByUserID.Valid {
+		createdByUserID, err = utils.DecryptString(poll.CreatedByUserID.String, s.db_encrypt_key)
+		if err != nil {
+			return CommunityPoll{}, err
+		}
+	}
+
+	optionsDB, err := s.db_queries.GetCommunityPollOptions(ctx, poll.PollID)
+	if err != nil {
+		return CommunityPoll{}, err
+	}
+	options := []CommunityPollOption{}
+	for _, optionDB := range optionsDB {
+		options = append(options, CommunityPollOption{
+			OptionID: optionDB.OptionID,
+			Label:    optionDB.Label,
+		})
+	}
+
+	return CommunityPoll{
+		PollID:            poll.PollID,
+		CommunityID:       poll.CommunityID,
+		CreatedByUserID:   createdByUserID,
+		Question:          poll.Question,
+		MultipleChoice:    poll.MultipleChoice,
+		Anonymous:         poll.Anonymous,
+		ResultsVisibility: poll.ResultsVisibility,
+		OpensAt:           poll.OpensAt,
+		ClosesAt:          poll.ClosesAt,
+		CreatedAt:         poll.CreatedAt,
+		VoterCount:        poll.VoterCount,
+		Options:           options,
+	}, nil
+}
+
+// Creates a poll with its options, in the order given
+func (s *service) CreateCommunityPoll(poll CommunityPoll) error {
+	ctx := context.Background()
+
+	encryptedCreatedByUserID, err := s.encryptOptionalUserID(poll.CreatedByUserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.db_queries.CreateCommunityPoll(ctx, sqlc.CreateCommunityPollParams{
+		PollID:            poll.PollID,
+		CommunityID:       poll.CommunityID,
+		CreatedByUserID:   encryptedCreatedByUserID,
+		Question:          poll.Question,
+		MultipleChoice:    poll.MultipleChoice,
+		Anonymous:         poll.Anonymous,
+		ResultsVisibility: poll.ResultsVisibility,
+		OpensAt:           poll.OpensAt,
+		ClosesAt:          poll.ClosesAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, option := range poll.Options {
+		err = s.db_queries.CreateCommunityPollOption(ctx, sqlc.CreateCommunityPollOptionParams{
+			OptionID: option.OptionID,
+			PollID:   poll.PollID,
+			Position: int16(i),
+			Label:    option.Label,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetCommunityPoll(pollID string) (CommunityPoll, error) {
+	ctx := context.Background()
+
+	poll, err := s.db_queries.GetCommunityPoll(ctx, pollID)
+	if err != nil {
+		return CommunityPoll{}, err
+	}
+	return s.decryptCommunityPoll(ctx, poll)
+}
+
+// Returns the polls of a community, newest first
+func (s *service) GetCommunityPolls(communityID string) ([]CommunityPoll, error) {
+	ctx := context.Background()
+
+	pollsDB, err := s.db_queries.GetCommunityPolls(ctx, communityID)
+	if err != nil {
+		return []CommunityPoll{}, err
+	}
+
+	polls := []CommunityPoll{}
+	for _, pollDB := range pollsDB {
+		poll, err := s.decryptCommunityPoll(ctx, sqlc.GetCommunityPollRow(pollDB))
+		if err != nil {
+			return []CommunityPoll{}, err
+		}
+		polls = append(polls, poll)
+	}
+	return polls, nil
+}
+
+// Returns the number of votes for each option of a poll with, unless the poll is anonymous,
+// the voters of each option
+func (s *service) GetCommunityPollResults(poll CommunityPoll) ([]CommunityPollResult, error) {
+	ctx := context.Background()
+
+	resultsDB, err := s.db_queries.GetCommunityPollResults(ctx, poll.PollID)
+	if err != nil {
+		return []CommunityPollResult{}, err
+	}
+
+	voters := map[string][]string{}
+	if !poll.Anonymous {
+		votersDB, err := s.db_queries.GetCommunityPollVoters(ctx, poll.PollID)
+		if err != nil {
+			return []CommunityPollResult{}, err
+		}
+		for _, voterDB := range votersDB {
+			userID, err := utils.DecryptString(voterDB.UserID.String, s.db_encrypt_key)
+			if err != nil {
+				return []CommunityPollResult{}, err
+			}
+			voters[voterDB.OptionID] = append(voters[voterDB.OptionID], userID)
+		}
+	}
+
+	results := []CommunityPollResult{}
+	for _, resultDB := range resultsDB {
+		optionVoters, ok := voters[resultDB.OptionID]
+		if !ok {
+			optionVoters = []string{}
+		}
+		results = append(results, CommunityPollResult{
+			OptionID: resultDB.OptionID,
+			Votes:    resultDB.Votes,
+			Voters:   optionVoters,
+		})
+	}
+	return results, nil
+}
+
+func (s *service) CheckIsCommunityPollVoter(pollID, userID string) (bool, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := s.db_queries.CheckIsCommunityPollVoter(ctx, sqlc.CheckIsCommunityPollVoterParams{
+		PollID: pollID,
+		UserID: encryptedUserID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Casts the ballot of a user for the given options of a poll. Returns false without an error if
+// the poll is not open. Casting a second ballot on the same poll is an error.
+func (s *service) CastCommunityPollBallot(pollID, userID string, optionIDs []string) (bool, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := s.db_queries.CastCommunityPollBallot(ctx, sqlc.CastCommunityPollBallotParams{
+		PollID:  pollID,
+		UserID:  encryptedUserID,
+		Column3: optionIDs,
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (s *service) DeleteCommunityPoll(pollID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityPoll(ctx, pollID)
+}
+
+// -------------- COMMUNITY DOCUMENTS ------------------
+
+func (s *service) decryptCommunityDocument(document sqlc.GetCommunityDocumentRow) (CommunityDocument, error) {
+	createdByUserID, err := s.decryptOptionalUserID(document.CreatedByUserID)
+	if err != nil {
+		return CommunityDocument{}, err
+	}
+
+	return CommunityDocument{
+		DocumentID:      document.DocumentID,
+		CommunityID:     document.CommunityID,
+		FolderID:        document.FolderID.String,
+		Title:           document.Title,
+		Visibility:      document.Visibility,
+		CreatedByUserID: createdByUserID,
+		CreatedAt:       document.CreatedAt,
+		Version:         document.Version,
+		FileName:        document.FileName,
+		MimeType:        document.MimeType,
+		Size:            document.Size,
+		UpdatedAt:       document.UpdatedAt,
+	}, nil
+}
+
+func (s *service) decryptCommunityDocumentFolder(folder sqlc.CommunitiesDocumentsFolder) (CommunityDocumentFolder, error) {
+	createdByUserID, err := s.decryptOptionalUserID(folder.CreatedByUserID)
+	if err != nil {
+		return CommunityDocumentFolder{}, err
+	}
+
+	return CommunityDocumentFolder{
+		FolderID:        folder.FolderID,
+		CommunityID:     folder.CommunityID,
+		ParentFolderID:  folder.ParentFolderID.String,
+		Name:            folder.Name,
+		CreatedByUserID: createdByUserID,
+		CreatedAt:       folder.CreatedAt,
+	}, nil
+}
+
+func (s *service) CreateCommunityDocumentFolder(folder CommunityDocumentFolder) error {
+	ctx := context.Background()
+
+	encryptedCreatedByUserID, err := s.encryptOptionalUserID(folder.CreatedByUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.CreateCommunityDocumentFolder(ctx, sqlc.CreateCommunityDocumentFolderParams{
+		FolderID:        folder.FolderID,
+		CommunityID:     folder.CommunityID,
+		ParentFolderID:  utils.CreateSQLNullString(folder.ParentFolderID),
+		Name:            folder.Name,
+		CreatedByUserID: encryptedCreatedByUserID,
+	})
+}
+
+func (s *service) GetCommunityDocumentFolder(folderID string) (CommunityDocumentFolder, error) {
+	ctx := context.Background()
+
+	folder, err := s.db_queries.GetCommunityDocumentFolder(ctx, folderID)
+	if err != nil {
+		return CommunityDocumentFolder{}, err
+	}
+	return s.decryptCommunityDocumentFolder(folder)
+}
+
+// Returns all the folders of a community by name, the folder tree is built from their parents
+func (s *service) GetCommunityDocumentFolders(communityID string) ([]CommunityDocumentFolder, error) {
+	ctx := context.Background()
+
+	foldersDB, err := s.db_queries.GetCommunityDocumentFolders(ctx, communityID)
+	if err != nil {
+		return []CommunityDocumentFolder{}, err
+	}
+
+	folders := []CommunityDocumentFolder{}
+	for _, folderDB := range foldersDB {
+		folder, err := s.decryptCommunityDocumentFolder(folderDB)
+		if err != nil {
+			return []CommunityDocumentFolder{}, err
+		}
+		folders = append(folders, folder)
+	}
+	return folders, nil
+}
+
+// Deletes a folder with its subfolders and their documents
+func (s *service) DeleteCommunityDocumentFolder(folderID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityDocumentFolder(ctx, folderID)
+}
+
+// Creates a document with its first version
+func (s *service) CreateCommunityDocument(document CommunityDocument, version CommunityDocumentVersion, file FileInternal) error {
+	ctx := context.Background()
+
+	encryptedCreatedByUserID, err := s.encryptOptionalUserID(document.CreatedByUserID)
+	if err != nil {
+		return err
+	}
+	encryptedUploadedByUserID, err := s.encryptOptionalUserID(version.UploadedByUserID)
+	if err != nil {
+		return err
+	}
+
+	// Insert the document with its first version at once, so that a failed upload does not leave
+	// a document without any version behind
+	return s.withTx(ctx, func(q *sqlc.Queries) error {
+		err := q.CreateCommunityDocument(ctx, sqlc.CreateCommunityDocumentParams{
+			DocumentID:      document.DocumentID,
+			CommunityID:     document.CommunityID,
+			FolderID:        utils.CreateSQLNullString(document.FolderID),
+			Title:           document.Title,
+			Visibility:      document.Visibility,
+			CreatedByUserID: encryptedCreatedByUserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = createCommunityDocumentVersion(ctx, q, version, encryptedUploadedByUserID, file)
+		return err
+	})
+}
+
+// Stores the file of a new version of a document in the blob store and returns the number of
+// the version
+func (s *service) CreateCommunityDocumentVersion(version CommunityDocumentVersion, file FileInternal) (int32, error) {
+	ctx := context.Background()
+
+	encryptedUploadedByUserID, err := s.encryptOptionalUserID(version.UploadedByUserID)
+	if err != nil {
+		return 0, err
+	}
+	var versionNum int32
+	err = s.withTx(ctx, func(q *sqlc.Queries) error {
+		versionNum, err = createCommunityDocumentVersion(ctx, q, version, encryptedUploadedByUserID, file)
+		return err
+	})
+	return versionNum, err
+}
+
+// createCommunityDocumentVersion stores the file of a version with the given queries, holding its
+// blob with the version in the same transaction
+func createCommunityDocumentVersion(ctx context.Context, q *sqlc.Queries, version CommunityDocumentVersion, encryptedUploadedByUserID sql.NullString, file FileInternal) (int32, error) {
+	contentHash, err := createFileBlob(ctx, q, file.Data)
+	if err != nil {
+		return 0, err
+	}
+
+	return q.CreateCommunityDocumentVersion(ctx, sqlc.CreateCommunityDocumentVersionParams{
+		DocumentID:       version.DocumentID,
+		FileName:         file.Filename,
+		MimeType:         file.Mimetype,
+		Size:             file.Size,
+		ContentHash:      contentHash,
+		UploadedByUserID: encryptedUploadedByUserID,
+		Note:             version.Note,
+	})
+}
+
+func (s *service) GetCommunityDocument(documentID string) (CommunityDocument, error) {
+	ctx := context.Background()
+
+	document, err := s.db_queries.GetCommunityDocument(ctx, documentID)
+	if err != nil {
+		return CommunityDocument{}, err
+	}
+	return s.decryptCommunityDocument(document)
+}
+
+// Returns all the documents of a community by title, regardless of their visibility
+func (s *service) GetCommunityDocuments(communityID string) ([]CommunityDocument, error) {
+	ctx := context.Background()
+
+	documentsDB, err := s.db_queries.GetCommunityDocuments(ctx, communityID)
+	if err != nil {
+		return []CommunityDocument{}, err
+	}
+
+	documents := []CommunityDocument{}
+	for _, documentDB := range documentsDB {
+		document, err := s.decryptCommunityDocument(sqlc.GetCommunityDocumentRow(documentDB))
+		if err != nil {
+			return []CommunityDocument{}, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, nil
+}
+
+// Returns the version history of a document, latest first
+func (s *service) GetCommunityDocumentVersions(documentID string) ([]CommunityDocumentVersion, error) {
+	ctx := context.Background()
+
+	versionsDB, err := s.db_queries.GetCommunityDocumentVersions(ctx, documentID)
+	if err != nil {
+		return []CommunityDocumentVersion{}, err
+	}
+
+	versions := []CommunityDocumentVersion{}
+	for _, versionDB := range versionsDB {
+		uploadedByUserID, err := s.decryptOptionalUserID(versionDB.UploadedByUserID)
+		if err != nil {
+			return []CommunityDocumentVersion{}, err
+		}
+		versions = append(versions, CommunityDocumentVersion{
+			DocumentID:       versionDB.DocumentID,
+			Version:          versionDB.Version,
+			FileName:         versionDB.FileName,
+			MimeType:         versionDB.MimeType,
+			Size:             versionDB.Size,
+			UploadedByUserID: uploadedByUserID,
+			Note:             versionDB.Note,
+			CreatedAt:        versionDB.CreatedAt,
+		})
+	}
+	return versions, nil
+}
+
+func (s *service) GetCommunityDocumentFile(documentID string, version int32) (FileInternal, error) {
+	ctx := context.Background()
+
+	file, err := s.db_queries.GetCommunityDocumentFile(ctx, sqlc.GetCommunityDocumentFileParams{
+		DocumentID: documentID,
+		Version:    version,
+	})
+	if err != nil {
+		return FileInternal{}, err
+	}
+	return FileInternal{
+		Filename: file.FileName,
+		Mimetype: file.MimeType,
+		Size:     file.Size,
+		Data:     file.Data,
+	}, nil
+}
+
+// Updates the folder, title and visibility of a document
+func (s *service) UpdateCommunityDocument(document CommunityDocument) error {
+	ctx := context.Background()
+	return s.db_queries.UpdateCommunityDocument(ctx, sqlc.UpdateCommunityDocumentParams{
+		DocumentID: document.DocumentID,
+		FolderID:   utils.CreateSQLNullString(document.FolderID),
+		Title:      document.Title,
+		Visibility: document.Visibility,
+	})
+}
+
+// Deletes a document with all its versions
+func (s *service) DeleteCommunityDocument(documentID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityDocument(ctx, documentID)
+}
+
+// -------------- COMMUNITY CHORES ------------------
+
+func (s *service) decryptCommunityChore(chore sqlc.CommunitiesChore) (CommunityChore, error) {
+	createdByUserID, err := s.decryptOptionalUserID(chore.CreatedByUserID)
+	if err != nil {
+		return CommunityChore{}, err
+	}
+
+	return CommunityChore{
+		ChoreID:         chore.ChoreID,
+		CommunityID:     chore.CommunityID,
+		Title:           chore.Title,
+		Description:     chore.Description,
+		Frequency:       chore.Frequency,
+		Interval:        chore.IntervalCount,
+		StartsOn:        chore.StartsOn.Format("2006-01-02"),
+		CreatedByUserID: createdByUserID,
+		CreatedAt:       chore.CreatedAt,
+		Rotation:        []string{},
+	}, nil
+}
+
+// getCommunityChoreRotations returns the rotation of each chore of a community, leaving out the
+// users who are no longer members of the community
+func (s *service) getCommunityChoreRotations(ctx context.Context, communityID string) (map[string][]string, error) {
+	rotationsDB, err := s.db_queries.GetCommunityChoreRotations(ctx, communityID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotations := map[string][]string{}
+	for _, rotationDB := range rotationsDB {
+		userID, err := utils.DecryptString(rotationDB.UserID, s.db_encrypt_key)
+		if err != nil {
+			return nil, err
+		}
+		rotations[rotationDB.ChoreID] = append(rotations[rotationDB.ChoreID], userID)
+	}
+	return rotations, nil
+}
+
+// decryptCommunityChores decrypts chores and adds their rotations
+func (s *service) decryptCommunityChores(ctx context.Context, choresDB []sqlc.CommunitiesChore) ([]CommunityChore, error) {
+	rotations := map[string]map[string][]string{} // by community
+	chores := []CommunityChore{}
+	for _, choreDB := range choresDB {
+		chore, err := s.decryptCommunityChore(choreDB)
+		if err != nil {
+			return []CommunityChore{}, err
+		}
+		if _, ok := rotations[chore.CommunityID]; !ok {
+			rotations[chore.CommunityID], err = s.getCommunityChoreRotations(ctx, chore.CommunityID)
+			if err != nil {
+				return []CommunityChore{}, err
+			}
+		}
+		if rotation, ok := rotations[chore.CommunityID][chore.ChoreID]; ok {
+			chore.Rotation = rotation
+		}
+		chores = append(chores, chore)
+	}
+	return chores, nil
+}
+
+// Creates a chore with its rotation, members take turns in the order of the rotation
+func (s *service) CreateCommunityChore(chore CommunityChore) error {
+	ctx := context.Background()
+
+	startsOn, err := time.Parse("2006-01-02", chore.StartsOn)
+	if err != nil {
+		return err
+	}
+	encryptedCreatedByUserID, err := s.encryptOptionalUserID(chore.CreatedByUserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.db_queries.CreateCommunityChore(ctx, sqlc.CreateCommunityChoreParams{
+		ChoreID:         chore.ChoreID,
+		CommunityID:     chore.CommunityID,
+		Title:           chore.Title,
+		Description:     chore.Description,
+		Frequency:       chore.Frequency,
+		IntervalCount:   chore.Interval,
+		StartsOn:        startsOn,
+		CreatedByUserID: encryptedCreatedByUserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for position, userID := range chore.Rotation {
+		encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+		if err != nil {
+			return err
+		}
+		err = s.db_queries.CreateCommunityChoreRotationMember(ctx, sqlc.CreateCommunityChoreRotationMemberParams{
+			ChoreID:  chore.ChoreID,
+			UserID:   encryptedUserID,
+			Position: int32(position),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetCommunityChore(choreID string) (CommunityChore, error) {
+	ctx := context.Background()
+
+	choreDB, err := s.db_queries.GetCommunityChore(ctx, choreID)
+	if err != nil {
+		return CommunityChore{}, err
+	}
+	chores, err := s.decryptCommunityChores(ctx, []sqlc.CommunitiesChore{choreDB})
+	if err != nil {
+		return CommunityChore{}, err
+	}
+	return chores[0], nil
+}
+
+// Returns the chores of a community with their rotations, by title
+func (s *service) GetCommunityChores(communityID string) ([]CommunityChore, error) {
+	ctx := context.Background()
+
+	choresDB, err := s.db_queries.GetCommunityChores(ctx, communityID)
+	if err != nil {
+		return []CommunityChore{}, err
+	}
+	return s.decryptCommunityChores(ctx, choresDB)
+}
+
+// Returns the chores of all the communities with their rotations, to schedule their tasks
+func (s *service) GetAllCommunityChores() ([]CommunityChore, error) {
+	ctx := context.Background()
+
+	choresDB, err := s.db_queries.GetAllCommunityChores(ctx)
+	if err != nil {
+		return []CommunityChore{}, err
+	}
+	return s.decryptCommunityChores(ctx, choresDB)
+}
+
+func (s *service) DeleteCommunityChore(choreID string) error {
+	ctx := context.Background()
+	return s.db_queries.DeleteCommunityChore(ctx, choreID)
+}
+
+// Returns the index of the last occurrence of a chore that has a task, -1 if none was scheduled yet
+func (s *service) GetCommunityChoreLastOccurrence(choreID string) (int32, error) {
+	ctx := context.Background()
+	return s.db_queries.GetCommunityChoreLastOccurrence(ctx, choreID)
+}
+
+// Creates the task of an occurrence of a chore, doing nothing if the occurrence already has a task
+func (s *service) CreateCommunityChoreTask(task CommunityChoreTask) error {
+	ctx := context.Background()
+
+	dueOn, err := time.Parse("2006-01-02", task.DueOn)
+	if err != nil {
+		return err
+	}
+	encryptedAssigneeUserID, err := s.encryptOptionalUserID(task.AssigneeUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.CreateCommunityChoreTask(ctx, sqlc.CreateCommunityChoreTaskParams{
+		TaskID:         task.TaskID,
+		ChoreID:        task.ChoreID,
+		Occurrence:     task.Occurrence,
+		DueOn:          dueOn,
+		AssigneeUserID: encryptedAssigneeUserID,
+	})
+}
+
+func (s *service) decryptCommunityChoreTask(task sqlc.GetCommunityChoreTaskRow) (CommunityChoreTask, error) {
+	assigneeUserID, err := s.decryptOptionalUserID(task.AssigneeUserID)
+	if err != nil {
+		return CommunityChoreTask{}, err
+	}
+	completedByUserID, err := s.decryptOptionalUserID(task.CompletedByUserID)
+	if err != nil {
+		return CommunityChoreTask{}, err
+	}
+	var completedAt *time.Time
+	if task.CompletedAt.Valid {
+		completedAt = &task.CompletedAt.Time
+	}
+
+	return CommunityChoreTask{
+		TaskID:            task.TaskID,
+		ChoreID:           task.ChoreID,
+		CommunityID:       task.CommunityID,
+		ChoreTitle:        task.Title,
+		Occurrence:        task.Occurrence,
+		DueOn:             task.DueOn.Format("2006-01-02"),
+		AssigneeUserID:    assigneeUserID,
+		CompletedAt:       completedAt,
+		CompletedByUserID: completedByUserID,
+		SwapTaskID:        task.SwapTaskID.String,
+	}, nil
+}
+
+func (s *service) GetCommunityChoreTask(taskID string) (CommunityChoreTask, error) {
+	ctx := context.Background()
+
+	task, err := s.db_queries.GetCommunityChoreTask(ctx, taskID)
+	if err != nil {
+		return CommunityChoreTask{}, err
+	}
+	return s.decryptCommunityChoreTask(task)
+}
+
+// Returns the tasks of the chores of a community due since a date along with the ones still to do,
+// by due date
+func (s *service) GetCommunityChoreTasks(communityID string, since time.Time) ([]CommunityChoreTask, error) {
+	ctx := context.Background()
+
+	tasksDB, err := s.db_queries.GetCommunityChoreTasks(ctx, sqlc.GetCommunityChoreTasksParams{
+		CommunityID: communityID,
+		DueOn:       since,
+	})
+	if err != nil {
+		return []CommunityChoreTask{}, err
+	}
+
+	tasks := []CommunityChoreTask{}
+	for _, taskDB := range tasksDB {
+		task, err := s.decryptCommunityChoreTask(sqlc.GetCommunityChoreTaskRow(taskDB))
+		if err != nil {
+			return []CommunityChoreTask{}, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// Returns the tasks assigned to a user across their communities due since a date along with the
+// ones still to do, by due date
+func (s *service) GetUserChoreTasks(userID string, since time.Time) ([]CommunityChoreTask, error) {
+	ctx := context.Background()
+
+	encryptedUserID, err := utils.EncryptString(userID, s.db_encrypt_key)
+	if err != nil {
+		return []CommunityChoreTask{}, err
+	}
+
+	tasksDB, err := s.db_queries.GetUserChoreTasks(ctx, sqlc.GetUserChoreTasksParams{
+		AssigneeUserID: sql.NullString{String: encryptedUserID, Valid: true},
+		DueOn:          since,
+	})
+	if err != nil {
+		return []CommunityChoreTask{}, err
+	}
+
+	tasks := []CommunityChoreTask{}
+	for _, taskDB := range tasksDB {
+		task, err := s.decryptCommunityChoreTask(sqlc.GetCommunityChoreTaskRow(taskDB))
+		if err != nil {
+			return []CommunityChoreTask{}, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// Marks a task done by a user at the given time, or to do again if the user is empty
+func (s *service) UpdateCommunityChoreTaskCompletion(taskID, completedByUserID string, completedAt time.Time) error {
+	ctx := context.Background()
+
+	encryptedCompletedByUserID, err := s.encryptOptionalUserID(completedByUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.db_queries.UpdateCommunityChoreTaskCompletion(ctx, sqlc.UpdateCommunityChoreTaskCompletionParams{
+		TaskID:            taskID,
+		CompletedAt:       sql.NullTime{Time: completedAt, Valid: encryptedCompletedByUserID.Valid},
+		CompletedByUserID: encryptedCompletedByUserID,
+	})
+}
+
+// Swaps the assignees of two tasks, returns false without swapping if either task is done already
+func (s *service) SwapCommunityChoreTaskAssignees(taskID, otherTaskID string) (bool, error) {
+	ctx := context.Background()
+
+	rows, err := s.db_queries.SwapCommunityChoreTaskAssignees(ctx, sqlc.SwapCommunityChoreTaskAssigneesParams{
+		TaskID:   taskID,
+		TaskID_2: otherTaskID,
+	})
+	return rows == 2, err
+}
+
+// Proposes to swap a task with another task, returns false without proposing if the task is done
+// already
+func (s *service) UpdateCommunityChoreTaskSwap(taskID, swapTaskID string) (bool, error) {
+	ctx := context.Background()
+
+	rows, err := s.db_queries.UpdateCommunityChoreTaskSwap(ctx, sqlc.UpdateCommunityChoreTaskSwapParams{
+		TaskID:     taskID,
+		SwapTaskID: utils.CreateSQLNullString(swapTaskID),
+	})
+	return rows == 1, err
+}
+
 // -----------------------------------------------------
 
 // DB entrance func to init
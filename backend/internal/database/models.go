@@ -86,6 +86,48 @@ type PropertyDetails struct {
 	Cost_dollars      int64  `json:"costDollars"`
 	Cost_cents        int16  `json:"costCents"`
 	Misc_note         string `json:"miscNote"`
+	Status            string `json:"status"`
+
+	// Rental terms
+	Price_type          string  `json:"priceType"` // monthly rent or sale price, applies to Cost_dollars/Cost_cents
+	Deposit_dollars     int64   `json:"depositDollars"`
+	Deposit_cents       int16   `json:"depositCents"`
+	Utilities_included  bool    `json:"utilitiesIncluded"`
+	Lease_length_months []int32 `json:"leaseLengthMonths"`
+	Available_from      string  `json:"availableFrom"` // YYYY-MM-DD, empty if available now
+	Rooms_available     int16   `json:"roomsAvailable"`
+	Pets_policy         string  `json:"petsPolicy"`
+	Smoking_policy      string  `json:"smokingPolicy"`
+
+	Amenities []string `json:"amenities"` // names of amenities from the amenities catalog
+
+	// Coordinates in degrees, both nil if unknown
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+
+	// When the listing expires unless renewed, set by the server while the property is published
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type Amenity struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// PropertySearchFilters narrows down the properties returned by the property search.
+// Zero values mean the filter is not applied.
+type PropertySearchFilters struct {
+	Address           string
+	PriceType         string
+	MaxCostDollars    int64
+	AvailableBy       string // YYYY-MM-DD
+	LeaseLengthMonths int32
+	UtilitiesIncluded bool
+	PetsAllowed       bool
+	SmokingAllowed    bool
+	MinRoomsAvailable int16
+	Amenities         []string
+	MatchAllAmenities bool // require all of Amenities instead of any of them
 }
 
 type PropertyFull struct {
@@ -93,6 +135,52 @@ type PropertyFull struct {
 	PropertyImages  []OrderedFileExternal `json:"images"`
 }
 
+type SimilarProperty struct {
+	PropertyID string  `json:"propertyId"`
+	Similarity float32 `json:"similarity"`
+}
+
+type PropertyDuplicateReview struct {
+	ID                    int32      `json:"id"`
+	PropertyID            string     `json:"propertyId"`
+	DuplicateOfPropertyID string     `json:"duplicateOfPropertyId"`
+	Similarity            float32    `json:"similarity"`
+	Status                string     `json:"status"`
+	ReviewedByUserID      string     `json:"reviewedByUserId"`
+	ReviewedAt            *time.Time `json:"reviewedAt"`
+	CreatedAt             time.Time  `json:"createdAt"`
+}
+
+type PropertyChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+type PropertyHistoryEntry struct {
+	PropertyChange  PropertyChange `json:"change"`
+	ChangedByUserID string         `json:"changedByUserId"`
+	CreatedAt       time.Time      `json:"createdAt"`
+}
+
+type RoomDetails struct {
+	RoomID           string `json:"roomId"`
+	PropertyID       string `json:"propertyId"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	Square_feet      int32  `json:"squareFeet"`
+	Cost_dollars     int64  `json:"costDollars"`
+	Cost_cents       int16  `json:"costCents"`
+	Is_available     bool   `json:"isAvailable"`
+	Available_from   string `json:"availableFrom"` // YYYY-MM-DD, empty if available now
+	Occupant_user_id string `json:"occupantUserId"`
+}
+
+type RoomFull struct {
+	RoomDetails RoomDetails           `json:"details"`
+	RoomImages  []OrderedFileExternal `json:"images"`
+}
+
 type CommunityDetails struct {
 	CommunityID string `json:"communityId"`
 	AdminUserID string `json:"adminUserId"`
@@ -100,6 +188,204 @@ type CommunityDetails struct {
 	Description string `json:"description"`
 }
 
+// CommunitySearchFilters narrow down the search of communities. A community is located by the
+// properties linked to it, zero values mean no filter.
+type CommunitySearchFilters struct {
+	Name        string
+	Description string
+	City        string
+	Latitude    float64
+	Longitude   float64
+	RadiusKm    float64 // within the radius of the coordinates
+	MinMembers  int64
+	MaxMembers  int64
+	HasVacancy  bool     // a linked property is published with rooms available
+	Interests   []string // held by any of the members
+}
+
+type CommunityMember struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// CommunityMembershipRequest is a pending membership of a user to a community, either requested
+// by the user to be approved by the community admin or an invite of the admin to be accepted by the user
+type CommunityMembershipRequest struct {
+	RequestID       string     `json:"requestId"`
+	CommunityID     string     `json:"communityId"`
+	UserID          string     `json:"userId"` // user requesting to join or being invited
+	Kind            string     `json:"kind"`
+	InvitedByUserID string     `json:"invitedByUserId"` // empty for requests
+	ExpiresAt       *time.Time `json:"expiresAt"`       // nil for requests, which do not expire
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// CommunityPropertyLinkRequest is a pending request of a community to link a property,
+// to be approved or rejected by the property's lister
+type CommunityPropertyLinkRequest struct {
+	RequestID         string    `json:"requestId"`
+	CommunityID       string    `json:"communityId"`
+	PropertyID        string    `json:"propertyId"`
+	RequestedByUserID string    `json:"requestedByUserId"` // empty if the requester deleted their account
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// CommunityPost is a post on the discussion board of a community, either a thread
+// or a reply to one
+type CommunityPost struct {
+	PostID         string    `json:"postId"`
+	CommunityID    string    `json:"communityId"`
+	ThreadID       string    `json:"threadId"` // empty for threads
+	UserID         string    `json:"userId"`
+	Title          string    `json:"title"` // empty for replies
+	Body           string    `json:"body"`
+	Pinned         bool      `json:"pinned"`
+	LastActivityAt time.Time `json:"lastActivityAt"` // of the thread, the time of its latest reply
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// CommunityChore is a recurring chore of a community, its members take turns on it in the
+// order of its rotation
+type CommunityChore struct {
+	ChoreID         string    `json:"choreId"`
+	CommunityID     string    `json:"communityId"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Frequency       string    `json:"frequency"`
+	Interval        int32     `json:"interval"` // recurs every interval of days, weeks or months
+	StartsOn        string    `json:"startsOn"` // YYYY-MM-DD
+	CreatedByUserID string    `json:"createdByUserId"`
+	CreatedAt       time.Time `json:"createdAt"`
+	Rotation        []string  `json:"rotation"` // ids of the members taking turns, in order
+}
+
+// CommunityChoreTask is an occurrence of a chore of a community assigned to a member
+type CommunityChoreTask struct {
+	TaskID            string     `json:"taskId"`
+	ChoreID           string     `json:"choreId"`
+	CommunityID       string     `json:"communityId"`
+	ChoreTitle        string     `json:"choreTitle"`
+	Occurrence        int32      `json:"occurrence"`
+	DueOn             string     `json:"dueOn"`          // YYYY-MM-DD
+	AssigneeUserID    string     `json:"assigneeUserId"` // empty if the assignee deleted their account
+	CompletedAt       *time.Time `json:"completedAt"`    // nil until the task is done
+	CompletedByUserID string     `json:"completedByUserId"`
+	SwapTaskID        string     `json:"swapTaskId"` // task the assignee proposed to swap this task with, if any
+}
+
+// CommunityDocumentFolder is a folder of the document library of a community
+type CommunityDocumentFolder struct {
+	FolderID        string    `json:"folderId"`
+	CommunityID     string    `json:"communityId"`
+	ParentFolderID  string    `json:"parentFolderId"` // empty for a top level folder
+	Name            string    `json:"name"`
+	CreatedByUserID string    `json:"createdByUserId"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// CommunityDocument is a document of the library of a community with the details of its
+// latest version
+type CommunityDocument struct {
+	DocumentID      string    `json:"documentId"`
+	CommunityID     string    `json:"communityId"`
+	FolderID        string    `json:"folderId"` // empty if not in a folder
+	Title           string    `json:"title"`
+	Visibility      string    `json:"visibility"`
+	CreatedByUserID string    `json:"createdByUserId"` // empty if the uploader deleted their account
+	CreatedAt       time.Time `json:"createdAt"`
+	Version         int32     `json:"version"`
+	FileName        string    `json:"fileName"`
+	MimeType        string    `json:"mimeType"`
+	Size            int64     `json:"size"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type CommunityDocumentVersion struct {
+	DocumentID       string    `json:"documentId"`
+	Version          int32     `json:"version"`
+	FileName         string    `json:"fileName"`
+	MimeType         string    `json:"mimeType"`
+	Size             int64     `json:"size"`
+	UploadedByUserID string    `json:"uploadedByUserId"`
+	Note             string    `json:"note"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// CommunityEvent is an event organized by a member of a community, optionally at one of
+// the community's properties
+type CommunityEvent struct {
+	EventID         string    `json:"eventId"`
+	CommunityID     string    `json:"communityId"`
+	PropertyID      string    `json:"propertyId"`      // empty if not at a property of the community
+	CreatedByUserID string    `json:"createdByUserId"` // empty if the organizer deleted their account
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Location        string    `json:"location"`
+	StartTime       time.Time `json:"startTime"`
+	EndTime         time.Time `json:"endTime"`
+	Capacity        int16     `json:"capacity"` // maximum number of yes RSVPs, 0 for no limit
+	YesCount        int64     `json:"yesCount"`
+	MaybeCount      int64     `json:"maybeCount"`
+}
+
+type CommunityEventRSVP struct {
+	EventID   string    `json:"eventId"`
+	UserID    string    `json:"userId"`
+	Response  string    `json:"response"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CommunityExpense is an expense paid by a member of a community and shared with other members,
+// amounts are in cents
+type CommunityExpense struct {
+	ExpenseID       string                  `json:"expenseId"`
+	CommunityID     string                  `json:"communityId"`
+	PayerUserID     string                  `json:"payerUserId"`     // empty if the payer deleted their account
+	CreatedByUserID string                  `json:"createdByUserId"` // empty if the member who recorded it deleted their account
+	Description     string                  `json:"description"`
+	Category        string                  `json:"category"`
+	AmountCents     int64                   `json:"amountCents"`
+	SplitType       string                  `json:"splitType"`
+	SpentOn         string                  `json:"spentOn"` // YYYY-MM-DD
+	CreatedAt       time.Time               `json:"createdAt"`
+	Splits          []CommunityExpenseSplit `json:"splits"`
+}
+
+// CommunityExpenseSplit is the part of an expense a member owes to its payer
+type CommunityExpenseSplit struct {
+	UserID      string `json:"userId"`      // empty if the member deleted their account
+	Shares      int32  `json:"shares"`      // only set when split by shares
+	AmountCents int64  `json:"amountCents"` // only given when split in exact amounts, computed otherwise
+}
+
+// CommunityPoll is a poll of a community, a member votes for one option or, on multiple choice
+// polls, for several options
+type CommunityPoll struct {
+	PollID            string                `json:"pollId"`
+	CommunityID       string                `json:"communityId"`
+	CreatedByUserID   string                `json:"createdByUserId"` // empty if the creator deleted their account
+	Question          string                `json:"question"`
+	MultipleChoice    bool                  `json:"multipleChoice"`
+	Anonymous         bool                  `json:"anonymous"`
+	ResultsVisibility string                `json:"resultsVisibility"`
+	OpensAt           time.Time             `json:"opensAt"`
+	ClosesAt          time.Time             `json:"closesAt"`
+	CreatedAt         time.Time             `json:"createdAt"`
+	VoterCount        int64                 `json:"voterCount"`
+	Options           []CommunityPollOption `json:"options"`
+}
+
+type CommunityPollOption struct {
+	OptionID string `json:"optionId"`
+	Label    string `json:"label"`
+}
+
+type CommunityPollResult struct {
+	OptionID string   `json:"optionId"`
+	Votes    int64    `json:"votes"`
+	Voters   []string `json:"voters"` // user ids, empty on anonymous polls
+}
+
 type CommunityFull struct {
 	CommunityDetails    CommunityDetails `json:"details"`
 	CommunityImages     []FileExternal   `json:"images"`
@@ -137,3 +423,61 @@ type PublicBasicListerDetails struct {
 	FirstName string `json:"firstName"`
 	LastName  string `json:"lastName"`
 }
+
+type ViewingSlot struct {
+	SlotID      string    `json:"slotId"`
+	PropertyID  string    `json:"propertyId"`
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	Capacity    int16     `json:"capacity"`
+	Note        string    `json:"note"`
+	BookedCount int64     `json:"bookedCount"`
+}
+
+type Notification struct {
+	ID        int32     `json:"id"`
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Link      string    `json:"link"`
+	IsRead    bool      `json:"isRead"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type RentalApplication struct {
+	ApplicationID      string    `json:"applicationId"`
+	PropertyID         string    `json:"propertyId"`
+	ApplicantUserID    string    `json:"applicantUserId"`
+	CoApplicantUserIDs []string  `json:"coApplicantUserIds"`
+	Message            string    `json:"message"`
+	MoveInDate         string    `json:"moveInDate"` // YYYY-MM-DD, empty if flexible
+	Status             string    `json:"status"`
+	DecisionNote       string    `json:"decisionNote"`
+	StatusUpdatedAt    time.Time `json:"statusUpdatedAt"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+// PropertyAddress is the address of a property along with its stored normalized form
+type PropertyAddress struct {
+	PropertyID        string
+	Address_1         string
+	Address_2         string
+	City              string
+	State             string
+	Zipcode           string
+	Country           string
+	NormalizedAddress string
+}
+
+// ExpiringProperty is a published property whose listing is about to expire or expired
+type ExpiringProperty struct {
+	PropertyID   string
+	ListerUserID string
+	Name         string
+	ExpiresAt    time.Time
+}
+
+type PropertyAnalyticsDay struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Views int32  `json:"views"`
+	Saves int32  `json:"saves"`
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Names of the property fields whose changes are recorded in a property's history.
+const (
+	PROPERTY_HISTORY_FIELD_NAME        = "name"
+	PROPERTY_HISTORY_FIELD_ADDRESS     = "address"
+	PROPERTY_HISTORY_FIELD_SQUARE_FEET = "squareFeet"
+	PROPERTY_HISTORY_FIELD_BEDROOMS    = "numBedrooms"
+	PROPERTY_HISTORY_FIELD_TOILETS     = "numToilets"
+	PROPERTY_HISTORY_FIELD_SHOWERS     = "numShowersBaths"
+	PROPERTY_HISTORY_FIELD_COST        = "cost"
+	PROPERTY_HISTORY_FIELD_PRICE_TYPE  = "priceType"
+	PROPERTY_HISTORY_FIELD_DEPOSIT     = "deposit"
+	PROPERTY_HISTORY_FIELD_UTILITIES   = "utilitiesIncluded"
+	PROPERTY_HISTORY_FIELD_LEASE       = "leaseLengthMonths"
+	PROPERTY_HISTORY_FIELD_AVAILABLE   = "availableFrom"
+	PROPERTY_HISTORY_FIELD_PETS        = "petsPolicy"
+	PROPERTY_HISTORY_FIELD_SMOKING     = "smokingPolicy"
+	PROPERTY_HISTORY_FIELD_AMENITIES   = "amenities"
+	PROPERTY_HISTORY_FIELD_STATUS      = "status"
+	PROPERTY_HISTORY_FIELD_LISTER      = "listerUserId"
+)
+
+// PROPERTY_HISTORY_PUBLIC_FIELDS are the history fields that anyone who can see a
+// property may see the changes of, e.g. so that tenants can spot price drops.
+// The full history is only shown to the property's lister and the admin.
+var PROPERTY_HISTORY_PUBLIC_FIELDS = map[string]struct{}{
+	PROPERTY_HISTORY_FIELD_COST:       {},
+	PROPERTY_HISTORY_FIELD_PRICE_TYPE: {},
+	PROPERTY_HISTORY_FIELD_DEPOSIT:    {},
+	PROPERTY_HISTORY_FIELD_STATUS:     {},
+}
+
+func formatDollarsCents(dollars int64, cents int16) string {
+	return fmt.Sprintf("%d.%02d", dollars, cents)
+}
+
+func formatPropertyAddress(details PropertyDetails) string {
+	parts := []string{details.Address_1}
+	if details.Address_2 != "" {
+		parts = append(parts, details.Address_2)
+	}
+	parts = append(parts, details.City, details.State, details.Zipcode, details.Country)
+	return strings.Join(parts, ", ")
+}
+
+func formatLeaseLengths(months []int32) string {
+	sorted := slices.Clone(months)
+	slices.Sort(sorted)
+	values := make([]string, len(sorted))
+	for i, v := range sorted {
+		values[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(values, ",")
+}
+
+func formatAmenities(amenities []string) string {
+	sorted := slices.Clone(amenities)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// DiffPropertyDetails returns the changes of the recorded key fields between two
+// versions of a property's details. Fields that did not change are omitted.
+func DiffPropertyDetails(before, after PropertyDetails) []PropertyChange {
+	fields := []struct {
+		name   string
+		before string
+		after  string
+	}{
+		{PROPERTY_HISTORY_FIELD_NAME, before.Name, after.Name},
+		{PROPERTY_HISTORY_FIELD_ADDRESS, formatPropertyAddress(before), formatPropertyAddress(after)},
+		{PROPERTY_HISTORY_FIELD_SQUARE_FEET, strconv.Itoa(int(before.Square_feet)), strconv.Itoa(int(after.Square_feet))},
+		{PROPERTY_HISTORY_FIELD_BEDROOMS, strconv.Itoa(int(before.Num_bedrooms)), strconv.Itoa(int(after.Num_bedrooms))},
+		{PROPERTY_HISTORY_FIELD_TOILETS, strconv.Itoa(int(before.Num_toilets)), strconv.Itoa(int(after.Num_toilets))},
+		{PROPERTY_HISTORY_FIELD_SHOWERS, strconv.Itoa(int(before.Num_showers_baths)), strconv.Itoa(int(after.Num_showers_baths))},
+		{PROPERTY_HISTORY_FIELD_COST, formatDollarsCents(before.Cost_dollars, before.Cost_cents), formatDollarsCents(after.Cost_dollars, after.Cost_cents)},
+		{PROPERTY_HISTORY_FIELD_PRICE_TYPE, before.Price_type, after.Price_type},
+		{PROPERTY_HISTORY_FIELD_DEPOSIT, formatDollarsCents(before.Deposit_dollars, before.Deposit_cents), formatDollarsCents(after.Deposit_dollars, after.Deposit_cents)},
+		{PROPERTY_HISTORY_FIELD_UTILITIES, strconv.FormatBool(before.Utilities_included), strconv.FormatBool(after.Utilities_included)},
+		{PROPERTY_HISTORY_FIELD_LEASE, formatLeaseLengths(before.Lease_length_months), formatLeaseLengths(after.Lease_length_months)},
+		{PROPERTY_HISTORY_FIELD_AVAILABLE, before.Available_from, after.Available_from},
+		{PROPERTY_HISTORY_FIELD_PETS, before.Pets_policy, after.Pets_policy},
+		{PROPERTY_HISTORY_FIELD_SMOKING, before.Smoking_policy, after.Smoking_policy},
+		{PROPERTY_HISTORY_FIELD_AMENITIES, formatAmenities(before.Amenities), formatAmenities(after.Amenities)},
+		{PROPERTY_HISTORY_FIELD_STATUS, before.Status, after.Status},
+		{PROPERTY_HISTORY_FIELD_LISTER, before.ListerUserID, after.ListerUserID},
+	}
+
+	changes := []PropertyChange{}
+	for _, field := range fields {
+		if field.before != field.after {
+			changes = append(changes, PropertyChange{
+				Field:    field.name,
+				OldValue: field.before,
+				NewValue: field.after,
+			})
+		}
+	}
+	return changes
+}
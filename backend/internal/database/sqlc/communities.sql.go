@@ -8,8 +8,32 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
+const checkIsCommunityUser = `-- name: CheckIsCommunityUser :one
+SELECT
+    count(*)
+FROM
+    communities_users
+WHERE
+    community_id = $1
+    AND user_id = $2
+`
+
+type CheckIsCommunityUserParams struct {
+	CommunityID string
+	UserID      string
+}
+
+func (q *Queries) CheckIsCommunityUser(ctx context.Context, arg CheckIsCommunityUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, checkIsCommunityUser, arg.CommunityID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCommunityDetails = `-- name: CreateCommunityDetails :exec
 INSERT INTO
     communities (
@@ -46,7 +70,7 @@ INSERT INTO
         file_name,
         mime_type,
         "size",
-        "data"
+        content_hash
     )
 VALUES
     ($1, $2, $3, $4, $5)
@@ -57,7 +81,7 @@ type CreateCommunityImageParams struct {
 	FileName    string
 	MimeType    string
 	Size        int64
-	Data        []byte
+	ContentHash string
 }
 
 func (q *Queries) CreateCommunityImage(ctx context.Context, arg CreateCommunityImageParams) error {
@@ -66,7 +90,7 @@ func (q *Queries) CreateCommunityImage(ctx context.Context, arg CreateCommunityI
 		arg.FileName,
 		arg.MimeType,
 		arg.Size,
-		arg.Data,
+		arg.ContentHash,
 	)
 	return err
 }
@@ -90,18 +114,19 @@ func (q *Queries) CreateCommunityProperty(ctx context.Context, arg CreateCommuni
 
 const createCommunityUser = `-- name: CreateCommunityUser :exec
 INSERT INTO
-    communities_users (community_id, user_id)
+    communities_users (community_id, user_id, role)
 VALUES
-    ($1, $2)
+    ($1, $2, $3)
 `
 
 type CreateCommunityUserParams struct {
 	CommunityID string
 	UserID      string
+	Role        string
 }
 
 func (q *Queries) CreateCommunityUser(ctx context.Context, arg CreateCommunityUserParams) error {
-	_, err := q.db.ExecContext(ctx, createCommunityUser, arg.CommunityID, arg.UserID)
+	_, err := q.db.ExecContext(ctx, createCommunityUser, arg.CommunityID, arg.UserID, arg.Role)
 	return err
 }
 
@@ -220,30 +245,40 @@ func (q *Queries) GetCommunityDetails(ctx context.Context, communityID string) (
 
 const getCommunityImages = `-- name: GetCommunityImages :many
 SELECT
-    id, community_id, file_name, mime_type, size, data, updated_at
+    communities_images.file_name,
+    communities_images.mime_type,
+    communities_images."size",
+    file_blobs."data"
 FROM
     communities_images
+    JOIN file_blobs ON communities_images.content_hash = file_blobs.content_hash
 WHERE
-    community_id = $1
+    communities_images.community_id = $1
+ORDER BY
+    communities_images.id
 `
 
-func (q *Queries) GetCommunityImages(ctx context.Context, communityID string) ([]CommunitiesImage, error) {
+type GetCommunityImagesRow struct {
+	FileName string
+	MimeType string
+	Size     int64
+	Data     []byte
+}
+
+func (q *Queries) GetCommunityImages(ctx context.Context, communityID string) ([]GetCommunityImagesRow, error) {
 	rows, err := q.db.QueryContext(ctx, getCommunityImages, communityID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []CommunitiesImage
+	var items []GetCommunityImagesRow
 	for rows.Next() {
-		var i CommunitiesImage
+		var i GetCommunityImagesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.CommunityID,
 			&i.FileName,
 			&i.MimeType,
 			&i.Size,
 			&i.Data,
-			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -290,9 +325,31 @@ func (q *Queries) GetCommunityProperties(ctx context.Context, communityID string
 	return items, nil
 }
 
+const getCommunityUserRole = `-- name: GetCommunityUserRole :one
+SELECT
+    role
+FROM
+    communities_users
+WHERE
+    community_id = $1
+    AND user_id = $2
+`
+
+type GetCommunityUserRoleParams struct {
+	CommunityID string
+	UserID      string
+}
+
+func (q *Queries) GetCommunityUserRole(ctx context.Context, arg GetCommunityUserRoleParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCommunityUserRole, arg.CommunityID, arg.UserID)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
 const getCommunityUsers = `-- name: GetCommunityUsers :many
 SELECT
-    id, community_id, user_id
+    id, community_id, user_id, role
 FROM
     communities_users
 WHERE
@@ -308,7 +365,12 @@ func (q *Queries) GetCommunityUsers(ctx context.Context, communityID string) ([]
 	var items []CommunitiesUser
 	for rows.Next() {
 		var i CommunitiesUser
-		if err := rows.Scan(&i.ID, &i.CommunityID, &i.UserID); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.CommunityID,
+			&i.UserID,
+			&i.Role,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -324,24 +386,126 @@ func (q *Queries) GetCommunityUsers(ctx context.Context, communityID string) ([]
 
 const getNextPageCommunities = `-- name: GetNextPageCommunities :many
 SELECT
-    community_id
+    communities.community_id
 FROM
     communities
+    CROSS JOIN LATERAL (
+        SELECT
+            count(*) AS member_count
+        FROM
+            communities_users
+        WHERE
+            communities_users.community_id = communities.community_id
+    ) AS members
+    CROSS JOIN LATERAL (
+        SELECT
+            count(DISTINCT members_interests.interest) AS shared_interests
+        FROM
+            communities_users
+            INNER JOIN users ON users.user_id = communities_users.user_id
+            CROSS JOIN LATERAL unnest(users.interests) AS members_interests (interest)
+        WHERE
+            communities_users.community_id = communities.community_id
+            AND members_interests.interest = ANY ($12::text[])
+    ) AS interests
+    CROSS JOIN LATERAL (
+        SELECT
+            min(
+                2 * 6371 * asin(
+                    least(
+                        1,
+                        sqrt(
+                            power(sin(radians(properties.latitude - $6::double precision) / 2), 2) + cos(radians($6)) * cos(radians(properties.latitude)) * power(sin(radians(properties.longitude - $7::double precision) / 2), 2)
+                        )
+                    )
+                )
+            ) AS distance_km
+        FROM
+            communities_properties
+            INNER JOIN properties ON properties.property_id = communities_properties.property_id
+        WHERE
+            communities_properties.community_id = communities.community_id
+            AND properties.status = $13
+            AND properties.latitude IS NOT NULL
+    ) AS nearest
+WHERE
+    (
+        $5::text = ''
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                communities_properties
+                INNER JOIN properties ON properties.property_id = communities_properties.property_id
+            WHERE
+                communities_properties.community_id = communities.community_id
+                AND properties.status = $13
+                AND lower(properties.city) = lower($5)
+        )
+    )
+    AND (
+        $8::double precision = 0
+        OR nearest.distance_km <= $8
+    )
+    AND members.member_count >= $9::bigint
+    AND (
+        $10::bigint = 0
+        OR members.member_count <= $10
+    )
+    AND (
+        NOT $11::boolean
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                communities_properties
+                INNER JOIN properties ON properties.property_id = communities_properties.property_id
+            WHERE
+                communities_properties.community_id = communities.community_id
+                AND properties.status = $13
+                AND (
+                    properties.rooms_available > 0
+                    OR EXISTS (
+                        SELECT
+                            1
+                        FROM
+                            properties_rooms
+                        WHERE
+                            properties_rooms.property_id = properties.property_id
+                            AND properties_rooms.is_available
+                    )
+                )
+        )
+    )
+    AND (
+        cardinality($12::text[]) = 0
+        OR interests.shared_interests > 0
+    )
 ORDER BY
-    CASE
-        WHEN $3 <> ''
-        AND $4 <> '' THEN 0.4 * similarity ("name", $3) + 0.6 * similarity ("description", $4)
-        WHEN $3 <> '' THEN CASE
-            WHEN "name" <> '' THEN similarity ("name", $3)
+    COALESCE(
+        CASE
+            WHEN $3 <> ''
+            AND $4 <> '' THEN 0.4 * similarity (communities."name", $3) + 0.6 * similarity (communities."description", $4)
+            WHEN $3 <> '' THEN CASE
+                WHEN communities."name" <> '' THEN similarity (communities."name", $3)
+                ELSE 0
+            END
+            WHEN $4 <> '' THEN CASE
+                WHEN communities."description" <> '' THEN similarity (communities."description", $4)
+                ELSE 0
+            END
             ELSE 0
-        END
-        WHEN $4 <> '' THEN CASE
-            WHEN "description" <> '' THEN similarity ("description", $4)
-            ELSE 0
-        END
+        END,
+        0
+    ) + CASE
+        WHEN cardinality($12::text[]) > 0 THEN interests.shared_interests::double precision / cardinality($12::text[])
+        ELSE 0
+    END + CASE
+        WHEN $8::double precision > 0 THEN 1 - nearest.distance_km / $8::double precision
         ELSE 0
     END DESC,
-    id
+    members.member_count DESC,
+    communities.id
 LIMIT
     $1
 OFFSET
@@ -349,18 +513,40 @@ OFFSET
 `
 
 type GetNextPageCommunitiesParams struct {
-	Limit   int32
-	Offset  int32
-	Column3 interface{}
-	Column4 interface{}
+	Limit    int32
+	Offset   int32
+	Column3  interface{}
+	Column4  interface{}
+	Column5  string
+	Column6  float64
+	Column7  float64
+	Column8  float64
+	Column9  int64
+	Column10 int64
+	Column11 bool
+	Column12 []string
+	Status   string
 }
 
+// Communities matching the search filters, most relevant first. A community is located by the
+// published properties linked to it and has vacancy when one of them has rooms available.
+// Relevance adds up the similarity of the name and description, the share of the searched
+// interests held by its members and how close its nearest property is within the radius.
 func (q *Queries) GetNextPageCommunities(ctx context.Context, arg GetNextPageCommunitiesParams) ([]string, error) {
 	rows, err := q.db.QueryContext(ctx, getNextPageCommunities,
 		arg.Limit,
 		arg.Offset,
 		arg.Column3,
 		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+		pq.Array(arg.Column12),
+		arg.Status,
 	)
 	if err != nil {
 		return nil, err
@@ -383,6 +569,41 @@ func (q *Queries) GetNextPageCommunities(ctx context.Context, arg GetNextPageCom
 	return items, nil
 }
 
+const getPropertyCommunities = `-- name: GetPropertyCommunities :many
+SELECT
+    community_id
+FROM
+    communities_properties
+WHERE
+    property_id = $1
+ORDER BY
+    id
+`
+
+// Communities a property is linked to
+func (q *Queries) GetPropertyCommunities(ctx context.Context, propertyID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getPropertyCommunities, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var community_id string
+		if err := rows.Scan(&community_id); err != nil {
+			return nil, err
+		}
+		items = append(items, community_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserOwnedCommunities = `-- name: GetUserOwnedCommunities :many
 SELECT
     community_id
@@ -461,3 +682,23 @@ func (q *Queries) UpdateCommunityDetails(ctx context.Context, arg UpdateCommunit
 	)
 	return err
 }
+
+const updateCommunityUserRole = `-- name: UpdateCommunityUserRole :exec
+UPDATE communities_users
+SET
+    role = $3
+WHERE
+    community_id = $1
+    AND user_id = $2
+`
+
+type UpdateCommunityUserRoleParams struct {
+	CommunityID string
+	UserID      string
+	Role        string
+}
+
+func (q *Queries) UpdateCommunityUserRole(ctx context.Context, arg UpdateCommunityUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, updateCommunityUserRole, arg.CommunityID, arg.UserID, arg.Role)
+	return err
+}
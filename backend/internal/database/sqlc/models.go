@@ -7,16 +7,198 @@ package sqlc
 import (
 	"database/sql"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+type Amenity struct {
+	ID        int32
+	Name      string
+	Category  string
+	CreatedAt time.Time
+}
+
+type CommunitiesChore struct {
+	ID              int32
+	ChoreID         string
+	CommunityID     string
+	Title           string
+	Description     string
+	Frequency       string
+	IntervalCount   int32
+	StartsOn        time.Time
+	CreatedByUserID sql.NullString
+	CreatedAt       time.Time
+}
+
+type CommunitiesChoresRotation struct {
+	ID       int32
+	ChoreID  string
+	UserID   string
+	Position int32
+}
+
+type CommunitiesChoresTask struct {
+	ID                int32
+	TaskID            string
+	ChoreID           string
+	Occurrence        int32
+	DueOn             time.Time
+	AssigneeUserID    sql.NullString
+	CompletedAt       sql.NullTime
+	CompletedByUserID sql.NullString
+	SwapTaskID        sql.NullString
+	CreatedAt         time.Time
+}
+
+type CommunitiesDocument struct {
+	ID              int32
+	DocumentID      string
+	CommunityID     string
+	FolderID        sql.NullString
+	Title           string
+	Visibility      string
+	CreatedByUserID sql.NullString
+	CreatedAt       time.Time
+}
+
+type CommunitiesDocumentsFolder struct {
+	ID              int32
+	FolderID        string
+	CommunityID     string
+	ParentFolderID  sql.NullString
+	Name            string
+	CreatedByUserID sql.NullString
+	CreatedAt       time.Time
+}
+
+type CommunitiesDocumentsVersion struct {
+	ID               int32
+	DocumentID       string
+	Version          int32
+	FileName         string
+	MimeType         string
+	Size             int64
+	ContentHash      string
+	UploadedByUserID sql.NullString
+	Note             string
+	CreatedAt        time.Time
+}
+
+type CommunitiesEvent struct {
+	ID              int32
+	EventID         string
+	CommunityID     string
+	PropertyID      sql.NullString
+	CreatedByUserID sql.NullString
+	Title           string
+	Description     string
+	Location        string
+	StartTime       time.Time
+	EndTime         time.Time
+	Capacity        sql.NullInt16
+	CreatedAt       time.Time
+}
+
+type CommunitiesEventsRsvp struct {
+	ID        int32
+	EventID   string
+	UserID    string
+	Response  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CommunitiesExpense struct {
+	ID              int32
+	ExpenseID       string
+	CommunityID     string
+	PayerUserID     sql.NullString
+	CreatedByUserID sql.NullString
+	Description     string
+	Category        string
+	AmountCents     int32
+	SplitType       string
+	SpentOn         time.Time
+	CreatedAt       time.Time
+}
+
+type CommunitiesExpensesSplit struct {
+	ID          int32
+	ExpenseID   string
+	UserID      sql.NullString
+	Shares      sql.NullInt32
+	AmountCents int32
+}
+
 type CommunitiesImage struct {
 	ID          int32
 	CommunityID string
 	FileName    string
 	MimeType    string
 	Size        int64
-	Data        []byte
 	UpdatedAt   time.Time
+	ContentHash string
+}
+
+type CommunitiesMembershipRequest struct {
+	ID              int32
+	RequestID       string
+	CommunityID     string
+	UserID          string
+	Kind            string
+	InvitedByUserID sql.NullString
+	ExpiresAt       sql.NullTime
+	CreatedAt       time.Time
+}
+
+type CommunitiesPoll struct {
+	ID                int32
+	PollID            string
+	CommunityID       string
+	CreatedByUserID   sql.NullString
+	Question          string
+	MultipleChoice    bool
+	Anonymous         bool
+	ResultsVisibility string
+	OpensAt           time.Time
+	ClosesAt          time.Time
+	CreatedAt         time.Time
+}
+
+type CommunitiesPollsBallot struct {
+	ID        int32
+	PollID    string
+	UserID    string
+	CreatedAt time.Time
+}
+
+type CommunitiesPollsOption struct {
+	ID       int32
+	OptionID string
+	PollID   string
+	Position int16
+	Label    string
+}
+
+type CommunitiesPollsVote struct {
+	VoteID   uuid.UUID
+	PollID   string
+	OptionID string
+	UserID   sql.NullString
+}
+
+type CommunitiesPost struct {
+	ID             int32
+	PostID         string
+	CommunityID    string
+	ThreadID       sql.NullString
+	UserID         string
+	Title          string
+	Body           string
+	Pinned         bool
+	LastActivityAt time.Time
+	CreatedAt      time.Time
 }
 
 type CommunitiesProperty struct {
@@ -25,10 +207,20 @@ type CommunitiesProperty struct {
 	PropertyID  string
 }
 
+type CommunitiesPropertyLinkRequest struct {
+	ID                int32
+	RequestID         string
+	CommunityID       string
+	PropertyID        string
+	RequestedByUserID sql.NullString
+	CreatedAt         time.Time
+}
+
 type CommunitiesUser struct {
 	ID          int32
 	CommunityID string
 	UserID      string
+	Role        string
 }
 
 type Community struct {
@@ -41,38 +233,188 @@ type Community struct {
 	UpdatedAt   time.Time
 }
 
+type FileBlob struct {
+	ID          int32
+	ContentHash string
+	Size        int64
+	Data        []byte
+	RefCount    int32
+	CreatedAt   time.Time
+}
+
+type Notification struct {
+	ID        int32
+	UserID    string
+	Type      string
+	Message   string
+	Link      string
+	IsRead    bool
+	CreatedAt time.Time
+}
+
+type PropertiesAmenity struct {
+	ID          int32
+	PropertyID  string
+	AmenityName string
+}
+
+type PropertiesAnalyticsDaily struct {
+	PropertyID string
+	Day        time.Time
+	Views      int32
+	Saves      int32
+}
+
+type PropertiesAnalyticsEvent struct {
+	PropertyID string
+	Day        time.Time
+	EventType  string
+	ViewerHash string
+}
+
+type PropertiesApplication struct {
+	ID              int32
+	ApplicationID   string
+	PropertyID      string
+	ApplicantUserID string
+	Message         string
+	MoveInDate      sql.NullTime
+	Status          string
+	DecisionNote    string
+	StatusUpdatedAt time.Time
+	CreatedAt       time.Time
+}
+
+type PropertiesApplicationsCoApplicant struct {
+	ApplicationID string
+	UserID        string
+}
+
+type PropertiesApplicationsDocument struct {
+	ID            int32
+	ApplicationID string
+	OrderNum      int16
+	FileName      string
+	MimeType      string
+	Size          int64
+	ContentHash   string
+	CreatedAt     time.Time
+}
+
+type PropertiesDuplicateReview struct {
+	ID                    int32
+	PropertyID            string
+	DuplicateOfPropertyID string
+	Similarity            float32
+	Status                string
+	ReviewedByUserID      sql.NullString
+	ReviewedAt            sql.NullTime
+	CreatedAt             time.Time
+}
+
+type PropertiesHistory struct {
+	ID              int32
+	PropertyID      string
+	ChangedByUserID sql.NullString
+	Field           string
+	OldValue        string
+	NewValue        string
+	CreatedAt       time.Time
+}
+
 type PropertiesImage struct {
+	ID          int32
+	PropertyID  string
+	OrderNum    int16
+	FileName    string
+	MimeType    string
+	Size        int64
+	CreatedAt   time.Time
+	ContentHash string
+}
+
+type PropertiesRoom struct {
+	ID             int32
+	RoomID         string
+	PropertyID     string
+	Name           string
+	Description    sql.NullString
+	SquareFeet     int32
+	CostDollars    int64
+	CostCents      int16
+	IsAvailable    bool
+	AvailableFrom  sql.NullTime
+	OccupantUserID sql.NullString
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type PropertiesRoomsImage struct {
+	ID          int32
+	RoomID      string
+	OrderNum    int16
+	FileName    string
+	MimeType    string
+	Size        int64
+	ContentHash string
+	CreatedAt   time.Time
+}
+
+type PropertiesViewingBooking struct {
+	ID        int32
+	SlotID    string
+	UserID    string
+	CreatedAt time.Time
+}
+
+type PropertiesViewingSlot struct {
 	ID         int32
+	SlotID     string
 	PropertyID string
-	OrderNum   int16
-	FileName   string
-	MimeType   string
-	Size       int64
-	Data       []byte
+	StartTime  time.Time
+	EndTime    time.Time
+	Capacity   int16
+	Note       string
 	CreatedAt  time.Time
 }
 
 type Property struct {
-	ID              int32
-	PropertyID      string
-	ListerUserID    string
-	Name            string
-	Description     sql.NullString
-	Address1        string
-	Address2        sql.NullString
-	City            string
-	State           string
-	Zipcode         string
-	Country         string
-	SquareFeet      int32
-	NumBedrooms     int16
-	NumToilets      int16
-	NumShowersBaths int16
-	CostDollars     int64
-	CostCents       int16
-	MiscNote        sql.NullString
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID                   int32
+	PropertyID           string
+	ListerUserID         string
+	Name                 string
+	Description          sql.NullString
+	Address1             string
+	Address2             sql.NullString
+	City                 string
+	State                string
+	Zipcode              string
+	Country              string
+	SquareFeet           int32
+	NumBedrooms          int16
+	NumToilets           int16
+	NumShowersBaths      int16
+	CostDollars          int64
+	CostCents            int16
+	MiscNote             sql.NullString
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+	Status               string
+	StatusUpdatedAt      time.Time
+	PriceType            string
+	DepositDollars       int64
+	DepositCents         int16
+	UtilitiesIncluded    bool
+	LeaseLengthMonths    []int32
+	AvailableFrom        sql.NullTime
+	RoomsAvailable       int16
+	PetsPolicy           string
+	SmokingPolicy        string
+	NormalizedAddress    string
+	Latitude             sql.NullFloat64
+	Longitude            sql.NullFloat64
+	ExpiresAt            sql.NullTime
+	ExpiryReminderSentAt sql.NullTime
 }
 
 type Role struct {
@@ -116,6 +458,12 @@ type UsersAvatar struct {
 	UpdatedAt time.Time
 }
 
+type UsersCalendarToken struct {
+	UserID    string
+	TokenHash string
+	CreatedAt time.Time
+}
+
 type UsersSavedCommunity struct {
 	ID          int32
 	UserID      string
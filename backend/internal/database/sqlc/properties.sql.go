@@ -8,6 +8,8 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const checkIsPropertyDuplicate = `-- name: CheckIsPropertyDuplicate :one
@@ -24,15 +26,17 @@ WHERE
         AND lower(trim(zipcode)) = lower(trim($5))
         AND lower(trim(country)) = lower(trim($6))
     )
+    AND property_id <> $7
 `
 
 type CheckIsPropertyDuplicateParams struct {
-	Btrim   string
-	Btrim_2 string
-	Btrim_3 string
-	Btrim_4 string
-	Btrim_5 string
-	Btrim_6 string
+	Btrim      string
+	Btrim_2    string
+	Btrim_3    string
+	Btrim_4    string
+	Btrim_5    string
+	Btrim_6    string
+	PropertyID string
 }
 
 func (q *Queries) CheckIsPropertyDuplicate(ctx context.Context, arg CheckIsPropertyDuplicateParams) (int64, error) {
@@ -43,6 +47,7 @@ func (q *Queries) CheckIsPropertyDuplicate(ctx context.Context, arg CheckIsPrope
 		arg.Btrim_4,
 		arg.Btrim_5,
 		arg.Btrim_6,
+		arg.PropertyID,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -68,7 +73,21 @@ INSERT INTO
         num_showers_baths,
         cost_dollars,
         cost_cents,
-        misc_note
+        misc_note,
+        status,
+        price_type,
+        deposit_dollars,
+        deposit_cents,
+        utilities_included,
+        lease_length_months,
+        available_from,
+        rooms_available,
+        pets_policy,
+        smoking_policy,
+        normalized_address,
+        latitude,
+        longitude,
+        expires_at
     )
 VALUES
     (
@@ -88,28 +107,56 @@ VALUES
         $14,
         $15,
         $16,
-        $17
+        $17,
+        $18,
+        $19,
+        $20,
+        $21,
+        $22,
+        $23,
+        $24,
+        $25,
+        $26,
+        $27,
+        $28,
+        $29,
+        $30,
+        $31
     )
 `
 
 type CreatePropertyDetailsParams struct {
-	PropertyID      string
-	ListerUserID    string
-	Name            string
-	Description     sql.NullString
-	Address1        string
-	Address2        sql.NullString
-	City            string
-	State           string
-	Zipcode         string
-	Country         string
-	SquareFeet      int32
-	NumBedrooms     int16
-	NumToilets      int16
-	NumShowersBaths int16
-	CostDollars     int64
-	CostCents       int16
-	MiscNote        sql.NullString
+	PropertyID        string
+	ListerUserID      string
+	Name              string
+	Description       sql.NullString
+	Address1          string
+	Address2          sql.NullString
+	City              string
+	State             string
+	Zipcode           string
+	Country           string
+	SquareFeet        int32
+	NumBedrooms       int16
+	NumToilets        int16
+	NumShowersBaths   int16
+	CostDollars       int64
+	CostCents         int16
+	MiscNote          sql.NullString
+	Status            string
+	PriceType         string
+	DepositDollars    int64
+	DepositCents      int16
+	UtilitiesIncluded bool
+	LeaseLengthMonths []int32
+	AvailableFrom     sql.NullTime
+	RoomsAvailable    int16
+	PetsPolicy        string
+	SmokingPolicy     string
+	NormalizedAddress string
+	Latitude          sql.NullFloat64
+	Longitude         sql.NullFloat64
+	ExpiresAt         sql.NullTime
 }
 
 func (q *Queries) CreatePropertyDetails(ctx context.Context, arg CreatePropertyDetailsParams) error {
@@ -131,6 +178,20 @@ func (q *Queries) CreatePropertyDetails(ctx context.Context, arg CreatePropertyD
 		arg.CostDollars,
 		arg.CostCents,
 		arg.MiscNote,
+		arg.Status,
+		arg.PriceType,
+		arg.DepositDollars,
+		arg.DepositCents,
+		arg.UtilitiesIncluded,
+		pq.Array(arg.LeaseLengthMonths),
+		arg.AvailableFrom,
+		arg.RoomsAvailable,
+		arg.PetsPolicy,
+		arg.SmokingPolicy,
+		arg.NormalizedAddress,
+		arg.Latitude,
+		arg.Longitude,
+		arg.ExpiresAt,
 	)
 	return err
 }
@@ -143,19 +204,19 @@ INSERT INTO
         file_name,
         mime_type,
         "size",
-        "data"
+        content_hash
     )
 VALUES
     ($1, $2, $3, $4, $5, $6)
 `
 
 type CreatePropertyImageParams struct {
-	PropertyID string
-	OrderNum   int16
-	FileName   string
-	MimeType   string
-	Size       int64
-	Data       []byte
+	PropertyID  string
+	OrderNum    int16
+	FileName    string
+	MimeType    string
+	Size        int64
+	ContentHash string
 }
 
 func (q *Queries) CreatePropertyImage(ctx context.Context, arg CreatePropertyImageParams) error {
@@ -165,7 +226,7 @@ func (q *Queries) CreatePropertyImage(ctx context.Context, arg CreatePropertyIma
 		arg.FileName,
 		arg.MimeType,
 		arg.Size,
-		arg.Data,
+		arg.ContentHash,
 	)
 	return err
 }
@@ -220,11 +281,113 @@ func (q *Queries) DeletePropertyImages(ctx context.Context, propertyID string) e
 	return err
 }
 
+const expireProperties = `-- name: ExpireProperties :many
+UPDATE properties
+SET
+    status = 'expired',
+    status_updated_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    status = 'published'
+    AND expires_at <= $1
+RETURNING
+    property_id,
+    lister_user_id,
+    name
+`
+
+type ExpirePropertiesRow struct {
+	PropertyID   string
+	ListerUserID string
+	Name         string
+}
+
+// Hides published properties whose listing expired by the given time
+func (q *Queries) ExpireProperties(ctx context.Context, expiresAt sql.NullTime) ([]ExpirePropertiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, expireProperties, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExpirePropertiesRow
+	for rows.Next() {
+		var i ExpirePropertiesRow
+		if err := rows.Scan(&i.PropertyID, &i.ListerUserID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNextPageProperties = `-- name: GetNextPageProperties :many
 SELECT
     property_id
 FROM
     properties
+WHERE
+    status = $4
+    AND (
+        $5::text = ''
+        OR price_type = $5
+    )
+    AND (
+        $6::bigint = 0
+        OR cost_dollars <= $6
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                properties_rooms
+            WHERE
+                properties_rooms.property_id = properties.property_id
+                AND properties_rooms.is_available
+                AND properties_rooms.cost_dollars <= $6
+        )
+    )
+    AND (
+        $7::text = ''
+        OR available_from IS NULL
+        OR available_from <= $7::date
+    )
+    AND (
+        $8::integer = 0
+        OR $8 = ANY (lease_length_months)
+    )
+    AND (
+        NOT $9::boolean
+        OR utilities_included
+    )
+    AND (
+        NOT $10::boolean
+        OR pets_policy IN ('allowed', 'negotiable')
+    )
+    AND (
+        NOT $11::boolean
+        OR smoking_policy IN ('allowed', 'outside_only')
+    )
+    AND rooms_available >= $12
+    AND (
+        cardinality($13::text[]) = 0
+        OR (
+            SELECT
+                count(DISTINCT amenity_name)
+            FROM
+                properties_amenities
+            WHERE
+                properties_amenities.property_id = properties.property_id
+                AND properties_amenities.amenity_name = ANY ($13::text[])
+        ) >= CASE
+            WHEN $14::boolean THEN cardinality($13::text[])
+            ELSE 1
+        END
+    )
 ORDER BY
     CASE
         WHEN $3 <> '' THEN similarity (
@@ -250,13 +413,39 @@ OFFSET
 `
 
 type GetNextPagePropertiesParams struct {
-	Limit   int32
-	Offset  int32
-	Column3 interface{}
+	Limit          int32
+	Offset         int32
+	Column3        interface{}
+	Status         string
+	Column5        string
+	Column6        int64
+	Column7        string
+	Column8        int32
+	Column9        bool
+	Column10       bool
+	Column11       bool
+	RoomsAvailable int16
+	Column13       []string
+	Column14       bool
 }
 
 func (q *Queries) GetNextPageProperties(ctx context.Context, arg GetNextPagePropertiesParams) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, getNextPageProperties, arg.Limit, arg.Offset, arg.Column3)
+	rows, err := q.db.QueryContext(ctx, getNextPageProperties,
+		arg.Limit,
+		arg.Offset,
+		arg.Column3,
+		arg.Status,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+		arg.RoomsAvailable,
+		pq.Array(arg.Column13),
+		arg.Column14,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -280,7 +469,7 @@ func (q *Queries) GetNextPageProperties(ctx context.Context, arg GetNextPageProp
 
 const getProperty = `-- name: GetProperty :one
 SELECT
-    id, property_id, lister_user_id, name, description, address_1, address_2, city, state, zipcode, country, square_feet, num_bedrooms, num_toilets, num_showers_baths, cost_dollars, cost_cents, misc_note, created_at, updated_at
+    id, property_id, lister_user_id, name, description, address_1, address_2, city, state, zipcode, country, square_feet, num_bedrooms, num_toilets, num_showers_baths, cost_dollars, cost_cents, misc_note, created_at, updated_at, status, status_updated_at, price_type, deposit_dollars, deposit_cents, utilities_included, lease_length_months, available_from, rooms_available, pets_policy, smoking_policy, normalized_address, latitude, longitude, expires_at, expiry_reminder_sent_at
 FROM
     properties
 WHERE
@@ -311,37 +500,199 @@ func (q *Queries) GetProperty(ctx context.Context, propertyID string) (Property,
 		&i.MiscNote,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.StatusUpdatedAt,
+		&i.PriceType,
+		&i.DepositDollars,
+		&i.DepositCents,
+		&i.UtilitiesIncluded,
+		pq.Array(&i.LeaseLengthMonths),
+		&i.AvailableFrom,
+		&i.RoomsAvailable,
+		&i.PetsPolicy,
+		&i.SmokingPolicy,
+		&i.NormalizedAddress,
+		&i.Latitude,
+		&i.Longitude,
+		&i.ExpiresAt,
+		&i.ExpiryReminderSentAt,
 	)
 	return i, err
 }
 
-const getPropertyImages = `-- name: GetPropertyImages :many
+const getPropertyAddresses = `-- name: GetPropertyAddresses :many
 SELECT
-    id, property_id, order_num, file_name, mime_type, size, data, created_at
+    property_id,
+    address_1,
+    address_2,
+    city,
+    "state",
+    zipcode,
+    country,
+    normalized_address
+FROM
+    properties
+ORDER BY
+    id
+`
+
+type GetPropertyAddressesRow struct {
+	PropertyID        string
+	Address1          string
+	Address2          sql.NullString
+	City              string
+	State             string
+	Zipcode           string
+	Country           string
+	NormalizedAddress string
+}
+
+func (q *Queries) GetPropertyAddresses(ctx context.Context) ([]GetPropertyAddressesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPropertyAddresses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPropertyAddressesRow
+	for rows.Next() {
+		var i GetPropertyAddressesRow
+		if err := rows.Scan(
+			&i.PropertyID,
+			&i.Address1,
+			&i.Address2,
+			&i.City,
+			&i.State,
+			&i.Zipcode,
+			&i.Country,
+			&i.NormalizedAddress,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPropertyImage = `-- name: GetPropertyImage :one
+SELECT
+    properties_images.order_num,
+    properties_images.file_name,
+    properties_images.mime_type,
+    properties_images."size",
+    file_blobs."data"
+FROM
+    properties_images
+    JOIN file_blobs ON properties_images.content_hash = file_blobs.content_hash
+WHERE
+    properties_images.property_id = $1
+    AND properties_images.order_num = $2
+`
+
+type GetPropertyImageParams struct {
+	PropertyID string
+	OrderNum   int16
+}
+
+type GetPropertyImageRow struct {
+	OrderNum int16
+	FileName string
+	MimeType string
+	Size     int64
+	Data     []byte
+}
+
+func (q *Queries) GetPropertyImage(ctx context.Context, arg GetPropertyImageParams) (GetPropertyImageRow, error) {
+	row := q.db.QueryRowContext(ctx, getPropertyImage, arg.PropertyID, arg.OrderNum)
+	var i GetPropertyImageRow
+	err := row.Scan(
+		&i.OrderNum,
+		&i.FileName,
+		&i.MimeType,
+		&i.Size,
+		&i.Data,
+	)
+	return i, err
+}
+
+const getPropertyImageOrderNums = `-- name: GetPropertyImageOrderNums :many
+SELECT
+    order_num
 FROM
     properties_images
 WHERE
     property_id = $1
+ORDER BY
+    order_num
 `
 
-func (q *Queries) GetPropertyImages(ctx context.Context, propertyID string) ([]PropertiesImage, error) {
+func (q *Queries) GetPropertyImageOrderNums(ctx context.Context, propertyID string) ([]int16, error) {
+	rows, err := q.db.QueryContext(ctx, getPropertyImageOrderNums, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int16
+	for rows.Next() {
+		var order_num int16
+		if err := rows.Scan(&order_num); err != nil {
+			return nil, err
+		}
+		items = append(items, order_num)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPropertyImages = `-- name: GetPropertyImages :many
+SELECT
+    properties_images.order_num,
+    properties_images.file_name,
+    properties_images.mime_type,
+    properties_images."size",
+    file_blobs."data"
+FROM
+    properties_images
+    JOIN file_blobs ON properties_images.content_hash = file_blobs.content_hash
+WHERE
+    properties_images.property_id = $1
+ORDER BY
+    properties_images.order_num
+`
+
+type GetPropertyImagesRow struct {
+	OrderNum int16
+	FileName string
+	MimeType string
+	Size     int64
+	Data     []byte
+}
+
+func (q *Queries) GetPropertyImages(ctx context.Context, propertyID string) ([]GetPropertyImagesRow, error) {
 	rows, err := q.db.QueryContext(ctx, getPropertyImages, propertyID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []PropertiesImage
+	var items []GetPropertyImagesRow
 	for rows.Next() {
-		var i PropertiesImage
+		var i GetPropertyImagesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.PropertyID,
 			&i.OrderNum,
 			&i.FileName,
 			&i.MimeType,
 			&i.Size,
 			&i.Data,
-			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -356,6 +707,160 @@ func (q *Queries) GetPropertyImages(ctx context.Context, propertyID string) ([]P
 	return items, nil
 }
 
+const getRecommendationCandidates = `-- name: GetRecommendationCandidates :many
+SELECT
+    property_id,
+    price_type,
+    cost_dollars,
+    cost_cents,
+    square_feet,
+    num_bedrooms,
+    num_showers_baths,
+    city,
+    "state",
+    zipcode,
+    latitude,
+    longitude
+FROM
+    properties
+WHERE
+    status = $1
+    AND price_type = $2
+    AND NOT (property_id = ANY ($3::text[]))
+    AND lister_user_id <> $4
+ORDER BY
+    abs(cost_dollars - $5::bigint),
+    property_id
+LIMIT
+    $6
+`
+
+type GetRecommendationCandidatesParams struct {
+	Status       string
+	PriceType    string
+	Column3      []string
+	ListerUserID string
+	Column5      int64
+	Limit        int32
+}
+
+type GetRecommendationCandidatesRow struct {
+	PropertyID      string
+	PriceType       string
+	CostDollars     int64
+	CostCents       int16
+	SquareFeet      int32
+	NumBedrooms     int16
+	NumShowersBaths int16
+	City            string
+	State           string
+	Zipcode         string
+	Latitude        sql.NullFloat64
+	Longitude       sql.NullFloat64
+}
+
+// Properties of the given status and price type closest in price to the given cost,
+// leaving out the excluded properties and the properties of the excluded lister.
+func (q *Queries) GetRecommendationCandidates(ctx context.Context, arg GetRecommendationCandidatesParams) ([]GetRecommendationCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecommendationCandidates,
+		arg.Status,
+		arg.PriceType,
+		pq.Array(arg.Column3),
+		arg.ListerUserID,
+		arg.Column5,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecommendationCandidatesRow
+	for rows.Next() {
+		var i GetRecommendationCandidatesRow
+		if err := rows.Scan(
+			&i.PropertyID,
+			&i.PriceType,
+			&i.CostDollars,
+			&i.CostCents,
+			&i.SquareFeet,
+			&i.NumBedrooms,
+			&i.NumShowersBaths,
+			&i.City,
+			&i.State,
+			&i.Zipcode,
+			&i.Latitude,
+			&i.Longitude,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSimilarProperties = `-- name: GetSimilarProperties :many
+SELECT
+    property_id,
+    similarity (normalized_address, $1::text)::real AS similarity
+FROM
+    properties
+WHERE
+    property_id <> $2
+    AND (
+        normalized_address = $1
+        OR (
+            normalized_address % $1
+            AND similarity (normalized_address, $1) >= $3::real
+        )
+    )
+ORDER BY
+    similarity DESC
+LIMIT
+    5
+`
+
+type GetSimilarPropertiesParams struct {
+	Column1    string
+	PropertyID string
+	Column3    float32
+}
+
+type GetSimilarPropertiesRow struct {
+	PropertyID string
+	Similarity float32
+}
+
+// Properties whose normalized address is similar to the given one by trigram similarity,
+// most similar first.
+func (q *Queries) GetSimilarProperties(ctx context.Context, arg GetSimilarPropertiesParams) ([]GetSimilarPropertiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSimilarProperties, arg.Column1, arg.PropertyID, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSimilarPropertiesRow
+	for rows.Next() {
+		var i GetSimilarPropertiesRow
+		if err := rows.Scan(&i.PropertyID, &i.Similarity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserOwnedProperties = `-- name: GetUserOwnedProperties :many
 SELECT
     property_id
@@ -390,6 +895,58 @@ func (q *Queries) GetUserOwnedProperties(ctx context.Context, listerUserID strin
 	return items, nil
 }
 
+const remindExpiringProperties = `-- name: RemindExpiringProperties :many
+UPDATE properties
+SET
+    expiry_reminder_sent_at = CURRENT_TIMESTAMP
+WHERE
+    status = 'published'
+    AND expires_at <= $1
+    AND expiry_reminder_sent_at IS NULL
+RETURNING
+    property_id,
+    lister_user_id,
+    name,
+    expires_at
+`
+
+type RemindExpiringPropertiesRow struct {
+	PropertyID   string
+	ListerUserID string
+	Name         string
+	ExpiresAt    sql.NullTime
+}
+
+// Marks published properties expiring by the given time whose lister was not reminded yet
+// as reminded, returning them to remind their listers
+func (q *Queries) RemindExpiringProperties(ctx context.Context, expiresAt sql.NullTime) ([]RemindExpiringPropertiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, remindExpiringProperties, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RemindExpiringPropertiesRow
+	for rows.Next() {
+		var i RemindExpiringPropertiesRow
+		if err := rows.Scan(
+			&i.PropertyID,
+			&i.ListerUserID,
+			&i.Name,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const transferAllPropertiesToAnotherLister = `-- name: TransferAllPropertiesToAnotherLister :exec
 UPDATE properties
 SET
@@ -428,29 +985,53 @@ SET
     cost_cents = $15,
     misc_note = $16,
     lister_user_id = $17,
+    price_type = $18,
+    deposit_dollars = $19,
+    deposit_cents = $20,
+    utilities_included = $21,
+    lease_length_months = $22,
+    available_from = $23,
+    rooms_available = $24,
+    pets_policy = $25,
+    smoking_policy = $26,
+    normalized_address = $27,
+    latitude = $28,
+    longitude = $29,
     updated_at = CURRENT_TIMESTAMP
 WHERE
     property_id = $1
 `
 
 type UpdatePropertyDetailsParams struct {
-	PropertyID      string
-	Name            string
-	Description     sql.NullString
-	Address1        string
-	Address2        sql.NullString
-	City            string
-	State           string
-	Zipcode         string
-	Country         string
-	SquareFeet      int32
-	NumBedrooms     int16
-	NumToilets      int16
-	NumShowersBaths int16
-	CostDollars     int64
-	CostCents       int16
-	MiscNote        sql.NullString
-	ListerUserID    string
+	PropertyID        string
+	Name              string
+	Description       sql.NullString
+	Address1          string
+	Address2          sql.NullString
+	City              string
+	State             string
+	Zipcode           string
+	Country           string
+	SquareFeet        int32
+	NumBedrooms       int16
+	NumToilets        int16
+	NumShowersBaths   int16
+	CostDollars       int64
+	CostCents         int16
+	MiscNote          sql.NullString
+	ListerUserID      string
+	PriceType         string
+	DepositDollars    int64
+	DepositCents      int16
+	UtilitiesIncluded bool
+	LeaseLengthMonths []int32
+	AvailableFrom     sql.NullTime
+	RoomsAvailable    int16
+	PetsPolicy        string
+	SmokingPolicy     string
+	NormalizedAddress string
+	Latitude          sql.NullFloat64
+	Longitude         sql.NullFloat64
 }
 
 func (q *Queries) UpdatePropertyDetails(ctx context.Context, arg UpdatePropertyDetailsParams) error {
@@ -472,10 +1053,42 @@ func (q *Queries) UpdatePropertyDetails(ctx context.Context, arg UpdatePropertyD
 		arg.CostCents,
 		arg.MiscNote,
 		arg.ListerUserID,
+		arg.PriceType,
+		arg.DepositDollars,
+		arg.DepositCents,
+		arg.UtilitiesIncluded,
+		pq.Array(arg.LeaseLengthMonths),
+		arg.AvailableFrom,
+		arg.RoomsAvailable,
+		arg.PetsPolicy,
+		arg.SmokingPolicy,
+		arg.NormalizedAddress,
+		arg.Latitude,
+		arg.Longitude,
 	)
 	return err
 }
 
+const updatePropertyExpiry = `-- name: UpdatePropertyExpiry :exec
+UPDATE properties
+SET
+    expires_at = $2,
+    expiry_reminder_sent_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    property_id = $1
+`
+
+type UpdatePropertyExpiryParams struct {
+	PropertyID string
+	ExpiresAt  sql.NullTime
+}
+
+func (q *Queries) UpdatePropertyExpiry(ctx context.Context, arg UpdatePropertyExpiryParams) error {
+	_, err := q.db.ExecContext(ctx, updatePropertyExpiry, arg.PropertyID, arg.ExpiresAt)
+	return err
+}
+
 const updatePropertyLister = `-- name: UpdatePropertyLister :exec
 UPDATE properties
 SET
@@ -494,3 +1107,44 @@ func (q *Queries) UpdatePropertyLister(ctx context.Context, arg UpdatePropertyLi
 	_, err := q.db.ExecContext(ctx, updatePropertyLister, arg.PropertyID, arg.ListerUserID)
 	return err
 }
+
+const updatePropertyNormalizedAddress = `-- name: UpdatePropertyNormalizedAddress :exec
+UPDATE properties
+SET
+    normalized_address = $2
+WHERE
+    property_id = $1
+`
+
+type UpdatePropertyNormalizedAddressParams struct {
+	PropertyID        string
+	NormalizedAddress string
+}
+
+func (q *Queries) UpdatePropertyNormalizedAddress(ctx context.Context, arg UpdatePropertyNormalizedAddressParams) error {
+	_, err := q.db.ExecContext(ctx, updatePropertyNormalizedAddress, arg.PropertyID, arg.NormalizedAddress)
+	return err
+}
+
+const updatePropertyStatus = `-- name: UpdatePropertyStatus :exec
+UPDATE properties
+SET
+    status = $2,
+    status_updated_at = CURRENT_TIMESTAMP,
+    expires_at = $3,
+    expiry_reminder_sent_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    property_id = $1
+`
+
+type UpdatePropertyStatusParams struct {
+	PropertyID string
+	Status     string
+	ExpiresAt  sql.NullTime
+}
+
+func (q *Queries) UpdatePropertyStatus(ctx context.Context, arg UpdatePropertyStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updatePropertyStatus, arg.PropertyID, arg.Status, arg.ExpiresAt)
+	return err
+}
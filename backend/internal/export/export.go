@@ -0,0 +1,465 @@
+// Package export renders properties in formats understood by other listing sites and search
+// engines: a RESO Data Dictionary style JSON feed, schema.org JSON-LD and CSV.
+package export
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const FORMAT_RESO = "reso"
+const FORMAT_JSONLD = "jsonld"
+const FORMAT_CSV = "csv"
+
+const CONTENT_TYPE_RESO = "application/json"
+const CONTENT_TYPE_JSONLD = "application/ld+json"
+const CONTENT_TYPE_CSV = "text/csv"
+
+// Prices are stored as dollars and cents
+const CURRENCY = "USD"
+
+// Listing is a property along with the absolute urls it can be found at
+type Listing struct {
+	Details   database.PropertyDetails
+	URL       string   // page of the property on the frontend
+	ImageURLs []string // in display order
+	RoomCount int64    // rooms of the property listed as sub-listings, vacant or not
+}
+
+// -------------- RESO ------------------
+
+// RESO Data Dictionary StandardStatus of each listing status
+var resoStandardStatus = map[string]string{
+	config.PROPERTY_STATUS_DRAFT:     "Incomplete",
+	config.PROPERTY_STATUS_PUBLISHED: "Active",
+	config.PROPERTY_STATUS_PAUSED:    "Hold",
+	config.PROPERTY_STATUS_RENTED:    "Closed",
+	config.PROPERTY_STATUS_ARCHIVED:  "Withdrawn",
+	config.PROPERTY_STATUS_EXPIRED:   "Expired",
+}
+
+var resoPetsAllowed = map[string][]string{
+	"allowed":     {"Yes"},
+	"not_allowed": {"No"},
+	"negotiable":  {"Call"},
+}
+
+type RESOMedia struct {
+	MediaURL      string `json:"MediaURL"`
+	MediaCategory string `json:"MediaCategory"`
+	Order         int    `json:"Order"`
+}
+
+// RESOProperty is a property using the field names of the RESO Data Dictionary Property resource.
+// Fields the dictionary has no equivalent for are local fields prefixed with Coop_.
+type RESOProperty struct {
+	ListingKey            string      `json:"ListingKey"`
+	ListAgentKey          string      `json:"ListAgentKey"`
+	StandardStatus        string      `json:"StandardStatus"`
+	PropertyType          string      `json:"PropertyType"`
+	ListPrice             float64     `json:"ListPrice"`
+	LeaseAmountFrequency  string      `json:"LeaseAmountFrequency,omitempty"`
+	LeaseTerm             []string    `json:"LeaseTerm,omitempty"`
+	AvailabilityDate      string      `json:"AvailabilityDate,omitempty"`
+	RentIncludes          []string    `json:"RentIncludes,omitempty"`
+	PetsAllowed           []string    `json:"PetsAllowed,omitempty"`
+	UnparsedAddress       string      `json:"UnparsedAddress"`
+	UnitNumber            string      `json:"UnitNumber,omitempty"`
+	City                  string      `json:"City"`
+	StateOrProvince       string      `json:"StateOrProvince"`
+	PostalCode            string      `json:"PostalCode"`
+	Country               string      `json:"Country"`
+	Latitude              *float64    `json:"Latitude,omitempty"`
+	Longitude             *float64    `json:"Longitude,omitempty"`
+	LivingArea            int32       `json:"LivingArea"`
+	LivingAreaUnits       string      `json:"LivingAreaUnits"`
+	BedroomsTotal         int16       `json:"BedroomsTotal"`
+	BathroomsTotalInteger int16       `json:"BathroomsTotalInteger"`
+	PublicRemarks         string      `json:"PublicRemarks"`
+	ListingURL            string      `json:"ListingURL"`
+	Media                 []RESOMedia `json:"Media"`
+
+	Coop_Name            string   `json:"Coop_Name"`
+	Coop_SecurityDeposit float64  `json:"Coop_SecurityDeposit"`
+	Coop_RoomsAvailable  int16    `json:"Coop_RoomsAvailable"`
+	Coop_SmokingPolicy   string   `json:"Coop_SmokingPolicy,omitempty"`
+	Coop_Amenities       []string `json:"Coop_Amenities"`
+	Coop_NumToiletsTotal int16    `json:"Coop_NumToiletsTotal"`
+}
+
+// RESOFeed is a RESO Web API style response containing any number of properties
+type RESOFeed struct {
+	Context string         `json:"@odata.context"`
+	Value   []RESOProperty `json:"value"`
+}
+
+func ToRESO(listing Listing) RESOProperty {
+	details := listing.Details
+
+	property := RESOProperty{
+		ListingKey:            details.PropertyID,
+		ListAgentKey:          details.ListerUserID,
+		StandardStatus:        resoStandardStatus[details.Status],
+		PropertyType:          "Residential",
+		ListPrice:             price(details.Cost_dollars, details.Cost_cents),
+		AvailabilityDate:      details.Available_from,
+		PetsAllowed:           resoPetsAllowed[details.Pets_policy],
+		UnparsedAddress:       details.Address_1,
+		UnitNumber:            details.Address_2,
+		City:                  details.City,
+		StateOrProvince:       details.State,
+		PostalCode:            details.Zipcode,
+		Country:               details.Country,
+		Latitude:              details.Latitude,
+		Longitude:             details.Longitude,
+		LivingArea:            details.Square_feet,
+		LivingAreaUnits:       "Square Feet",
+		BedroomsTotal:         details.Num_bedrooms,
+		BathroomsTotalInteger: details.Num_showers_baths,
+		PublicRemarks:         details.Description,
+		ListingURL:            listing.URL,
+		Media:                 []RESOMedia{},
+
+		Coop_Name:            details.Name,
+		Coop_SecurityDeposit: price(details.Deposit_dollars, details.Deposit_cents),
+		Coop_RoomsAvailable:  details.Rooms_available,
+		Coop_SmokingPolicy:   details.Smoking_policy,
+		Coop_Amenities:       details.Amenities,
+		Coop_NumToiletsTotal: details.Num_toilets,
+	}
+	if property.Coop_Amenities == nil {
+		property.Coop_Amenities = []string{}
+	}
+
+	if details.Price_type == config.PROPERTY_PRICE_TYPE_MONTHLY {
+		property.PropertyType = "Residential Lease"
+		property.LeaseAmountFrequency = "Monthly"
+		for _, months := range details.Lease_length_months {
+			property.LeaseTerm = append(property.LeaseTerm, leaseTerm(months))
+		}
+		if details.Utilities_included {
+			property.RentIncludes = []string{"All Utilities"}
+		}
+	}
+
+	for i, imageURL := range listing.ImageURLs {
+		property.Media = append(property.Media, RESOMedia{
+			MediaURL:      imageURL,
+			MediaCategory: "Photo",
+			Order:         i + 1,
+		})
+	}
+
+	return property
+}
+
+func ToRESOFeed(listings []Listing) RESOFeed {
+	feed := RESOFeed{
+		Context: "$metadata#Property",
+		Value:   []RESOProperty{},
+	}
+	for _, listing := range listings {
+		feed.Value = append(feed.Value, ToRESO(listing))
+	}
+	return feed
+}
+
+func leaseTerm(months int32) string {
+	if months == 1 {
+		return "Month To Month"
+	}
+	return fmt.Sprintf("%d Months", months)
+}
+
+// -------------- JSON-LD ------------------
+
+const SCHEMA_ORG_CONTEXT = "https://schema.org"
+
+type JSONLDPostalAddress struct {
+	Type            string `json:"@type"`
+	StreetAddress   string `json:"streetAddress"`
+	AddressLocality string `json:"addressLocality"`
+	AddressRegion   string `json:"addressRegion"`
+	PostalCode      string `json:"postalCode"`
+	AddressCountry  string `json:"addressCountry"`
+}
+
+type JSONLDGeoCoordinates struct {
+	Type      string  `json:"@type"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type JSONLDQuantitativeValue struct {
+	Type     string `json:"@type"`
+	Value    int32  `json:"value"`
+	UnitCode string `json:"unitCode"`
+}
+
+type JSONLDAmenityFeature struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value bool   `json:"value"`
+}
+
+type JSONLDAccommodation struct {
+	Type                   string                  `json:"@type"`
+	Name                   string                  `json:"name"`
+	Description            string                  `json:"description,omitempty"`
+	URL                    string                  `json:"url,omitempty"`
+	Image                  []string                `json:"image"`
+	Address                JSONLDPostalAddress     `json:"address"`
+	Geo                    *JSONLDGeoCoordinates   `json:"geo,omitempty"`
+	FloorSize              JSONLDQuantitativeValue `json:"floorSize"`
+	NumberOfBedrooms       int16                   `json:"numberOfBedrooms"`
+	NumberOfBathroomsTotal int16                   `json:"numberOfBathroomsTotal"`
+	NumberOfRooms          int16                   `json:"numberOfRooms,omitempty"`
+	PetsAllowed            *bool                   `json:"petsAllowed,omitempty"`
+	AmenityFeature         []JSONLDAmenityFeature  `json:"amenityFeature,omitempty"`
+}
+
+type JSONLDPriceSpecification struct {
+	Type          string  `json:"@type"`
+	Price         float64 `json:"price"`
+	PriceCurrency string  `json:"priceCurrency"`
+	UnitCode      string  `json:"unitCode,omitempty"`
+}
+
+// JSONLDOffer is a schema.org Offer to lease out or sell the Accommodation it offers
+type JSONLDOffer struct {
+	Context            string                   `json:"@context,omitempty"`
+	Type               string                   `json:"@type"`
+	ID                 string                   `json:"@id,omitempty"`
+	URL                string                   `json:"url,omitempty"`
+	BusinessFunction   string                   `json:"businessFunction"`
+	Price              float64                  `json:"price"`
+	PriceCurrency      string                   `json:"priceCurrency"`
+	PriceSpecification JSONLDPriceSpecification `json:"priceSpecification"`
+	Availability       string                   `json:"availability"`
+	AvailabilityStarts string                   `json:"availabilityStarts,omitempty"`
+	ItemOffered        JSONLDAccommodation      `json:"itemOffered"`
+}
+
+type JSONLDGraph struct {
+	Context string        `json:"@context"`
+	Graph   []JSONLDOffer `json:"@graph"`
+}
+
+// schema.org ItemAvailability of each listing status
+var jsonldAvailability = map[string]string{
+	config.PROPERTY_STATUS_DRAFT:     "https://schema.org/PreOrder",
+	config.PROPERTY_STATUS_PUBLISHED: "https://schema.org/InStock",
+	config.PROPERTY_STATUS_PAUSED:    "https://schema.org/OutOfStock",
+	config.PROPERTY_STATUS_RENTED:    "https://schema.org/SoldOut",
+	config.PROPERTY_STATUS_ARCHIVED:  "https://schema.org/Discontinued",
+	config.PROPERTY_STATUS_EXPIRED:   "https://schema.org/Discontinued",
+}
+
+func ToJSONLD(listing Listing) JSONLDOffer {
+	offer := toJSONLDOffer(listing)
+	offer.Context = SCHEMA_ORG_CONTEXT
+	return offer
+}
+
+func ToJSONLDGraph(listings []Listing) JSONLDGraph {
+	graph := JSONLDGraph{
+		Context: SCHEMA_ORG_CONTEXT,
+		Graph:   []JSONLDOffer{},
+	}
+	for _, listing := range listings {
+		graph.Graph = append(graph.Graph, toJSONLDOffer(listing))
+	}
+	return graph
+}
+
+func toJSONLDOffer(listing Listing) JSONLDOffer {
+	details := listing.Details
+
+	accommodation := JSONLDAccommodation{
+		Type:        "Accommodation",
+		Name:        details.Name,
+		Description: details.Description,
+		URL:         listing.URL,
+		Image:       listing.ImageURLs,
+		Address: JSONLDPostalAddress{
+			Type:            "PostalAddress",
+			StreetAddress:   strings.TrimSpace(details.Address_1 + " " + details.Address_2),
+			AddressLocality: details.City,
+			AddressRegion:   details.State,
+			PostalCode:      details.Zipcode,
+			AddressCountry:  details.Country,
+		},
+		FloorSize: JSONLDQuantitativeValue{
+			Type:     "QuantitativeValue",
+			Value:    details.Square_feet,
+			UnitCode: "FTK", // UN/CEFACT code for square feet
+		},
+		NumberOfBedrooms:       details.Num_bedrooms,
+		NumberOfBathroomsTotal: details.Num_showers_baths,
+		NumberOfRooms:          int16(listing.RoomCount),
+	}
+	if accommodation.Image == nil {
+		accommodation.Image = []string{}
+	}
+	if details.Latitude != nil && details.Longitude != nil {
+		accommodation.Geo = &JSONLDGeoCoordinates{
+			Type:      "GeoCoordinates",
+			Latitude:  *details.Latitude,
+			Longitude: *details.Longitude,
+		}
+	}
+	switch details.Pets_policy {
+	case "allowed":
+		petsAllowed := true
+		accommodation.PetsAllowed = &petsAllowed
+	case "not_allowed":
+		petsAllowed := false
+		accommodation.PetsAllowed = &petsAllowed
+	}
+	for _, amenity := range details.Amenities {
+		accommodation.AmenityFeature = append(accommodation.AmenityFeature, JSONLDAmenityFeature{
+			Type:  "LocationFeatureSpecification",
+			Name:  amenity,
+			Value: true,
+		})
+	}
+
+	offer := JSONLDOffer{
+		Type:          "Offer",
+		ID:            listing.URL,
+		URL:           listing.URL,
+		Price:         price(details.Cost_dollars, details.Cost_cents),
+		PriceCurrency: CURRENCY,
+		PriceSpecification: JSONLDPriceSpecification{
+			Type:          "UnitPriceSpecification",
+			Price:         price(details.Cost_dollars, details.Cost_cents),
+			PriceCurrency: CURRENCY,
+		},
+		Availability:       jsonldAvailability[details.Status],
+		AvailabilityStarts: details.Available_from,
+		ItemOffered:        accommodation,
+	}
+	if details.Price_type == config.PROPERTY_PRICE_TYPE_SALE {
+		offer.BusinessFunction = "http://purl.org/goodrelations/v1#Sell"
+	} else {
+		offer.BusinessFunction = "http://purl.org/goodrelations/v1#LeaseOut"
+		offer.PriceSpecification.UnitCode = "MON" // UN/CEFACT code for month
+	}
+
+	return offer
+}
+
+// -------------- CSV ------------------
+
+// Columns of the CSV export, the same names as the json fields of database.PropertyDetails
+// so that a file can be edited and used as a bulk import (after removing url and imageUrls).
+var CSV_COLUMNS = []string{
+	"propertyId",
+	"listerUserId",
+	"name",
+	"description",
+	"address1",
+	"address2",
+	"city",
+	"state",
+	"zipcode",
+	"country",
+	"squareFeet",
+	"numBedrooms",
+	"numToilets",
+	"numShowersBaths",
+	"costDollars",
+	"costCents",
+	"miscNote",
+	"status",
+	"priceType",
+	"depositDollars",
+	"depositCents",
+	"utilitiesIncluded",
+	"leaseLengthMonths",
+	"availableFrom",
+	"roomsAvailable",
+	"petsPolicy",
+	"smokingPolicy",
+	"amenities",
+	"latitude",
+	"longitude",
+	"url",
+	"imageUrls",
+}
+
+func WriteCSV(w io.Writer, listings []Listing) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(CSV_COLUMNS)
+	if err != nil {
+		return err
+	}
+
+	for _, listing := range listings {
+		details := listing.Details
+
+		leaseLengths := []string{}
+		for _, months := range details.Lease_length_months {
+			leaseLengths = append(leaseLengths, strconv.Itoa(int(months)))
+		}
+
+		err = writer.Write([]string{
+			details.PropertyID,
+			details.ListerUserID,
+			details.Name,
+			details.Description,
+			details.Address_1,
+			details.Address_2,
+			details.City,
+			details.State,
+			details.Zipcode,
+			details.Country,
+			strconv.Itoa(int(details.Square_feet)),
+			strconv.Itoa(int(details.Num_bedrooms)),
+			strconv.Itoa(int(details.Num_toilets)),
+			strconv.Itoa(int(details.Num_showers_baths)),
+			strconv.FormatInt(details.Cost_dollars, 10),
+			strconv.Itoa(int(details.Cost_cents)),
+			details.Misc_note,
+			details.Status,
+			details.Price_type,
+			strconv.FormatInt(details.Deposit_dollars, 10),
+			strconv.Itoa(int(details.Deposit_cents)),
+			strconv.FormatBool(details.Utilities_included),
+			strings.Join(leaseLengths, config.CSV_LIST_SEPARATOR),
+			details.Available_from,
+			strconv.Itoa(int(details.Rooms_available)),
+			details.Pets_policy,
+			details.Smoking_policy,
+			strings.Join(details.Amenities, config.CSV_LIST_SEPARATOR),
+			formatCoordinate(details.Latitude),
+			formatCoordinate(details.Longitude),
+			listing.URL,
+			strings.Join(listing.ImageURLs, config.CSV_LIST_SEPARATOR),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatCoordinate formats an optional coordinate, empty if unknown
+func formatCoordinate(coordinate *float64) string {
+	if coordinate == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*coordinate, 'f', -1, 64)
+}
+
+func price(dollars int64, cents int16) float64 {
+	return float64(dollars) + float64(cents)/100
+}
@@ -5,6 +5,7 @@ import (
 	"backend/internal/auth"
 	"backend/internal/config"
 	"backend/internal/database"
+	"backend/internal/export"
 	"backend/internal/interfaces"
 	"backend/internal/utils"
 	"backend/internal/validation"
@@ -265,8 +266,9 @@ func (h *AccountHandler) GetAccountOwnedCommunitiesHandler(w http.ResponseWriter
 
 // GetAccountOwnedPropertiesHandler handles requests to return a user's properties where they are the lister.
 //
-// AUTHED GET .../account/properties
-// Lister is able to retrieve the properties that they are put on the site
+// AUTHED GET .../account/properties?format=csv
+// Lister is able to retrieve the properties that they are put on the site.
+// With format=csv, the full details of every property are downloaded as a CSV file instead of their ids.
 func (h *AccountHandler) GetAccountOwnedPropertiesHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user id
 	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
@@ -285,6 +287,37 @@ func (h *AccountHandler) GetAccountOwnedPropertiesHandler(w http.ResponseWriter,
 		propertyIDs = []string{}
 	}
 
+	if format := r.URL.Query().Get("format"); format != "" {
+		if format != export.FORMAT_CSV {
+			utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("export format \"%s\" is not one of our supported options", format))
+			return
+		}
+
+		properties := []database.PropertyDetails{}
+		for _, propertyID := range propertyIDs {
+			propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+			if err != nil {
+				utils.RespondWithError(w, http.StatusInternalServerError, err)
+				return
+			}
+			properties = append(properties, propertyDetails)
+		}
+		listings, err := exportListings(h.server, r, properties)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", export.CONTENT_TYPE_CSV)
+		w.Header().Set("Content-Disposition", `attachment; filename="properties.csv"`)
+		w.WriteHeader(http.StatusOK)
+		err = export.WriteCSV(w, listings)
+		if err != nil {
+			log.Printf("failed to write properties csv: %s", err)
+		}
+		return
+	}
+
 	// Return the propertyIDs
 	utils.RespondWithJSON(w, http.StatusOK, struct {
 		PropertyIDs []string `json:"propertyIDs"`
@@ -468,13 +501,32 @@ func (h *AccountHandler) CreateAccountSavedPropertyHandler(w http.ResponseWriter
 		return
 	}
 
+	// Only properties the user can already see can be saved, as saving a paused or rented
+	// property makes it visible to the user
+	propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+	canView, err := canViewProperty(h.server, config.GlobalConfig.ADMIN_USER_ID, userID, propertyDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !canView {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+
 	// Save property id to user saved properties
-	err := h.server.DB().CreateUserSavedProperty(userID, propertyID)
+	err = h.server.DB().CreateUserSavedProperty(userID, propertyID)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	recordPropertyEvent(h.server, r, propertyDetails, config.PROPERTY_ANALYTICS_EVENT_SAVE)
+
 	// Respond with created
 	w.WriteHeader(http.StatusCreated)
 }
@@ -212,7 +212,7 @@ func (h *AdminHandler) UpdateUserRoleHandler(w http.ResponseWriter, r *http.Requ
 				return
 			}
 
-			err = h.server.DB().TransferAllPropertiesToOtherUser(userID, userToTransferTo)
+			err = transferAllProperties(h.server, userID, userToTransferTo, h.adminUserID)
 			if err != nil {
 				utils.RespondWithError(w, http.StatusInternalServerError, err)
 				return
@@ -383,3 +383,157 @@ func (h *AdminHandler) GetTotalUsersCountHandler(w http.ResponseWriter, r *http.
 	}
 	utils.RespondWithJSON(w, http.StatusOK, count)
 }
+
+// POST .../admin/amenities
+// AUTHED
+// Adds an amenity to the catalog that properties can be tagged with.
+func (h *AdminHandler) AdminCreateAmenityHandler(w http.ResponseWriter, r *http.Request) {
+	var amenity database.Amenity
+	err := json.NewDecoder(r.Body).Decode(&amenity)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = validation.ValidateAmenity(amenity)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	catalog, err := database.AmenityCatalog(h.server.DB())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, exists := catalog[amenity.Name]; exists {
+		utils.RespondWithError(w, http.StatusConflict, fmt.Errorf("amenity \"%s\" already exists", amenity.Name))
+		return
+	}
+
+	err = h.server.DB().CreateAmenity(amenity)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// DELETE .../admin/amenities/{name}
+// AUTHED
+// Removes an amenity from the catalog, properties tagged with it lose the tag.
+func (h *AdminHandler) AdminDeleteAmenityHandler(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	err := h.server.DB().DeleteAmenity(name)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET .../admin/properties/duplicates?status=pending&limit=10&offset=0
+// AUTHED
+// Returns the queue of properties flagged as suspected duplicates of another listing.
+func (h *AdminHandler) AdminGetPropertyDuplicateReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	status := query.Get("status")
+	if status == "" {
+		status = config.PROPERTY_DUPLICATE_REVIEW_PENDING
+	}
+	if _, ok := config.PROPERTY_DUPLICATE_REVIEW_STATUS_OPTIONS[status]; !ok {
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("invalid duplicate review status \"%s\"", status))
+		return
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, errors.New("invalid limit string"))
+		return
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, errors.New("invalid offset string"))
+		return
+	}
+
+	reviews, err := h.server.DB().GetPropertyDuplicateReviews(status, int32(limit), int32(offset))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, reviews)
+}
+
+// PUT .../admin/properties/duplicates/{id}
+// AUTHED
+// Resolves a suspected duplicate. Confirming it archives the flagged property,
+// dismissing it leaves both listings as they are.
+func (h *AdminHandler) AdminResolvePropertyDuplicateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	reviewID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("invalid duplicate review id"))
+		return
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.Status != config.PROPERTY_DUPLICATE_REVIEW_CONFIRMED && body.Status != config.PROPERTY_DUPLICATE_REVIEW_DISMISSED {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("duplicate review can only be confirmed or dismissed"))
+		return
+	}
+
+	review, err := h.server.DB().GetPropertyDuplicateReview(int32(reviewID))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, err)
+		return
+	}
+	if review.Status != config.PROPERTY_DUPLICATE_REVIEW_PENDING {
+		utils.RespondWithError(w, http.StatusConflict, errors.New("duplicate review has already been resolved"))
+		return
+	}
+
+	if body.Status == config.PROPERTY_DUPLICATE_REVIEW_CONFIRMED {
+		propertyDetails, err := h.server.DB().GetPropertyDetails(review.PropertyID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if propertyDetails.Status != config.PROPERTY_STATUS_ARCHIVED {
+			err = h.server.DB().UpdatePropertyStatus(review.PropertyID, config.PROPERTY_STATUS_ARCHIVED)
+			if err != nil {
+				utils.RespondWithError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			err = h.server.DB().CreatePropertyHistory(review.PropertyID, h.adminUserID, []database.PropertyChange{{
+				Field:    database.PROPERTY_HISTORY_FIELD_STATUS,
+				OldValue: propertyDetails.Status,
+				NewValue: config.PROPERTY_STATUS_ARCHIVED,
+			}})
+			if err != nil {
+				utils.RespondWithError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+	}
+
+	err = h.server.DB().UpdatePropertyDuplicateReviewStatus(review.ID, body.Status, h.adminUserID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
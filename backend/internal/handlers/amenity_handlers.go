@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"backend/internal/interfaces"
+	"backend/internal/utils"
+	"net/http"
+)
+
+type AmenityHandler struct {
+	server interfaces.Server
+}
+
+func NewAmenityHandlers(s interfaces.Server) *AmenityHandler {
+	return &AmenityHandler{server: s}
+}
+
+// GET .../amenities
+// NO AUTH
+func (h *AmenityHandler) GetAmenitiesHandler(w http.ResponseWriter, r *http.Request) {
+	amenities, err := h.server.DB().GetAmenities()
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, amenities)
+}
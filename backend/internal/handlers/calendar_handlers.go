@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/database"
+	"backend/internal/ical"
+	"backend/internal/interfaces"
+	"backend/internal/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CalendarHandler struct {
+	server interfaces.Server
+}
+
+func NewCalendarHandlers(s interfaces.Server) *CalendarHandler {
+	return &CalendarHandler{server: s}
+}
+
+// userCalendar returns the calendar of everything upcoming for a user
+func userCalendar(s interfaces.Server, userID string) (ical.Calendar, error) {
+	events, err := userViewingEvents(s, userID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	return ical.Calendar{
+		Name:   "Coop",
+		Events: events,
+	}, nil
+}
+
+// GET .../calendar/{token}.ics
+// NO AUTH
+// The calendar feed of a user for calendar apps to subscribe to. Calendar apps cannot log in,
+// the secret token in the url identifies the user instead.
+func (h *CalendarHandler) GetCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.server.DB().GetUserIDByCalendarToken(chi.URLParam(r, "token"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("calendar not found"))
+		return
+	}
+
+	calendar, err := userCalendar(h.server, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.CONTENT_TYPE)
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar.Bytes())
+}
+
+// GetAccountViewingsHandler handles requests to return the upcoming viewings of a user,
+// the ones they booked and the booked viewings of the properties they list.
+//
+// AUTHED GET .../account/viewings
+func (h *AccountHandler) GetAccountViewingsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	slots, err := h.server.DB().GetUserUpcomingViewingSlots(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Viewings []database.ViewingSlot `json:"viewings"`
+	}{
+		Viewings: slots,
+	})
+}
+
+// CreateAccountCalendarTokenHandler handles requests to get the url of the user's calendar feed.
+// Every request creates a new secret url, the previous one stops working.
+//
+// AUTHED POST .../account/calendar
+func (h *AccountHandler) CreateAccountCalendarTokenHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	tokenBytes := make([]byte, 32)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	err = h.server.DB().SetUserCalendarToken(userID, token)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, struct {
+		URL string `json:"url"`
+	}{
+		URL: fmt.Sprintf("%s/api/v1/calendar/%s.ics", apiOrigin(r), token),
+	})
+}
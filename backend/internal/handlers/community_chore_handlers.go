@@ -0,0 +1,437 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/jobs"
+	"backend/internal/recurrence"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// getCommunityChore gets the chore of the request's "choreId" URL param, ensuring that it belongs
+// to the given community. It responds with an error and returns false otherwise.
+func (h *CommunityHandler) getCommunityChore(w http.ResponseWriter, r *http.Request, communityDetails database.CommunityDetails) (database.CommunityChore, bool) {
+	chore, err := h.server.DB().GetCommunityChore(chi.URLParam(r, "choreId"))
+	if err != nil || chore.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("chore not found"))
+		return database.CommunityChore{}, false
+	}
+	return chore, true
+}
+
+// getCommunityChoreTask gets a task of a chore, ensuring that it belongs to the given community.
+// It responds with an error and returns false otherwise.
+func (h *CommunityHandler) getCommunityChoreTask(w http.ResponseWriter, taskID string, communityDetails database.CommunityDetails) (database.CommunityChoreTask, bool) {
+	task, err := h.server.DB().GetCommunityChoreTask(taskID)
+	if err != nil || task.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("task not found"))
+		return database.CommunityChoreTask{}, false
+	}
+	return task, true
+}
+
+// parseChoreTasksSince parses the optional "since" query param of a request for tasks, a date in
+// YYYY-MM-DD format that defaults to today
+func parseChoreTasksSince(r *http.Request) (time.Time, error) {
+	sinceRaw := r.URL.Query().Get("since")
+	if sinceRaw == "" {
+		return recurrence.Day(time.Now()), nil
+	}
+	since, err := time.Parse("2006-01-02", sinceRaw)
+	if err != nil {
+		return time.Time{}, errors.New("since date must be in YYYY-MM-DD format")
+	}
+	return since, nil
+}
+
+// GET .../communities/{id}/chores
+// AUTHED
+// Returns the chores of the community with their rotations. Only members of the community can see its chores.
+func (h *CommunityHandler) GetCommunityChoresHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_CHORES) {
+		return
+	}
+
+	chores, err := h.server.DB().GetCommunityChores(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, chores)
+}
+
+// POST .../communities/{id}/chores
+// AUTHED
+// Creates a recurring chore in the community and schedules its first tasks. The chore recurs every
+// day, week or month unless an interval is given, starts today unless a date is given and rotates
+// between all the members of the community unless a rotation is given.
+func (h *CommunityHandler) CreateCommunityChoreHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_CHORES) {
+		return
+	}
+
+	var body struct {
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Frequency   string   `json:"frequency"`
+		Interval    int32    `json:"interval"`
+		StartsOn    string   `json:"startsOn"`
+		Rotation    []string `json:"rotation"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	members, err := h.server.DB().GetCommunityMembers(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	now := time.Now()
+	chore := database.CommunityChore{
+		ChoreID:         uuid.New().String(),
+		CommunityID:     communityDetails.CommunityID,
+		Title:           body.Title,
+		Description:     body.Description,
+		Frequency:       body.Frequency,
+		Interval:        body.Interval,
+		StartsOn:        body.StartsOn,
+		CreatedByUserID: authedUserID,
+		CreatedAt:       now,
+		Rotation:        body.Rotation,
+	}
+	if chore.Interval == 0 {
+		chore.Interval = 1
+	}
+	if chore.StartsOn == "" {
+		chore.StartsOn = now.Format("2006-01-02")
+	}
+	if len(chore.Rotation) == 0 {
+		chore.Rotation = []string{}
+		for _, member := range members {
+			chore.Rotation = append(chore.Rotation, member.UserID)
+		}
+	}
+	err = validation.ValidateCommunityChore(chore)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Chores can only rotate between members of the community
+	memberIDs := map[string]struct{}{}
+	for _, member := range members {
+		memberIDs[member.UserID] = struct{}{}
+	}
+	for _, userID := range chore.Rotation {
+		if _, ok := memberIDs[userID]; !ok {
+			utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("user %s is not a member of the community", userID))
+			return
+		}
+	}
+
+	err = h.server.DB().CreateCommunityChore(chore)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The background job schedules the tasks again if this fails
+	err = jobs.ScheduleCommunityChoreTasks(h.server.DB(), chore, now)
+	if err != nil {
+		log.Printf("failed to schedule the tasks of chore %s: %s", chore.ChoreID, err)
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, chore)
+}
+
+// DELETE .../communities/{id}/chores/{choreId}
+// AUTHED
+// Deletes a chore of the community with all its tasks. Chores are deleted by their creator or by
+// members allowed to manage chores.
+func (h *CommunityHandler) DeleteCommunityChoreHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	chore, ok := h.getCommunityChore(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_CHORES
+	if chore.CreatedByUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_CHORES
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	err := h.server.DB().DeleteCommunityChore(chore.ChoreID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET .../communities/{id}/chores/tasks?since=2024-06-01
+// AUTHED
+// Returns the tasks of the chores of the community due since a date, today by default, along with
+// the overdue tasks still to do, by due date.
+func (h *CommunityHandler) GetCommunityChoreTasksHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_CHORES) {
+		return
+	}
+
+	since, err := parseChoreTasksSince(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks, err := h.server.DB().GetCommunityChoreTasks(communityDetails.CommunityID, since)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, tasks)
+}
+
+// PUT .../communities/{id}/chores/tasks/{taskId}/done
+// AUTHED
+// Marks a task of a chore of the community done, or to do again. Tasks are marked by their assignee
+// or by members allowed to manage chores.
+func (h *CommunityHandler) UpdateCommunityChoreTaskDoneHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	task, ok := h.getCommunityChoreTask(w, chi.URLParam(r, "taskId"), communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_CHORES
+	if task.AssigneeUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_CHORES
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	var body struct {
+		Done bool `json:"done"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	task.CompletedAt = nil
+	task.CompletedByUserID = ""
+	if body.Done {
+		now := time.Now()
+		task.CompletedAt = &now
+		task.CompletedByUserID = authedUserID
+	}
+
+	var completedAt time.Time
+	if task.CompletedAt != nil {
+		completedAt = *task.CompletedAt
+	}
+	err = h.server.DB().UpdateCommunityChoreTaskCompletion(task.TaskID, task.CompletedByUserID, completedAt)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, task)
+}
+
+// POST .../communities/{id}/chores/tasks/{taskId}/swap
+// AUTHED
+// Swaps a task of a chore of the community with a task of another member, each member taking over
+// the task of the other. Expects the id of the other task. An assignee swaps their task with an
+// unassigned one right away, but a task is not pushed onto another member without their consent: the
+// swap is proposed to the other assignee, notified of it, and done once they swap back. Members
+// allowed to manage chores swap the tasks of two others right away and notify both assignees.
+func (h *CommunityHandler) SwapCommunityChoreTaskHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	task, ok := h.getCommunityChoreTask(w, chi.URLParam(r, "taskId"), communityDetails)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		TaskID string `json:"taskId"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	otherTask, ok := h.getCommunityChoreTask(w, body.TaskID, communityDetails)
+	if !ok {
+		return
+	}
+
+	// the task of the authed user comes first
+	if otherTask.AssigneeUserID == authedUserID {
+		task, otherTask = otherTask, task
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_CHORES
+	if task.AssigneeUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_CHORES
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	if task.AssigneeUserID == otherTask.AssigneeUserID {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("tasks must be assigned to different members to be swapped"))
+		return
+	}
+	if task.CompletedAt != nil || otherTask.CompletedAt != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("tasks already done cannot be swapped"))
+		return
+	}
+
+	// the other assignee consents once they proposed the swap themselves
+	if task.AssigneeUserID == authedUserID && otherTask.AssigneeUserID != "" && otherTask.SwapTaskID != task.TaskID {
+		proposed, err := h.server.DB().UpdateCommunityChoreTaskSwap(task.TaskID, otherTask.TaskID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if !proposed {
+			utils.RespondWithError(w, http.StatusBadRequest, errors.New("tasks already done cannot be swapped"))
+			return
+		}
+
+		notifyUser(h.server, otherTask.AssigneeUserID, config.NOTIFICATION_TYPE_COMMUNITY_CHORE_TASK_SWAP_PROPOSED,
+			fmt.Sprintf("You were asked to swap the task \"%s\" of %s due on %s for the task \"%s\" due on %s", otherTask.ChoreTitle, communityDetails.Name, otherTask.DueOn, task.ChoreTitle, task.DueOn), communityPageURL(communityDetails.CommunityID))
+
+		task.SwapTaskID = otherTask.TaskID
+		utils.RespondWithJSON(w, http.StatusAccepted, []database.CommunityChoreTask{task, otherTask})
+		return
+	}
+
+	swapped, err := h.server.DB().SwapCommunityChoreTaskAssignees(task.TaskID, otherTask.TaskID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !swapped {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("tasks already done cannot be swapped"))
+		return
+	}
+
+	task.AssigneeUserID, otherTask.AssigneeUserID = otherTask.AssigneeUserID, task.AssigneeUserID
+	task.SwapTaskID, otherTask.SwapTaskID = "", ""
+	for _, swappedTask := range []database.CommunityChoreTask{task, otherTask} {
+		if swappedTask.AssigneeUserID == "" || swappedTask.AssigneeUserID == authedUserID {
+			continue
+		}
+		notifyUser(h.server, swappedTask.AssigneeUserID, config.NOTIFICATION_TYPE_COMMUNITY_CHORE_TASK_SWAPPED,
+			fmt.Sprintf("You were swapped onto the task \"%s\" of %s due on %s", swappedTask.ChoreTitle, communityDetails.Name, swappedTask.DueOn), communityPageURL(communityDetails.CommunityID))
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, []database.CommunityChoreTask{task, otherTask})
+}
+
+// GetAccountChoreTasksHandler handles requests to return the tasks of chores assigned to a user across
+// their communities, due since a date, today by default, along with the overdue tasks still to do.
+//
+// AUTHED GET .../account/tasks?since=2024-06-01
+func (h *AccountHandler) GetAccountChoreTasksHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	since, err := parseChoreTasksSince(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tasks, err := h.server.DB().GetUserChoreTasks(userID, since)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, tasks)
+}
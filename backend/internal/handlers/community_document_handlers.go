@@ -0,0 +1,557 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// getCommunityRoleOfRequest gets the role in the community of the user of the request, empty if
+// the request is not authed or the user is not a member. It responds with an error and returns
+// false if the role cannot be looked up.
+func (h *CommunityHandler) getCommunityRoleOfRequest(w http.ResponseWriter, r *http.Request, communityID string) (string, bool) {
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	if userID == "" {
+		return "", true
+	}
+	role, err := h.server.DB().GetCommunityUserRole(communityID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return "", false
+	}
+	return role, true
+}
+
+// getVisibleCommunityDocument gets the document of the request's "documentId" URL param, ensuring
+// that it belongs to the given community and that the user of the request can see it. It returns
+// the role of the user in the community along with it, or responds with an error and returns false.
+func (h *CommunityHandler) getVisibleCommunityDocument(w http.ResponseWriter, r *http.Request, communityDetails database.CommunityDetails) (database.CommunityDocument, string, bool) {
+	document, err := h.server.DB().GetCommunityDocument(chi.URLParam(r, "documentId"))
+	if err != nil || document.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("document not found"))
+		return database.CommunityDocument{}, "", false
+	}
+
+	role, ok := h.getCommunityRoleOfRequest(w, r, communityDetails.CommunityID)
+	if !ok {
+		return database.CommunityDocument{}, "", false
+	}
+	if validation.ValidateCommunityDocumentAccess(document.Visibility, role) != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("document not found"))
+		return database.CommunityDocument{}, "", false
+	}
+
+	return document, role, true
+}
+
+// checkCommunityDocumentFolder ensures that the folder exists in the given community, a document or
+// folder without a folder is at the top level of the library. It responds with an error and returns
+// false otherwise.
+func (h *CommunityHandler) checkCommunityDocumentFolder(w http.ResponseWriter, communityID, folderID string) bool {
+	if folderID == "" {
+		return true
+	}
+	folder, err := h.server.DB().GetCommunityDocumentFolder(folderID)
+	if err != nil || folder.CommunityID != communityID {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("folder not found"))
+		return false
+	}
+	return true
+}
+
+// readCommunityDocumentForm parses a multipart form of a document upload and reads its "file",
+// with its type detected from its content rather than the one sent by the client.
+func readCommunityDocumentForm(w http.ResponseWriter, r *http.Request) (database.FileInternal, error) {
+	// Prepare reading body form by allocating max memory to read
+	MAX_SIZE := config.COMMUNITY_DOCUMENT_MAX_SIZE + 1<<20
+	r.Body = http.MaxBytesReader(w, r.Body, int64(MAX_SIZE))
+	err := r.ParseMultipartForm(int64(MAX_SIZE + 512))
+	if err != nil {
+		return database.FileInternal{}, err
+	}
+
+	fileDataRaw, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		return database.FileInternal{}, err
+	}
+	fileData, err := io.ReadAll(fileDataRaw)
+	fileDataRaw.Close()
+	if err != nil {
+		return database.FileInternal{}, err
+	}
+
+	return database.FileInternal{
+		Filename: fileHeader.Filename,
+		Mimetype: http.DetectContentType(fileData),
+		Size:     fileHeader.Size,
+		Data:     fileData,
+	}, nil
+}
+
+// GET .../communities/{id}/documents
+// OPTIONAL AUTH
+// Returns the documents of the community the user can see, with the folders of the library for
+// members. Public documents are listed to everyone.
+func (h *CommunityHandler) GetCommunityDocumentsHandler(w http.ResponseWriter, r *http.Request) {
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	role, ok := h.getCommunityRoleOfRequest(w, r, communityDetails.CommunityID)
+	if !ok {
+		return
+	}
+
+	documents, err := h.server.DB().GetCommunityDocuments(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	visibleDocuments := []database.CommunityDocument{}
+	for _, document := range documents {
+		if validation.ValidateCommunityDocumentAccess(document.Visibility, role) == nil {
+			visibleDocuments = append(visibleDocuments, document)
+		}
+	}
+
+	folders := []database.CommunityDocumentFolder{}
+	if validation.ValidateCommunityPermission(role, config.COMMUNITY_PERMISSION_DOCUMENTS) == nil {
+		folders, err = h.server.DB().GetCommunityDocumentFolders(communityDetails.CommunityID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Folders   []database.CommunityDocumentFolder `json:"folders"`
+		Documents []database.CommunityDocument       `json:"documents"`
+	}{
+		Folders:   folders,
+		Documents: visibleDocuments,
+	})
+}
+
+// POST .../communities/{id}/documents
+// AUTHED
+// Uploads a document to the library of the community. Expects a multipart form with a "details"
+// json of the title, folder id, visibility and an optional note, and the "file" of the document.
+// Only members allowed to manage documents can upload documents visible to moderators.
+func (h *CommunityHandler) CreateCommunityDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_DOCUMENTS) {
+		return
+	}
+
+	file, err := readCommunityDocumentForm(w, r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	var details struct {
+		Title      string `json:"title"`
+		FolderID   string `json:"folderId"`
+		Visibility string `json:"visibility"`
+		Note       string `json:"note"`
+	}
+	err = json.Unmarshal([]byte(r.FormValue("details")), &details)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	document := database.CommunityDocument{
+		DocumentID:      uuid.New().String(),
+		CommunityID:     communityDetails.CommunityID,
+		FolderID:        details.FolderID,
+		Title:           details.Title,
+		Visibility:      details.Visibility,
+		CreatedByUserID: authedUserID,
+		CreatedAt:       now,
+		Version:         1,
+		FileName:        file.Filename,
+		MimeType:        file.Mimetype,
+		Size:            file.Size,
+		UpdatedAt:       now,
+	}
+	if document.Visibility == "" {
+		document.Visibility = config.COMMUNITY_DOCUMENT_VISIBILITY_MEMBERS
+	}
+	version := database.CommunityDocumentVersion{
+		DocumentID:       document.DocumentID,
+		Version:          1,
+		FileName:         file.Filename,
+		MimeType:         file.Mimetype,
+		Size:             file.Size,
+		UploadedByUserID: authedUserID,
+		Note:             details.Note,
+		CreatedAt:        now,
+	}
+	err = validation.ValidateCommunityDocument(document)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	err = validation.ValidateCommunityDocumentVersion(version, file)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Members can only upload documents they are able to see
+	role, err := h.server.DB().GetCommunityUserRole(communityDetails.CommunityID, authedUserID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if validation.ValidateCommunityDocumentAccess(document.Visibility, role) != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+		return
+	}
+	if !h.checkCommunityDocumentFolder(w, communityDetails.CommunityID, document.FolderID) {
+		return
+	}
+
+	err = h.server.DB().CreateCommunityDocument(document, version, file)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, document)
+}
+
+// GET .../communities/{id}/documents/{documentId}
+// OPTIONAL AUTH
+// Returns a document of the community with its version history, latest first.
+func (h *CommunityHandler) GetCommunityDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	document, _, ok := h.getVisibleCommunityDocument(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	versions, err := h.server.DB().GetCommunityDocumentVersions(document.DocumentID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Document database.CommunityDocument          `json:"document"`
+		Versions []database.CommunityDocumentVersion `json:"versions"`
+	}{
+		Document: document,
+		Versions: versions,
+	})
+}
+
+// GET .../communities/{id}/documents/{documentId}/file?version=2
+// OPTIONAL AUTH
+// Downloads the file of a document of the community, of its latest version unless a version is given.
+func (h *CommunityHandler) GetCommunityDocumentFileHandler(w http.ResponseWriter, r *http.Request) {
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	document, _, ok := h.getVisibleCommunityDocument(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	version := document.Version
+	if versionRaw := r.URL.Query().Get("version"); versionRaw != "" {
+		versionInt64, err := strconv.ParseInt(versionRaw, 10, 32)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, errors.New("invalid document version"))
+			return
+		}
+		version = int32(versionInt64)
+	}
+
+	file, err := h.server.DB().GetCommunityDocumentFile(document.DocumentID, version)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("document version not found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", file.Mimetype)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Filename}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Data)
+}
+
+// POST .../communities/{id}/documents/{documentId}/versions
+// AUTHED
+// Uploads a new version of a document of the community, keeping the previous versions in its history.
+// Expects a multipart form with the "file" of the new version and an optional "note" on what changed.
+// New versions are uploaded by the uploader of the document or by members allowed to manage documents.
+func (h *CommunityHandler) CreateCommunityDocumentVersionHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	document, _, ok := h.getVisibleCommunityDocument(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_DOCUMENTS
+	if document.CreatedByUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_DOCUMENTS
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	file, err := readCommunityDocumentForm(w, r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	version := database.CommunityDocumentVersion{
+		DocumentID:       document.DocumentID,
+		FileName:         file.Filename,
+		MimeType:         file.Mimetype,
+		Size:             file.Size,
+		UploadedByUserID: authedUserID,
+		Note:             r.FormValue("note"),
+		CreatedAt:        time.Now(),
+	}
+	err = validation.ValidateCommunityDocumentVersion(version, file)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	version.Version, err = h.server.DB().CreateCommunityDocumentVersion(version, file)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, version)
+}
+
+// PUT .../communities/{id}/documents/{documentId}
+// AUTHED
+// Updates the title, folder and visibility of a document of the community. Documents are updated
+// by their uploader or by members allowed to manage documents.
+func (h *CommunityHandler) UpdateCommunityDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	document, role, ok := h.getVisibleCommunityDocument(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_DOCUMENTS
+	if document.CreatedByUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_DOCUMENTS
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	var body struct {
+		Title      string `json:"title"`
+		FolderID   string `json:"folderId"`
+		Visibility string `json:"visibility"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	document.Title = body.Title
+	document.FolderID = body.FolderID
+	document.Visibility = body.Visibility
+	err = validation.ValidateCommunityDocument(document)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	if validation.ValidateCommunityDocumentAccess(document.Visibility, role) != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+		return
+	}
+	if !h.checkCommunityDocumentFolder(w, communityDetails.CommunityID, document.FolderID) {
+		return
+	}
+
+	err = h.server.DB().UpdateCommunityDocument(document)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, document)
+}
+
+// DELETE .../communities/{id}/documents/{documentId}
+// AUTHED
+// Deletes a document of the community with all its versions. Documents are deleted by their uploader
+// or by members allowed to manage documents.
+func (h *CommunityHandler) DeleteCommunityDocumentHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	document, _, ok := h.getVisibleCommunityDocument(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_DOCUMENTS
+	if document.CreatedByUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_DOCUMENTS
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	err := h.server.DB().DeleteCommunityDocument(document.DocumentID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// POST .../communities/{id}/documents/folders
+// AUTHED
+// Adds a folder to the document library of the community, at the top level unless a parent folder is given.
+func (h *CommunityHandler) CreateCommunityDocumentFolderHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_DOCUMENTS) {
+		return
+	}
+
+	var body struct {
+		Name           string `json:"name"`
+		ParentFolderID string `json:"parentFolderId"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	folder := database.CommunityDocumentFolder{
+		FolderID:        uuid.New().String(),
+		CommunityID:     communityDetails.CommunityID,
+		ParentFolderID:  body.ParentFolderID,
+		Name:            body.Name,
+		CreatedByUserID: authedUserID,
+		CreatedAt:       time.Now(),
+	}
+	err = validation.ValidateCommunityDocumentFolder(folder)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !h.checkCommunityDocumentFolder(w, communityDetails.CommunityID, folder.ParentFolderID) {
+		return
+	}
+
+	err = h.server.DB().CreateCommunityDocumentFolder(folder)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, folder)
+}
+
+// DELETE .../communities/{id}/documents/folders/{folderId}
+// AUTHED
+// Deletes a folder of the document library of the community with its subfolders and all their
+// documents. Only members allowed to manage documents can delete folders.
+func (h *CommunityHandler) DeleteCommunityDocumentFolderHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MANAGE_DOCUMENTS) {
+		return
+	}
+
+	folder, err := h.server.DB().GetCommunityDocumentFolder(chi.URLParam(r, "folderId"))
+	if err != nil || folder.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("folder not found"))
+		return
+	}
+
+	err = h.server.DB().DeleteCommunityDocumentFolder(folder.FolderID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
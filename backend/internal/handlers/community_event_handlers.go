@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/ical"
+	"backend/internal/interfaces"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// communityEventCalendarEvent returns the calendar event of an event of a community
+func communityEventCalendarEvent(event database.CommunityEvent) ical.Event {
+	return ical.Event{
+		UID:         fmt.Sprintf("community-event-%s@coop", event.EventID),
+		Start:       event.StartTime,
+		End:         event.EndTime,
+		Summary:     event.Title,
+		Description: event.Description,
+		Location:    event.Location,
+		URL:         communityPageURL(event.CommunityID),
+		Status:      ical.STATUS_CONFIRMED,
+	}
+}
+
+// communityCalendar returns the calendar of the upcoming and recent events of a community
+func communityCalendar(s interfaces.Server, communityDetails database.CommunityDetails) (ical.Calendar, error) {
+	since := time.Now().AddDate(0, 0, -config.COMMUNITY_EVENTS_FEED_PAST_DAYS)
+	communityEvents, err := s.DB().GetCommunityEvents(communityDetails.CommunityID, since)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	events := []ical.Event{}
+	for _, event := range communityEvents {
+		events = append(events, communityEventCalendarEvent(event))
+	}
+	return ical.Calendar{
+		Name:   communityDetails.Name,
+		Events: events,
+	}, nil
+}
+
+// getCommunityEvent gets the event of the request's "eventId" URL param, ensuring that it belongs
+// to the given community. It responds with an error and returns false otherwise.
+func (h *CommunityHandler) getCommunityEvent(w http.ResponseWriter, r *http.Request, communityDetails database.CommunityDetails) (database.CommunityEvent, bool) {
+	event, err := h.server.DB().GetCommunityEvent(chi.URLParam(r, "eventId"))
+	if err != nil || event.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("event not found"))
+		return database.CommunityEvent{}, false
+	}
+	return event, true
+}
+
+// GET .../communities/{id}/events
+// AUTHED
+// Returns the upcoming events of the community, soonest first. Only members of the community can see its events.
+func (h *CommunityHandler) GetCommunityEventsHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EVENTS) {
+		return
+	}
+
+	events, err := h.server.DB().GetCommunityEvents(communityDetails.CommunityID, time.Now())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, events)
+}
+
+// POST .../communities/{id}/events
+// AUTHED
+// Organizes a new event of the community. An event can take place at one of the community's properties,
+// in which case its location defaults to the property's address.
+func (h *CommunityHandler) CreateCommunityEventHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EVENTS) {
+		return
+	}
+
+	var body struct {
+		PropertyID  string    `json:"propertyId"`
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		Location    string    `json:"location"`
+		StartTime   time.Time `json:"startTime"`
+		EndTime     time.Time `json:"endTime"`
+		Capacity    int16     `json:"capacity"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if body.PropertyID != "" {
+		communityPropertyIDs, err := h.server.DB().GetCommunityProperties(communityDetails.CommunityID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if !slices.Contains(communityPropertyIDs, body.PropertyID) {
+			utils.RespondWithError(w, http.StatusBadRequest, errors.New("property is not a property of the community"))
+			return
+		}
+		if body.Location == "" {
+			propertyDetails, err := h.server.DB().GetPropertyDetails(body.PropertyID)
+			if err != nil {
+				utils.RespondWithError(w, http.StatusInternalServerError, err)
+				return
+			}
+			body.Location = propertyAddress(propertyDetails)
+		}
+	}
+
+	now := time.Now()
+	event := database.CommunityEvent{
+		EventID:         uuid.New().String(),
+		CommunityID:     communityDetails.CommunityID,
+		PropertyID:      body.PropertyID,
+		CreatedByUserID: authedUserID,
+		Title:           body.Title,
+		Description:     body.Description,
+		Location:        body.Location,
+		StartTime:       body.StartTime,
+		EndTime:         body.EndTime,
+		Capacity:        body.Capacity,
+	}
+	err = validation.ValidateCommunityEvent(event, now)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateCommunityEvent(event)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, event)
+}
+
+// GET .../communities/{id}/events/{eventId}
+// AUTHED
+// Returns an event of the community with the RSVPs of its members.
+func (h *CommunityHandler) GetCommunityEventHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EVENTS) {
+		return
+	}
+	event, ok := h.getCommunityEvent(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	rsvps, err := h.server.DB().GetCommunityEventRSVPs(event.EventID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Event database.CommunityEvent       `json:"event"`
+		RSVPs []database.CommunityEventRSVP `json:"rsvps"`
+	}{
+		Event: event,
+		RSVPs: rsvps,
+	})
+}
+
+// DELETE .../communities/{id}/events/{eventId}
+// AUTHED
+// Cancels an event of the community, members who RSVPed yes or maybe to an upcoming event are notified.
+// Events are cancelled by their organizer or by members allowed to manage events.
+func (h *CommunityHandler) DeleteCommunityEventHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	event, ok := h.getCommunityEvent(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_EVENTS
+	if event.CreatedByUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_EVENTS
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	// Get the RSVPs before they are deleted along with the event
+	rsvps, err := h.server.DB().GetCommunityEventRSVPs(event.EventID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.server.DB().DeleteCommunityEvent(event.EventID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if event.EndTime.After(time.Now()) {
+		for _, rsvp := range rsvps {
+			if rsvp.UserID == authedUserID || rsvp.Response == config.COMMUNITY_EVENT_RSVP_NO {
+				continue
+			}
+			notifyUser(h.server, rsvp.UserID, config.NOTIFICATION_TYPE_COMMUNITY_EVENT_CANCELLED,
+				fmt.Sprintf("The event \"%s\" of %s on %s was cancelled", event.Title, communityDetails.Name, event.StartTime.Format(time.RFC1123)), communityPageURL(communityDetails.CommunityID))
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// PUT .../communities/{id}/events/{eventId}/rsvp
+// AUTHED
+// RSVPs yes, no or maybe to an event of the community, replacing a previous RSVP. RSVPs close once
+// the event starts and a yes is refused once the event is at capacity.
+func (h *CommunityHandler) SetCommunityEventRSVPHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EVENTS) {
+		return
+	}
+	event, ok := h.getCommunityEvent(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Response string `json:"response"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	if _, exists := config.COMMUNITY_EVENT_RSVP_OPTIONS[body.Response]; !exists {
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("response must be one of \"%s\", \"%s\" or \"%s\"", config.COMMUNITY_EVENT_RSVP_YES, config.COMMUNITY_EVENT_RSVP_NO, config.COMMUNITY_EVENT_RSVP_MAYBE))
+		return
+	}
+
+	set, err := h.server.DB().SetCommunityEventRSVP(event.EventID, authedUserID, body.Response)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !set {
+		if !event.StartTime.After(time.Now()) {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("event has already started"))
+		} else {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("event is at capacity"))
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DELETE .../communities/{id}/events/{eventId}/rsvp
+// AUTHED
+// Removes the user's RSVP to an event of the community.
+func (h *CommunityHandler) DeleteCommunityEventRSVPHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	event, ok := h.getCommunityEvent(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	err := h.server.DB().DeleteCommunityEventRSVP(event.EventID, authedUserID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET .../calendar/{token}/communities/{id}.ics
+// NO AUTH
+// The calendar feed of the events of a community for calendar apps to subscribe to. The secret
+// token of the user's own calendar feed identifies the user, who must be a member of the community.
+func (h *CalendarHandler) GetCommunityCalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.server.DB().GetUserIDByCalendarToken(chi.URLParam(r, "token"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("calendar not found"))
+		return
+	}
+
+	communityDetails, err := h.server.DB().GetCommunityDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("calendar not found"))
+		return
+	}
+	role, err := h.server.DB().GetCommunityUserRole(communityDetails.CommunityID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := validation.ValidateCommunityPermission(role, config.COMMUNITY_PERMISSION_EVENTS); err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("calendar not found"))
+		return
+	}
+
+	calendar, err := communityCalendar(h.server, communityDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.CONTENT_TYPE)
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar.Bytes())
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/export"
+	"backend/internal/ledger"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// getCommunityExpense gets the expense of the request's "expenseId" URL param, ensuring that it
+// belongs to the given community. It responds with an error and returns false otherwise.
+func (h *CommunityHandler) getCommunityExpense(w http.ResponseWriter, r *http.Request, communityDetails database.CommunityDetails) (database.CommunityExpense, bool) {
+	expense, err := h.server.DB().GetCommunityExpense(chi.URLParam(r, "expenseId"))
+	if err != nil || expense.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("expense not found"))
+		return database.CommunityExpense{}, false
+	}
+	return expense, true
+}
+
+// GET .../communities/{id}/expenses
+// AUTHED
+// Returns the shared expenses of the community with their splits, in the order they were spent.
+// Only members of the community can see its expenses.
+func (h *CommunityHandler) GetCommunityExpensesHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EXPENSES) {
+		return
+	}
+
+	expenses, err := h.server.DB().GetCommunityExpenses(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, expenses)
+}
+
+// POST .../communities/{id}/expenses
+// AUTHED
+// Records a shared expense of the community. The payer defaults to the member recording it and the
+// date to today. The payer and the members sharing the expense must be members of the community.
+func (h *CommunityHandler) CreateCommunityExpenseHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EXPENSES) {
+		return
+	}
+
+	var body struct {
+		PayerUserID string                           `json:"payerUserId"`
+		Description string                           `json:"description"`
+		Category    string                           `json:"category"`
+		AmountCents int64                            `json:"amountCents"`
+		SplitType   string                           `json:"splitType"`
+		SpentOn     string                           `json:"spentOn"`
+		Splits      []database.CommunityExpenseSplit `json:"splits"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	expense := database.CommunityExpense{
+		ExpenseID:       uuid.New().String(),
+		CommunityID:     communityDetails.CommunityID,
+		PayerUserID:     body.PayerUserID,
+		CreatedByUserID: authedUserID,
+		Description:     body.Description,
+		Category:        body.Category,
+		AmountCents:     body.AmountCents,
+		SplitType:       body.SplitType,
+		SpentOn:         body.SpentOn,
+		CreatedAt:       time.Now(),
+		Splits:          body.Splits,
+	}
+	if expense.PayerUserID == "" {
+		expense.PayerUserID = authedUserID
+	}
+	if expense.SpentOn == "" {
+		expense.SpentOn = expense.CreatedAt.Format("2006-01-02")
+	}
+	err = validation.ValidateCommunityExpense(expense)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	expense.Splits, err = ledger.Split(expense.AmountCents, expense.SplitType, expense.Splits)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Expenses can only be shared between members of the community
+	members, err := h.server.DB().GetCommunityMembers(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	memberIDs := map[string]struct{}{}
+	for _, member := range members {
+		memberIDs[member.UserID] = struct{}{}
+	}
+	if _, ok := memberIDs[expense.PayerUserID]; !ok {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("payer is not a member of the community"))
+		return
+	}
+	for _, split := range expense.Splits {
+		if _, ok := memberIDs[split.UserID]; !ok {
+			utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("user %s is not a member of the community", split.UserID))
+			return
+		}
+	}
+
+	err = h.server.DB().CreateCommunityExpense(expense)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, expense)
+}
+
+// DELETE .../communities/{id}/expenses/{expenseId}
+// AUTHED
+// Deletes a shared expense of the community. Expenses are deleted by the member who recorded or
+// paid them, or by members allowed to manage expenses.
+func (h *CommunityHandler) DeleteCommunityExpenseHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	expense, ok := h.getCommunityExpense(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_EXPENSES
+	if expense.CreatedByUserID == authedUserID || expense.PayerUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_EXPENSES
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	err := h.server.DB().DeleteCommunityExpense(expense.ExpenseID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET .../communities/{id}/expenses/balances
+// AUTHED
+// Returns the balance of every member sharing expenses in the community, the payments that
+// settle them up and the statement of the authed member with their running balance.
+func (h *CommunityHandler) GetCommunityExpenseBalancesHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EXPENSES) {
+		return
+	}
+
+	expenses, err := h.server.DB().GetCommunityExpenses(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	balances := ledger.Balances(expenses)
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Balances    []ledger.Balance        `json:"balances"`
+		Settlements []ledger.Settlement     `json:"settlements"`
+		Statement   []ledger.StatementEntry `json:"statement"`
+	}{
+		Balances:    balances,
+		Settlements: ledger.Settle(balances),
+		Statement:   ledger.Statement(expenses, authedUserID),
+	})
+}
+
+// GET .../communities/{id}/expenses/export
+// AUTHED
+// Downloads the shared expenses of the community as csv, with a row for each member sharing an expense.
+func (h *CommunityHandler) ExportCommunityExpensesHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EXPENSES) {
+		return
+	}
+
+	expenses, err := h.server.DB().GetCommunityExpenses(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.CONTENT_TYPE_CSV)
+	w.Header().Set("Content-Disposition", `attachment; filename="expenses.csv"`)
+	w.WriteHeader(http.StatusOK)
+	err = ledger.WriteCSV(w, expenses)
+	if err != nil {
+		log.Printf("failed to write expenses csv: %s", err)
+	}
+}
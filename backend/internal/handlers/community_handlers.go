@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"backend/internal/app_middleware"
+	"backend/internal/config"
 	"backend/internal/database"
 	"backend/internal/interfaces"
 	"backend/internal/utils"
@@ -12,6 +13,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
@@ -25,6 +28,21 @@ func NewCommunityHandlers(s interfaces.Server) *CommunityHandler {
 	return &CommunityHandler{server: s}
 }
 
+// checkCommunityPermission ensures that the user's role in the community grants the given permission.
+// It responds with an error and returns false otherwise.
+func (h *CommunityHandler) checkCommunityPermission(w http.ResponseWriter, communityID, userID, permission string) bool {
+	role, err := h.server.DB().GetCommunityUserRole(communityID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return false
+	}
+	if err := validation.ValidateCommunityPermission(role, permission); err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+		return false
+	}
+	return true
+}
+
 // GET .../communities/{id}
 // NO AUTH
 func (h *CommunityHandler) GetCommunityHandler(w http.ResponseWriter, r *http.Request) {
@@ -79,16 +97,70 @@ func (h *CommunityHandler) GetCommunityHandler(w http.ResponseWriter, r *http.Re
 	utils.RespondWithJSON(w, http.StatusOK, communityFull)
 }
 
+// parseCommunitySearchFilters reads the optional community search filters from the query parameters.
+func parseCommunitySearchFilters(query url.Values) (database.CommunitySearchFilters, error) {
+	filters := database.CommunitySearchFilters{
+		Name:        query.Get("communityFilterName"),
+		Description: query.Get("communityFilterDescription"),
+		City:        query.Get("communityFilterCity"),
+	}
+
+	// Searching near coordinates needs both of them and a radius
+	near := 0
+	for name, dest := range map[string]*float64{
+		"communityFilterLatitude":  &filters.Latitude,
+		"communityFilterLongitude": &filters.Longitude,
+		"communityFilterRadiusKm":  &filters.RadiusKm,
+	} {
+		if v := query.Get(name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return filters, fmt.Errorf("unable to parse %s: %s", name, v)
+			}
+			*dest = f
+			near++
+		}
+	}
+	if near != 0 && (near != 3 || filters.RadiusKm == 0) {
+		return filters, errors.New("communityFilterLatitude, communityFilterLongitude and a non zero communityFilterRadiusKm must be given together")
+	}
+
+	for name, dest := range map[string]*int64{
+		"communityFilterMinMembers": &filters.MinMembers,
+		"communityFilterMaxMembers": &filters.MaxMembers,
+	} {
+		if v := query.Get(name); v != "" {
+			count, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return filters, fmt.Errorf("unable to parse %s: %s", name, v)
+			}
+			*dest = count
+		}
+	}
+	if v := query.Get("communityFilterHasVacancy"); v != "" {
+		hasVacancy, err := strconv.ParseBool(v)
+		if err != nil {
+			return filters, fmt.Errorf("unable to parse communityFilterHasVacancy: %s", v)
+		}
+		filters.HasVacancy = hasVacancy
+	}
+	if v := query.Get("communityFilterInterests"); v != "" {
+		filters.Interests = utils.SplitCommaSeparated(v)
+	}
+
+	return filters, nil
+}
+
 // GET .../communities
 // NO AUTH
-// Public api to search through all communities
+// Public api to search through all communities. Communities can be filtered by the city of or distance
+// to their linked properties, their number of members, vacancy in their linked properties and the
+// interests of their members, and are ranked by relevance to the filters.
 func (h *CommunityHandler) GetCommunitiesHandler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 
 	pageStr := query.Get("page")
 	limitStr := query.Get("limit")
-	filterName := query.Get("communityFilterName")
-	filterDescription := query.Get("communityFilterDescription")
 
 	// Parse offset and limit
 	var offset int
@@ -108,8 +180,19 @@ func (h *CommunityHandler) GetCommunitiesHandler(w http.ResponseWriter, r *http.
 	// Calculate the correct offset
 	offset = offset * limit
 
+	filters, err := parseCommunitySearchFilters(query)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	err = validation.ValidateCommunitySearchFilters(filters)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
 	// Get communities with optional filters
-	communityIds, err := h.server.DB().GetNextPageCommunities(int32(limit), int32(offset), filterName, filterDescription)
+	communityIds, err := h.server.DB().GetNextPageCommunities(int32(limit), int32(offset), filters)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -217,7 +300,8 @@ func (h *CommunityHandler) CreateCommunitiesHandler(w http.ResponseWriter, r *ht
 
 // POST .../communities/users
 // AUTHED
-// adds a given userId to the given communityId, where the userid in the token must be an admin (currently only one admin per group allowed)
+// invites a given userId to the given communityId, where the userid in the token must be allowed to manage the community's members.
+// The user only joins the community once they accept the invite, see CreateCommunityInviteHandler.
 func (h *CommunityHandler) CreateCommunitiesUserHandler(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user's ID
 	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
@@ -251,30 +335,24 @@ func (h *CommunityHandler) CreateCommunitiesUserHandler(w http.ResponseWriter, r
 		return
 	}
 
-	// Validate userId in JWT matches the adminId of the given community
+	// Validate userId in JWT is allowed to manage the members of the given community
 	communityDetails, err := h.server.DB().GetCommunityDetails(data.CommunityID)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
-	if authedUserID != communityDetails.AdminUserID {
-		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("userId in JWT not the admin userId of community requested"))
-		return
-	}
-
-	// Add given user to given community
-	err = h.server.DB().CreateCommunityUser(data.CommunityID, data.UserID)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err)
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MANAGE_MEMBERS) {
 		return
 	}
 
-	w.WriteHeader(201)
+	// Invite given user to given community
+	h.inviteCommunityUser(w, communityDetails, authedUserID, data.UserID)
 }
 
 // POST .../communities/properties
 // AUTHED
-// adds a given propertyId to the given communityId, where userid in token must be an admin
+// requests to link a given propertyId to the given communityId, where userid in token must be allowed to link properties.
+// The property is only linked once its lister approves, see requestCommunityPropertyLink.
 func (h *CommunityHandler) CreateCommunitiesPropertyHandler(w http.ResponseWriter, r *http.Request) {
 	// Get authenticated user's ID
 	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
@@ -308,25 +386,18 @@ func (h *CommunityHandler) CreateCommunitiesPropertyHandler(w http.ResponseWrite
 		return
 	}
 
-	// Validate userId in JWT matches the adminId of the given community
+	// Validate userId in JWT is allowed to link properties to the given community
 	communityDetails, err := h.server.DB().GetCommunityDetails(data.CommunityID)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
-	if authedUserID != communityDetails.AdminUserID {
-		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("userId in JWT not the admin userId of community requested"))
-		return
-	}
-
-	// Add propertyID to this community
-	err = h.server.DB().CreateCommunityProperty(communityDetails.CommunityID, data.PropertyID)
-	if err != nil {
-		utils.RespondWithError(w, http.StatusInternalServerError, err)
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_LINK_PROPERTIES) {
 		return
 	}
 
-	w.WriteHeader(201)
+	// Request to link propertyID to this community
+	h.requestCommunityPropertyLink(w, communityDetails, authedUserID, data.PropertyID)
 }
 
 // PUT .../communities/{id}
@@ -376,9 +447,8 @@ func (h *CommunityHandler) UpdateCommunitiesHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Validate community's admin is the same id in token
-	if authedUserID != communityDetails.AdminUserID {
-		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("userId in token does not match adminUserId of community details to be created"))
+	// Validate user in token is allowed to edit the community's details
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_EDIT_DETAILS) {
 		return
 	}
 
@@ -445,6 +515,49 @@ func (h *CommunityHandler) UpdateCommunitiesHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
+	// Users can be removed from the community here, but only join it through membership requests and invites
+	currentMembers, err := h.server.DB().GetCommunityMembers(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	currentUserIDs := []string{}
+	for _, member := range currentMembers {
+		currentUserIDs = append(currentUserIDs, member.UserID)
+	}
+	for _, userID := range userIDs {
+		if !slices.Contains(currentUserIDs, userID) {
+			utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("user %s is not a member of the community, users join by requesting to or being invited", userID))
+			return
+		}
+	}
+
+	// Removing members requires the same permissions as removing them one by one
+	for _, member := range currentMembers {
+		if slices.Contains(userIDs, member.UserID) {
+			continue
+		}
+		if !h.checkCanRemoveCommunityUser(w, communityDetails.CommunityID, authedUserID, member.Role) {
+			return
+		}
+	}
+
+	// Properties can be unlinked from the community here, but only linked with the consent of their lister
+	currentPropertyIDs, err := h.server.DB().GetCommunityProperties(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, propertyID := range propertyIDs {
+		if !slices.Contains(currentPropertyIDs, propertyID) {
+			utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("property %s is not linked to the community, properties are linked by requesting their lister's approval", propertyID))
+			return
+		}
+	}
+	if len(propertyIDs) < len(currentPropertyIDs) && !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_LINK_PROPERTIES) {
+		return
+	}
+
 	// Validate community as a whole before committing changes to db
 	community := database.CommunityFullInternal{
 		CommunityDetails:    communityDetails,
@@ -510,9 +623,8 @@ func (h *CommunityHandler) DeleteCommunitiesHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Ensure user is an admin of the community
-	if communityDetails.AdminUserID != authedUserID {
-		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+	// Ensure user is allowed to delete the community
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_DELETE_COMMUNITY) {
 		return
 	}
 
@@ -547,22 +659,23 @@ func (h *CommunityHandler) DeleteCommunitiesUserHandler(w http.ResponseWriter, r
 	}
 
 	// Try to get community details to validate existence of community
-	communityDetails, err := h.server.DB().GetCommunityDetails(communityId)
+	_, err := h.server.DB().GetCommunityDetails(communityId)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	// Ensure authedUserID is the same as the adminId of community
-	if communityDetails.AdminUserID != authedUserID {
-		utils.RespondWithError(w, http.StatusUnauthorized, err)
+	// Ensure authedUserID is allowed to remove a member with the role of userId
+	role, err := h.server.DB().GetCommunityUserRole(communityId, userId)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
-
-	// Ensure userId is NOT adminId of community
-	// (Don't allow admin to remove themselves, they should delete the community instead)
-	if authedUserID == userId {
-		utils.RespondWithError(w, http.StatusBadRequest, errors.New("community admin cannot remove themselves from the community"))
+	if role == "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("user is not a member of the community"))
+		return
+	}
+	if !h.checkCanRemoveCommunityUser(w, communityId, authedUserID, role) {
 		return
 	}
 
@@ -594,6 +707,7 @@ func (h *CommunityHandler) DeleteCommunitiesPropertiesHandler(w http.ResponseWri
 	// Ensure presence of query parameters
 	if communityId == "" {
 		utils.RespondWithError(w, http.StatusBadRequest, errors.New("missing \"communityId\" query parameter"))
+		return
 	}
 	if propertyId == "" {
 		utils.RespondWithError(w, http.StatusBadRequest, errors.New("missing \"propertyId\" query parameter"))
@@ -601,15 +715,14 @@ func (h *CommunityHandler) DeleteCommunitiesPropertiesHandler(w http.ResponseWri
 	}
 
 	// Try to get community details to validate existence of community
-	communityDetails, err := h.server.DB().GetCommunityDetails(communityId)
+	_, err := h.server.DB().GetCommunityDetails(communityId)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	// Ensure authedUserID is the same as the adminId of community
-	if communityDetails.AdminUserID != authedUserID {
-		utils.RespondWithError(w, http.StatusUnauthorized, err)
+	// Ensure authedUserID is allowed to unlink properties of the community
+	if !h.checkCommunityPermission(w, communityId, authedUserID, config.COMMUNITY_PERMISSION_LINK_PROPERTIES) {
 		return
 	}
 
@@ -641,6 +754,7 @@ func (h *CommunityHandler) TransferCommunityOwnershipHandler(w http.ResponseWrit
 	// Ensure presence of query parameters
 	if communityId == "" {
 		utils.RespondWithError(w, http.StatusBadRequest, errors.New("missing \"communityId\" query parameter"))
+		return
 	}
 	if userId == "" {
 		utils.RespondWithError(w, http.StatusBadRequest, errors.New("missing \"userId\" query parameter"))
@@ -661,6 +775,11 @@ func (h *CommunityHandler) TransferCommunityOwnershipHandler(w http.ResponseWrit
 		return
 	}
 
+	// Ensure authedUserID owns the community
+	if !h.checkCommunityPermission(w, communityId, authedUserID, config.COMMUNITY_PERMISSION_TRANSFER_OWNERSHIP) {
+		return
+	}
+
 	// Ensure the other user Id exists
 	_, err = h.server.DB().GetPublicUserProfile(userId)
 	if err != nil {
@@ -668,30 +787,19 @@ func (h *CommunityHandler) TransferCommunityOwnershipHandler(w http.ResponseWrit
 		return
 	}
 
-	// Add the new admin user id to the community's list of users if not already a member
-	communityUserIDs, err := h.server.DB().GetCommunityUsers(communityId)
+	// The new admin must already be a member of the community, users are not added without their consent
+	isMember, err := h.server.DB().IsCommunityUser(communityId, userId)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, errors.New("couldn't get the community's members list"))
 		return
 	}
-	found := false
-	for _, id := range communityUserIDs {
-		if userId == id {
-			found = true
-			break
-		}
-	}
-	if !found {
-		communityUserIDs = append(communityUserIDs, userId)
-		err = h.server.DB().UpdateCommunityUsers(communityId, communityUserIDs)
-		if err != nil {
-			utils.RespondWithError(w, http.StatusInternalServerError, err)
-			return
-		}
+	if !isMember {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("user is not a member of the community"))
+		return
 	}
 
-	// Update the community's admin user id
-	err = h.server.DB().UpdateCommunityAdmin(communityId, userId)
+	// Make the user the community's admin and owner, the previous owner stays on as a moderator
+	err = h.server.DB().TransferCommunityOwnership(communityId, authedUserID, userId)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -700,3 +808,89 @@ func (h *CommunityHandler) TransferCommunityOwnershipHandler(w http.ResponseWrit
 	// Success, respond ok
 	w.WriteHeader(http.StatusOK)
 }
+
+// checkCanRemoveCommunityUser ensures that the user is allowed to remove a member with the given role from the community.
+// The owner cannot be removed, they transfer ownership or delete the community instead.
+// It responds with an error and returns false otherwise.
+func (h *CommunityHandler) checkCanRemoveCommunityUser(w http.ResponseWriter, communityID, userID, role string) bool {
+	switch role {
+	case config.COMMUNITY_ROLE_OWNER:
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("community owner cannot be removed from the community"))
+		return false
+	case config.COMMUNITY_ROLE_MODERATOR:
+		return h.checkCommunityPermission(w, communityID, userID, config.COMMUNITY_PERMISSION_MANAGE_ROLES)
+	default:
+		return h.checkCommunityPermission(w, communityID, userID, config.COMMUNITY_PERMISSION_MANAGE_MEMBERS)
+	}
+}
+
+// GET .../communities/{id}/members
+// NO AUTH
+// Returns the members of the community with their roles.
+func (h *CommunityHandler) GetCommunityMembersHandler(w http.ResponseWriter, r *http.Request) {
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.server.DB().GetCommunityMembers(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, members)
+}
+
+// PUT .../communities/{id}/members/{userId}/role
+// AUTHED
+// Changes the role of a member of the community to moderator or member.
+// Only members allowed to manage roles can change them, ownership is changed by transferring it.
+func (h *CommunityHandler) UpdateCommunityMemberRoleHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MANAGE_ROLES) {
+		return
+	}
+
+	var body struct {
+		Role string `json:"role"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	userID := chi.URLParam(r, "userId")
+	currentRole, err := h.server.DB().GetCommunityUserRole(communityDetails.CommunityID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if currentRole == "" {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("user is not a member of the community"))
+		return
+	}
+	err = validation.ValidateCommunityRoleChange(currentRole, body.Role)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().UpdateCommunityUserRole(communityDetails.CommunityID, userID, body.Role)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// communityPageURL returns the absolute url of a community's page on the frontend
+func communityPageURL(communityID string) string {
+	return fmt.Sprintf("%s/communities/%s", config.GlobalConfig.FRONTEND_ORIGIN, communityID)
+}
+
+// getCommunity gets the community of the request's "id" URL param. It responds with an error and returns false
+// if it does not exist.
+func (h *CommunityHandler) getCommunity(w http.ResponseWriter, r *http.Request) (database.CommunityDetails, bool) {
+	communityDetails, err := h.server.DB().GetCommunityDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("community not found"))
+		return database.CommunityDetails{}, false
+	}
+	return communityDetails, true
+}
+
+// getCommunityMembershipRequest gets the pending membership request of the request's "requestId" URL param,
+// ensuring that it belongs to the given community. It responds with an error and returns false otherwise.
+func (h *CommunityHandler) getCommunityMembershipRequest(w http.ResponseWriter, r *http.Request, communityDetails database.CommunityDetails) (database.CommunityMembershipRequest, bool) {
+	request, err := h.server.DB().GetCommunityMembershipRequest(chi.URLParam(r, "requestId"))
+	if err != nil || request.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("membership request not found"))
+		return database.CommunityMembershipRequest{}, false
+	}
+	return request, true
+}
+
+// checkCanJoinCommunity ensures that the user is not a member of the community yet and has no pending
+// request or invite to it. It responds with an error and returns false otherwise.
+func (h *CommunityHandler) checkCanJoinCommunity(w http.ResponseWriter, communityID, userID string) bool {
+	isMember, err := h.server.DB().IsCommunityUser(communityID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return false
+	}
+	if isMember {
+		utils.RespondWithError(w, http.StatusConflict, errors.New("user is already a member of the community"))
+		return false
+	}
+
+	pending, err := h.server.DB().GetCommunityMembershipRequestOfUser(communityID, userID)
+	if err == nil {
+		if pending.Kind == config.COMMUNITY_MEMBERSHIP_KIND_INVITE {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("user already has a pending invite to the community"))
+		} else {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("user already requested to join the community"))
+		}
+		return false
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+// inviteCommunityUser invites a user to a community on behalf of one of its members and responds with the invite
+func (h *CommunityHandler) inviteCommunityUser(w http.ResponseWriter, communityDetails database.CommunityDetails, inviterUserID, userID string) {
+	if _, err := h.server.DB().GetUserDetails(userID); err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("user does not exist"))
+		return
+	}
+	if !h.checkCanJoinCommunity(w, communityDetails.CommunityID, userID) {
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.AddDate(0, 0, config.COMMUNITY_INVITE_EXPIRY_DAYS)
+	invite := database.CommunityMembershipRequest{
+		RequestID:       uuid.New().String(),
+		CommunityID:     communityDetails.CommunityID,
+		UserID:          userID,
+		Kind:            config.COMMUNITY_MEMBERSHIP_KIND_INVITE,
+		InvitedByUserID: inviterUserID,
+		ExpiresAt:       &expiresAt,
+		CreatedAt:       now,
+	}
+	err := validation.ValidateCommunityMembershipRequest(invite, now)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateCommunityMembershipRequest(invite)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	notifyUser(h.server, userID, config.NOTIFICATION_TYPE_COMMUNITY_INVITED,
+		fmt.Sprintf("You are invited to join the community %s", communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+
+	utils.RespondWithJSON(w, http.StatusCreated, invite)
+}
+
+// POST .../communities/{id}/membership/requests
+// AUTHED
+// Requests to join the community, to be approved or declined by the community admin.
+func (h *CommunityHandler) CreateCommunityMembershipRequestHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCanJoinCommunity(w, communityDetails.CommunityID, userID) {
+		return
+	}
+
+	now := time.Now()
+	request := database.CommunityMembershipRequest{
+		RequestID:   uuid.New().String(),
+		CommunityID: communityDetails.CommunityID,
+		UserID:      userID,
+		Kind:        config.COMMUNITY_MEMBERSHIP_KIND_REQUEST,
+		CreatedAt:   now,
+	}
+	err := validation.ValidateCommunityMembershipRequest(request, now)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateCommunityMembershipRequest(request)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	notifyUser(h.server, communityDetails.AdminUserID, config.NOTIFICATION_TYPE_COMMUNITY_JOIN_REQUESTED,
+		fmt.Sprintf("A user requested to join your community %s", communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+
+	utils.RespondWithJSON(w, http.StatusCreated, request)
+}
+
+// POST .../communities/{id}/membership/invites
+// AUTHED
+// Invites a user to the community, to be accepted or declined by the user before the invite expires.
+// Only members allowed to manage the community's members can invite users.
+func (h *CommunityHandler) CreateCommunityInviteHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MANAGE_MEMBERS) {
+		return
+	}
+
+	var body struct {
+		UserID string `json:"userId"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.inviteCommunityUser(w, communityDetails, authedUserID, body.UserID)
+}
+
+// GET .../communities/{id}/membership
+// AUTHED
+// Returns the pending membership requests and invites of the community, oldest first.
+// Only members allowed to manage the community's members can see them.
+func (h *CommunityHandler) GetCommunityMembershipRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MANAGE_MEMBERS) {
+		return
+	}
+
+	requests, err := h.server.DB().GetCommunityMembershipRequests(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, requests)
+}
+
+// PUT .../communities/{id}/membership/{requestId}
+// AUTHED
+// Accepts or declines a pending membership. Requests to join are decided by members allowed to manage
+// the community's members, invites by the invited user. Accepting adds the user to the community,
+// responding with a conflict if they are a member already.
+func (h *CommunityHandler) DecideCommunityMembershipRequestHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	request, ok := h.getCommunityMembershipRequest(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	// The side that did not start the membership decides on it and the other side is notified
+	notifiedUserID := request.UserID
+	if request.Kind == config.COMMUNITY_MEMBERSHIP_KIND_INVITE {
+		if authedUserID != request.UserID {
+			utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+			return
+		}
+		notifiedUserID = request.InvitedByUserID
+		if notifiedUserID == "" {
+			notifiedUserID = communityDetails.AdminUserID
+		}
+	} else if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MANAGE_MEMBERS) {
+		return
+	}
+
+	var body struct {
+		Decision string `json:"decision"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch body.Decision {
+	case config.COMMUNITY_MEMBERSHIP_DECISION_ACCEPT:
+		err = h.server.DB().AcceptCommunityMembershipRequest(request)
+		if errors.Is(err, database.ErrAlreadyCommunityMember) {
+			utils.RespondWithError(w, http.StatusConflict, err)
+			return
+		}
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		notifyUser(h.server, notifiedUserID, config.NOTIFICATION_TYPE_COMMUNITY_MEMBERSHIP_ACCEPTED,
+			fmt.Sprintf("The %s to join the community %s was accepted", request.Kind, communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+	case config.COMMUNITY_MEMBERSHIP_DECISION_DECLINE:
+		err = h.server.DB().DeleteCommunityMembershipRequest(request.RequestID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		notifyUser(h.server, notifiedUserID, config.NOTIFICATION_TYPE_COMMUNITY_MEMBERSHIP_DECLINED,
+			fmt.Sprintf("The %s to join the community %s was declined", request.Kind, communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+	default:
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("decision must be either \"%s\" or \"%s\"", config.COMMUNITY_MEMBERSHIP_DECISION_ACCEPT, config.COMMUNITY_MEMBERSHIP_DECISION_DECLINE))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DELETE .../communities/{id}/membership/{requestId}
+// AUTHED
+// Withdraws a pending membership. Requests to join are withdrawn by the requesting user,
+// invites by members allowed to manage the community's members.
+func (h *CommunityHandler) DeleteCommunityMembershipRequestHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	request, ok := h.getCommunityMembershipRequest(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	if request.Kind == config.COMMUNITY_MEMBERSHIP_KIND_INVITE {
+		if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MANAGE_MEMBERS) {
+			return
+		}
+	} else if authedUserID != request.UserID {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+		return
+	}
+
+	err := h.server.DB().DeleteCommunityMembershipRequest(request.RequestID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetAccountCommunityMembershipRequestsHandler handles requests to return the pending community
+// memberships of the user, most recent first. Sent are the user's requests to join and the invites
+// of the communities they manage the members of, received are the invites to the user and the
+// requests to join the communities they manage the members of.
+//
+// AUTHED GET .../account/communities/membership
+func (h *AccountHandler) GetAccountCommunityMembershipRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	requests, err := h.server.DB().GetUserCommunityMembershipRequests(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	sent := []database.CommunityMembershipRequest{}
+	received := []database.CommunityMembershipRequest{}
+	for _, request := range requests {
+		isOwnRequest := request.Kind == config.COMMUNITY_MEMBERSHIP_KIND_REQUEST && request.UserID == userID
+		isInviteOfCommunity := request.Kind == config.COMMUNITY_MEMBERSHIP_KIND_INVITE && request.UserID != userID
+		if isOwnRequest || isInviteOfCommunity {
+			sent = append(sent, request)
+		} else {
+			received = append(received, request)
+		}
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Sent     []database.CommunityMembershipRequest `json:"sent"`
+		Received []database.CommunityMembershipRequest `json:"received"`
+	}{
+		Sent:     sent,
+		Received: received,
+	})
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// getCommunityPoll gets the poll of the request's "pollId" URL param, ensuring that it belongs
+// to the given community. It responds with an error and returns false otherwise.
+func (h *CommunityHandler) getCommunityPoll(w http.ResponseWriter, r *http.Request, communityDetails database.CommunityDetails) (database.CommunityPoll, bool) {
+	poll, err := h.server.DB().GetCommunityPoll(chi.URLParam(r, "pollId"))
+	if err != nil || poll.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("poll not found"))
+		return database.CommunityPoll{}, false
+	}
+	return poll, true
+}
+
+// respondWithCommunityPoll responds with a poll, whether the user voted on it and its results
+// if the user can see them yet
+func (h *CommunityHandler) respondWithCommunityPoll(w http.ResponseWriter, status int, poll database.CommunityPoll, userID string) {
+	hasVoted, err := h.server.DB().CheckIsCommunityPollVoter(poll.PollID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var results []database.CommunityPollResult
+	if validation.ValidateCommunityPollResultsAccess(poll, hasVoted, time.Now()) == nil {
+		results, err = h.server.DB().GetCommunityPollResults(poll)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	utils.RespondWithJSON(w, status, struct {
+		Poll     database.CommunityPoll         `json:"poll"`
+		HasVoted bool                           `json:"hasVoted"`
+		Results  []database.CommunityPollResult `json:"results"` // null until the user can see them
+	}{
+		Poll:     poll,
+		HasVoted: hasVoted,
+		Results:  results,
+	})
+}
+
+// GET .../communities/{id}/polls
+// AUTHED
+// Returns the polls of the community, newest first. Only members of the community can see its polls.
+func (h *CommunityHandler) GetCommunityPollsHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POLLS) {
+		return
+	}
+
+	polls, err := h.server.DB().GetCommunityPolls(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, polls)
+}
+
+// POST .../communities/{id}/polls
+// AUTHED
+// Creates a poll in the community. The poll opens right away unless an opening time is given and
+// its results are shown after voting unless they are to be shown after it closes.
+func (h *CommunityHandler) CreateCommunityPollHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POLLS) {
+		return
+	}
+
+	var body struct {
+		Question          string    `json:"question"`
+		MultipleChoice    bool      `json:"multipleChoice"`
+		Anonymous         bool      `json:"anonymous"`
+		ResultsVisibility string    `json:"resultsVisibility"`
+		OpensAt           time.Time `json:"opensAt"`
+		ClosesAt          time.Time `json:"closesAt"`
+		Options           []string  `json:"options"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	poll := database.CommunityPoll{
+		PollID:            uuid.New().String(),
+		CommunityID:       communityDetails.CommunityID,
+		CreatedByUserID:   authedUserID,
+		Question:          body.Question,
+		MultipleChoice:    body.MultipleChoice,
+		Anonymous:         body.Anonymous,
+		ResultsVisibility: body.ResultsVisibility,
+		OpensAt:           body.OpensAt,
+		ClosesAt:          body.ClosesAt,
+		CreatedAt:         now,
+		Options:           []database.CommunityPollOption{},
+	}
+	if poll.ResultsVisibility == "" {
+		poll.ResultsVisibility = config.COMMUNITY_POLL_RESULTS_AFTER_VOTE
+	}
+	if poll.OpensAt.IsZero() {
+		poll.OpensAt = now
+	}
+	for _, label := range body.Options {
+		poll.Options = append(poll.Options, database.CommunityPollOption{
+			OptionID: uuid.New().String(),
+			Label:    label,
+		})
+	}
+	err = validation.ValidateCommunityPoll(poll, now)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateCommunityPoll(poll)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, poll)
+}
+
+// GET .../communities/{id}/polls/{pollId}
+// AUTHED
+// Returns a poll of the community with whether the user voted on it. The results are included
+// once the poll closes or, if the poll shows them after voting, once the user voted.
+func (h *CommunityHandler) GetCommunityPollHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POLLS) {
+		return
+	}
+	poll, ok := h.getCommunityPoll(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	h.respondWithCommunityPoll(w, http.StatusOK, poll, authedUserID)
+}
+
+// POST .../communities/{id}/polls/{pollId}/votes
+// AUTHED
+// Votes on an open poll of the community. A member votes only once per poll, for a single option
+// unless the poll is multiple choice. Responds with the poll as it is seen after voting.
+func (h *CommunityHandler) VoteCommunityPollHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POLLS) {
+		return
+	}
+	poll, ok := h.getCommunityPoll(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		OptionIDs []string `json:"optionIds"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = validation.ValidateCommunityPollBallot(poll, body.OptionIDs, time.Now())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	hasVoted, err := h.server.DB().CheckIsCommunityPollVoter(poll.PollID, authedUserID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if hasVoted {
+		utils.RespondWithError(w, http.StatusConflict, errors.New("you already voted on this poll"))
+		return
+	}
+
+	cast, err := h.server.DB().CastCommunityPollBallot(poll.PollID, authedUserID, body.OptionIDs)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !cast {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("poll is not open"))
+		return
+	}
+
+	poll.VoterCount++
+	h.respondWithCommunityPoll(w, http.StatusCreated, poll, authedUserID)
+}
+
+// DELETE .../communities/{id}/polls/{pollId}
+// AUTHED
+// Deletes a poll of the community with its votes. Polls are deleted by their creator or by members
+// allowed to manage polls.
+func (h *CommunityHandler) DeleteCommunityPollHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	poll, ok := h.getCommunityPoll(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MANAGE_POLLS
+	if poll.CreatedByUserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_POLLS
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	err := h.server.DB().DeleteCommunityPoll(poll.PollID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
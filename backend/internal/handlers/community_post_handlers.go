@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// parseCommunityPostsPage parses the "page" and "limit" query params of a page of posts
+// into the limit and offset of the page.
+func parseCommunityPostsPage(r *http.Request) (int32, int32, error) {
+	query := r.URL.Query()
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 0 {
+		return 0, 0, fmt.Errorf("unable to parse page: %s", query.Get("page"))
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit < 1 || limit > config.COMMUNITY_POSTS_MAX_LIMIT {
+		return 0, 0, fmt.Errorf("limit must be a number between 1 and %d", config.COMMUNITY_POSTS_MAX_LIMIT)
+	}
+
+	return int32(limit), int32(page * limit), nil
+}
+
+// getCommunityPost gets the post of the request's "postId" URL param, ensuring that it belongs
+// to the given community. It responds with an error and returns false otherwise.
+func (h *CommunityHandler) getCommunityPost(w http.ResponseWriter, r *http.Request, communityDetails database.CommunityDetails) (database.CommunityPost, bool) {
+	post, err := h.server.DB().GetCommunityPost(chi.URLParam(r, "postId"))
+	if err != nil || post.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("post not found"))
+		return database.CommunityPost{}, false
+	}
+	return post, true
+}
+
+// GET .../communities/{id}/posts?page=0&limit=20
+// AUTHED
+// Returns a page of the threads of the community, pinned first and then most recently active first.
+// Only members of the community can see its posts.
+func (h *CommunityHandler) GetCommunityThreadsHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POST) {
+		return
+	}
+
+	limit, offset, err := parseCommunityPostsPage(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	threads, err := h.server.DB().GetCommunityThreads(communityDetails.CommunityID, limit, offset)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, threads)
+}
+
+// POST .../communities/{id}/posts
+// AUTHED
+// Starts a new thread on the discussion board of the community.
+func (h *CommunityHandler) CreateCommunityThreadHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POST) {
+		return
+	}
+
+	var body struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	thread := database.CommunityPost{
+		PostID:         uuid.New().String(),
+		CommunityID:    communityDetails.CommunityID,
+		UserID:         authedUserID,
+		Title:          body.Title,
+		Body:           body.Body,
+		LastActivityAt: now,
+		CreatedAt:      now,
+	}
+	err = validation.ValidateCommunityPost(thread)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateCommunityPost(thread)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, thread)
+}
+
+// GET .../communities/{id}/posts/{postId}?page=0&limit=20
+// AUTHED
+// Returns a thread of the community with a page of its replies, oldest first.
+func (h *CommunityHandler) GetCommunityThreadHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POST) {
+		return
+	}
+	thread, ok := h.getCommunityPost(w, r, communityDetails)
+	if !ok {
+		return
+	}
+	if thread.ThreadID != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("post is a reply, not a thread"))
+		return
+	}
+
+	limit, offset, err := parseCommunityPostsPage(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	replies, err := h.server.DB().GetCommunityThreadReplies(thread.PostID, limit, offset)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Thread  database.CommunityPost   `json:"thread"`
+		Replies []database.CommunityPost `json:"replies"`
+	}{
+		Thread:  thread,
+		Replies: replies,
+	})
+}
+
+// POST .../communities/{id}/posts/{postId}/replies
+// AUTHED
+// Replies to a thread of the community. The author of the thread is notified.
+func (h *CommunityHandler) CreateCommunityReplyHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_POST) {
+		return
+	}
+	thread, ok := h.getCommunityPost(w, r, communityDetails)
+	if !ok {
+		return
+	}
+	if thread.ThreadID != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("cannot reply to a reply, reply to its thread instead"))
+		return
+	}
+
+	var body struct {
+		Body string `json:"body"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	now := time.Now()
+	reply := database.CommunityPost{
+		PostID:         uuid.New().String(),
+		CommunityID:    communityDetails.CommunityID,
+		ThreadID:       thread.PostID,
+		UserID:         authedUserID,
+		Body:           body.Body,
+		LastActivityAt: now,
+		CreatedAt:      now,
+	}
+	err = validation.ValidateCommunityPost(reply)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateCommunityPost(reply)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if thread.UserID != authedUserID {
+		notifyUser(h.server, thread.UserID, config.NOTIFICATION_TYPE_COMMUNITY_POST_REPLIED,
+			fmt.Sprintf("There is a new reply to your thread \"%s\" in %s", thread.Title, communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, reply)
+}
+
+// PUT .../communities/{id}/posts/{postId}/pin
+// AUTHED
+// Pins or unpins a thread of the community. Only members allowed to moderate posts can pin threads.
+func (h *CommunityHandler) UpdateCommunityThreadPinnedHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_MODERATE_POSTS) {
+		return
+	}
+	thread, ok := h.getCommunityPost(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Pinned bool `json:"pinned"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Only the pinned flag changes, the rest of the thread was validated when it was posted
+	if thread.ThreadID != "" {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("a reply cannot be pinned"))
+		return
+	}
+
+	err = h.server.DB().UpdateCommunityPostPinned(thread.PostID, body.Pinned)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DELETE .../communities/{id}/posts/{postId}
+// AUTHED
+// Deletes a post of the community, deleting a thread deletes its replies. Posts are deleted by their
+// author or by members allowed to moderate posts.
+func (h *CommunityHandler) DeleteCommunityPostHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	post, ok := h.getCommunityPost(w, r, communityDetails)
+	if !ok {
+		return
+	}
+
+	permission := config.COMMUNITY_PERMISSION_MODERATE_POSTS
+	if post.UserID == authedUserID {
+		permission = config.COMMUNITY_PERMISSION_POST
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, permission) {
+		return
+	}
+
+	err := h.server.DB().DeleteCommunityPost(post.PostID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// requestCommunityPropertyLink requests to link a property to a community on behalf of one of its members
+// and responds with the request. The property's lister approves or rejects the request, unless they are the
+// requesting member themselves, in which case the property is linked right away.
+func (h *CommunityHandler) requestCommunityPropertyLink(w http.ResponseWriter, communityDetails database.CommunityDetails, requesterUserID, propertyID string) {
+	propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("property does not exist"))
+		return
+	}
+
+	communityPropertyIDs, err := h.server.DB().GetCommunityProperties(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if slices.Contains(communityPropertyIDs, propertyID) {
+		utils.RespondWithError(w, http.StatusConflict, errors.New("property is already linked to the community"))
+		return
+	}
+	_, err = h.server.DB().GetCommunityPropertyLinkRequestOfProperty(communityDetails.CommunityID, propertyID)
+	if err == nil {
+		utils.RespondWithError(w, http.StatusConflict, errors.New("there is already a pending request to link the property"))
+		return
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Listers do not need to approve linking their own property
+	if propertyDetails.ListerUserID == requesterUserID {
+		err = h.server.DB().CreateCommunityProperty(communityDetails.CommunityID, propertyID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	request := database.CommunityPropertyLinkRequest{
+		RequestID:         uuid.New().String(),
+		CommunityID:       communityDetails.CommunityID,
+		PropertyID:        propertyID,
+		RequestedByUserID: requesterUserID,
+		CreatedAt:         time.Now(),
+	}
+	err = validation.ValidateCommunityPropertyLinkRequest(request)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateCommunityPropertyLinkRequest(request)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	notifyUser(h.server, propertyDetails.ListerUserID, config.NOTIFICATION_TYPE_COMMUNITY_PROPERTY_LINK_REQUESTED,
+		fmt.Sprintf("The community %s requested to link your property %s", communityDetails.Name, propertyDetails.Name), communityPageURL(communityDetails.CommunityID))
+
+	utils.RespondWithJSON(w, http.StatusCreated, request)
+}
+
+// getListerProperty gets the property of the request's "id" URL param, ensuring that it is listed
+// by the given user. It responds with an error and returns false otherwise.
+func (h *AccountHandler) getListerProperty(w http.ResponseWriter, r *http.Request, userID string) (database.PropertyDetails, bool) {
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil || propertyDetails.ListerUserID != userID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return database.PropertyDetails{}, false
+	}
+	return propertyDetails, true
+}
+
+// GET .../communities/{id}/properties/requests
+// AUTHED
+// Returns the pending requests of the community to link properties, oldest first.
+// Only members allowed to link properties can see them.
+func (h *CommunityHandler) GetCommunityPropertyLinkRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_LINK_PROPERTIES) {
+		return
+	}
+
+	requests, err := h.server.DB().GetCommunityPropertyLinkRequests(communityDetails.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, requests)
+}
+
+// DELETE .../communities/{id}/properties/requests/{requestId}
+// AUTHED
+// Withdraws a pending request of the community to link a property.
+// Only members allowed to link properties can withdraw requests.
+func (h *CommunityHandler) DeleteCommunityPropertyLinkRequestHandler(w http.ResponseWriter, r *http.Request) {
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	communityDetails, ok := h.getCommunity(w, r)
+	if !ok {
+		return
+	}
+	if !h.checkCommunityPermission(w, communityDetails.CommunityID, authedUserID, config.COMMUNITY_PERMISSION_LINK_PROPERTIES) {
+		return
+	}
+
+	request, err := h.server.DB().GetCommunityPropertyLinkRequest(chi.URLParam(r, "requestId"))
+	if err != nil || request.CommunityID != communityDetails.CommunityID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("link request not found"))
+		return
+	}
+
+	err = h.server.DB().DeleteCommunityPropertyLinkRequest(request.RequestID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetAccountCommunityPropertyLinkRequestsHandler handles requests to return the pending requests
+// of communities to link the properties listed by the user, most recent first.
+//
+// AUTHED GET .../account/properties/communities/requests
+func (h *AccountHandler) GetAccountCommunityPropertyLinkRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	requests, err := h.server.DB().GetListerCommunityPropertyLinkRequests(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, requests)
+}
+
+// DecideAccountCommunityPropertyLinkRequestHandler handles requests of a lister to approve or reject
+// a request of a community to link one of their properties. Approving links the property to the community.
+//
+// AUTHED PUT .../account/properties/communities/requests/{requestId}
+func (h *AccountHandler) DecideAccountCommunityPropertyLinkRequestHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	request, err := h.server.DB().GetCommunityPropertyLinkRequest(chi.URLParam(r, "requestId"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("link request not found"))
+		return
+	}
+	propertyDetails, err := h.server.DB().GetPropertyDetails(request.PropertyID)
+	if err != nil || propertyDetails.ListerUserID != userID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("link request not found"))
+		return
+	}
+	communityDetails, err := h.server.DB().GetCommunityDetails(request.CommunityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var body struct {
+		Decision string `json:"decision"`
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The member who requested the link is notified of the decision, or the community admin if they are gone
+	notifiedUserID := request.RequestedByUserID
+	if notifiedUserID == "" {
+		notifiedUserID = communityDetails.AdminUserID
+	}
+
+	switch body.Decision {
+	case config.COMMUNITY_PROPERTY_LINK_DECISION_APPROVE:
+		err = h.server.DB().AcceptCommunityPropertyLinkRequest(request)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		notifyUser(h.server, notifiedUserID, config.NOTIFICATION_TYPE_COMMUNITY_PROPERTY_LINK_APPROVED,
+			fmt.Sprintf("The property %s was linked to the community %s", propertyDetails.Name, communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+	case config.COMMUNITY_PROPERTY_LINK_DECISION_REJECT:
+		err = h.server.DB().DeleteCommunityPropertyLinkRequest(request.RequestID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		notifyUser(h.server, notifiedUserID, config.NOTIFICATION_TYPE_COMMUNITY_PROPERTY_LINK_REJECTED,
+			fmt.Sprintf("The lister of %s rejected linking it to the community %s", propertyDetails.Name, communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+	default:
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("decision must be either \"%s\" or \"%s\"", config.COMMUNITY_PROPERTY_LINK_DECISION_APPROVE, config.COMMUNITY_PROPERTY_LINK_DECISION_REJECT))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetAccountPropertyCommunitiesHandler handles requests to return the ids of the communities
+// one of the lister's properties is linked to.
+//
+// AUTHED GET .../account/properties/{id}/communities
+func (h *AccountHandler) GetAccountPropertyCommunitiesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, ok := h.getListerProperty(w, r, userID)
+	if !ok {
+		return
+	}
+
+	communityIDs, err := h.server.DB().GetPropertyCommunities(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		CommunityIDs []string `json:"communityIDs"`
+	}{
+		CommunityIDs: communityIDs,
+	})
+}
+
+// DeleteAccountPropertyCommunityHandler handles requests of a lister to detach one of their properties
+// from a community it is linked to. The community admin is notified.
+//
+// AUTHED DELETE .../account/properties/{id}/communities/{communityId}
+func (h *AccountHandler) DeleteAccountPropertyCommunityHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, ok := h.getListerProperty(w, r, userID)
+	if !ok {
+		return
+	}
+
+	communityIDs, err := h.server.DB().GetPropertyCommunities(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	communityID := chi.URLParam(r, "communityId")
+	if !slices.Contains(communityIDs, communityID) {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property is not linked to the community"))
+		return
+	}
+	communityDetails, err := h.server.DB().GetCommunityDetails(communityID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.server.DB().DeleteCommunityProperty(communityID, propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if communityDetails.AdminUserID != userID {
+		notifyUser(h.server, communityDetails.AdminUserID, config.NOTIFICATION_TYPE_COMMUNITY_PROPERTY_DETACHED,
+			fmt.Sprintf("The lister of %s detached it from your community %s", propertyDetails.Name, communityDetails.Name), communityPageURL(communityDetails.CommunityID))
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/database"
+	"backend/internal/interfaces"
+	"backend/internal/utils"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// notifyUser sends an in-app notification to a user. Notifications accompany an action that
+// already succeeded, so failing to send one is logged instead of failing the request.
+func notifyUser(s interfaces.Server, userID, notificationType, message, link string) {
+	err := s.DB().CreateNotification(userID, notificationType, message, link)
+	if err != nil {
+		log.Printf("failed to send %s notification: %s", notificationType, err)
+	}
+}
+
+// GetAccountNotificationsHandler handles requests to return the notifications of a user, most recent first.
+//
+// AUTHED GET .../account/notifications?limit=10&offset=0
+func (h *AccountHandler) GetAccountNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, errors.New("invalid limit string"))
+		return
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, errors.New("invalid offset string"))
+		return
+	}
+
+	notifications, err := h.server.DB().GetNotifications(userID, int32(limit), int32(offset))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	unreadCount, err := h.server.DB().CountUnreadNotifications(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		Notifications []database.Notification `json:"notifications"`
+		UnreadCount   int64                   `json:"unreadCount"`
+	}{
+		Notifications: notifications,
+		UnreadCount:   unreadCount,
+	})
+}
+
+// UpdateAccountNotificationReadHandler handles requests to mark a notification of a user as read.
+//
+// AUTHED PUT .../account/notifications/{id}/read
+func (h *AccountHandler) UpdateAccountNotificationReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	notificationID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("invalid notification id"))
+		return
+	}
+
+	err = h.server.DB().MarkNotificationRead(userID, int32(notificationID))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UpdateAccountNotificationsReadHandler handles requests to mark all notifications of a user as read.
+//
+// AUTHED PUT .../account/notifications/read
+func (h *AccountHandler) UpdateAccountNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	err := h.server.DB().MarkAllNotificationsRead(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"backend/internal/analytics"
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/interfaces"
+	"backend/internal/utils"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// clientIP returns the ip address of the client. In production the server is behind the proxy and
+// it is X-Real-IP, which the proxy sets to the address it was connected from. Otherwise the header
+// could be sent by the client itself and the address of the connection is used. X-Forwarded-For is
+// never used as the proxy appends to the value sent by the client, whose first entries can then be
+// anything.
+func clientIP(r *http.Request) string {
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" && config.GlobalConfig.IS_PROD {
+		return realIP
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// recordPropertyEvent records a view or save of a property for the lister's analytics. Signed in
+// viewers are identified by their user id, anonymous viewers by their ip address and user agent.
+// The lister's own activity is not counted. Analytics accompany a request that already succeeded,
+// so failing to record an event is logged instead of failing the request.
+func recordPropertyEvent(s interfaces.Server, r *http.Request, propertyDetails database.PropertyDetails, eventType string) {
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	if userID != "" && userID == propertyDetails.ListerUserID {
+		return
+	}
+
+	viewer := "user:" + userID
+	if userID == "" {
+		viewer = fmt.Sprintf("anonymous:%s|%s", clientIP(r), r.UserAgent())
+	}
+
+	err := s.DB().RecordPropertyEvent(propertyDetails.PropertyID, eventType, viewer)
+	if err != nil {
+		log.Printf("failed to record property %s event: %s", eventType, err)
+	}
+}
+
+// parseAnalyticsRange reads the optional "days" query parameter and returns the range of days
+// ending today the analytics are for.
+func parseAnalyticsRange(r *http.Request) (time.Time, time.Time, error) {
+	days := config.PROPERTY_ANALYTICS_DEFAULT_DAYS
+	if daysRaw := r.URL.Query().Get("days"); daysRaw != "" {
+		var err error
+		days, err = strconv.Atoi(daysRaw)
+		if err != nil || days < 1 || days > config.PROPERTY_ANALYTICS_MAX_DAYS {
+			return time.Time{}, time.Time{}, fmt.Errorf("days must be between 1 and %d", config.PROPERTY_ANALYTICS_MAX_DAYS)
+		}
+	}
+	from, to := analytics.Range(time.Now().UTC(), days)
+	return from, to, nil
+}
+
+// GetAccountPropertiesAnalyticsHandler handles requests to return the views and saves of all of the
+// lister's properties over the last days, in total, per day and per property.
+//
+// AUTHED GET .../account/properties/analytics?days=30
+func (h *AccountHandler) GetAccountPropertiesAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	from, to, err := parseAnalyticsRange(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	propertyIDs, err := h.server.DB().GetListerOwnedProperties(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	summaries := []analytics.PropertySummary{}
+	for _, propertyID := range propertyIDs {
+		propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		days, err := h.server.DB().GetPropertyAnalytics(propertyID, from)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		summaries = append(summaries, analytics.Summarize(propertyDetails, from, to, days))
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, analytics.NewPortfolio(from, to, summaries))
+}
+
+// GetAccountPropertyAnalyticsHandler handles requests to return the daily views and saves of one
+// of the lister's properties over the last days.
+//
+// AUTHED GET .../account/properties/analytics/{id}?days=30
+func (h *AccountHandler) GetAccountPropertyAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	from, to, err := parseAnalyticsRange(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil || propertyDetails.ListerUserID != userID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+
+	days, err := h.server.DB().GetPropertyAnalytics(propertyDetails.PropertyID, from)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, analytics.Summarize(propertyDetails, from, to, days))
+}
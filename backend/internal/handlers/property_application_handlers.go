@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// rentalApplicationFull is a rental application together with its supporting documents
+type rentalApplicationFull struct {
+	database.RentalApplication
+	Documents []database.OrderedFileExternal `json:"documents"`
+}
+
+// isRentalApplicant returns whether the user is the applicant or one of the co-applicants
+func isRentalApplicant(application database.RentalApplication, userID string) bool {
+	return application.ApplicantUserID == userID || slices.Contains(application.CoApplicantUserIDs, userID)
+}
+
+// notifyRentalApplicants sends a notification to the applicant and all co-applicants
+func (h *PropertyHandler) notifyRentalApplicants(application database.RentalApplication, notificationType, message string) {
+	for _, userID := range append([]string{application.ApplicantUserID}, application.CoApplicantUserIDs...) {
+		notifyUser(h.server, userID, notificationType, message, propertyPageURL(application.PropertyID))
+	}
+}
+
+// getPropertyApplication gets the rental application of the request's "applicationId" URL param, ensuring that
+// it belongs to the given property. It responds with an error and returns false otherwise.
+func (h *PropertyHandler) getPropertyApplication(w http.ResponseWriter, r *http.Request, propertyDetails database.PropertyDetails) (database.RentalApplication, bool) {
+	application, err := h.server.DB().GetRentalApplication(chi.URLParam(r, "applicationId"))
+	if err != nil || application.PropertyID != propertyDetails.PropertyID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("application not found"))
+		return database.RentalApplication{}, false
+	}
+	return application, true
+}
+
+// POST .../properties/{id}/applications
+// AUTHED
+// Applies to rent a published property. Expects a multipart form with a "details" json
+// of the message, move in date and co-applicant user ids, and optionally the supporting
+// documents as "numDocuments" and "document0", "document1", ...
+// The lister and the co-applicants are notified.
+func (h *PropertyHandler) CreatePropertyApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+	if propertyDetails.Status != config.PROPERTY_STATUS_PUBLISHED {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("applications can only be made to published properties"))
+		return
+	}
+	if propertyDetails.ListerUserID == userID {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("cannot apply to your own property"))
+		return
+	}
+
+	// Prepare reading body form by allocating max memory to read
+	MAX_SIZE := 55 << 20 // 55 MiB
+	r.Body = http.MaxBytesReader(w, r.Body, int64(MAX_SIZE))
+	err := r.ParseMultipartForm(int64(MAX_SIZE + 512))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var application database.RentalApplication
+	err = json.Unmarshal([]byte(r.FormValue("details")), &application)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	application.ApplicationID = uuid.New().String()
+	application.PropertyID = propertyDetails.PropertyID
+	application.ApplicantUserID = userID
+	application.Status = config.APPLICATION_STATUS_SUBMITTED
+	application.DecisionNote = ""
+	if application.CoApplicantUserIDs == nil {
+		application.CoApplicantUserIDs = []string{}
+	}
+
+	err = validation.ValidateRentalApplication(application, time.Now())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	for _, coApplicantUserID := range application.CoApplicantUserIDs {
+		if coApplicantUserID == propertyDetails.ListerUserID {
+			utils.RespondWithError(w, http.StatusBadRequest, errors.New("the lister cannot be a co-applicant"))
+			return
+		}
+		if _, err := h.server.DB().GetUserDetails(coApplicantUserID); err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("co-applicant %s does not exist", coApplicantUserID))
+			return
+		}
+	}
+
+	documents, err := readOrderedFilesForm(r, "numDocuments", "document", config.APPLICATION_MAX_DOCUMENTS)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// A user can only be part of one application per property
+	userApplications, err := h.server.DB().GetUserRentalApplications(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, userApplication := range userApplications {
+		if userApplication.PropertyID == propertyDetails.PropertyID {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("you already applied to this property"))
+			return
+		}
+	}
+
+	err = h.server.DB().CreateRentalApplication(application, documents)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	notifyUser(h.server, propertyDetails.ListerUserID, config.NOTIFICATION_TYPE_APPLICATION_SUBMITTED,
+		fmt.Sprintf("You received a new rental application for %s.", propertyDetails.Name),
+		propertyPageURL(propertyDetails.PropertyID))
+	for _, coApplicantUserID := range application.CoApplicantUserIDs {
+		notifyUser(h.server, coApplicantUserID, config.NOTIFICATION_TYPE_APPLICATION_SUBMITTED,
+			fmt.Sprintf("You were added as a co-applicant to a rental application for %s.", propertyDetails.Name),
+			propertyPageURL(propertyDetails.PropertyID))
+	}
+
+	application, err = h.server.DB().GetRentalApplication(application.ApplicationID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, application)
+}
+
+// GET .../properties/{id}/applications
+// AUTHED
+// Returns the rental applications to a property, oldest first, without their documents.
+// Only for the property's lister and the admin.
+func (h *PropertyHandler) GetPropertyApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+
+	applications, err := h.server.DB().GetPropertyRentalApplications(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, applications)
+}
+
+// GET .../properties/{id}/applications/{applicationId}
+// AUTHED
+// Returns a rental application with its documents to the property's lister, the admin
+// and the applicants.
+func (h *PropertyHandler) GetPropertyApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+	application, ok := h.getPropertyApplication(w, r, propertyDetails)
+	if !ok {
+		return
+	}
+	if userID != propertyDetails.ListerUserID && userID != h.adminUserID && !isRentalApplicant(application, userID) {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("application not found"))
+		return
+	}
+
+	documentsBinary, err := h.server.DB().GetRentalApplicationDocuments(application.ApplicationID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	documentsB64 := []database.OrderedFileExternal{}
+	for _, document := range documentsBinary {
+		documentsB64 = append(documentsB64, database.OrderedFileExternal{
+			OrderNum: document.OrderNum,
+			File: database.FileExternal{
+				Filename: document.File.Filename,
+				Mimetype: document.File.Mimetype,
+				Size:     document.File.Size,
+				Data:     base64.StdEncoding.EncodeToString(document.File.Data),
+			},
+		})
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, rentalApplicationFull{
+		RentalApplication: application,
+		Documents:         documentsB64,
+	})
+}
+
+// PUT .../properties/{id}/applications/{applicationId}/status
+// AUTHED
+// Moves a rental application to under review, accepted or rejected. Expects a json body of the
+// new status and an optional note to the applicants, who are notified of the update.
+func (h *PropertyHandler) UpdatePropertyApplicationStatusHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+	application, ok := h.getPropertyApplication(w, r, propertyDetails)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = validation.ValidateRentalApplicationDecision(application.Status, body.Status, body.Note)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	updated, err := h.server.DB().UpdateRentalApplicationStatus(application.ApplicationID, application.Status, body.Status, body.Note)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !updated {
+		utils.RespondWithError(w, http.StatusConflict, errors.New("application was changed in the meantime, reload and try again"))
+		return
+	}
+
+	application, err = h.server.DB().GetRentalApplication(application.ApplicationID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var message string
+	switch application.Status {
+	case config.APPLICATION_STATUS_UNDER_REVIEW:
+		message = fmt.Sprintf("Your rental application for %s is under review.", propertyDetails.Name)
+	case config.APPLICATION_STATUS_ACCEPTED:
+		message = fmt.Sprintf("Your rental application for %s was accepted.", propertyDetails.Name)
+	case config.APPLICATION_STATUS_REJECTED:
+		message = fmt.Sprintf("Your rental application for %s was rejected.", propertyDetails.Name)
+	}
+	if application.DecisionNote != "" {
+		message += " " + application.DecisionNote
+	}
+	h.notifyRentalApplicants(application, config.NOTIFICATION_TYPE_APPLICATION_STATUS_CHANGED, message)
+
+	utils.RespondWithJSON(w, http.StatusOK, application)
+}
+
+// DELETE .../properties/{id}/applications/{applicationId}
+// AUTHED
+// Withdraws a rental application that has not been decided yet. Only the applicant who submitted
+// it can withdraw it, the lister and the co-applicants are notified.
+func (h *PropertyHandler) DeletePropertyApplicationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+	application, ok := h.getPropertyApplication(w, r, propertyDetails)
+	if !ok {
+		return
+	}
+	if application.ApplicantUserID != userID {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("only the applicant can withdraw an application"))
+		return
+	}
+	if _, undecided := config.APPLICATION_STATUS_TRANSITIONS[application.Status]; !undecided {
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("cannot withdraw an application that was %s", application.Status))
+		return
+	}
+
+	err = h.server.DB().DeleteRentalApplication(application.ApplicationID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	message := fmt.Sprintf("A rental application for %s was withdrawn.", propertyDetails.Name)
+	notifyUser(h.server, propertyDetails.ListerUserID, config.NOTIFICATION_TYPE_APPLICATION_WITHDRAWN, message, propertyPageURL(propertyDetails.PropertyID))
+	for _, coApplicantUserID := range application.CoApplicantUserIDs {
+		notifyUser(h.server, coApplicantUserID, config.NOTIFICATION_TYPE_APPLICATION_WITHDRAWN, message, propertyPageURL(propertyDetails.PropertyID))
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetAccountApplicationsHandler handles requests to return the rental applications the user
+// submitted or is a co-applicant of, most recent first.
+//
+// AUTHED GET .../account/applications
+func (h *AccountHandler) GetAccountApplicationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	applications, err := h.server.DB().GetUserRentalApplications(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, applications)
+}
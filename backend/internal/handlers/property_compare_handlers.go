@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/compare"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parseComparePoint reads the optional "lat" and "lon" query parameters of the point to measure
+// the distance of compared properties to. Returns nil if neither is given.
+func parseComparePoint(r *http.Request) (*compare.Point, error) {
+	latRaw := r.URL.Query().Get("lat")
+	lonRaw := r.URL.Query().Get("lon")
+	if latRaw == "" && lonRaw == "" {
+		return nil, nil
+	}
+	if latRaw == "" || lonRaw == "" {
+		return nil, errors.New("lat and lon must be given together")
+	}
+
+	latitude, err := strconv.ParseFloat(latRaw, 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return nil, errors.New("lat must be between -90 and 90")
+	}
+	longitude, err := strconv.ParseFloat(lonRaw, 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return nil, errors.New("lon must be between -180 and 180")
+	}
+	return &compare.Point{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// GET .../properties/compare?ids=id1,id2&lat=40.7&lon=-74
+// OPTIONAL AUTH
+// Returns the properties side by side with their price per square foot, bed and bath counts, amenities
+// and, if a point is given, distance to it, flagging which property is best on each dimension.
+func (h *PropertyHandler) GetPropertiesComparisonHandler(w http.ResponseWriter, r *http.Request) {
+	propertyIDs := []string{}
+	for _, propertyID := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		propertyID = strings.TrimSpace(propertyID)
+		if propertyID != "" && !slices.Contains(propertyIDs, propertyID) {
+			propertyIDs = append(propertyIDs, propertyID)
+		}
+	}
+	if len(propertyIDs) < config.PROPERTY_COMPARE_MIN_PROPERTIES || len(propertyIDs) > config.PROPERTY_COMPARE_MAX_PROPERTIES {
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("between %d and %d distinct property ids must be given", config.PROPERTY_COMPARE_MIN_PROPERTIES, config.PROPERTY_COMPARE_MAX_PROPERTIES))
+		return
+	}
+	point, err := parseComparePoint(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	properties := []database.PropertyDetails{}
+	for _, propertyID := range propertyIDs {
+		propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusNotFound, fmt.Errorf("property %s not found", propertyID))
+			return
+		}
+		canView, err := h.canViewProperty(userID, propertyDetails)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if !canView {
+			utils.RespondWithError(w, http.StatusNotFound, fmt.Errorf("property %s not found", propertyID))
+			return
+		}
+		properties = append(properties, propertyDetails)
+	}
+
+	comparison, err := compare.Compare(properties, point)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, comparison)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/export"
+	"backend/internal/interfaces"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// apiOrigin returns the scheme and host the request was made to, for building absolute urls
+// to other endpoints of the api. Behind the proxy the scheme is in X-Forwarded-Proto.
+func apiOrigin(r *http.Request) string {
+	scheme := r.Header.Get("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+	}
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
+
+// propertyPageURL returns the absolute url of a property's page on the frontend
+func propertyPageURL(propertyID string) string {
+	return fmt.Sprintf("%s/properties/%s", config.GlobalConfig.FRONTEND_ORIGIN, propertyID)
+}
+
+// exportListings adds the urls of the property pages and property images and the number of rooms
+// to properties to export
+func exportListings(s interfaces.Server, r *http.Request, properties []database.PropertyDetails) ([]export.Listing, error) {
+	listings := []export.Listing{}
+	for _, propertyDetails := range properties {
+		orderNums, err := s.DB().GetPropertyImageOrderNums(propertyDetails.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+
+		imageURLs := []string{}
+		for _, orderNum := range orderNums {
+			imageURLs = append(imageURLs, fmt.Sprintf("%s/api/v1/properties/%s/images/%d", apiOrigin(r), propertyDetails.PropertyID, orderNum))
+		}
+
+		roomCount, err := s.DB().CountPropertyRooms(propertyDetails.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+
+		listings = append(listings, export.Listing{
+			Details:   propertyDetails,
+			URL:       propertyPageURL(propertyDetails.PropertyID),
+			ImageURLs: imageURLs,
+			RoomCount: roomCount,
+		})
+	}
+	return listings, nil
+}
+
+// respondWithExport writes the listings in the requested export format, a single listing
+// is written on its own rather than as a feed.
+func respondWithExport(w http.ResponseWriter, format string, listings []export.Listing, single bool) {
+	var body any
+	var contentType string
+	switch format {
+	case export.FORMAT_RESO, "":
+		contentType = export.CONTENT_TYPE_RESO
+		if single {
+			body = export.ToRESO(listings[0])
+		} else {
+			body = export.ToRESOFeed(listings)
+		}
+	case export.FORMAT_JSONLD:
+		contentType = export.CONTENT_TYPE_JSONLD
+		if single {
+			body = export.ToJSONLD(listings[0])
+		} else {
+			body = export.ToJSONLDGraph(listings)
+		}
+	default:
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("export format \"%s\" is not one of our supported options", format))
+		return
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// GET .../properties/{id}/export?format=reso|jsonld
+// OPTIONAL AUTH
+// Renders a property as a RESO style json listing (default) or as schema.org JSON-LD.
+func (h *PropertyHandler) GetPropertyExportHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+
+	listings, err := exportListings(h.server, r, []database.PropertyDetails{propertyDetails})
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithExport(w, r.URL.Query().Get("format"), listings, true)
+}
+
+// GET .../properties/{id}/images/{orderNum}
+// OPTIONAL AUTH
+// Serves a single image of a property as is, so that exported listings can link to it. Images of
+// a type that is not a supported image type are served as binary data, never as a page.
+func (h *PropertyHandler) GetPropertyImageHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+
+	orderNum, err := strconv.ParseInt(chi.URLParam(r, "orderNum"), 10, 16)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("invalid image order number"))
+		return
+	}
+
+	image, err := h.server.DB().GetPropertyImage(propertyDetails.PropertyID, int16(orderNum))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("image not found"))
+		return
+	}
+
+	contentType := "application/octet-stream"
+	if mimeType, _, err := mime.ParseMediaType(image.Mimetype); err == nil {
+		if _, ok := config.PROPERTY_IMAGE_MIME_TYPE_OPTIONS[mimeType]; ok {
+			contentType = image.Mimetype
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Length", strconv.FormatInt(image.Size, 10))
+	w.WriteHeader(http.StatusOK)
+	w.Write(image.Data)
+}
+
+// GET .../lister/{id}/export?format=reso|jsonld
+// NO AUTH
+// Renders every published property of a lister as a RESO style json feed (default) or as a
+// schema.org JSON-LD graph, for syndication to other listing sites.
+func (h *ListerHandler) GetListerPropertiesExportHandler(w http.ResponseWriter, r *http.Request) {
+	listerUserID := chi.URLParam(r, "id")
+
+	propertyIDs, err := h.server.DB().GetListerOwnedProperties(listerUserID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	properties := []database.PropertyDetails{}
+	for _, propertyID := range propertyIDs {
+		propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if propertyDetails.Status == config.PROPERTY_STATUS_PUBLISHED {
+			properties = append(properties, propertyDetails)
+		}
+	}
+
+	listings, err := exportListings(h.server, r, properties)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respondWithExport(w, r.URL.Query().Get("format"), listings, false)
+}
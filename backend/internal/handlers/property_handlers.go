@@ -12,8 +12,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -27,8 +31,37 @@ func NewPropertyHandlers(s interfaces.Server) *PropertyHandler {
 	return &PropertyHandler{server: s, adminUserID: config.GlobalConfig.ADMIN_USER_ID}
 }
 
+// canViewProperty reports whether the user (empty if unauthenticated) may see a property
+// given its listing status. Published properties are public, paused and rented properties
+// remain visible to users that saved them, and the lister and admin can always see their properties.
+func (h *PropertyHandler) canViewProperty(userID string, propertyDetails database.PropertyDetails) (bool, error) {
+	return canViewProperty(h.server, h.adminUserID, userID, propertyDetails)
+}
+
+// canViewProperty reports whether the user may see a property, shared with the handlers that
+// have no admin user id of their own.
+func canViewProperty(s interfaces.Server, adminUserID, userID string, propertyDetails database.PropertyDetails) (bool, error) {
+	if propertyDetails.Status == config.PROPERTY_STATUS_PUBLISHED {
+		return true, nil
+	}
+	if userID == "" {
+		return false, nil
+	}
+	if userID == propertyDetails.ListerUserID || userID == adminUserID {
+		return true, nil
+	}
+	if propertyDetails.Status == config.PROPERTY_STATUS_PAUSED || propertyDetails.Status == config.PROPERTY_STATUS_RENTED {
+		savedPropertyIDs, err := s.DB().GetUserSavedProperties(userID)
+		if err != nil {
+			return false, err
+		}
+		return slices.Contains(savedPropertyIDs, propertyDetails.PropertyID), nil
+	}
+	return false, nil
+}
+
 // GET .../properties/{id}
-// NO AUTH
+// OPTIONAL AUTH
 func (h *PropertyHandler) GetPropertyHandler(w http.ResponseWriter, r *http.Request) {
 	propertyID := chi.URLParam(r, "id")
 
@@ -39,6 +72,18 @@ func (h *PropertyHandler) GetPropertyHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Hide properties that are not visible to the requester as if they did not exist
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	canView, err := h.canViewProperty(userID, propertyDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !canView {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+
 	propertyImagesBinary, err := h.server.DB().GetPropertyImages(propertyID)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
@@ -63,9 +108,110 @@ func (h *PropertyHandler) GetPropertyHandler(w http.ResponseWriter, r *http.Requ
 		PropertyImages:  propertyImagesB64,
 	}
 
+	recordPropertyEvent(h.server, r, propertyDetails, config.PROPERTY_ANALYTICS_EVENT_VIEW)
+
 	utils.RespondWithJSON(w, http.StatusOK, property)
 }
 
+// parsePropertySearchFilters reads the optional property search filters from the query parameters.
+func parsePropertySearchFilters(query url.Values) (database.PropertySearchFilters, error) {
+	filters := database.PropertySearchFilters{
+		Address:     query.Get("filterAddress"),
+		PriceType:   query.Get("filterPriceType"),
+		AvailableBy: query.Get("filterAvailableBy"),
+	}
+
+	if filters.PriceType != "" {
+		if _, exists := config.PROPERTY_PRICE_TYPE_OPTIONS[filters.PriceType]; !exists {
+			return filters, fmt.Errorf("unable to parse filterPriceType: %s", filters.PriceType)
+		}
+	}
+	if filters.AvailableBy != "" {
+		if _, err := time.Parse("2006-01-02", filters.AvailableBy); err != nil {
+			return filters, fmt.Errorf("unable to parse filterAvailableBy: %s", filters.AvailableBy)
+		}
+	}
+	if v := query.Get("filterMaxCost"); v != "" {
+		maxCost, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || maxCost < 0 {
+			return filters, fmt.Errorf("unable to parse filterMaxCost: %s", v)
+		}
+		filters.MaxCostDollars = maxCost
+	}
+	if v := query.Get("filterLeaseLength"); v != "" {
+		leaseLength, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || leaseLength < 0 {
+			return filters, fmt.Errorf("unable to parse filterLeaseLength: %s", v)
+		}
+		filters.LeaseLengthMonths = int32(leaseLength)
+	}
+	if v := query.Get("filterMinRoomsAvailable"); v != "" {
+		minRooms, err := strconv.ParseInt(v, 10, 16)
+		if err != nil || minRooms < 0 {
+			return filters, fmt.Errorf("unable to parse filterMinRoomsAvailable: %s", v)
+		}
+		filters.MinRoomsAvailable = int16(minRooms)
+	}
+	if v := query.Get("filterAmenities"); v != "" {
+		filters.Amenities = utils.SplitCommaSeparated(v)
+	}
+	switch v := query.Get("filterAmenitiesMatch"); v {
+	case "", "any":
+		filters.MatchAllAmenities = false
+	case "all":
+		filters.MatchAllAmenities = true
+	default:
+		return filters, fmt.Errorf("unable to parse filterAmenitiesMatch, expected \"all\" or \"any\": %s", v)
+	}
+	for name, dest := range map[string]*bool{
+		"filterUtilitiesIncluded": &filters.UtilitiesIncluded,
+		"filterPetsAllowed":       &filters.PetsAllowed,
+		"filterSmokingAllowed":    &filters.SmokingAllowed,
+	} {
+		if v := query.Get(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return filters, fmt.Errorf("unable to parse %s: %s", name, v)
+			}
+			*dest = b
+		}
+	}
+
+	return filters, nil
+}
+
+// normalizedAddress returns the canonical form of a property's address used for duplicate detection
+func normalizedAddress(details database.PropertyDetails) string {
+	return utils.NormalizeAddress(details.Address_1, details.Address_2, details.City, details.State, details.Zipcode, details.Country)
+}
+
+// transferAllProperties transfers every property of a lister to another lister and
+// records the change of lister in each property's history.
+func transferAllProperties(s interfaces.Server, fromUserID, toUserID, changedByUserID string) error {
+	propertyIDs, err := s.DB().GetListerOwnedProperties(fromUserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB().TransferAllPropertiesToOtherUser(fromUserID, toUserID)
+	if err != nil {
+		return err
+	}
+
+	for _, propertyID := range propertyIDs {
+		err = s.DB().CreatePropertyHistory(propertyID, changedByUserID, []database.PropertyChange{{
+			Field:    database.PROPERTY_HISTORY_FIELD_LISTER,
+			OldValue: fromUserID,
+			NewValue: toUserID,
+		}})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GET .../properties
 // NO AUTH
 func (h *PropertyHandler) GetPropertiesHandler(w http.ResponseWriter, r *http.Request) {
@@ -73,7 +219,6 @@ func (h *PropertyHandler) GetPropertiesHandler(w http.ResponseWriter, r *http.Re
 
 	pageStr := query.Get("page")
 	limitStr := query.Get("limit")
-	filterAddress := query.Get("filterAddress")
 
 	// Parse offset and limit
 	var offset int
@@ -93,8 +238,14 @@ func (h *PropertyHandler) GetPropertiesHandler(w http.ResponseWriter, r *http.Re
 	// Calculate the correct offset
 	offset = offset * limit
 
+	filters, err := parsePropertySearchFilters(query)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
 	// Get the property IDs from DB
-	properties, err := h.server.DB().GetNextPageProperties(int32(limit), int32(offset), filterAddress)
+	properties, err := h.server.DB().GetNextPageProperties(int32(limit), int32(offset), filters)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -150,6 +301,20 @@ func (h *PropertyHandler) CreatePropertiesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Prices are monthly rent unless stated otherwise
+	if propertyDetails.Price_type == "" {
+		propertyDetails.Price_type = config.PROPERTY_PRICE_TYPE_MONTHLY
+	}
+
+	// New properties are published unless explicitly saved as a draft
+	if propertyDetails.Status == "" {
+		propertyDetails.Status = config.PROPERTY_STATUS_PUBLISHED
+	}
+	if propertyDetails.Status != config.PROPERTY_STATUS_PUBLISHED && propertyDetails.Status != config.PROPERTY_STATUS_DRAFT {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("new properties can only be created as a draft or published"))
+		return
+	}
+
 	// Validate property details
 	err = validation.ValidatePropertyDetails(propertyDetails)
 	if err != nil {
@@ -157,6 +322,18 @@ func (h *PropertyHandler) CreatePropertiesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Validate amenities against the amenities catalog
+	catalog, err := database.AmenityCatalog(h.server.DB())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	err = validation.ValidatePropertyAmenities(propertyDetails.Amenities, catalog)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	// Check that property address is not a duplicate of an existing one before creation
 	err = h.server.DB().CheckDuplicateProperty(propertyDetails)
 	if err != nil {
@@ -202,17 +379,16 @@ func (h *PropertyHandler) CreatePropertiesHandler(w http.ResponseWriter, r *http
 
 		imageFile := database.FileInternal{
 			Filename: imageFileHeader.Filename,
-			Mimetype: imageFileHeader.Header.Get("Content-Type"),
+			Mimetype: http.DetectContentType(imageData),
 			Size:     imageFileHeader.Size,
 			Data:     imageData,
 		}
 
-		// Validation of images not implemented atm.
-		// err = validation.ValidateImage(imageFile)
-		// if err != nil {
-		// 	utils.RespondWithError(w, http.StatusBadRequest, err)
-		// 	return
-		// }
+		err = validation.ValidatePropertyImage(imageFile)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, err)
+			return
+		}
 
 		images = append(images, database.OrderedFileInternal{
 			OrderNum: i,
@@ -227,6 +403,13 @@ func (h *PropertyHandler) CreatePropertiesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Queue the property for admin review if its address resembles an existing listing. The
+	// property is created already, so a failure is only logged.
+	err = h.server.DB().FlagSimilarProperties(propertyDetails, config.PROPERTY_DUPLICATE_SIMILARITY_THRESHOLD)
+	if err != nil {
+		log.Printf("failed to flag the similar properties of property %s: %s", propertyDetails.PropertyID, err)
+	}
+
 	// Respond with ok created
 	w.WriteHeader(201)
 }
@@ -309,6 +492,11 @@ func (h *PropertyHandler) UpdatePropertiesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Prices are monthly rent unless stated otherwise
+	if propertyDetails.Price_type == "" {
+		propertyDetails.Price_type = config.PROPERTY_PRICE_TYPE_MONTHLY
+	}
+
 	// Validate property details
 	err = validation.ValidatePropertyDetails(propertyDetails)
 	if err != nil {
@@ -316,6 +504,18 @@ func (h *PropertyHandler) UpdatePropertiesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Validate amenities against the amenities catalog
+	catalog, err := database.AmenityCatalog(h.server.DB())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	err = validation.ValidatePropertyAmenities(propertyDetails.Amenities, catalog)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	// Get count of property images sent
 	numberImagesRaw := r.FormValue("numImages") // should be at most 10, limited on expected frontend.
 	numberImagesInt64, err := strconv.ParseInt(numberImagesRaw, 10, 16)
@@ -353,17 +553,37 @@ func (h *PropertyHandler) UpdatePropertiesHandler(w http.ResponseWriter, r *http
 			}
 		}
 
+		imageFile := database.FileInternal{
+			Filename: imageFileHeader.Filename,
+			Mimetype: http.DetectContentType(imageData),
+			Size:     imageFileHeader.Size,
+			Data:     imageData,
+		}
+		err = validation.ValidatePropertyImage(imageFile)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+
 		images = append(images, database.OrderedFileInternal{
 			OrderNum: i,
-			File: database.FileInternal{
-				Filename: imageFileHeader.Filename,
-				Mimetype: imageFileHeader.Header.Get("Content-Type"),
-				Size:     imageFileHeader.Size,
-				Data:     imageData,
-			},
+			File:     imageFile,
 		})
 	}
 
+	// Status is changed on its own endpoint
+	propertyDetails.Status = currDBPropertyDetails.Status
+
+	// Check that the new address is not a duplicate of another property
+	addressChanged := normalizedAddress(propertyDetails) != normalizedAddress(currDBPropertyDetails)
+	if addressChanged {
+		err = h.server.DB().CheckDuplicateProperty(propertyDetails)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
 	// Update property details
 	err = h.server.DB().UpdatePropertyDetails(propertyDetails)
 	if err != nil {
@@ -371,6 +591,13 @@ func (h *PropertyHandler) UpdatePropertiesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Record what was changed in the property's history
+	err = h.server.DB().CreatePropertyHistory(propertyDetails.PropertyID, authedUserID, database.DiffPropertyDetails(currDBPropertyDetails, propertyDetails))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
 	// Update property images
 	err = h.server.DB().UpdatePropertyImages(propertyDetails.PropertyID, images)
 	if err != nil {
@@ -378,10 +605,182 @@ func (h *PropertyHandler) UpdatePropertiesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	// Queue the property for admin review if its new address resembles an existing listing. The
+	// property is updated already, so a failure is only logged.
+	if addressChanged {
+		err = h.server.DB().FlagSimilarProperties(propertyDetails, config.PROPERTY_DUPLICATE_SIMILARITY_THRESHOLD)
+		if err != nil {
+			log.Printf("failed to flag the similar properties of property %s: %s", propertyDetails.PropertyID, err)
+		}
+	}
+
 	// Respond with ok
 	w.WriteHeader(200)
 }
 
+// GET .../properties/{id}/history
+// OPTIONAL AUTH
+// Returns the change history of a property, most recent first. The lister and admin see
+// every recorded change and who made it, everyone else only sees price and status changes.
+func (h *PropertyHandler) GetPropertyHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.server.DB().GetPropertyHistory(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	if userID != "" && (userID == propertyDetails.ListerUserID || userID == h.adminUserID) {
+		utils.RespondWithJSON(w, http.StatusOK, history)
+		return
+	}
+
+	publicHistory := []database.PropertyHistoryEntry{}
+	for _, entry := range history {
+		if _, isPublic := database.PROPERTY_HISTORY_PUBLIC_FIELDS[entry.PropertyChange.Field]; isPublic {
+			entry.ChangedByUserID = ""
+			publicHistory = append(publicHistory, entry)
+		}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, publicHistory)
+}
+
+// PUT .../properties/{id}/status
+// AUTHED
+// Moves a property through its listing lifecycle (e.g. draft -> published -> rented).
+func (h *PropertyHandler) UpdatePropertyStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user id
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyID := chi.URLParam(r, "id")
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Try to get the requested property
+	propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Ensure that the user owns the property OR
+	// that the user is the admin
+	if propertyDetails.ListerUserID != userID && userID != h.adminUserID {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+		return
+	}
+
+	// Setting the status it already has is a noop
+	if propertyDetails.Status == body.Status {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	err = validation.ValidatePropertyStatusTransition(propertyDetails.Status, body.Status)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Drafts can be imported without images, they must have some before they are published
+	if body.Status == config.PROPERTY_STATUS_PUBLISHED {
+		imageOrderNums, err := h.server.DB().GetPropertyImageOrderNums(propertyID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if len(imageOrderNums) == 0 {
+			utils.RespondWithError(w, http.StatusBadRequest, errors.New("property must have at least one image to be published"))
+			return
+		}
+	}
+
+	err = h.server.DB().UpdatePropertyStatus(propertyID, body.Status)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.server.DB().CreatePropertyHistory(propertyID, userID, []database.PropertyChange{{
+		Field:    database.PROPERTY_HISTORY_FIELD_STATUS,
+		OldValue: propertyDetails.Status,
+		NewValue: body.Status,
+	}})
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// POST .../properties/{id}/renew
+// AUTHED
+// Starts a new listing period for a published property, or publishes an expired property again.
+// Returns when the listing now expires.
+func (h *PropertyHandler) RenewPropertyHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+	userID := r.Context().Value(app_middleware.UserIDKey).(string)
+
+	switch propertyDetails.Status {
+	case config.PROPERTY_STATUS_PUBLISHED:
+		err := h.server.DB().UpdatePropertyExpiry(propertyDetails.PropertyID, time.Now().AddDate(0, 0, config.PROPERTY_LISTING_DURATION_DAYS))
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+	case config.PROPERTY_STATUS_EXPIRED:
+		err := h.server.DB().UpdatePropertyStatus(propertyDetails.PropertyID, config.PROPERTY_STATUS_PUBLISHED)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		err = h.server.DB().CreatePropertyHistory(propertyDetails.PropertyID, userID, []database.PropertyChange{{
+			Field:    database.PROPERTY_HISTORY_FIELD_STATUS,
+			OldValue: propertyDetails.Status,
+			NewValue: config.PROPERTY_STATUS_PUBLISHED,
+		}})
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+	default:
+		utils.RespondWithError(w, http.StatusBadRequest, fmt.Errorf("a %s property cannot be renewed, only published or expired properties can", propertyDetails.Status))
+		return
+	}
+
+	renewedDetails, err := h.server.DB().GetPropertyDetails(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, struct {
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}{
+		ExpiresAt: renewedDetails.ExpiresAt,
+	})
+}
+
 // DELETE .../properties/{id}
 // AUTHED
 func (h *PropertyHandler) DeletePropertiesHandler(w http.ResponseWriter, r *http.Request) {
@@ -468,7 +867,7 @@ func (h *PropertyHandler) TransferAllPropertiesOwnershipHandler(w http.ResponseW
 	}
 
 	// Transfer all of authed user's properties to the other lister+ account
-	err = h.server.DB().TransferAllPropertiesToOtherUser(authedUserID, userId)
+	err = transferAllProperties(h.server, authedUserID, userId, authedUserID)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusInternalServerError, err)
 		return
@@ -510,7 +909,7 @@ func (h *PropertyHandler) TransferPropertyOwnershipHandler(w http.ResponseWriter
 	}
 
 	// Ensure property exists
-	_, err := h.server.DB().GetPropertyDetails(propertyId)
+	propertyDetails, err := h.server.DB().GetPropertyDetails(propertyId)
 	if err != nil {
 		utils.RespondWithError(w, http.StatusBadRequest, errors.New("property does not exist"))
 		return
@@ -541,6 +940,16 @@ func (h *PropertyHandler) TransferPropertyOwnershipHandler(w http.ResponseWriter
 		return
 	}
 
+	err = h.server.DB().CreatePropertyHistory(propertyId, authedUserID, []database.PropertyChange{{
+		Field:    database.PROPERTY_HISTORY_FIELD_LISTER,
+		OldValue: propertyDetails.ListerUserID,
+		NewValue: userId,
+	}})
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
 	// Respond with Ok
 	w.WriteHeader(http.StatusOK)
 }
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/importer"
+	"backend/internal/utils"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// POST .../properties/import?dryRun=true
+// AUTHED
+// Creates properties in bulk from a CSV or JSON file in the "file" form field, with their images
+// in an optional zip archive in the "images" form field. Responds with whether each row was
+// imported or why it was not, a dry run only validates the rows.
+func (h *PropertyHandler) ImportPropertiesHandler(w http.ResponseWriter, r *http.Request) {
+	// Get user id
+	authedUserID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	// Ensure user has permission to create properties
+	role, err := h.server.DB().GetUserRole(authedUserID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if role == config.USER_ROLE_REGULAR {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("you do not have permission to access this endpoint"))
+		return
+	}
+
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dryRun"); dryRunStr != "" {
+		dryRun, err = strconv.ParseBool(dryRunStr)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, errors.New("invalid dryRun string"))
+			return
+		}
+	}
+
+	// Prepare reading body form by allocating max memory to read
+	MAX_SIZE := 200 << 20 // 200 MiB
+	r.Body = http.MaxBytesReader(w, r.Body, int64(MAX_SIZE))
+	err = r.ParseMultipartForm(int64(MAX_SIZE + 512))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Read the properties to import
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer file.Close()
+
+	format := r.FormValue("format")
+	if format == "" {
+		format, err = importer.FormatFromFilename(fileHeader.Filename)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	rows, err := importer.ParseRows(file, format)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Read the optional image archive
+	images, err := readImageArchiveForm(r, rows)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := importer.New(h.server.DB()).Import(rows, images, authedUserID, role == config.USER_ROLE_ADMIN, dryRun)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, report)
+}
+
+// readImageArchiveForm reads the images referenced by the rows from the zip archive in the "images"
+// form field, if given
+func readImageArchiveForm(r *http.Request, rows []importer.PropertyRow) (map[string]database.FileInternal, error) {
+	archive, _, err := r.FormFile("images")
+	if errors.Is(err, http.ErrMissingFile) {
+		return map[string]database.FileInternal{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	archiveData, err := io.ReadAll(archive)
+	if err != nil {
+		return nil, err
+	}
+	return importer.ReadImageArchive(archiveData, rows)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/recommend"
+	"backend/internal/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// parseRecommendationLimit reads the optional "limit" query parameter of recommendation results
+func parseRecommendationLimit(r *http.Request) (int, error) {
+	limitRaw := r.URL.Query().Get("limit")
+	if limitRaw == "" {
+		return config.RECOMMENDATION_DEFAULT_LIMIT, nil
+	}
+	limit, err := strconv.Atoi(limitRaw)
+	if err != nil || limit < 1 || limit > config.RECOMMENDATION_MAX_LIMIT {
+		return 0, fmt.Errorf("limit must be between 1 and %d", config.RECOMMENDATION_MAX_LIMIT)
+	}
+	return limit, nil
+}
+
+// GET .../properties/{id}/similar?limit=10
+// OPTIONAL AUTH
+// Returns the ids of published properties ranked by similarity to the property on price,
+// bedrooms, bathrooms, square footage and location, most similar first.
+func (h *PropertyHandler) GetSimilarPropertiesHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+	limit, err := parseRecommendationLimit(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	candidates, err := h.server.DB().GetRecommendationCandidates(propertyDetails, []string{propertyDetails.PropertyID}, "", config.RECOMMENDATION_MAX_CANDIDATES)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, recommend.Rank(propertyDetails, candidates, limit))
+}
+
+// GetAccountRecommendationsHandler handles requests to return the ids of published properties
+// recommended to the user based on the properties they saved, most similar first. Properties
+// the user already saved or lists themself are not recommended.
+//
+// AUTHED GET .../account/recommendations?limit=10
+func (h *AccountHandler) GetAccountRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+	limit, err := parseRecommendationLimit(r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	savedPropertyIDs, err := h.server.DB().GetUserSavedProperties(userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	seedPropertyIDs := savedPropertyIDs
+	if len(seedPropertyIDs) > config.RECOMMENDATION_MAX_SAVED_PROPERTIES {
+		seedPropertyIDs = seedPropertyIDs[:config.RECOMMENDATION_MAX_SAVED_PROPERTIES]
+	}
+
+	rankings := [][]recommend.Match{}
+	for _, propertyID := range seedPropertyIDs {
+		propertyDetails, err := h.server.DB().GetPropertyDetails(propertyID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		candidates, err := h.server.DB().GetRecommendationCandidates(propertyDetails, savedPropertyIDs, userID, config.RECOMMENDATION_MAX_CANDIDATES)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		rankings = append(rankings, recommend.Rank(propertyDetails, candidates, limit))
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, recommend.Merge(rankings, limit))
+}
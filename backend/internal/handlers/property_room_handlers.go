@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MAX_ROOM_IMAGES is the most images a single room can have.
+const MAX_ROOM_IMAGES = 10
+
+// readOrderedImagesForm reads the "numImages" count and the "image%d" files of a
+// multipart form that has already been parsed.
+func readOrderedImagesForm(r *http.Request, maxImages int16) ([]database.OrderedFileInternal, error) {
+	return readOrderedFilesForm(r, "numImages", "image", maxImages)
+}
+
+// readOrderedFilesForm reads the count field and the numbered files of a multipart form that
+// has already been parsed, e.g. "numDocuments" and the files "document0", "document1", ...
+func readOrderedFilesForm(r *http.Request, countField, filePrefix string, maxFiles int16) ([]database.OrderedFileInternal, error) {
+	numberFilesRaw := r.FormValue(countField)
+	if numberFilesRaw == "" {
+		return []database.OrderedFileInternal{}, nil
+	}
+	numberFilesInt64, err := strconv.ParseInt(numberFilesRaw, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	numberFiles := int16(numberFilesInt64)
+	if numberFiles < 0 || numberFiles > maxFiles {
+		return nil, fmt.Errorf("number of %ss must be between 0 and %d", filePrefix, maxFiles)
+	}
+
+	var files []database.OrderedFileInternal
+	for i := range numberFiles {
+		fileDataRaw, fileHeader, err := r.FormFile(fmt.Sprintf("%s%d", filePrefix, i))
+		if err != nil {
+			return nil, err
+		}
+		fileData, err := io.ReadAll(fileDataRaw)
+		fileDataRaw.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, database.OrderedFileInternal{
+			OrderNum: i,
+			File: database.FileInternal{
+				Filename: fileHeader.Filename,
+				Mimetype: fileHeader.Header.Get("Content-Type"),
+				Size:     fileHeader.Size,
+				Data:     fileData,
+			},
+		})
+	}
+
+	return files, nil
+}
+
+// getOwnedProperty gets the property of the request's "id" URL param and ensures that the
+// authenticated user is its lister or the admin. It responds with an error and returns false otherwise.
+func (h *PropertyHandler) getOwnedProperty(w http.ResponseWriter, r *http.Request) (database.PropertyDetails, bool) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return database.PropertyDetails{}, false
+	}
+
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return database.PropertyDetails{}, false
+	}
+
+	if propertyDetails.ListerUserID != userID && userID != h.adminUserID {
+		utils.RespondWithError(w, http.StatusUnauthorized, errors.New("account not authorized for this action"))
+		return database.PropertyDetails{}, false
+	}
+
+	return propertyDetails, true
+}
+
+// getVisibleProperty gets the property of the request's "id" URL param if the (optionally) authenticated
+// user is allowed to see it. It responds with an error and returns false otherwise.
+func (h *PropertyHandler) getVisibleProperty(w http.ResponseWriter, r *http.Request) (database.PropertyDetails, bool) {
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return database.PropertyDetails{}, false
+	}
+
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	canView, err := h.canViewProperty(userID, propertyDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return database.PropertyDetails{}, false
+	}
+	if !canView {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return database.PropertyDetails{}, false
+	}
+
+	return propertyDetails, true
+}
+
+// hideRoomOccupant removes the occupant of a room unless the requester is the
+// property's lister, the admin or the occupant themself.
+func (h *PropertyHandler) hideRoomOccupant(r *http.Request, propertyDetails database.PropertyDetails, room database.RoomDetails) database.RoomDetails {
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	if userID != "" && (userID == propertyDetails.ListerUserID || userID == h.adminUserID || userID == room.Occupant_user_id) {
+		return room
+	}
+	room.Occupant_user_id = ""
+	return room
+}
+
+// parseRoomForm parses the multipart form of a room create or update request.
+func (h *PropertyHandler) parseRoomForm(w http.ResponseWriter, r *http.Request) (database.RoomDetails, []database.OrderedFileInternal, error) {
+	// Prepare reading body form by allocating max memory to read
+	MAX_SIZE := 55 << 20 // 55 MiB
+	r.Body = http.MaxBytesReader(w, r.Body, int64(MAX_SIZE))
+	err := r.ParseMultipartForm(int64(MAX_SIZE + 512))
+	if err != nil {
+		return database.RoomDetails{}, nil, err
+	}
+
+	var roomDetails database.RoomDetails
+	err = json.Unmarshal([]byte(r.FormValue("details")), &roomDetails)
+	if err != nil {
+		return database.RoomDetails{}, nil, err
+	}
+
+	images, err := readOrderedImagesForm(r, MAX_ROOM_IMAGES)
+	if err != nil {
+		return database.RoomDetails{}, nil, err
+	}
+
+	return roomDetails, images, nil
+}
+
+// validateRoomOccupant ensures that the occupant of a room, if any, is an existing user.
+func (h *PropertyHandler) validateRoomOccupant(roomDetails database.RoomDetails) error {
+	if roomDetails.Occupant_user_id == "" {
+		return nil
+	}
+	if _, err := h.server.DB().GetUserDetails(roomDetails.Occupant_user_id); err != nil {
+		return errors.New("occupant user does not exist")
+	}
+	return nil
+}
+
+// GET .../properties/{id}/rooms
+// OPTIONAL AUTH
+func (h *PropertyHandler) GetPropertyRoomsHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+
+	rooms, err := h.server.DB().GetPropertyRooms(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for i, room := range rooms {
+		rooms[i] = h.hideRoomOccupant(r, propertyDetails, room)
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, rooms)
+}
+
+// GET .../properties/{id}/rooms/{roomId}
+// OPTIONAL AUTH
+func (h *PropertyHandler) GetPropertyRoomHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+
+	roomDetails, err := h.server.DB().GetPropertyRoomDetails(chi.URLParam(r, "roomId"))
+	if err != nil || roomDetails.PropertyID != propertyDetails.PropertyID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("room not found"))
+		return
+	}
+
+	roomImagesBinary, err := h.server.DB().GetPropertyRoomImages(roomDetails.RoomID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	roomImagesB64 := []database.OrderedFileExternal{}
+	for _, image := range roomImagesBinary {
+		roomImagesB64 = append(roomImagesB64, database.OrderedFileExternal{
+			OrderNum: image.OrderNum,
+			File: database.FileExternal{
+				Filename: image.File.Filename,
+				Mimetype: image.File.Mimetype,
+				Size:     image.File.Size,
+				Data:     base64.StdEncoding.EncodeToString(image.File.Data),
+			},
+		})
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, database.RoomFull{
+		RoomDetails: h.hideRoomOccupant(r, propertyDetails, roomDetails),
+		RoomImages:  roomImagesB64,
+	})
+}
+
+// POST .../properties/{id}/rooms
+// AUTHED
+func (h *PropertyHandler) CreatePropertyRoomHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+
+	roomDetails, images, err := h.parseRoomForm(w, r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if roomDetails.PropertyID != propertyDetails.PropertyID {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("propertyId of URLParam does not match propertyId of data in request body"))
+		return
+	}
+
+	err = validation.ValidateRoomDetails(roomDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	err = h.validateRoomOccupant(roomDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// A property cannot have more rooms than it has bedrooms
+	roomCount, err := h.server.DB().CountPropertyRooms(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if roomCount >= int64(propertyDetails.Num_bedrooms) {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("property cannot have more rooms than bedrooms"))
+		return
+	}
+
+	err = h.server.DB().CreatePropertyRoom(roomDetails, images)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// PUT .../properties/{id}/rooms/{roomId}
+// AUTHED
+func (h *PropertyHandler) UpdatePropertyRoomHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+
+	currRoomDetails, err := h.server.DB().GetPropertyRoomDetails(chi.URLParam(r, "roomId"))
+	if err != nil || currRoomDetails.PropertyID != propertyDetails.PropertyID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("room not found"))
+		return
+	}
+
+	roomDetails, images, err := h.parseRoomForm(w, r)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Rooms cannot be moved between properties or have their id changed
+	if roomDetails.RoomID != currRoomDetails.RoomID || roomDetails.PropertyID != currRoomDetails.PropertyID {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("room and property ids of URLParams do not match the data in request body"))
+		return
+	}
+
+	err = validation.ValidateRoomDetails(roomDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	err = h.validateRoomOccupant(roomDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().UpdatePropertyRoomDetails(roomDetails)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.server.DB().UpdatePropertyRoomImages(roomDetails.RoomID, images)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DELETE .../properties/{id}/rooms/{roomId}
+// AUTHED
+func (h *PropertyHandler) DeletePropertyRoomHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+
+	roomDetails, err := h.server.DB().GetPropertyRoomDetails(chi.URLParam(r, "roomId"))
+	if err != nil || roomDetails.PropertyID != propertyDetails.PropertyID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("room not found"))
+		return
+	}
+
+	err = h.server.DB().DeletePropertyRoom(roomDetails.RoomID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"backend/internal/app_middleware"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/ical"
+	"backend/internal/interfaces"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// viewingSlotResponse is a viewing slot as seen by the requester, only the property's
+// lister and the admin see who booked it.
+type viewingSlotResponse struct {
+	database.ViewingSlot
+	IsBookedByMe  bool     `json:"isBookedByMe"`
+	BookerUserIDs []string `json:"bookerUserIds,omitempty"`
+}
+
+// propertyAddress formats a property's address on a single line
+func propertyAddress(details database.PropertyDetails) string {
+	parts := []string{}
+	for _, part := range []string{details.Address_1, details.Address_2, details.City, details.State + " " + details.Zipcode, details.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// viewingEvent returns the calendar event of a viewing slot
+func viewingEvent(details database.PropertyDetails, slot database.ViewingSlot) ical.Event {
+	return ical.Event{
+		UID:         fmt.Sprintf("viewing-%s@coop", slot.SlotID),
+		Start:       slot.StartTime,
+		End:         slot.EndTime,
+		Summary:     fmt.Sprintf("Viewing: %s", details.Name),
+		Description: slot.Note,
+		Location:    propertyAddress(details),
+		URL:         propertyPageURL(details.PropertyID),
+		Status:      ical.STATUS_CONFIRMED,
+	}
+}
+
+// userViewingEvents returns the calendar events of the upcoming viewings of a user,
+// both the ones they booked and the booked viewings of the properties they list.
+func userViewingEvents(s interfaces.Server, userID string) ([]ical.Event, error) {
+	slots, err := s.DB().GetUserUpcomingViewingSlots(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]database.PropertyDetails)
+	events := []ical.Event{}
+	for _, slot := range slots {
+		propertyDetails, ok := properties[slot.PropertyID]
+		if !ok {
+			propertyDetails, err = s.DB().GetPropertyDetails(slot.PropertyID)
+			if err != nil {
+				return nil, err
+			}
+			properties[slot.PropertyID] = propertyDetails
+		}
+		events = append(events, viewingEvent(propertyDetails, slot))
+	}
+	return events, nil
+}
+
+// getPropertyViewingSlot gets the viewing slot of the request's "slotId" URL param, ensuring that it
+// belongs to the given property. It responds with an error and returns false otherwise.
+func (h *PropertyHandler) getPropertyViewingSlot(w http.ResponseWriter, r *http.Request, propertyDetails database.PropertyDetails) (database.ViewingSlot, bool) {
+	slot, err := h.server.DB().GetViewingSlot(chi.URLParam(r, "slotId"))
+	if err != nil || slot.PropertyID != propertyDetails.PropertyID {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("viewing slot not found"))
+		return database.ViewingSlot{}, false
+	}
+	return slot, true
+}
+
+// GET .../properties/{id}/viewings
+// OPTIONAL AUTH
+// Returns the upcoming viewing slots of a property.
+func (h *PropertyHandler) GetPropertyViewingSlotsHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+
+	slots, err := h.server.DB().GetUpcomingViewingSlots(propertyDetails.PropertyID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	userID, _ := r.Context().Value(app_middleware.UserIDKey).(string)
+	isLister := userID != "" && (userID == propertyDetails.ListerUserID || userID == h.adminUserID)
+
+	response := []viewingSlotResponse{}
+	for _, slot := range slots {
+		slotResponse := viewingSlotResponse{ViewingSlot: slot}
+		if userID != "" && slot.BookedCount > 0 {
+			bookerUserIDs, err := h.server.DB().GetViewingSlotBookers(slot.SlotID)
+			if err != nil {
+				utils.RespondWithError(w, http.StatusInternalServerError, err)
+				return
+			}
+			slotResponse.IsBookedByMe = slices.Contains(bookerUserIDs, userID)
+			if isLister {
+				slotResponse.BookerUserIDs = bookerUserIDs
+			}
+		}
+		response = append(response, slotResponse)
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, response)
+}
+
+// POST .../properties/{id}/viewings
+// AUTHED
+// Publishes a viewing time slot of a property. Expects a json body of the start and end time
+// (RFC 3339), how many users can book it and an optional note to the visitors.
+func (h *PropertyHandler) CreatePropertyViewingSlotHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+
+	var slot database.ViewingSlot
+	err := json.NewDecoder(r.Body).Decode(&slot)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+	slot.SlotID = uuid.New().String()
+	slot.PropertyID = propertyDetails.PropertyID
+	slot.BookedCount = 0
+	if slot.Capacity == 0 {
+		slot.Capacity = 1
+	}
+
+	err = validation.ValidateViewingSlot(slot, time.Now())
+	if err != nil {
+		utils.RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.server.DB().CreateViewingSlot(slot)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusCreated, slot)
+}
+
+// DELETE .../properties/{id}/viewings/{slotId}
+// AUTHED
+// Removes a viewing slot, everyone who booked it is notified that it was cancelled.
+func (h *PropertyHandler) DeletePropertyViewingSlotHandler(w http.ResponseWriter, r *http.Request) {
+	propertyDetails, ok := h.getOwnedProperty(w, r)
+	if !ok {
+		return
+	}
+	slot, ok := h.getPropertyViewingSlot(w, r, propertyDetails)
+	if !ok {
+		return
+	}
+
+	bookerUserIDs, err := h.server.DB().GetViewingSlotBookers(slot.SlotID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.server.DB().DeleteViewingSlot(slot.SlotID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if slot.StartTime.After(time.Now()) {
+		for _, bookerUserID := range bookerUserIDs {
+			notifyUser(h.server, bookerUserID, config.NOTIFICATION_TYPE_VIEWING_CANCELLED,
+				fmt.Sprintf("The lister cancelled your viewing of %s on %s.", propertyDetails.Name, slot.StartTime.UTC().Format(time.RFC1123)),
+				propertyPageURL(propertyDetails.PropertyID))
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// POST .../properties/{id}/viewings/{slotId}/booking
+// AUTHED
+// Books a viewing slot of a published property, the user and the lister are sent a confirmation.
+func (h *PropertyHandler) CreatePropertyViewingBookingHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, ok := h.getVisibleProperty(w, r)
+	if !ok {
+		return
+	}
+	if propertyDetails.Status != config.PROPERTY_STATUS_PUBLISHED {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("viewings can only be booked for published properties"))
+		return
+	}
+	if propertyDetails.ListerUserID == userID {
+		utils.RespondWithError(w, http.StatusBadRequest, errors.New("cannot book a viewing of your own property"))
+		return
+	}
+
+	slot, ok := h.getPropertyViewingSlot(w, r, propertyDetails)
+	if !ok {
+		return
+	}
+
+	booked, err := h.server.DB().BookViewingSlot(slot.SlotID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !booked {
+		// Work out why for a helpful error
+		bookerUserIDs, err := h.server.DB().GetViewingSlotBookers(slot.SlotID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if slices.Contains(bookerUserIDs, userID) {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("you already booked this viewing"))
+		} else if !slot.StartTime.After(time.Now()) {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("viewing has already started"))
+		} else {
+			utils.RespondWithError(w, http.StatusConflict, errors.New("viewing is fully booked"))
+		}
+		return
+	}
+
+	when := slot.StartTime.UTC().Format(time.RFC1123)
+	notifyUser(h.server, userID, config.NOTIFICATION_TYPE_VIEWING_BOOKED,
+		fmt.Sprintf("Your viewing of %s on %s is booked.", propertyDetails.Name, when),
+		propertyPageURL(propertyDetails.PropertyID))
+	notifyUser(h.server, propertyDetails.ListerUserID, config.NOTIFICATION_TYPE_VIEWING_BOOKED,
+		fmt.Sprintf("A viewing of %s on %s was booked.", propertyDetails.Name, when),
+		propertyPageURL(propertyDetails.PropertyID))
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// DELETE .../properties/{id}/viewings/{slotId}/booking
+// AUTHED
+// Cancels the user's booking of a viewing slot, the lister is notified.
+func (h *PropertyHandler) DeletePropertyViewingBookingHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+	slot, ok := h.getPropertyViewingSlot(w, r, propertyDetails)
+	if !ok {
+		return
+	}
+
+	bookerUserIDs, err := h.server.DB().GetViewingSlotBookers(slot.SlotID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !slices.Contains(bookerUserIDs, userID) {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("you have not booked this viewing"))
+		return
+	}
+
+	err = h.server.DB().CancelViewingBooking(slot.SlotID, userID)
+	if err != nil {
+		utils.RespondWithError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	notifyUser(h.server, propertyDetails.ListerUserID, config.NOTIFICATION_TYPE_VIEWING_CANCELLED,
+		fmt.Sprintf("A booking for the viewing of %s on %s was cancelled.", propertyDetails.Name, slot.StartTime.UTC().Format(time.RFC1123)),
+		propertyPageURL(propertyDetails.PropertyID))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GET .../properties/{id}/viewings/{slotId}/ics
+// AUTHED
+// Downloads a booked viewing as an iCalendar file, for the users who booked it and the lister.
+func (h *PropertyHandler) GetPropertyViewingICSHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(app_middleware.UserIDKey).(string)
+	if !ok {
+		utils.RespondWithError(w, http.StatusMethodNotAllowed, errors.New("user id blank"))
+		return
+	}
+
+	propertyDetails, err := h.server.DB().GetPropertyDetails(chi.URLParam(r, "id"))
+	if err != nil {
+		utils.RespondWithError(w, http.StatusNotFound, errors.New("property not found"))
+		return
+	}
+	slot, ok := h.getPropertyViewingSlot(w, r, propertyDetails)
+	if !ok {
+		return
+	}
+
+	if userID != propertyDetails.ListerUserID && userID != h.adminUserID {
+		bookerUserIDs, err := h.server.DB().GetViewingSlotBookers(slot.SlotID)
+		if err != nil {
+			utils.RespondWithError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if !slices.Contains(bookerUserIDs, userID) {
+			utils.RespondWithError(w, http.StatusNotFound, errors.New("you have not booked this viewing"))
+			return
+		}
+	}
+
+	calendar := ical.Calendar{
+		Method: "PUBLISH",
+		Events: []ical.Event{viewingEvent(propertyDetails, slot)},
+	}
+
+	w.Header().Set("Content-Type", ical.CONTENT_TYPE)
+	w.Header().Set("Content-Disposition", `attachment; filename="viewing.ics"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar.Bytes())
+}
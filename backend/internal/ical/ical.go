@@ -0,0 +1,122 @@
+// Package ical writes iCalendar (RFC 5545) files, used for downloadable events and
+// calendar feeds that users can subscribe to from their calendar app.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const PRODID = "-//Coop//Coop Casa//EN"
+
+const CONTENT_TYPE = "text/calendar; charset=utf-8"
+
+const STATUS_CONFIRMED = "CONFIRMED"
+const STATUS_TENTATIVE = "TENTATIVE"
+const STATUS_CANCELLED = "CANCELLED"
+
+// Lines longer than this many octets are folded onto continuation lines
+const maxLineLength = 75
+
+const timeFormat = "20060102T150405Z"
+
+type Event struct {
+	UID         string // globally unique and stable across updates of the event
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string    // one of the STATUS_* constants, omitted if empty
+	Sequence    int       // incremented every time the event is changed
+	Stamp       time.Time // when the event was last modified, the time of writing if zero
+}
+
+type Calendar struct {
+	Name   string // display name of a subscribed calendar
+	Method string // e.g. PUBLISH or CANCEL, omitted if empty
+	Events []Event
+}
+
+// Bytes renders the calendar as an iCalendar file
+func (c Calendar) Bytes() []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+PRODID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		writeLine(&buf, "METHOD:"+c.Method)
+	}
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	now := time.Now()
+	for _, event := range c.Events {
+		stamp := event.Stamp
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+event.UID)
+		writeLine(&buf, "DTSTAMP:"+formatTime(stamp))
+		writeLine(&buf, "DTSTART:"+formatTime(event.Start))
+		writeLine(&buf, "DTEND:"+formatTime(event.End))
+		writeLine(&buf, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.Location != "" {
+			writeLine(&buf, "LOCATION:"+escapeText(event.Location))
+		}
+		if event.URL != "" {
+			writeLine(&buf, "URL:"+event.URL)
+		}
+		if event.Status != "" {
+			writeLine(&buf, "STATUS:"+event.Status)
+		}
+		if event.Sequence > 0 {
+			writeLine(&buf, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		}
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// escapeText escapes the characters that have a special meaning in TEXT values
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeLine writes a content line terminated by CRLF, folding it so that no line is longer
+// than maxLineLength octets without splitting a multi-byte character.
+func writeLine(buf *bytes.Buffer, line string) {
+	lineLength := 0
+	for _, r := range line {
+		runeLength := len(string(r))
+		if lineLength+runeLength > maxLineLength {
+			buf.WriteString("\r\n ")
+			lineLength = 1
+		}
+		buf.WriteRune(r)
+		lineLength += runeLength
+	}
+	buf.WriteString("\r\n")
+}
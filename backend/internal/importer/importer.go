@@ -0,0 +1,471 @@
+// Package importer creates properties in bulk from CSV or JSON files, used by both the
+// bulk import endpoint and the import command line tool.
+package importer
+
+import (
+	"archive/zip"
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"backend/internal/validation"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const FORMAT_CSV = "csv"
+const FORMAT_JSON = "json"
+
+const ROW_STATUS_CREATED = "created"
+const ROW_STATUS_VALID = "valid"
+const ROW_STATUS_ERROR = "error"
+
+// PropertyRow is a single property to import. Images are filenames of images
+// in the accompanying image archive, in display order.
+type PropertyRow struct {
+	database.PropertyDetails
+	Images []string `json:"images"`
+}
+
+// RowResult is the outcome of importing a single row, rows are numbered from 1.
+type RowResult struct {
+	Row        int    `json:"row"`
+	PropertyID string `json:"propertyId"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Report struct {
+	DryRun    bool        `json:"dryRun"`
+	Total     int         `json:"total"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Rows      []RowResult `json:"rows"`
+}
+
+// The type of every CSV column that is not a plain string, columns are named after
+// the json fields of PropertyRow.
+var csvIntColumns = map[string]struct{}{
+	"squareFeet":      {},
+	"numBedrooms":     {},
+	"numToilets":      {},
+	"numShowersBaths": {},
+	"costDollars":     {},
+	"costCents":       {},
+	"depositDollars":  {},
+	"depositCents":    {},
+	"roomsAvailable":  {},
+}
+var csvFloatColumns = map[string]struct{}{
+	"latitude":  {},
+	"longitude": {},
+}
+var csvBoolColumns = map[string]struct{}{
+	"utilitiesIncluded": {},
+}
+var csvIntListColumns = map[string]struct{}{
+	"leaseLengthMonths": {},
+}
+var csvStringListColumns = map[string]struct{}{
+	"amenities": {},
+	"images":    {},
+}
+var csvStringColumns = map[string]struct{}{
+	"propertyId":    {},
+	"listerUserId":  {},
+	"name":          {},
+	"description":   {},
+	"address1":      {},
+	"address2":      {},
+	"city":          {},
+	"state":         {},
+	"zipcode":       {},
+	"country":       {},
+	"miscNote":      {},
+	"status":        {},
+	"priceType":     {},
+	"availableFrom": {},
+	"petsPolicy":    {},
+	"smokingPolicy": {},
+}
+
+// FormatFromFilename guesses the import format from the extension of a filename
+func FormatFromFilename(filename string) (string, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return FORMAT_CSV, nil
+	case ".json":
+		return FORMAT_JSON, nil
+	}
+	return "", fmt.Errorf("cannot determine import format of file \"%s\", expected a .csv or .json file", filename)
+}
+
+// ParseRows reads the properties to import from a CSV or JSON file
+func ParseRows(r io.Reader, format string) ([]PropertyRow, error) {
+	var rows []PropertyRow
+	var err error
+	switch format {
+	case FORMAT_CSV:
+		rows, err = ParseCSV(r)
+	case FORMAT_JSON:
+		rows, err = ParseJSON(r)
+	default:
+		return nil, fmt.Errorf("import format \"%s\" is not one of our supported options", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("import file does not contain any properties")
+	}
+	if len(rows) > config.PROPERTY_IMPORT_MAX_ROWS {
+		return nil, fmt.Errorf("import file cannot contain more than %d properties", config.PROPERTY_IMPORT_MAX_ROWS)
+	}
+	return rows, nil
+}
+
+// ParseJSON reads a JSON array of properties
+func ParseJSON(r io.Reader) ([]PropertyRow, error) {
+	var rows []PropertyRow
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&rows)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json import file: %s", err)
+	}
+	return rows, nil
+}
+
+// ParseCSV reads a CSV file of properties. The header row names the columns after the json
+// fields of PropertyRow, list columns separate their values with config.CSV_LIST_SEPARATOR.
+func ParseCSV(r io.Reader) ([]PropertyRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv import file: %s", err)
+	}
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		if !isCSVColumn(column) {
+			return nil, fmt.Errorf("unknown csv column \"%s\"", column)
+		}
+		header[i] = column
+	}
+
+	rows := []PropertyRow{}
+	for rowNum := 1; ; rowNum++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv import file: %s", err)
+		}
+
+		fields := make(map[string]any, len(header))
+		for i, column := range header {
+			value, err := parseCSVValue(column, strings.TrimSpace(record[i]))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: column \"%s\": %s", rowNum, column, err)
+			}
+			fields[column] = value
+		}
+
+		// Reuse the json field names and types of PropertyRow to fill it in
+		fieldsJSON, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		var row PropertyRow
+		err = json.Unmarshal(fieldsJSON, &row)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %s", rowNum, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func isCSVColumn(column string) bool {
+	for _, columns := range []map[string]struct{}{csvStringColumns, csvIntColumns, csvFloatColumns, csvBoolColumns, csvIntListColumns, csvStringListColumns} {
+		if _, ok := columns[column]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCSVValue(column, value string) (any, error) {
+	if _, ok := csvIntColumns[column]; ok {
+		if value == "" {
+			return 0, nil
+		}
+		return strconv.ParseInt(value, 10, 64)
+	}
+	if _, ok := csvFloatColumns[column]; ok {
+		if value == "" {
+			return nil, nil
+		}
+		return strconv.ParseFloat(value, 64)
+	}
+	if _, ok := csvBoolColumns[column]; ok {
+		if value == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(value)
+	}
+	if _, ok := csvIntListColumns[column]; ok {
+		values := []int64{}
+		for _, item := range splitCSVList(value) {
+			num, err := strconv.ParseInt(item, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, num)
+		}
+		return values, nil
+	}
+	if _, ok := csvStringListColumns[column]; ok {
+		return splitCSVList(value), nil
+	}
+	return value, nil
+}
+
+func splitCSVList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, config.CSV_LIST_SEPARATOR) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ReadImageArchive reads the images of a zip archive that are referenced by the given rows, keyed by
+// their path in the archive. Other files are skipped. The number of files in the archive and the
+// decompressed size of each image and of all of them are limited, so that a small archive cannot
+// expand into more data than an import may hold.
+func ReadImageArchive(data []byte, rows []PropertyRow) (map[string]database.FileInternal, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image archive: %s", err)
+	}
+	if len(archive.File) > config.PROPERTY_IMPORT_MAX_ARCHIVE_FILES {
+		return nil, fmt.Errorf("image archive cannot have more than %d files", config.PROPERTY_IMPORT_MAX_ARCHIVE_FILES)
+	}
+
+	referenced := make(map[string]bool)
+	for _, row := range rows {
+		for _, imageName := range row.Images {
+			referenced[imageName] = true
+		}
+	}
+
+	images := make(map[string]database.FileInternal)
+	var totalSize int64
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !referenced[file.Name] {
+			continue
+		}
+		if _, exists := images[file.Name]; exists {
+			continue
+		}
+
+		// The sizes declared by the archive are checked first to fail early, the reads are still
+		// limited as the declared sizes cannot be trusted
+		if file.UncompressedSize64 > config.PROPERTY_IMPORT_MAX_IMAGE_SIZE {
+			return nil, fmt.Errorf("image \"%s\" is larger than %d MiB", file.Name, config.PROPERTY_IMPORT_MAX_IMAGE_SIZE>>20)
+		}
+		if uint64(totalSize)+file.UncompressedSize64 > config.PROPERTY_IMPORT_MAX_IMAGES_SIZE {
+			return nil, fmt.Errorf("images are larger than %d MiB in total", config.PROPERTY_IMPORT_MAX_IMAGES_SIZE>>20)
+		}
+		imageData, err := readArchiveFile(file, config.PROPERTY_IMPORT_MAX_IMAGE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		totalSize += int64(len(imageData))
+		if totalSize > config.PROPERTY_IMPORT_MAX_IMAGES_SIZE {
+			return nil, fmt.Errorf("images are larger than %d MiB in total", config.PROPERTY_IMPORT_MAX_IMAGES_SIZE>>20)
+		}
+
+		images[file.Name] = database.FileInternal{
+			Filename: path.Base(file.Name),
+			Mimetype: http.DetectContentType(imageData),
+			Size:     int64(len(imageData)),
+			Data:     imageData,
+		}
+	}
+	return images, nil
+}
+
+// readArchiveFile reads a file of a zip archive, failing if it decompresses to more than maxSize bytes
+func readArchiveFile(file *zip.File, maxSize int64) ([]byte, error) {
+	fileReader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fileReader.Close()
+
+	// Read one byte past the limit to tell a file of exactly maxSize bytes from a larger one
+	data, err := io.ReadAll(io.LimitReader(fileReader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("image \"%s\" is larger than %d MiB", file.Name, maxSize>>20)
+	}
+	return data, nil
+}
+
+// Importer validates and creates imported properties on behalf of a lister
+type Importer struct {
+	db database.Service
+}
+
+func New(db database.Service) *Importer {
+	return &Importer{db: db}
+}
+
+// Import validates every row and, unless it is a dry run, creates the properties of the valid
+// rows. Rows are independent of each other, an invalid row does not stop the rest from being imported.
+// Properties are owned by listerUserID unless the importing user is the admin and the row names a lister.
+func (im *Importer) Import(rows []PropertyRow, images map[string]database.FileInternal, listerUserID string, isAdmin, dryRun bool) (Report, error) {
+	catalog, err := database.AmenityCatalog(im.db)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   []RowResult{},
+	}
+
+	// Normalized addresses of the rows before the current one, duplicates within the file
+	// are not in the db yet on a dry run so they are checked separately
+	seenAddresses := make(map[string]int)
+
+	for i, row := range rows {
+		result := RowResult{Row: i + 1}
+
+		propertyDetails, propertyImages, err := im.prepareRow(row, images, catalog, listerUserID, isAdmin)
+		if err == nil {
+			address := utils.NormalizeAddress(propertyDetails.Address_1, propertyDetails.Address_2, propertyDetails.City, propertyDetails.State, propertyDetails.Zipcode, propertyDetails.Country)
+			if otherRow, seen := seenAddresses[address]; seen {
+				err = fmt.Errorf("property uses the same address as row %d", otherRow)
+			} else {
+				seenAddresses[address] = i + 1
+			}
+		}
+		if err == nil && !dryRun {
+			err = im.create(propertyDetails, propertyImages)
+		}
+
+		result.PropertyID = propertyDetails.PropertyID
+		if err != nil {
+			result.Status = ROW_STATUS_ERROR
+			result.Error = err.Error()
+			report.Failed++
+		} else {
+			if dryRun {
+				result.Status = ROW_STATUS_VALID
+			} else {
+				result.Status = ROW_STATUS_CREATED
+			}
+			report.Succeeded++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
+
+// prepareRow fills in the defaults of a row the same way as creating a single property
+// and checks that it could be created
+func (im *Importer) prepareRow(row PropertyRow, images map[string]database.FileInternal, catalog map[string]struct{}, listerUserID string, isAdmin bool) (database.PropertyDetails, []database.OrderedFileInternal, error) {
+	propertyDetails := row.PropertyDetails
+
+	if propertyDetails.PropertyID == "" {
+		propertyDetails.PropertyID = uuid.New().String()
+	}
+	if propertyDetails.ListerUserID == "" || !isAdmin {
+		if propertyDetails.ListerUserID != "" && propertyDetails.ListerUserID != listerUserID {
+			return propertyDetails, nil, errors.New("cannot import properties for another lister")
+		}
+		propertyDetails.ListerUserID = listerUserID
+	}
+	if propertyDetails.Price_type == "" {
+		propertyDetails.Price_type = config.PROPERTY_PRICE_TYPE_MONTHLY
+	}
+	if propertyDetails.Status == "" {
+		propertyDetails.Status = config.PROPERTY_STATUS_PUBLISHED
+	}
+	if propertyDetails.Status != config.PROPERTY_STATUS_PUBLISHED && propertyDetails.Status != config.PROPERTY_STATUS_DRAFT {
+		return propertyDetails, nil, errors.New("new properties can only be created as a draft or published")
+	}
+
+	err := validation.ValidatePropertyDetails(propertyDetails)
+	if err != nil {
+		return propertyDetails, nil, err
+	}
+	err = validation.ValidatePropertyAmenities(propertyDetails.Amenities, catalog)
+	if err != nil {
+		return propertyDetails, nil, err
+	}
+	err = im.db.CheckDuplicateProperty(propertyDetails)
+	if err != nil {
+		return propertyDetails, nil, err
+	}
+
+	if len(row.Images) > config.PROPERTY_IMPORT_MAX_IMAGES {
+		return propertyDetails, nil, fmt.Errorf("property cannot have more than %d images", config.PROPERTY_IMPORT_MAX_IMAGES)
+	}
+	// Drafts can be imported without images and have them added before they are published
+	if len(row.Images) == 0 && propertyDetails.Status == config.PROPERTY_STATUS_PUBLISHED {
+		return propertyDetails, nil, errors.New("published property must have at least one image")
+	}
+	propertyImages := []database.OrderedFileInternal{}
+	for i, imageName := range row.Images {
+		image, ok := images[imageName]
+		if !ok {
+			return propertyDetails, nil, fmt.Errorf("image \"%s\" is not in the image archive", imageName)
+		}
+		err = validation.ValidatePropertyImage(image)
+		if err != nil {
+			return propertyDetails, nil, err
+		}
+		propertyImages = append(propertyImages, database.OrderedFileInternal{
+			OrderNum: int16(i),
+			File:     image,
+		})
+	}
+
+	return propertyDetails, propertyImages, nil
+}
+
+func (im *Importer) create(propertyDetails database.PropertyDetails, images []database.OrderedFileInternal) error {
+	err := im.db.CreateProperty(propertyDetails, images)
+	if err != nil {
+		return err
+	}
+	// The property is created already, so a failure to flag it is only logged
+	err = im.db.FlagSimilarProperties(propertyDetails, config.PROPERTY_DUPLICATE_SIMILARITY_THRESHOLD)
+	if err != nil {
+		log.Printf("failed to flag the similar properties of property %s: %s", propertyDetails.PropertyID, err)
+	}
+	return nil
+}
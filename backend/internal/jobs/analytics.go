@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"time"
+)
+
+// AnalyticsPruningJob deletes the listing analytics viewer events no longer needed to de-duplicate
+// views and saves. The daily counters are kept.
+func AnalyticsPruningJob(db database.Service) Job {
+	return Job{
+		Name:     "analytics pruning",
+		Interval: config.JOB_INTERVAL_ANALYTICS_PRUNING,
+		Run: func(now time.Time) error {
+			_, err := db.DeletePropertyAnalyticsEventsBefore(now.AddDate(0, 0, -config.PROPERTY_ANALYTICS_EVENTS_RETENTION_DAYS))
+			return err
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/recurrence"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommunityInvitesPruningJob deletes the community invites that expired without being accepted or declined
+func CommunityInvitesPruningJob(db database.Service) Job {
+	return Job{
+		Name:     "community invites pruning",
+		Interval: config.JOB_INTERVAL_COMMUNITY_INVITES_PRUNING,
+		Run: func(now time.Time) error {
+			_, err := db.DeleteExpiredCommunityMembershipRequests(now)
+			return err
+		},
+	}
+}
+
+// CommunityChoreTasksJob schedules the tasks of the chores of every community for the days ahead
+func CommunityChoreTasksJob(db database.Service) Job {
+	return Job{
+		Name:     "community chore tasks",
+		Interval: config.JOB_INTERVAL_COMMUNITY_CHORE_TASKS,
+		Run: func(now time.Time) error {
+			chores, err := db.GetAllCommunityChores()
+			if err != nil {
+				return err
+			}
+			for _, chore := range chores {
+				// A chore failing to be scheduled does not hold back the others
+				err = ScheduleCommunityChoreTasks(db, chore, now)
+				if err != nil {
+					log.Printf("failed to schedule the tasks of chore %s: %s", chore.ChoreID, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// ScheduleCommunityChoreTasks creates the tasks of the occurrences of a chore due within the days
+// scheduled ahead. The nth occurrence of a chore is assigned to the nth member of its rotation, so
+// swapping a task does not change who is next. Occurrences already past that were never scheduled,
+// e.g. of a chore starting in the past, are skipped.
+func ScheduleCommunityChoreTasks(db database.Service, chore database.CommunityChore, now time.Time) error {
+	if len(chore.Rotation) == 0 {
+		return nil
+	}
+
+	startsOn, err := time.Parse("2006-01-02", chore.StartsOn)
+	if err != nil {
+		return err
+	}
+	rule := recurrence.Rule{
+		Frequency: chore.Frequency,
+		Interval:  int(chore.Interval),
+		Start:     startsOn,
+	}
+
+	lastOccurrence, err := db.GetCommunityChoreLastOccurrence(chore.ChoreID)
+	if err != nil {
+		return err
+	}
+
+	scheduleUntil := recurrence.Day(now).AddDate(0, 0, config.COMMUNITY_CHORE_SCHEDULE_DAYS)
+	for n := max(int(lastOccurrence)+1, rule.Next(now)); !rule.Occurrence(n).After(scheduleUntil); n++ {
+		err = db.CreateCommunityChoreTask(database.CommunityChoreTask{
+			TaskID:         uuid.New().String(),
+			ChoreID:        chore.ChoreID,
+			CommunityID:    chore.CommunityID,
+			ChoreTitle:     chore.Title,
+			Occurrence:     int32(n),
+			DueOn:          rule.Occurrence(n).Format("2006-01-02"),
+			AssigneeUserID: chore.Rotation[n%len(chore.Rotation)],
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
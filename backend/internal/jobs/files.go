@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"time"
+)
+
+// FileBlobsPruningJob deletes the file blobs no longer referenced by any file, e.g. left behind by
+// an upload that failed after storing its blob.
+func FileBlobsPruningJob(db database.Service) Job {
+	return Job{
+		Name:     "file blobs pruning",
+		Interval: config.JOB_INTERVAL_FILE_BLOBS_PRUNING,
+		Run: func(now time.Time) error {
+			_, err := db.DeleteUnreferencedFileBlobs(now.Add(-config.FILE_BLOBS_UNREFERENCED_RETENTION_HOURS * time.Hour))
+			return err
+		},
+	}
+}
@@ -0,0 +1,71 @@
+// Package jobs runs the recurring background work of the server, e.g. expiring listings
+// that were not renewed.
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Job is work run every interval, it is given the time of the run. A job without an interval is
+// only run once, e.g. to backfill data on startup.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+}
+
+// Scheduler runs each of its jobs once when started and then every interval of the job
+// until stopped. Errors of a run are logged, the job still runs again on its next interval.
+type Scheduler struct {
+	jobs []Job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler(jobs ...Job) *Scheduler {
+	return &Scheduler{
+		jobs: jobs,
+		stop: make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(job)
+	}
+}
+
+// Stop stops scheduling runs and waits for the runs in progress to finish
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(job Job) {
+	defer s.wg.Done()
+
+	if job.Interval == 0 {
+		if err := job.Run(time.Now()); err != nil {
+			log.Printf("job %s failed: %s", job.Name, err)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(time.Now()); err != nil {
+			log.Printf("job %s failed: %s", job.Name, err)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
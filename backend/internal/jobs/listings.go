@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/utils"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ListingExpiryJob reminds listers of listings about to expire and expires the listings that
+// were not renewed in time.
+func ListingExpiryJob(db database.Service) Job {
+	return Job{
+		Name:     "listing expiry",
+		Interval: config.JOB_INTERVAL_LISTING_EXPIRY,
+		Run: func(now time.Time) error {
+			err := RemindExpiringListings(db, now)
+			if err != nil {
+				return err
+			}
+			return ExpireListings(db, now)
+		},
+	}
+}
+
+// propertyPageURL returns the absolute url of a property's page on the frontend
+func propertyPageURL(propertyID string) string {
+	return fmt.Sprintf("%s/properties/%s", config.GlobalConfig.FRONTEND_ORIGIN, propertyID)
+}
+
+// notifyLister notifies the lister of a property, failing to do so does not fail the job
+func notifyLister(db database.Service, property database.ExpiringProperty, notificationType, message string) {
+	err := db.CreateNotification(property.ListerUserID, notificationType, message, propertyPageURL(property.PropertyID))
+	if err != nil {
+		log.Printf("failed to send %s notification: %s", notificationType, err)
+	}
+}
+
+// RemindExpiringListings notifies the listers of published listings expiring within the reminder
+// period to renew them, once per listing period.
+func RemindExpiringListings(db database.Service, now time.Time) error {
+	properties, err := db.RemindExpiringProperties(now.AddDate(0, 0, config.PROPERTY_EXPIRY_REMINDER_DAYS))
+	if err != nil {
+		return err
+	}
+
+	for _, property := range properties {
+		notifyLister(db, property, config.NOTIFICATION_TYPE_LISTING_EXPIRING,
+			fmt.Sprintf("Your listing %s expires on %s, renew it to keep it published", property.Name, property.ExpiresAt.Format("January 2, 2006")))
+	}
+	return nil
+}
+
+// ExpireListings hides the published listings that expired, recording the status change in the
+// history of each property and notifying their listers.
+func ExpireListings(db database.Service, now time.Time) error {
+	properties, err := db.ExpireProperties(now)
+	if err != nil {
+		return err
+	}
+
+	for _, property := range properties {
+		err = db.CreatePropertyHistory(property.PropertyID, "", []database.PropertyChange{{
+			Field:    database.PROPERTY_HISTORY_FIELD_STATUS,
+			OldValue: config.PROPERTY_STATUS_PUBLISHED,
+			NewValue: config.PROPERTY_STATUS_EXPIRED,
+		}})
+		if err != nil {
+			return err
+		}
+
+		notifyLister(db, property, config.NOTIFICATION_TYPE_LISTING_EXPIRED,
+			fmt.Sprintf("Your listing %s expired and is no longer published, renew it to publish it again", property.Name))
+	}
+	return nil
+}
+
+// AddressNormalizationJob backfills the normalized addresses used for duplicate detection with
+// utils.NormalizeAddress. It only runs once on startup, as new and updated properties are
+// normalized when stored, so properties created before address normalization, or before a change
+// to it, are normalized again on the next start.
+func AddressNormalizationJob(db database.Service) Job {
+	return Job{
+		Name: "address normalization",
+		Run: func(now time.Time) error {
+			_, err := NormalizePropertyAddresses(db)
+			return err
+		},
+	}
+}
+
+// NormalizePropertyAddresses updates the properties whose stored normalized address differs from
+// the normalization of their address, returning the number of properties updated.
+func NormalizePropertyAddresses(db database.Service) (int, error) {
+	addresses, err := db.GetPropertyAddresses()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, address := range addresses {
+		normalizedAddress := utils.NormalizeAddress(address.Address_1, address.Address_2, address.City, address.State, address.Zipcode, address.Country)
+		if normalizedAddress == address.NormalizedAddress {
+			continue
+		}
+		err = db.UpdatePropertyNormalizedAddress(address.PropertyID, normalizedAddress)
+		if err != nil {
+			return updated, err
+		}
+		updated++
+	}
+	return updated, nil
+}
@@ -0,0 +1,283 @@
+// Package ledger splits the shared expenses of a community between its members, keeps their
+// balances and works out who owes whom to settle up. Amounts are in cents.
+package ledger
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Balance is where a member stands in the ledger of a community. The members who deleted their
+// account share a single balance with an empty user id, so that the balances still add up.
+type Balance struct {
+	UserID       string `json:"userId"`
+	PaidCents    int64  `json:"paidCents"`    // paid for the expenses of others and themselves
+	OwedCents    int64  `json:"owedCents"`    // their own part of the expenses
+	BalanceCents int64  `json:"balanceCents"` // positive if others owe them, negative if they owe others
+}
+
+// Settlement is a payment from a member to another one to settle their balances
+type Settlement struct {
+	FromUserID  string `json:"fromUserId"`
+	ToUserID    string `json:"toUserId"`
+	AmountCents int64  `json:"amountCents"`
+}
+
+// StatementEntry is an expense of the ledger from the point of view of a single member
+type StatementEntry struct {
+	ExpenseID    string `json:"expenseId"`
+	SpentOn      string `json:"spentOn"`
+	Description  string `json:"description"`
+	Category     string `json:"category"`
+	ChangeCents  int64  `json:"changeCents"`  // how much the expense changed the balance of the member
+	BalanceCents int64  `json:"balanceCents"` // running balance of the member after the expense
+}
+
+// Split computes the part each member owes of an expense following its split type. The amounts
+// of the splits are only kept when the expense is split in exact amounts, which must add up to
+// the amount of the expense. Cents that cannot be split evenly go to the first members.
+func Split(amountCents int64, splitType string, splits []database.CommunityExpenseSplit) ([]database.CommunityExpenseSplit, error) {
+	if len(splits) == 0 {
+		return nil, errors.New("an expense must be split with at least one member")
+	}
+
+	result := make([]database.CommunityExpenseSplit, len(splits))
+	copy(result, splits)
+
+	switch splitType {
+	case config.COMMUNITY_EXPENSE_SPLIT_EQUAL:
+		for i := range result {
+			result[i].Shares = 0
+			result[i].AmountCents = amountCents / int64(len(result))
+			if int64(i) < amountCents%int64(len(result)) {
+				result[i].AmountCents++
+			}
+		}
+	case config.COMMUNITY_EXPENSE_SPLIT_SHARES:
+		var totalShares int64
+		for _, split := range result {
+			if split.Shares <= 0 {
+				return nil, errors.New("each member must have at least one share of the expense")
+			}
+			totalShares += int64(split.Shares)
+		}
+
+		// Round every part down, then hand out the cents left over to the largest remainders
+		remainders := make([]int64, len(result))
+		leftoverCents := amountCents
+		for i := range result {
+			result[i].AmountCents = amountCents * int64(result[i].Shares) / totalShares
+			remainders[i] = amountCents * int64(result[i].Shares) % totalShares
+			leftoverCents -= result[i].AmountCents
+		}
+		order := make([]int, len(result))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return remainders[order[a]] > remainders[order[b]]
+		})
+		for i := int64(0); i < leftoverCents; i++ {
+			result[order[i]].AmountCents++
+		}
+	case config.COMMUNITY_EXPENSE_SPLIT_EXACT:
+		var totalCents int64
+		for i, split := range result {
+			if split.AmountCents < 0 {
+				return nil, errors.New("the amount owed by a member cannot be negative")
+			}
+			result[i].Shares = 0
+			totalCents += split.AmountCents
+		}
+		if totalCents != amountCents {
+			return nil, fmt.Errorf("the exact amounts add up to %s instead of %s", FormatCents(totalCents), FormatCents(amountCents))
+		}
+	default:
+		return nil, fmt.Errorf("split type \"%s\" is not one of our supported options", splitType)
+	}
+
+	return result, nil
+}
+
+// Balances computes the balance of every member appearing in the expenses, the members owed
+// the most first
+func Balances(expenses []database.CommunityExpense) []Balance {
+	balances := map[string]*Balance{}
+	balanceOf := func(userID string) *Balance {
+		if _, ok := balances[userID]; !ok {
+			balances[userID] = &Balance{UserID: userID}
+		}
+		return balances[userID]
+	}
+
+	for _, expense := range expenses {
+		balanceOf(expense.PayerUserID).PaidCents += expense.AmountCents
+		for _, split := range expense.Splits {
+			balanceOf(split.UserID).OwedCents += split.AmountCents
+		}
+	}
+
+	result := []Balance{}
+	for _, balance := range balances {
+		balance.BalanceCents = balance.PaidCents - balance.OwedCents
+		result = append(result, *balance)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BalanceCents != result[j].BalanceCents {
+			return result[i].BalanceCents > result[j].BalanceCents
+		}
+		return result[i].UserID < result[j].UserID
+	})
+	return result
+}
+
+// Settle works out the payments that settle all the balances. The member owing the most pays the
+// member owed the most until either is settled, which takes at most one payment less than the
+// number of members with a balance.
+func Settle(balances []Balance) []Settlement {
+	creditors := []Balance{}
+	debtors := []Balance{}
+	for _, balance := range balances {
+		if balance.BalanceCents > 0 {
+			creditors = append(creditors, balance)
+		} else if balance.BalanceCents < 0 {
+			debtors = append(debtors, balance)
+		}
+	}
+
+	settlements := []Settlement{}
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.SliceStable(creditors, func(i, j int) bool { return creditors[i].BalanceCents > creditors[j].BalanceCents })
+		sort.SliceStable(debtors, func(i, j int) bool { return debtors[i].BalanceCents < debtors[j].BalanceCents })
+
+		amountCents := min(creditors[0].BalanceCents, -debtors[0].BalanceCents)
+		settlements = append(settlements, Settlement{
+			FromUserID:  debtors[0].UserID,
+			ToUserID:    creditors[0].UserID,
+			AmountCents: amountCents,
+		})
+
+		creditors[0].BalanceCents -= amountCents
+		debtors[0].BalanceCents += amountCents
+		if creditors[0].BalanceCents == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].BalanceCents == 0 {
+			debtors = debtors[1:]
+		}
+	}
+	return settlements
+}
+
+// Statement lists the expenses a member paid or shares with the running balance of the member
+// after each of them, the expenses are expected in the order they were spent
+func Statement(expenses []database.CommunityExpense, userID string) []StatementEntry {
+	entries := []StatementEntry{}
+	var balanceCents int64
+	for _, expense := range expenses {
+		var changeCents int64
+		involved := false
+		if expense.PayerUserID == userID {
+			changeCents += expense.AmountCents
+			involved = true
+		}
+		for _, split := range expense.Splits {
+			if split.UserID == userID {
+				changeCents -= split.AmountCents
+				involved = true
+			}
+		}
+		if !involved {
+			continue
+		}
+
+		balanceCents += changeCents
+		entries = append(entries, StatementEntry{
+			ExpenseID:    expense.ExpenseID,
+			SpentOn:      expense.SpentOn,
+			Description:  expense.Description,
+			Category:     expense.Category,
+			ChangeCents:  changeCents,
+			BalanceCents: balanceCents,
+		})
+	}
+	return entries
+}
+
+// FormatCents formats an amount in cents as dollars, e.g. 1234 as 12.34
+func FormatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+var CSV_COLUMNS = []string{
+	"expenseId",
+	"spentOn",
+	"description",
+	"category",
+	"payerUserId",
+	"amount",
+	"splitType",
+	"userId",
+	"shares",
+	"owed",
+}
+
+// csvCell escapes a value written by members so that a spreadsheet opening the csv shows it as text
+// rather than evaluating it as a formula, e.g. =HYPERLINK(...)
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// WriteCSV writes the expenses as csv with a row for each split of an expense. Descriptions are
+// escaped so that they are not evaluated as formulas.
+func WriteCSV(w io.Writer, expenses []database.CommunityExpense) error {
+	writer := csv.NewWriter(w)
+
+	err := writer.Write(CSV_COLUMNS)
+	if err != nil {
+		return err
+	}
+
+	for _, expense := range expenses {
+		for _, split := range expense.Splits {
+			shares := ""
+			if split.Shares > 0 {
+				shares = strconv.Itoa(int(split.Shares))
+			}
+
+			err = writer.Write([]string{
+				expense.ExpenseID,
+				expense.SpentOn,
+				csvCell(expense.Description),
+				expense.Category,
+				expense.PayerUserID,
+				FormatCents(expense.AmountCents),
+				expense.SplitType,
+				split.UserID,
+				shares,
+				FormatCents(split.AmountCents),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,132 @@
+// Package recommend ranks properties by how similar they are to a given property on
+// price, bedrooms, bathrooms, square footage and location.
+package recommend
+
+import (
+	"backend/internal/database"
+	"backend/internal/utils"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Weight of each dimension in the similarity of two properties, they add up to 1
+const (
+	WEIGHT_PRICE       = 0.30
+	WEIGHT_BEDROOMS    = 0.20
+	WEIGHT_BATHROOMS   = 0.10
+	WEIGHT_SQUARE_FEET = 0.15
+	WEIGHT_LOCATION    = 0.25
+)
+
+// Properties at least this far apart are not similar in location at all
+const MAX_DISTANCE_KM = 25.0
+
+// Differences in room counts at which properties are not similar on that dimension at all
+const maxBedroomsDifference = 3
+const maxBathroomsDifference = 2
+
+type Match struct {
+	PropertyID string  `json:"propertyId"`
+	Similarity float64 `json:"similarity"` // between 0 and 1, 1 being most similar
+}
+
+// Similarity returns how similar two properties are, between 0 and 1
+func Similarity(a, b database.PropertyDetails) float64 {
+	price := relativeSimilarity(
+		float64(a.Cost_dollars)+float64(a.Cost_cents)/100,
+		float64(b.Cost_dollars)+float64(b.Cost_cents)/100,
+	)
+	bedrooms := countSimilarity(a.Num_bedrooms, b.Num_bedrooms, maxBedroomsDifference)
+	bathrooms := countSimilarity(a.Num_showers_baths, b.Num_showers_baths, maxBathroomsDifference)
+	squareFeet := relativeSimilarity(float64(a.Square_feet), float64(b.Square_feet))
+
+	return WEIGHT_PRICE*price +
+		WEIGHT_BEDROOMS*bedrooms +
+		WEIGHT_BATHROOMS*bathrooms +
+		WEIGHT_SQUARE_FEET*squareFeet +
+		WEIGHT_LOCATION*LocationSimilarity(a, b)
+}
+
+// LocationSimilarity compares the distance of two properties if both have coordinates,
+// and falls back to comparing their zipcode, city and state otherwise.
+func LocationSimilarity(a, b database.PropertyDetails) float64 {
+	if a.Latitude != nil && a.Longitude != nil && b.Latitude != nil && b.Longitude != nil {
+		distance := utils.DistanceKm(*a.Latitude, *a.Longitude, *b.Latitude, *b.Longitude)
+		return 1 - math.Min(distance/MAX_DISTANCE_KM, 1)
+	}
+
+	sameState := strings.EqualFold(strings.TrimSpace(a.State), strings.TrimSpace(b.State))
+	switch {
+	case sameState && strings.EqualFold(strings.TrimSpace(a.Zipcode), strings.TrimSpace(b.Zipcode)):
+		return 1
+	case sameState && strings.EqualFold(strings.TrimSpace(a.City), strings.TrimSpace(b.City)):
+		return 0.7
+	case sameState:
+		return 0.3
+	}
+	return 0
+}
+
+// Rank returns the candidates ordered by their similarity to the property, most similar
+// first, keeping at most limit matches.
+func Rank(property database.PropertyDetails, candidates []database.PropertyDetails, limit int) []Match {
+	matches := []Match{}
+	for _, candidate := range candidates {
+		if candidate.PropertyID == property.PropertyID {
+			continue
+		}
+		matches = append(matches, Match{
+			PropertyID: candidate.PropertyID,
+			Similarity: Similarity(property, candidate),
+		})
+	}
+	return top(matches, limit)
+}
+
+// Merge combines the rankings for several properties into one, e.g. for each of a user's
+// saved properties. A property matching several of them keeps its highest similarity.
+func Merge(rankings [][]Match, limit int) []Match {
+	best := make(map[string]float64)
+	for _, ranking := range rankings {
+		for _, match := range ranking {
+			if similarity, exists := best[match.PropertyID]; !exists || match.Similarity > similarity {
+				best[match.PropertyID] = match.Similarity
+			}
+		}
+	}
+
+	matches := []Match{}
+	for propertyID, similarity := range best {
+		matches = append(matches, Match{PropertyID: propertyID, Similarity: similarity})
+	}
+	return top(matches, limit)
+}
+
+// top sorts the matches by similarity and then id, so that ties are ranked consistently
+func top(matches []Match, limit int) []Match {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Similarity != matches[j].Similarity {
+			return matches[i].Similarity > matches[j].Similarity
+		}
+		return matches[i].PropertyID < matches[j].PropertyID
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// relativeSimilarity is 1 for equal values and approaches 0 the further apart they are relative to the larger one
+func relativeSimilarity(a, b float64) float64 {
+	larger := math.Max(a, b)
+	if larger <= 0 {
+		return 1
+	}
+	return 1 - math.Abs(a-b)/larger
+}
+
+// countSimilarity is 1 for equal counts and 0 once they differ by maxDifference or more
+func countSimilarity(a, b int16, maxDifference float64) float64 {
+	return 1 - math.Min(math.Abs(float64(a-b))/maxDifference, 1)
+}
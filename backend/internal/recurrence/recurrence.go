@@ -0,0 +1,62 @@
+// Package recurrence computes the dates on which something recurring is due, e.g. the chores of
+// a community. Dates are days, their time of day is ignored.
+package recurrence
+
+import (
+	"backend/internal/config"
+	"time"
+)
+
+// Rule recurs every interval of days, weeks or months from a start date
+type Rule struct {
+	Frequency string
+	Interval  int
+	Start     time.Time
+}
+
+// Occurrence returns the date of the nth occurrence of the rule, the first being its start date.
+// Monthly occurrences fall on the day of the month of the start date, or on the last day of the
+// month for shorter months, e.g. the 31st then the 30th of April.
+func (r Rule) Occurrence(n int) time.Time {
+	start := Day(r.Start)
+	switch r.Frequency {
+	case config.COMMUNITY_CHORE_FREQUENCY_WEEKLY:
+		return start.AddDate(0, 0, 7*n*r.Interval)
+	case config.COMMUNITY_CHORE_FREQUENCY_MONTHLY:
+		firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+		day := min(start.Day(), firstOfMonth.AddDate(0, 1, -1).Day())
+		return firstOfMonth.AddDate(0, 0, day-1)
+	default:
+		return start.AddDate(0, 0, n*r.Interval)
+	}
+}
+
+// Next returns the index of the first occurrence of the rule that is on or after the given date
+func (r Rule) Next(date time.Time) int {
+	date = Day(date)
+	start := Day(r.Start)
+	if !date.After(start) {
+		return 0
+	}
+
+	// Estimate the index from the number of days in between, then step to the exact one
+	days := int(date.Sub(start).Hours() / 24)
+	var n int
+	switch r.Frequency {
+	case config.COMMUNITY_CHORE_FREQUENCY_WEEKLY:
+		n = days / (7 * r.Interval)
+	case config.COMMUNITY_CHORE_FREQUENCY_MONTHLY:
+		n = days / (31 * r.Interval)
+	default:
+		n = days / r.Interval
+	}
+	for r.Occurrence(n).Before(date) {
+		n++
+	}
+	return n
+}
+
+// Day truncates a time to the date it falls on, in UTC
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -38,7 +38,14 @@ func NewAccountRouter(s interfaces.Server) http.Handler {
 	r.Delete("/", accountHandlers.DeleteAccountHandler)
 	r.Get("/role", accountHandlers.GetAccountRoleHandler)
 	r.Get("/communities", accountHandlers.GetAccountOwnedCommunitiesHandler)
+	r.Get("/communities/membership", accountHandlers.GetAccountCommunityMembershipRequestsHandler)
 	r.Get("/properties", accountHandlers.GetAccountOwnedPropertiesHandler)
+	r.Get("/properties/analytics", accountHandlers.GetAccountPropertiesAnalyticsHandler)
+	r.Get("/properties/analytics/{id}", accountHandlers.GetAccountPropertyAnalyticsHandler)
+	r.Get("/properties/{id}/communities", accountHandlers.GetAccountPropertyCommunitiesHandler)
+	r.Delete("/properties/{id}/communities/{communityId}", accountHandlers.DeleteAccountPropertyCommunityHandler)
+	r.Get("/properties/communities/requests", accountHandlers.GetAccountCommunityPropertyLinkRequestsHandler)
+	r.Put("/properties/communities/requests/{requestId}", accountHandlers.DecideAccountCommunityPropertyLinkRequestHandler)
 	r.Get("/images", accountHandlers.GetAccountProfileImagesHandler)
 	r.Post("/images", accountHandlers.UpdateAccountProfileImagesHandler)
 
@@ -62,6 +69,24 @@ func NewAccountRouter(s interfaces.Server) http.Handler {
 	r.Get("/status", accountHandlers.GetAccountStatusHandler)
 	r.Put("/status", accountHandlers.UpdateAccountStatusHandler)
 
+	// notifications
+	r.Get("/notifications", accountHandlers.GetAccountNotificationsHandler)
+	r.Put("/notifications/read", accountHandlers.UpdateAccountNotificationsReadHandler)
+	r.Put("/notifications/{id}/read", accountHandlers.UpdateAccountNotificationReadHandler)
+
+	// chore tasks assigned to the user
+	r.Get("/tasks", accountHandlers.GetAccountChoreTasksHandler)
+
+	// viewings and calendar
+	r.Get("/viewings", accountHandlers.GetAccountViewingsHandler)
+	r.Post("/calendar", accountHandlers.CreateAccountCalendarTokenHandler)
+
+	// rental applications
+	r.Get("/applications", accountHandlers.GetAccountApplicationsHandler)
+
+	// recommended properties based on the saved properties
+	r.Get("/recommendations", accountHandlers.GetAccountRecommendationsHandler)
+
 	return r
 }
 
@@ -73,6 +98,7 @@ func NewListerRouter(s interfaces.Server) http.Handler {
 	listerHandlers := handlers.NewListerHandlers(s)
 
 	r.Get("/{id}", listerHandlers.GetListerInfoHandler)
+	r.Get("/{id}/export", listerHandlers.GetListerPropertiesExportHandler)
 	r.With(app_middleware.AuthMiddleware).Get("/", listerHandlers.GetListersFromListersHandler)
 
 	return r
@@ -97,6 +123,12 @@ func NewAdminRouter(s interfaces.Server) http.Handler {
 	r.Get("/total/communities", adminHandlers.GetTotalCommunitiesCountHandler)
 	r.Get("/total/users", adminHandlers.GetTotalUsersCountHandler)
 
+	r.Post("/amenities", adminHandlers.AdminCreateAmenityHandler)
+	r.Delete("/amenities/{name}", adminHandlers.AdminDeleteAmenityHandler)
+
+	r.Get("/properties/duplicates", adminHandlers.AdminGetPropertyDuplicateReviewsHandler)
+	r.Put("/properties/duplicates/{id}", adminHandlers.AdminResolvePropertyDuplicateReviewHandler)
+
 	return r
 }
 
@@ -106,15 +138,68 @@ func NewPropertyRouter(s interfaces.Server) http.Handler {
 	r := chi.NewRouter()
 
 	propertyHandlers := handlers.NewPropertyHandlers(s)
-	r.Get("/{id}", propertyHandlers.GetPropertyHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}", propertyHandlers.GetPropertyHandler)
 	r.Get("/", propertyHandlers.GetPropertiesHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/compare", propertyHandlers.GetPropertiesComparisonHandler)
 
 	r.With(app_middleware.AuthMiddleware).Post("/", propertyHandlers.CreatePropertiesHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/import", propertyHandlers.ImportPropertiesHandler)
 	r.With(app_middleware.AuthMiddleware).Put("/{id}", propertyHandlers.UpdatePropertiesHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/status", propertyHandlers.UpdatePropertyStatusHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/renew", propertyHandlers.RenewPropertyHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/history", propertyHandlers.GetPropertyHistoryHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/export", propertyHandlers.GetPropertyExportHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/images/{orderNum}", propertyHandlers.GetPropertyImageHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/similar", propertyHandlers.GetSimilarPropertiesHandler)
 	r.With(app_middleware.AuthMiddleware).Put("/transfer/ownership", propertyHandlers.TransferPropertyOwnershipHandler)
 	r.With(app_middleware.AuthMiddleware).Post("/transfer/ownership/all", propertyHandlers.TransferAllPropertiesOwnershipHandler)
 	r.With(app_middleware.AuthMiddleware).Delete("/{id}", propertyHandlers.DeletePropertiesHandler)
 
+	// rooms of a property
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/rooms", propertyHandlers.GetPropertyRoomsHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/rooms/{roomId}", propertyHandlers.GetPropertyRoomHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/rooms", propertyHandlers.CreatePropertyRoomHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/rooms/{roomId}", propertyHandlers.UpdatePropertyRoomHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/rooms/{roomId}", propertyHandlers.DeletePropertyRoomHandler)
+
+	// viewings of a property
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/viewings", propertyHandlers.GetPropertyViewingSlotsHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/viewings", propertyHandlers.CreatePropertyViewingSlotHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/viewings/{slotId}", propertyHandlers.DeletePropertyViewingSlotHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/viewings/{slotId}/booking", propertyHandlers.CreatePropertyViewingBookingHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/viewings/{slotId}/booking", propertyHandlers.DeletePropertyViewingBookingHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/viewings/{slotId}/ics", propertyHandlers.GetPropertyViewingICSHandler)
+
+	// rental applications to a property
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/applications", propertyHandlers.GetPropertyApplicationsHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/applications", propertyHandlers.CreatePropertyApplicationHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/applications/{applicationId}", propertyHandlers.GetPropertyApplicationHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/applications/{applicationId}/status", propertyHandlers.UpdatePropertyApplicationStatusHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/applications/{applicationId}", propertyHandlers.DeletePropertyApplicationHandler)
+
+	return r
+}
+
+// NewAmenityRouter creates a new subrouter for the amenity endpoint.
+// .../amenities
+func NewAmenityRouter(s interfaces.Server) http.Handler {
+	r := chi.NewRouter()
+
+	amenityHandlers := handlers.NewAmenityHandlers(s)
+	r.Get("/", amenityHandlers.GetAmenitiesHandler)
+
+	return r
+}
+
+// NewCalendarRouter creates a new subrouter for the calendar feed endpoints.
+// .../calendar
+func NewCalendarRouter(s interfaces.Server) http.Handler {
+	r := chi.NewRouter()
+
+	calendarHandlers := handlers.NewCalendarHandlers(s)
+	r.Get("/{token}.ics", calendarHandlers.GetCalendarFeedHandler)
+	r.Get("/{token}/communities/{id}.ics", calendarHandlers.GetCommunityCalendarFeedHandler)
+
 	return r
 }
 
@@ -137,6 +222,70 @@ func NewCommunityRouter(s interfaces.Server) http.Handler {
 	r.With(app_middleware.AuthMiddleware).Delete("/users", communityHandlers.DeleteCommunitiesUserHandler)
 	r.With(app_middleware.AuthMiddleware).Delete("/properties", communityHandlers.DeleteCommunitiesPropertiesHandler)
 
+	// membership of a community
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/membership", communityHandlers.GetCommunityMembershipRequestsHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/membership/requests", communityHandlers.CreateCommunityMembershipRequestHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/membership/invites", communityHandlers.CreateCommunityInviteHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/membership/{requestId}", communityHandlers.DecideCommunityMembershipRequestHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/membership/{requestId}", communityHandlers.DeleteCommunityMembershipRequestHandler)
+
+	// requests of a community to link properties, decided by their listers
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/properties/requests", communityHandlers.GetCommunityPropertyLinkRequestsHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/properties/requests/{requestId}", communityHandlers.DeleteCommunityPropertyLinkRequestHandler)
+
+	// members of a community and their roles
+	r.Get("/{id}/members", communityHandlers.GetCommunityMembersHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/members/{userId}/role", communityHandlers.UpdateCommunityMemberRoleHandler)
+
+	// discussion board of a community
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/posts", communityHandlers.GetCommunityThreadsHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/posts", communityHandlers.CreateCommunityThreadHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/posts/{postId}", communityHandlers.GetCommunityThreadHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/posts/{postId}/replies", communityHandlers.CreateCommunityReplyHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/posts/{postId}/pin", communityHandlers.UpdateCommunityThreadPinnedHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/posts/{postId}", communityHandlers.DeleteCommunityPostHandler)
+
+	// events of a community
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/events", communityHandlers.GetCommunityEventsHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/events", communityHandlers.CreateCommunityEventHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/events/{eventId}", communityHandlers.GetCommunityEventHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/events/{eventId}", communityHandlers.DeleteCommunityEventHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/events/{eventId}/rsvp", communityHandlers.SetCommunityEventRSVPHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/events/{eventId}/rsvp", communityHandlers.DeleteCommunityEventRSVPHandler)
+
+	// shared expenses of a community
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/expenses", communityHandlers.GetCommunityExpensesHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/expenses", communityHandlers.CreateCommunityExpenseHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/expenses/balances", communityHandlers.GetCommunityExpenseBalancesHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/expenses/export", communityHandlers.ExportCommunityExpensesHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/expenses/{expenseId}", communityHandlers.DeleteCommunityExpenseHandler)
+
+	// polls of a community
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/polls", communityHandlers.GetCommunityPollsHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/polls", communityHandlers.CreateCommunityPollHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/polls/{pollId}", communityHandlers.GetCommunityPollHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/polls/{pollId}/votes", communityHandlers.VoteCommunityPollHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/polls/{pollId}", communityHandlers.DeleteCommunityPollHandler)
+
+	// document library of a community
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/documents", communityHandlers.GetCommunityDocumentsHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/documents", communityHandlers.CreateCommunityDocumentHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/documents/folders", communityHandlers.CreateCommunityDocumentFolderHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/documents/folders/{folderId}", communityHandlers.DeleteCommunityDocumentFolderHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/documents/{documentId}", communityHandlers.GetCommunityDocumentHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/documents/{documentId}", communityHandlers.UpdateCommunityDocumentHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/documents/{documentId}", communityHandlers.DeleteCommunityDocumentHandler)
+	r.With(app_middleware.OptionalAuthMiddleware).Get("/{id}/documents/{documentId}/file", communityHandlers.GetCommunityDocumentFileHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/documents/{documentId}/versions", communityHandlers.CreateCommunityDocumentVersionHandler)
+
+	// chores of a community and their tasks
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/chores", communityHandlers.GetCommunityChoresHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/chores", communityHandlers.CreateCommunityChoreHandler)
+	r.With(app_middleware.AuthMiddleware).Get("/{id}/chores/tasks", communityHandlers.GetCommunityChoreTasksHandler)
+	r.With(app_middleware.AuthMiddleware).Put("/{id}/chores/tasks/{taskId}/done", communityHandlers.UpdateCommunityChoreTaskDoneHandler)
+	r.With(app_middleware.AuthMiddleware).Post("/{id}/chores/tasks/{taskId}/swap", communityHandlers.SwapCommunityChoreTaskHandler)
+	r.With(app_middleware.AuthMiddleware).Delete("/{id}/chores/{choreId}", communityHandlers.DeleteCommunityChoreHandler)
+
 	return r
 }
 
@@ -191,6 +340,14 @@ func RegisterRoutes(s interfaces.Server) http.Handler {
 	propertyRouter := NewPropertyRouter(s)
 	apiRouter.Mount("/properties", propertyRouter)
 
+	// Amenities catalog
+	amenityRouter := NewAmenityRouter(s)
+	apiRouter.Mount("/amenities", amenityRouter)
+
+	// Calendar feeds
+	calendarRouter := NewCalendarRouter(s)
+	apiRouter.Mount("/calendar", calendarRouter)
+
 	// Communities
 	communityRouter := NewCommunityRouter(s)
 	apiRouter.Mount("/communities", communityRouter)
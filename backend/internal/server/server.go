@@ -10,6 +10,7 @@ import (
 
 	"backend/internal/config"
 	"backend/internal/database"
+	"backend/internal/jobs"
 	"backend/internal/routes"
 )
 
@@ -26,6 +27,16 @@ func NewServer() *http.Server {
 		db: database.New(),
 	}
 
+	// Start the background jobs, they run for as long as the process does
+	jobs.NewScheduler(
+		jobs.ListingExpiryJob(s.db),
+		jobs.AddressNormalizationJob(s.db),
+		jobs.AnalyticsPruningJob(s.db),
+		jobs.CommunityInvitesPruningJob(s.db),
+		jobs.CommunityChoreTasksJob(s.db),
+		jobs.FileBlobsPruningJob(s.db),
+	).Start()
+
 	// Declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", config.GlobalConfig.PORT),
@@ -5,11 +5,17 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/md5"
+	"crypto/sha256"
 	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"log"
+	"math"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -135,3 +141,139 @@ func CreateSQLNullString(s string) sql.NullString {
 		Valid:  true,
 	}
 }
+
+// CreateSQLNullDate is a utility that parses a date string of the form YYYY-MM-DD
+// into a SQL Null time, which is set to invalid if the string is empty.
+func CreateSQLNullDate(s string) (sql.NullTime, error) {
+	if s == "" {
+		return sql.NullTime{Valid: false}, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return sql.NullTime{Valid: false}, err
+	}
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
+
+// FormatSQLNullDate formats a SQL Null time as a date string of the form YYYY-MM-DD,
+// returning an empty string if it is invalid.
+func FormatSQLNullDate(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02")
+}
+
+// CreateSQLNullFloat64 is a utility that creates a SQL Null float that is
+// set to invalid if the value is nil.
+func CreateSQLNullFloat64(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{Valid: false}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
+
+// FormatSQLNullFloat64 returns the value of a SQL Null float, or nil if it is invalid.
+func FormatSQLNullFloat64(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+// DistanceKm returns the great-circle distance in kilometers between two coordinates
+// given in degrees, using the haversine formula.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// SplitCommaSeparated splits a comma separated list, e.g. of a query parameter, into its distinct
+// values. Whitespace around the values is trimmed and empty values are dropped.
+func SplitCommaSeparated(s string) []string {
+	values := []string{}
+	for _, value := range strings.Split(s, ",") {
+		value = strings.TrimSpace(value)
+		if value != "" && !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// ContentHash returns the hex encoded sha256 hash of the given data. It is used
+// as the key for content-addressed file storage.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// addressAbbreviations maps common spellings of street suffixes, directions and unit
+// designators to a single canonical abbreviation so that e.g. "123 Main Street, Apt 4"
+// and "123 main st #4" normalize to the same address.
+var addressAbbreviations = map[string]string{
+	"street":    "st",
+	"str":       "st",
+	"avenue":    "ave",
+	"av":        "ave",
+	"road":      "rd",
+	"boulevard": "blvd",
+	"drive":     "dr",
+	"lane":      "ln",
+	"court":     "ct",
+	"place":     "pl",
+	"terrace":   "ter",
+	"highway":   "hwy",
+	"parkway":   "pkwy",
+	"square":    "sq",
+	"circle":    "cir",
+	"north":     "n",
+	"south":     "s",
+	"east":      "e",
+	"west":      "w",
+	"northeast": "ne",
+	"northwest": "nw",
+	"southeast": "se",
+	"southwest": "sw",
+	"apartment": "unit",
+	"apt":       "unit",
+	"suite":     "unit",
+	"ste":       "unit",
+	"number":    "unit",
+	"no":        "unit",
+	"room":      "unit",
+	"rm":        "unit",
+	"floor":     "fl",
+	"usa":       "us",
+}
+
+var nonAlphanumericRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// NormalizeAddress builds a canonical, lowercase form of an address for duplicate
+// detection. Punctuation is dropped, whitespace collapsed and common abbreviations
+// unified. A "#" unit marker is treated like any other unit designator.
+func NormalizeAddress(address1, address2, city, state, zipcode, country string) string {
+	raw := strings.ToLower(strings.Join([]string{address1, address2, city, state, zipcode, country}, " "))
+	raw = strings.ReplaceAll(raw, "united states of america", "us")
+	raw = strings.ReplaceAll(raw, "united states", "us")
+	raw = strings.ReplaceAll(raw, "#", " unit ")
+
+	var normalized []string
+	for _, word := range strings.Fields(nonAlphanumericRegex.ReplaceAllString(raw, " ")) {
+		if abbreviation, exists := addressAbbreviations[word]; exists {
+			word = abbreviation
+		}
+		// Collapse repeated unit designators such as "apt #4" -> "unit unit 4"
+		if word == "unit" && len(normalized) > 0 && normalized[len(normalized)-1] == "unit" {
+			continue
+		}
+		normalized = append(normalized, word)
+	}
+	return strings.Join(normalized, " ")
+}
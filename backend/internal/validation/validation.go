@@ -7,9 +7,13 @@ import (
 	"backend/internal/utils"
 	"errors"
 	"fmt"
+	"math"
+	"mime"
+	"net/http"
 	"net/mail"
 	"regexp"
 	"strings"
+	"time"
 
 	goaway "github.com/TwiN/go-away"
 	"github.com/google/uuid"
@@ -95,6 +99,528 @@ func ValidateCommunityDetails(details database.CommunityDetails) error {
 	return nil
 }
 
+// ValidateCommunityMembershipRequest validates a new request of a user to join a community
+// or invite of the community admin to a user.
+func ValidateCommunityMembershipRequest(request database.CommunityMembershipRequest, now time.Time) error {
+	if _, err := uuid.Parse(request.RequestID); err != nil {
+		return errors.New("request id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(request.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if err := ValidateOpenID(request.UserID, "user id"); err != nil {
+		return err
+	}
+
+	switch request.Kind {
+	case config.COMMUNITY_MEMBERSHIP_KIND_REQUEST:
+		if request.InvitedByUserID != "" || request.ExpiresAt != nil {
+			return errors.New("a request to join cannot have an inviter or expire")
+		}
+	case config.COMMUNITY_MEMBERSHIP_KIND_INVITE:
+		if err := ValidateOpenID(request.InvitedByUserID, "inviter id"); err != nil {
+			return err
+		}
+		if request.InvitedByUserID == request.UserID {
+			return errors.New("cannot invite yourself")
+		}
+		if request.ExpiresAt == nil || !request.ExpiresAt.After(now) {
+			return errors.New("an invite must expire in the future")
+		}
+	default:
+		return fmt.Errorf("membership kind \"%s\" is not valid", request.Kind)
+	}
+
+	return nil
+}
+
+// ValidateCommunityPropertyLinkRequest validates a new request of a community to link a property
+func ValidateCommunityPropertyLinkRequest(request database.CommunityPropertyLinkRequest) error {
+	if _, err := uuid.Parse(request.RequestID); err != nil {
+		return errors.New("request id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(request.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(request.PropertyID); err != nil {
+		return errors.New("property id is not a valid uuid")
+	}
+	if err := ValidateOpenID(request.RequestedByUserID, "requester id"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateCommunityPermission ensures that a member with the given community role
+// holds the requested permission. An empty role belongs to a non-member.
+func ValidateCommunityPermission(role, permission string) error {
+	if role == "" {
+		return errors.New("user is not a member of the community")
+	}
+	permissions, exists := config.COMMUNITY_ROLE_PERMISSIONS[role]
+	if !exists {
+		return fmt.Errorf("community role \"%s\" is not valid", role)
+	}
+	if _, exists := permissions[permission]; !exists {
+		return fmt.Errorf("community role \"%s\" does not have permission \"%s\"", role, permission)
+	}
+	return nil
+}
+
+// ValidateCommunityRoleChange ensures that a member can be given the requested role.
+// Ownership only changes hands through an ownership transfer.
+func ValidateCommunityRoleChange(currentRole, newRole string) error {
+	if _, exists := config.COMMUNITY_ROLE_OPTIONS[newRole]; !exists {
+		return fmt.Errorf("community role \"%s\" is not valid", newRole)
+	}
+	if currentRole == config.COMMUNITY_ROLE_OWNER || newRole == config.COMMUNITY_ROLE_OWNER {
+		return errors.New("community ownership can only be transferred")
+	}
+	return nil
+}
+
+// ValidateCommunityPost validates a new thread or reply on the discussion board of a community.
+// Threads have a title, replies belong to a thread and have none.
+func ValidateCommunityPost(post database.CommunityPost) error {
+	if _, err := uuid.Parse(post.PostID); err != nil {
+		return errors.New("post id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(post.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if err := ValidateOpenID(post.UserID, "user id"); err != nil {
+		return err
+	}
+
+	if post.ThreadID == "" {
+		if len(post.Title) == 0 || len(post.Title) > config.COMMUNITY_POST_MAX_TITLE_LENGTH {
+			return fmt.Errorf("thread title must be between 1 and %d characters", config.COMMUNITY_POST_MAX_TITLE_LENGTH)
+		}
+		if goaway.IsProfane(post.Title) {
+			return fmt.Errorf("title cannot contain profanity: %s", goaway.ExtractProfanity(post.Title))
+		}
+	} else {
+		if _, err := uuid.Parse(post.ThreadID); err != nil {
+			return errors.New("thread id is not a valid uuid")
+		}
+		if post.Title != "" {
+			return errors.New("a reply cannot have a title")
+		}
+		if post.Pinned {
+			return errors.New("a reply cannot be pinned")
+		}
+	}
+
+	if len(post.Body) == 0 || len(post.Body) > config.COMMUNITY_POST_MAX_BODY_LENGTH {
+		return fmt.Errorf("post body must be between 1 and %d characters", config.COMMUNITY_POST_MAX_BODY_LENGTH)
+	}
+	if goaway.IsProfane(post.Body) {
+		return fmt.Errorf("body cannot contain profanity: %s", goaway.ExtractProfanity(post.Body))
+	}
+
+	return nil
+}
+
+// ValidateCommunityEvent validates a new event of a community
+func ValidateCommunityEvent(event database.CommunityEvent, now time.Time) error {
+	if _, err := uuid.Parse(event.EventID); err != nil {
+		return errors.New("event id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(event.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if event.PropertyID != "" {
+		if _, err := uuid.Parse(event.PropertyID); err != nil {
+			return errors.New("property id is not a valid uuid")
+		}
+	}
+	if err := ValidateOpenID(event.CreatedByUserID, "organizer id"); err != nil {
+		return err
+	}
+
+	if len(event.Title) == 0 || len(event.Title) > config.COMMUNITY_EVENT_MAX_TITLE_LENGTH {
+		return fmt.Errorf("title must be between 1 and %d characters", config.COMMUNITY_EVENT_MAX_TITLE_LENGTH)
+	}
+	if goaway.IsProfane(event.Title) {
+		return fmt.Errorf("title cannot contain profanity: %s", goaway.ExtractProfanity(event.Title))
+	}
+	if len(event.Description) > config.COMMUNITY_EVENT_MAX_DESCRIPTION_LENGTH {
+		return fmt.Errorf("description cannot be longer than %d characters", config.COMMUNITY_EVENT_MAX_DESCRIPTION_LENGTH)
+	}
+	if goaway.IsProfane(event.Description) {
+		return fmt.Errorf("description cannot contain profanity: %s", goaway.ExtractProfanity(event.Description))
+	}
+	if len(event.Location) == 0 || len(event.Location) > config.COMMUNITY_EVENT_MAX_LOCATION_LENGTH {
+		return fmt.Errorf("location must be between 1 and %d characters", config.COMMUNITY_EVENT_MAX_LOCATION_LENGTH)
+	}
+
+	if !event.StartTime.After(now) {
+		return errors.New("event must start in the future")
+	}
+	if !event.EndTime.After(event.StartTime) {
+		return errors.New("event must end after it starts")
+	}
+	if event.EndTime.Sub(event.StartTime) > config.COMMUNITY_EVENT_MAX_DURATION_DAYS*24*time.Hour {
+		return fmt.Errorf("event cannot be longer than %d days", config.COMMUNITY_EVENT_MAX_DURATION_DAYS)
+	}
+
+	// A capacity of 0 means there is no limit
+	if event.Capacity < 0 || event.Capacity > config.COMMUNITY_EVENT_MAX_CAPACITY {
+		return fmt.Errorf("event capacity must be between 1 and %d, or 0 for no limit", config.COMMUNITY_EVENT_MAX_CAPACITY)
+	}
+
+	return nil
+}
+
+// ValidateCommunityExpense validates a new shared expense of a community, the amounts of its
+// splits are checked when they are computed
+func ValidateCommunityExpense(expense database.CommunityExpense) error {
+	if _, err := uuid.Parse(expense.ExpenseID); err != nil {
+		return errors.New("expense id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(expense.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if err := ValidateOpenID(expense.PayerUserID, "payer id"); err != nil {
+		return err
+	}
+	if err := ValidateOpenID(expense.CreatedByUserID, "recorder id"); err != nil {
+		return err
+	}
+
+	if len(expense.Description) == 0 || len(expense.Description) > config.COMMUNITY_EXPENSE_MAX_DESCRIPTION_LENGTH {
+		return fmt.Errorf("description must be between 1 and %d characters", config.COMMUNITY_EXPENSE_MAX_DESCRIPTION_LENGTH)
+	}
+	if goaway.IsProfane(expense.Description) {
+		return fmt.Errorf("description cannot contain profanity: %s", goaway.ExtractProfanity(expense.Description))
+	}
+	if _, ok := config.COMMUNITY_EXPENSE_CATEGORY_OPTIONS[expense.Category]; !ok {
+		return fmt.Errorf("expense category \"%s\" is not one of our supported options", expense.Category)
+	}
+	if expense.AmountCents < 1 || expense.AmountCents > config.COMMUNITY_EXPENSE_MAX_AMOUNT_CENTS {
+		return fmt.Errorf("amount must be between 1 and %d cents", config.COMMUNITY_EXPENSE_MAX_AMOUNT_CENTS)
+	}
+	if _, err := time.Parse("2006-01-02", expense.SpentOn); err != nil {
+		return errors.New("spent on date must be in YYYY-MM-DD format")
+	}
+
+	if _, ok := config.COMMUNITY_EXPENSE_SPLIT_OPTIONS[expense.SplitType]; !ok {
+		return fmt.Errorf("split type \"%s\" is not one of our supported options", expense.SplitType)
+	}
+	if len(expense.Splits) == 0 || len(expense.Splits) > config.COMMUNITY_EXPENSE_MAX_SPLITS {
+		return fmt.Errorf("an expense must be split between 1 and %d members", config.COMMUNITY_EXPENSE_MAX_SPLITS)
+	}
+	userIDs := map[string]struct{}{}
+	for _, split := range expense.Splits {
+		if err := ValidateOpenID(split.UserID, "member id"); err != nil {
+			return err
+		}
+		if _, ok := userIDs[split.UserID]; ok {
+			return errors.New("a member can only appear once in the splits of an expense")
+		}
+		userIDs[split.UserID] = struct{}{}
+
+		// Shares are ignored by the other split types
+		if expense.SplitType == config.COMMUNITY_EXPENSE_SPLIT_SHARES && (split.Shares < 1 || split.Shares > config.COMMUNITY_EXPENSE_MAX_SHARES) {
+			return fmt.Errorf("shares of a member must be between 1 and %d", config.COMMUNITY_EXPENSE_MAX_SHARES)
+		}
+	}
+
+	return nil
+}
+
+// ValidateCommunitySearchFilters validates the filters of a search of communities
+func ValidateCommunitySearchFilters(filters database.CommunitySearchFilters) error {
+	if filters.RadiusKm < 0 || filters.RadiusKm > config.COMMUNITY_SEARCH_MAX_RADIUS_KM {
+		return fmt.Errorf("radius must be between 0 and %d km", config.COMMUNITY_SEARCH_MAX_RADIUS_KM)
+	}
+	if filters.Latitude < -90 || filters.Latitude > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if filters.Longitude < -180 || filters.Longitude > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+
+	if filters.MinMembers < 0 || filters.MaxMembers < 0 {
+		return errors.New("member counts cannot be negative")
+	}
+	if filters.MaxMembers != 0 && filters.MaxMembers < filters.MinMembers {
+		return errors.New("maximum member count cannot be less than the minimum")
+	}
+
+	if len(filters.Interests) > config.COMMUNITY_SEARCH_MAX_INTERESTS {
+		return fmt.Errorf("cannot search for more than %d interests", config.COMMUNITY_SEARCH_MAX_INTERESTS)
+	}
+	for _, interest := range filters.Interests {
+		if interest == "" {
+			return errors.New("interests cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// ValidateCommunityPoll validates a new poll of a community
+func ValidateCommunityPoll(poll database.CommunityPoll, now time.Time) error {
+	if _, err := uuid.Parse(poll.PollID); err != nil {
+		return errors.New("poll id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(poll.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if err := ValidateOpenID(poll.CreatedByUserID, "creator id"); err != nil {
+		return err
+	}
+
+	if len(poll.Question) == 0 || len(poll.Question) > config.COMMUNITY_POLL_MAX_QUESTION_LENGTH {
+		return fmt.Errorf("question must be between 1 and %d characters", config.COMMUNITY_POLL_MAX_QUESTION_LENGTH)
+	}
+	if goaway.IsProfane(poll.Question) {
+		return fmt.Errorf("question cannot contain profanity: %s", goaway.ExtractProfanity(poll.Question))
+	}
+	if _, ok := config.COMMUNITY_POLL_RESULTS_OPTIONS[poll.ResultsVisibility]; !ok {
+		return fmt.Errorf("results visibility \"%s\" is not one of our supported options", poll.ResultsVisibility)
+	}
+
+	if len(poll.Options) < config.COMMUNITY_POLL_MIN_OPTIONS || len(poll.Options) > config.COMMUNITY_POLL_MAX_OPTIONS {
+		return fmt.Errorf("a poll must have between %d and %d options", config.COMMUNITY_POLL_MIN_OPTIONS, config.COMMUNITY_POLL_MAX_OPTIONS)
+	}
+	labels := map[string]struct{}{}
+	for _, option := range poll.Options {
+		if _, err := uuid.Parse(option.OptionID); err != nil {
+			return errors.New("option id is not a valid uuid")
+		}
+		label := strings.TrimSpace(option.Label)
+		if len(label) == 0 || len(label) > config.COMMUNITY_POLL_MAX_OPTION_LENGTH {
+			return fmt.Errorf("options must be between 1 and %d characters", config.COMMUNITY_POLL_MAX_OPTION_LENGTH)
+		}
+		if goaway.IsProfane(label) {
+			return fmt.Errorf("options cannot contain profanity: %s", goaway.ExtractProfanity(label))
+		}
+		if _, ok := labels[strings.ToLower(label)]; ok {
+			return fmt.Errorf("option \"%s\" is given more than once", label)
+		}
+		labels[strings.ToLower(label)] = struct{}{}
+	}
+
+	if !poll.ClosesAt.After(poll.OpensAt) {
+		return errors.New("poll must close after it opens")
+	}
+	if !poll.ClosesAt.After(now) {
+		return errors.New("poll must close in the future")
+	}
+	if poll.ClosesAt.Sub(poll.OpensAt) > config.COMMUNITY_POLL_MAX_DURATION_DAYS*24*time.Hour {
+		return fmt.Errorf("poll cannot be open for longer than %d days", config.COMMUNITY_POLL_MAX_DURATION_DAYS)
+	}
+
+	return nil
+}
+
+// ValidateCommunityPollBallot validates the options a member chose on a poll, a single option
+// unless the poll is multiple choice, while the poll is open
+func ValidateCommunityPollBallot(poll database.CommunityPoll, optionIDs []string, now time.Time) error {
+	if now.Before(poll.OpensAt) {
+		return errors.New("poll is not open yet")
+	}
+	if !now.Before(poll.ClosesAt) {
+		return errors.New("poll is closed")
+	}
+
+	if len(optionIDs) == 0 {
+		return errors.New("at least one option must be chosen")
+	}
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		return errors.New("only one option can be chosen on this poll")
+	}
+
+	pollOptionIDs := map[string]struct{}{}
+	for _, option := range poll.Options {
+		pollOptionIDs[option.OptionID] = struct{}{}
+	}
+	chosen := map[string]struct{}{}
+	for _, optionID := range optionIDs {
+		if _, ok := pollOptionIDs[optionID]; !ok {
+			return fmt.Errorf("option %s is not an option of this poll", optionID)
+		}
+		if _, ok := chosen[optionID]; ok {
+			return errors.New("an option can only be chosen once")
+		}
+		chosen[optionID] = struct{}{}
+	}
+
+	return nil
+}
+
+// ValidateCommunityPollResultsAccess ensures the results of a poll can be seen, by everyone once
+// the poll is closed and before that only by its voters if the poll shows results after voting
+func ValidateCommunityPollResultsAccess(poll database.CommunityPoll, hasVoted bool, now time.Time) error {
+	if !now.Before(poll.ClosesAt) {
+		return nil
+	}
+	if poll.ResultsVisibility == config.COMMUNITY_POLL_RESULTS_AFTER_VOTE {
+		if hasVoted {
+			return nil
+		}
+		return errors.New("results of this poll are visible after voting")
+	}
+	return errors.New("results of this poll are visible after it closes")
+}
+
+// ValidateCommunityDocumentFolder validates a new folder of the document library of a community
+func ValidateCommunityDocumentFolder(folder database.CommunityDocumentFolder) error {
+	if _, err := uuid.Parse(folder.FolderID); err != nil {
+		return errors.New("folder id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(folder.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if folder.ParentFolderID != "" {
+		if _, err := uuid.Parse(folder.ParentFolderID); err != nil {
+			return errors.New("parent folder id is not a valid uuid")
+		}
+	}
+	if err := ValidateOpenID(folder.CreatedByUserID, "creator id"); err != nil {
+		return err
+	}
+
+	if len(folder.Name) == 0 || len(folder.Name) > config.COMMUNITY_DOCUMENT_MAX_FOLDER_NAME_LENGTH {
+		return fmt.Errorf("folder name must be between 1 and %d characters", config.COMMUNITY_DOCUMENT_MAX_FOLDER_NAME_LENGTH)
+	}
+	if goaway.IsProfane(folder.Name) {
+		return fmt.Errorf("folder name cannot contain profanity: %s", goaway.ExtractProfanity(folder.Name))
+	}
+
+	return nil
+}
+
+// ValidateCommunityDocument validates the details of a document of a community
+func ValidateCommunityDocument(document database.CommunityDocument) error {
+	if _, err := uuid.Parse(document.DocumentID); err != nil {
+		return errors.New("document id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(document.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if document.FolderID != "" {
+		if _, err := uuid.Parse(document.FolderID); err != nil {
+			return errors.New("folder id is not a valid uuid")
+		}
+	}
+
+	if len(document.Title) == 0 || len(document.Title) > config.COMMUNITY_DOCUMENT_MAX_TITLE_LENGTH {
+		return fmt.Errorf("title must be between 1 and %d characters", config.COMMUNITY_DOCUMENT_MAX_TITLE_LENGTH)
+	}
+	if goaway.IsProfane(document.Title) {
+		return fmt.Errorf("title cannot contain profanity: %s", goaway.ExtractProfanity(document.Title))
+	}
+	if _, ok := config.COMMUNITY_DOCUMENT_VISIBILITY_OPTIONS[document.Visibility]; !ok {
+		return fmt.Errorf("document visibility \"%s\" is not one of our supported options", document.Visibility)
+	}
+
+	return nil
+}
+
+// ValidateCommunityDocumentVersion validates a new version of a document of a community with its file
+func ValidateCommunityDocumentVersion(version database.CommunityDocumentVersion, file database.FileInternal) error {
+	if _, err := uuid.Parse(version.DocumentID); err != nil {
+		return errors.New("document id is not a valid uuid")
+	}
+	if err := ValidateOpenID(version.UploadedByUserID, "uploader id"); err != nil {
+		return err
+	}
+
+	if len(version.Note) > config.COMMUNITY_DOCUMENT_MAX_NOTE_LENGTH {
+		return fmt.Errorf("note cannot be longer than %d characters", config.COMMUNITY_DOCUMENT_MAX_NOTE_LENGTH)
+	}
+	if goaway.IsProfane(version.Note) {
+		return fmt.Errorf("note cannot contain profanity: %s", goaway.ExtractProfanity(version.Note))
+	}
+
+	if file.Filename == "" {
+		return errors.New("file name cannot be empty")
+	}
+	if file.Size <= 0 || file.Size > config.COMMUNITY_DOCUMENT_MAX_SIZE {
+		return fmt.Errorf("file must be between 1 byte and %d MiB", config.COMMUNITY_DOCUMENT_MAX_SIZE>>20)
+	}
+	// The type is detected from the content, as the one sent by the client cannot be trusted
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(file.Data))
+	if err != nil {
+		return err
+	}
+	if _, ok := config.COMMUNITY_DOCUMENT_MIME_TYPE_OPTIONS[mimeType]; !ok {
+		return fmt.Errorf("file type \"%s\" is not one of our supported types", mimeType)
+	}
+
+	return nil
+}
+
+// ValidateCommunityDocumentAccess ensures that a user with the given community role, empty if
+// the user is not a member, can see a document of the given visibility
+func ValidateCommunityDocumentAccess(visibility, role string) error {
+	switch visibility {
+	case config.COMMUNITY_DOCUMENT_VISIBILITY_PUBLIC:
+		return nil
+	case config.COMMUNITY_DOCUMENT_VISIBILITY_MEMBERS:
+		return ValidateCommunityPermission(role, config.COMMUNITY_PERMISSION_DOCUMENTS)
+	case config.COMMUNITY_DOCUMENT_VISIBILITY_MODERATORS:
+		return ValidateCommunityPermission(role, config.COMMUNITY_PERMISSION_MANAGE_DOCUMENTS)
+	default:
+		return fmt.Errorf("document visibility \"%s\" is not one of our supported options", visibility)
+	}
+}
+
+// ValidateCommunityChore validates a new chore of a community with its rotation
+func ValidateCommunityChore(chore database.CommunityChore) error {
+	if _, err := uuid.Parse(chore.ChoreID); err != nil {
+		return errors.New("chore id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(chore.CommunityID); err != nil {
+		return errors.New("community id is not a valid uuid")
+	}
+	if err := ValidateOpenID(chore.CreatedByUserID, "creator id"); err != nil {
+		return err
+	}
+
+	if len(chore.Title) == 0 || len(chore.Title) > config.COMMUNITY_CHORE_MAX_TITLE_LENGTH {
+		return fmt.Errorf("title must be between 1 and %d characters", config.COMMUNITY_CHORE_MAX_TITLE_LENGTH)
+	}
+	if goaway.IsProfane(chore.Title) {
+		return fmt.Errorf("title cannot contain profanity: %s", goaway.ExtractProfanity(chore.Title))
+	}
+	if len(chore.Description) > config.COMMUNITY_CHORE_MAX_DESCRIPTION_LENGTH {
+		return fmt.Errorf("description cannot be longer than %d characters", config.COMMUNITY_CHORE_MAX_DESCRIPTION_LENGTH)
+	}
+	if goaway.IsProfane(chore.Description) {
+		return fmt.Errorf("description cannot contain profanity: %s", goaway.ExtractProfanity(chore.Description))
+	}
+
+	if _, ok := config.COMMUNITY_CHORE_FREQUENCY_OPTIONS[chore.Frequency]; !ok {
+		return fmt.Errorf("chore frequency \"%s\" is not one of our supported options", chore.Frequency)
+	}
+	if chore.Interval < 1 || chore.Interval > config.COMMUNITY_CHORE_MAX_INTERVAL {
+		return fmt.Errorf("interval must be between 1 and %d", config.COMMUNITY_CHORE_MAX_INTERVAL)
+	}
+	if _, err := time.Parse("2006-01-02", chore.StartsOn); err != nil {
+		return errors.New("starts on date must be in YYYY-MM-DD format")
+	}
+
+	if len(chore.Rotation) == 0 || len(chore.Rotation) > config.COMMUNITY_CHORE_MAX_ROTATION {
+		return fmt.Errorf("a chore must rotate between 1 and %d members", config.COMMUNITY_CHORE_MAX_ROTATION)
+	}
+	userIDs := map[string]struct{}{}
+	for _, userID := range chore.Rotation {
+		if err := ValidateOpenID(userID, "member id"); err != nil {
+			return err
+		}
+		if _, ok := userIDs[userID]; ok {
+			return errors.New("a member can only appear once in the rotation of a chore")
+		}
+		userIDs[userID] = struct{}{}
+	}
+
+	return nil
+}
+
 func ValidatePropertyDetails(propertyDetails database.PropertyDetails) error {
 	// Ensure property id is a valid uuidv4
 	if _, err := uuid.Parse(propertyDetails.PropertyID); err != nil {
@@ -197,6 +723,294 @@ func ValidatePropertyDetails(propertyDetails database.PropertyDetails) error {
 		return errors.New("cost in cents invalid")
 	}
 
+	// Listing status, if present
+	if len(propertyDetails.Status) > 0 {
+		if _, exists := config.PROPERTY_STATUS_OPTIONS[propertyDetails.Status]; !exists {
+			return fmt.Errorf("property status \"%s\" is not valid", propertyDetails.Status)
+		}
+	}
+
+	// Coordinates, if present
+	if (propertyDetails.Latitude == nil) != (propertyDetails.Longitude == nil) {
+		return errors.New("latitude and longitude must be given together")
+	}
+	if propertyDetails.Latitude != nil {
+		if v := *propertyDetails.Latitude; math.IsNaN(v) || v < -90 || v > 90 {
+			return errors.New("latitude must be between -90 and 90")
+		}
+		if v := *propertyDetails.Longitude; math.IsNaN(v) || v < -180 || v > 180 {
+			return errors.New("longitude must be between -180 and 180")
+		}
+	}
+
+	// Rental terms
+	return validatePropertyRentalTerms(propertyDetails)
+}
+
+// validatePropertyRentalTerms validates the structured rental terms of a property.
+// All of the terms are optional, zero values are treated as unspecified.
+func validatePropertyRentalTerms(propertyDetails database.PropertyDetails) error {
+	// Price type
+	if len(propertyDetails.Price_type) > 0 {
+		if _, exists := config.PROPERTY_PRICE_TYPE_OPTIONS[propertyDetails.Price_type]; !exists {
+			return fmt.Errorf("price type \"%s\" is not valid", propertyDetails.Price_type)
+		}
+	}
+
+	// Deposit
+	if v := propertyDetails.Deposit_dollars; v < 0 || v > 999999999999 {
+		return errors.New("deposit in dollars invalid")
+	}
+	if v := propertyDetails.Deposit_cents; v < 0 || v > 99 {
+		return errors.New("deposit in cents invalid")
+	}
+
+	// Lease length options, only meaningful for rentals
+	if len(propertyDetails.Lease_length_months) > 0 && propertyDetails.Price_type == config.PROPERTY_PRICE_TYPE_SALE {
+		return errors.New("properties for sale cannot have lease length options")
+	}
+	seenLeaseLengths := make(map[int32]struct{})
+	for _, v := range propertyDetails.Lease_length_months {
+		if v <= 0 || v > config.PROPERTY_MAX_LEASE_LENGTH_MONTHS {
+			return fmt.Errorf("lease length of %d months invalid", v)
+		}
+		if _, exists := seenLeaseLengths[v]; exists {
+			return fmt.Errorf("duplicate lease length of %d months", v)
+		}
+		seenLeaseLengths[v] = struct{}{}
+	}
+
+	// Available from date
+	if len(propertyDetails.Available_from) > 0 {
+		if _, err := time.Parse("2006-01-02", propertyDetails.Available_from); err != nil {
+			return errors.New("available from date invalid, expected YYYY-MM-DD")
+		}
+	}
+
+	// Rooms available
+	if v := propertyDetails.Rooms_available; v < 0 || v > propertyDetails.Num_bedrooms {
+		return errors.New("number of rooms available invalid")
+	}
+
+	// Pets and smoking policies
+	if len(propertyDetails.Pets_policy) > 0 {
+		if _, exists := config.PROPERTY_PETS_POLICY_OPTIONS[propertyDetails.Pets_policy]; !exists {
+			return fmt.Errorf("pets policy \"%s\" is not valid", propertyDetails.Pets_policy)
+		}
+	}
+	if len(propertyDetails.Smoking_policy) > 0 {
+		if _, exists := config.PROPERTY_SMOKING_POLICY_OPTIONS[propertyDetails.Smoking_policy]; !exists {
+			return fmt.Errorf("smoking policy \"%s\" is not valid", propertyDetails.Smoking_policy)
+		}
+	}
+
+	return nil
+}
+
+// ValidatePropertyAmenities ensures that every amenity a property is tagged with
+// is in the amenities catalog and is only given once.
+func ValidatePropertyAmenities(amenities []string, catalog map[string]struct{}) error {
+	seen := make(map[string]struct{})
+	for _, amenity := range amenities {
+		if _, exists := catalog[amenity]; !exists {
+			return fmt.Errorf("amenity \"%s\" is not one of our supported options", amenity)
+		}
+		if _, exists := seen[amenity]; exists {
+			return fmt.Errorf("duplicate amenity \"%s\"", amenity)
+		}
+		seen[amenity] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateAmenity validates a new entry for the amenities catalog.
+// Amenity names are lowercase snake case identifiers, e.g. "in_unit_laundry".
+func ValidateAmenity(amenity database.Amenity) error {
+	if !regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`).MatchString(amenity.Name) || len(amenity.Name) > 64 {
+		return fmt.Errorf("amenity name \"%s\" must be lowercase snake case and at most 64 characters", amenity.Name)
+	}
+	if goaway.IsProfane(amenity.Name) {
+		return fmt.Errorf("amenity name cannot contain profanity: %s", goaway.ExtractProfanity(amenity.Name))
+	}
+	if _, exists := config.AMENITY_CATEGORY_OPTIONS[amenity.Category]; !exists {
+		return fmt.Errorf("amenity category \"%s\" is not valid", amenity.Category)
+	}
+	return nil
+}
+
+func ValidateRoomDetails(roomDetails database.RoomDetails) error {
+	// Ensure room and property ids are valid uuids
+	if _, err := uuid.Parse(roomDetails.RoomID); err != nil {
+		return errors.New("room id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(roomDetails.PropertyID); err != nil {
+		return errors.New("property id is not a valid uuid")
+	}
+
+	// Room Name
+	if len(roomDetails.Name) == 0 {
+		return errors.New("name cannot be empty")
+	}
+	if goaway.IsProfane(roomDetails.Name) {
+		return fmt.Errorf("name cannot contain profanity: %s", goaway.ExtractProfanity(roomDetails.Name))
+	}
+
+	// Room Description
+	if goaway.IsProfane(roomDetails.Description) {
+		return fmt.Errorf("description cannot contain profanity: %s", goaway.ExtractProfanity(roomDetails.Description))
+	}
+
+	// Square Feet
+	if v := roomDetails.Square_feet; v <= 0 || v > 999999999 {
+		return errors.New("square feet invalid")
+	}
+
+	// Cost in Dollars
+	if v := roomDetails.Cost_dollars; v <= 0 || v > 999999999999 {
+		return errors.New("cost in dollars invalid")
+	}
+
+	// Cost in Cents
+	if v := roomDetails.Cost_cents; v < 0 || v > 99 {
+		return errors.New("cost in cents invalid")
+	}
+
+	// Available from date
+	if len(roomDetails.Available_from) > 0 {
+		if _, err := time.Parse("2006-01-02", roomDetails.Available_from); err != nil {
+			return errors.New("available from date invalid, expected YYYY-MM-DD")
+		}
+	}
+
+	// Occupant, if any. An occupied room can only be listed as available once
+	// it is known when the current occupant moves out.
+	if len(roomDetails.Occupant_user_id) > 0 {
+		if err := ValidateOpenID(roomDetails.Occupant_user_id, "occupant id"); err != nil {
+			return err
+		}
+		if roomDetails.Is_available && len(roomDetails.Available_from) == 0 {
+			return errors.New("an occupied room must have an available from date to be available")
+		}
+	}
+
+	return nil
+}
+
+// ValidatePropertyStatusTransition ensures that a property listing is allowed to move
+// from its current status to the requested status.
+func ValidatePropertyStatusTransition(currentStatus, newStatus string) error {
+	if _, exists := config.PROPERTY_STATUS_OPTIONS[newStatus]; !exists {
+		return fmt.Errorf("property status \"%s\" is not valid", newStatus)
+	}
+	allowed, exists := config.PROPERTY_STATUS_TRANSITIONS[currentStatus]
+	if !exists {
+		return fmt.Errorf("property status \"%s\" is not valid", currentStatus)
+	}
+	if _, exists := allowed[newStatus]; !exists {
+		return fmt.Errorf("cannot change property status from \"%s\" to \"%s\"", currentStatus, newStatus)
+	}
+	return nil
+}
+
+// ValidateViewingSlot ensures that a new viewing slot of a property is in the future
+// and within the limits of a single viewing.
+func ValidateViewingSlot(slot database.ViewingSlot, now time.Time) error {
+	if _, err := uuid.Parse(slot.SlotID); err != nil {
+		return errors.New("slot id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(slot.PropertyID); err != nil {
+		return errors.New("property id is not a valid uuid")
+	}
+
+	if !slot.StartTime.After(now) {
+		return errors.New("viewing must start in the future")
+	}
+	if !slot.EndTime.After(slot.StartTime) {
+		return errors.New("viewing must end after it starts")
+	}
+	if slot.EndTime.Sub(slot.StartTime) > config.VIEWING_SLOT_MAX_DURATION_MINUTES*time.Minute {
+		return fmt.Errorf("viewing cannot be longer than %d minutes", config.VIEWING_SLOT_MAX_DURATION_MINUTES)
+	}
+
+	if slot.Capacity < 1 || slot.Capacity > config.VIEWING_SLOT_MAX_CAPACITY {
+		return fmt.Errorf("viewing capacity must be between 1 and %d", config.VIEWING_SLOT_MAX_CAPACITY)
+	}
+
+	if len(slot.Note) > config.VIEWING_SLOT_MAX_NOTE_LENGTH {
+		return fmt.Errorf("note cannot be longer than %d characters", config.VIEWING_SLOT_MAX_NOTE_LENGTH)
+	}
+	if goaway.IsProfane(slot.Note) {
+		return fmt.Errorf("note cannot contain profanity: %s", goaway.ExtractProfanity(slot.Note))
+	}
+
+	return nil
+}
+
+// ValidateRentalApplication validates a new rental application of a user to a property
+// and their co-applicants.
+func ValidateRentalApplication(application database.RentalApplication, now time.Time) error {
+	if _, err := uuid.Parse(application.ApplicationID); err != nil {
+		return errors.New("application id is not a valid uuid")
+	}
+	if _, err := uuid.Parse(application.PropertyID); err != nil {
+		return errors.New("property id is not a valid uuid")
+	}
+	if err := ValidateOpenID(application.ApplicantUserID, "applicant id"); err != nil {
+		return err
+	}
+
+	if len(application.CoApplicantUserIDs) > config.APPLICATION_MAX_CO_APPLICANTS {
+		return fmt.Errorf("cannot have more than %d co-applicants", config.APPLICATION_MAX_CO_APPLICANTS)
+	}
+	seen := map[string]struct{}{application.ApplicantUserID: {}}
+	for _, userID := range application.CoApplicantUserIDs {
+		if err := ValidateOpenID(userID, "co-applicant id"); err != nil {
+			return err
+		}
+		if _, exists := seen[userID]; exists {
+			return fmt.Errorf("duplicate applicant %s", userID)
+		}
+		seen[userID] = struct{}{}
+	}
+
+	if len(application.Message) > config.APPLICATION_MAX_MESSAGE_LENGTH {
+		return fmt.Errorf("message cannot be longer than %d characters", config.APPLICATION_MAX_MESSAGE_LENGTH)
+	}
+	if goaway.IsProfane(application.Message) {
+		return fmt.Errorf("message cannot contain profanity: %s", goaway.ExtractProfanity(application.Message))
+	}
+
+	if application.MoveInDate != "" {
+		moveInDate, err := time.Parse("2006-01-02", application.MoveInDate)
+		if err != nil {
+			return errors.New("move in date must be of the form YYYY-MM-DD")
+		}
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if moveInDate.Before(today) {
+			return errors.New("move in date cannot be in the past")
+		}
+	}
+
+	return nil
+}
+
+// ValidateRentalApplicationDecision validates the lister moving a rental application
+// from its current status to a new one, with an optional note to the applicants.
+func ValidateRentalApplicationDecision(currentStatus, newStatus, note string) error {
+	if _, exists := config.APPLICATION_STATUS_OPTIONS[newStatus]; !exists {
+		return fmt.Errorf("application status \"%s\" is not valid", newStatus)
+	}
+	if _, exists := config.APPLICATION_STATUS_TRANSITIONS[currentStatus][newStatus]; !exists {
+		return fmt.Errorf("cannot change application status from \"%s\" to \"%s\"", currentStatus, newStatus)
+	}
+
+	if len(note) > config.APPLICATION_MAX_DECISION_NOTE_LENGTH {
+		return fmt.Errorf("note cannot be longer than %d characters", config.APPLICATION_MAX_DECISION_NOTE_LENGTH)
+	}
+	if goaway.IsProfane(note) {
+		return fmt.Errorf("note cannot contain profanity: %s", goaway.ExtractProfanity(note))
+	}
+
 	return nil
 }
 
@@ -301,6 +1115,19 @@ func ValidateUserStatusData(status database.UserStatus) error {
 	return nil
 }
 
+// ValidatePropertyImage ensures that the image of a property is one of the supported image types.
+// The type is detected from the content, as the one sent by the client cannot be trusted.
+func ValidatePropertyImage(image database.FileInternal) error {
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(image.Data))
+	if err != nil {
+		return err
+	}
+	if _, ok := config.PROPERTY_IMAGE_MIME_TYPE_OPTIONS[mimeType]; !ok {
+		return fmt.Errorf("image \"%s\" of type \"%s\" is not one of our supported image types", image.Filename, mimeType)
+	}
+	return nil
+}
+
 // TODO: use Google Cloud Vision API to validate image data for NSFW content.
 //
 // reject any image that has NSFW flagged.
@@ -0,0 +1,46 @@
+-- name: CreateAmenity :exec
+INSERT INTO
+    amenities ("name", category)
+VALUES
+    ($1, $2);
+
+
+-- name: GetAmenities :many
+SELECT
+    *
+FROM
+    amenities
+ORDER BY
+    category,
+    "name";
+
+
+-- name: DeleteAmenity :exec
+DELETE FROM amenities
+WHERE
+    "name" = $1;
+
+
+-- name: CreatePropertyAmenity :exec
+INSERT INTO
+    properties_amenities (property_id, amenity_name)
+VALUES
+    ($1, $2)
+ON CONFLICT (property_id, amenity_name) DO NOTHING;
+
+
+-- name: GetPropertyAmenities :many
+SELECT
+    amenity_name
+FROM
+    properties_amenities
+WHERE
+    property_id = $1
+ORDER BY
+    amenity_name;
+
+
+-- name: DeletePropertyAmenities :exec
+DELETE FROM properties_amenities
+WHERE
+    property_id = $1;
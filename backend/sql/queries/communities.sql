@@ -1,23 +1,129 @@
 -- name: GetNextPageCommunities :many
+-- Communities matching the search filters, most relevant first. A community is located by the
+-- published properties linked to it and has vacancy when one of them has rooms available.
+-- Relevance adds up the similarity of the name and description, the share of the searched
+-- interests held by its members and how close its nearest property is within the radius.
 SELECT
-    community_id
+    communities.community_id
 FROM
     communities
+    CROSS JOIN LATERAL (
+        SELECT
+            count(*) AS member_count
+        FROM
+            communities_users
+        WHERE
+            communities_users.community_id = communities.community_id
+    ) AS members
+    CROSS JOIN LATERAL (
+        SELECT
+            count(DISTINCT members_interests.interest) AS shared_interests
+        FROM
+            communities_users
+            INNER JOIN users ON users.user_id = communities_users.user_id
+            CROSS JOIN LATERAL unnest(users.interests) AS members_interests (interest)
+        WHERE
+            communities_users.community_id = communities.community_id
+            AND members_interests.interest = ANY ($12::text[])
+    ) AS interests
+    CROSS JOIN LATERAL (
+        SELECT
+            min(
+                2 * 6371 * asin(
+                    least(
+                        1,
+                        sqrt(
+                            power(sin(radians(properties.latitude - $6::double precision) / 2), 2) + cos(radians($6)) * cos(radians(properties.latitude)) * power(sin(radians(properties.longitude - $7::double precision) / 2), 2)
+                        )
+                    )
+                )
+            ) AS distance_km
+        FROM
+            communities_properties
+            INNER JOIN properties ON properties.property_id = communities_properties.property_id
+        WHERE
+            communities_properties.community_id = communities.community_id
+            AND properties.status = $13
+            AND properties.latitude IS NOT NULL
+    ) AS nearest
+WHERE
+    (
+        $5::text = ''
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                communities_properties
+                INNER JOIN properties ON properties.property_id = communities_properties.property_id
+            WHERE
+                communities_properties.community_id = communities.community_id
+                AND properties.status = $13
+                AND lower(properties.city) = lower($5)
+        )
+    )
+    AND (
+        $8::double precision = 0
+        OR nearest.distance_km <= $8
+    )
+    AND members.member_count >= $9::bigint
+    AND (
+        $10::bigint = 0
+        OR members.member_count <= $10
+    )
+    AND (
+        NOT $11::boolean
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                communities_properties
+                INNER JOIN properties ON properties.property_id = communities_properties.property_id
+            WHERE
+                communities_properties.community_id = communities.community_id
+                AND properties.status = $13
+                AND (
+                    properties.rooms_available > 0
+                    OR EXISTS (
+                        SELECT
+                            1
+                        FROM
+                            properties_rooms
+                        WHERE
+                            properties_rooms.property_id = properties.property_id
+                            AND properties_rooms.is_available
+                    )
+                )
+        )
+    )
+    AND (
+        cardinality($12::text[]) = 0
+        OR interests.shared_interests > 0
+    )
 ORDER BY
-    CASE
-        WHEN $3 <> ''
-        AND $4 <> '' THEN 0.4 * similarity ("name", $3) + 0.6 * similarity ("description", $4)
-        WHEN $3 <> '' THEN CASE
-            WHEN "name" <> '' THEN similarity ("name", $3)
-            ELSE 0
-        END
-        WHEN $4 <> '' THEN CASE
-            WHEN "description" <> '' THEN similarity ("description", $4)
+    COALESCE(
+        CASE
+            WHEN $3 <> ''
+            AND $4 <> '' THEN 0.4 * similarity (communities."name", $3) + 0.6 * similarity (communities."description", $4)
+            WHEN $3 <> '' THEN CASE
+                WHEN communities."name" <> '' THEN similarity (communities."name", $3)
+                ELSE 0
+            END
+            WHEN $4 <> '' THEN CASE
+                WHEN communities."description" <> '' THEN similarity (communities."description", $4)
+                ELSE 0
+            END
             ELSE 0
-        END
+        END,
+        0
+    ) + CASE
+        WHEN cardinality($12::text[]) > 0 THEN interests.shared_interests::double precision / cardinality($12::text[])
+        ELSE 0
+    END + CASE
+        WHEN $8::double precision > 0 THEN 1 - nearest.distance_km / $8::double precision
         ELSE 0
     END DESC,
-    id
+    members.member_count DESC,
+    communities.id
 LIMIT
     $1
 OFFSET
@@ -43,7 +149,7 @@ INSERT INTO
         file_name,
         mime_type,
         "size",
-        "data"
+        content_hash
     )
 VALUES
     ($1, $2, $3, $4, $5);
@@ -58,9 +164,19 @@ VALUES
 
 -- name: CreateCommunityUser :exec
 INSERT INTO
-    communities_users (community_id, user_id)
+    communities_users (community_id, user_id, role)
 VALUES
-    ($1, $2);
+    ($1, $2, $3);
+
+
+-- name: CheckIsCommunityUser :one
+SELECT
+    count(*)
+FROM
+    communities_users
+WHERE
+    community_id = $1
+    AND user_id = $2;
 
 
 -- name: GetCommunityDetails :one
@@ -74,11 +190,17 @@ WHERE
 
 -- name: GetCommunityImages :many
 SELECT
-    *
+    communities_images.file_name,
+    communities_images.mime_type,
+    communities_images."size",
+    file_blobs."data"
 FROM
     communities_images
+    JOIN file_blobs ON communities_images.content_hash = file_blobs.content_hash
 WHERE
-    community_id = $1;
+    communities_images.community_id = $1
+ORDER BY
+    communities_images.id;
 
 
 -- name: GetCommunityProperties :many
@@ -99,6 +221,16 @@ WHERE
     community_id = $1;
 
 
+-- name: GetCommunityUserRole :one
+SELECT
+    role
+FROM
+    communities_users
+WHERE
+    community_id = $1
+    AND user_id = $2;
+
+
 -- name: GetUserOwnedCommunities :many
 SELECT
     community_id
@@ -108,6 +240,18 @@ WHERE
     admin_user_id = $1;
 
 
+-- name: GetPropertyCommunities :many
+-- Communities a property is linked to
+SELECT
+    community_id
+FROM
+    communities_properties
+WHERE
+    property_id = $1
+ORDER BY
+    id;
+
+
 -- name: UpdateCommunityDetails :exec
 UPDATE communities
 SET
@@ -119,6 +263,15 @@ WHERE
     community_id = $1;
 
 
+-- name: UpdateCommunityUserRole :exec
+UPDATE communities_users
+SET
+    role = $3
+WHERE
+    community_id = $1
+    AND user_id = $2;
+
+
 -- name: UpdateCommunityAdmin :exec
 UPDATE communities
 SET
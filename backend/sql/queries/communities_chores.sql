@@ -0,0 +1,230 @@
+-- name: CreateCommunityChore :exec
+INSERT INTO
+    communities_chores (
+        chore_id,
+        community_id,
+        title,
+        description,
+        frequency,
+        interval_count,
+        starts_on,
+        created_by_user_id
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8);
+
+
+-- name: CreateCommunityChoreRotationMember :exec
+INSERT INTO
+    communities_chores_rotation (chore_id, user_id, position)
+VALUES
+    ($1, $2, $3);
+
+
+-- name: CreateCommunityChoreTask :exec
+-- Scheduling an occurrence of a chore that already has a task does nothing
+INSERT INTO
+    communities_chores_tasks (
+        task_id,
+        chore_id,
+        occurrence,
+        due_on,
+        assignee_user_id
+    )
+VALUES
+    ($1, $2, $3, $4, $5)
+ON CONFLICT (chore_id, occurrence) DO NOTHING;
+
+
+-- name: DeleteCommunityChore :exec
+DELETE FROM communities_chores
+WHERE
+    chore_id = $1;
+
+
+-- name: GetAllCommunityChores :many
+SELECT
+    *
+FROM
+    communities_chores
+ORDER BY
+    id;
+
+
+-- name: GetCommunityChore :one
+SELECT
+    *
+FROM
+    communities_chores
+WHERE
+    chore_id = $1;
+
+
+-- name: GetCommunityChoreLastOccurrence :one
+-- Index of the last occurrence of a chore with a task, -1 if none was scheduled yet
+SELECT
+    COALESCE(max(occurrence), -1)::integer AS last_occurrence
+FROM
+    communities_chores_tasks
+WHERE
+    chore_id = $1;
+
+
+-- name: GetCommunityChoreRotations :many
+-- Rotations of all the chores of a community, leaving out the users who are no longer members
+SELECT
+    communities_chores_rotation.chore_id,
+    communities_chores_rotation.user_id
+FROM
+    communities_chores_rotation
+    INNER JOIN communities_chores ON communities_chores.chore_id = communities_chores_rotation.chore_id
+    INNER JOIN communities_users ON communities_users.community_id = communities_chores.community_id
+    AND communities_users.user_id = communities_chores_rotation.user_id
+WHERE
+    communities_chores.community_id = $1
+ORDER BY
+    communities_chores_rotation.chore_id,
+    communities_chores_rotation.position;
+
+
+-- name: GetCommunityChoreTask :one
+SELECT
+    communities_chores_tasks.task_id,
+    communities_chores_tasks.chore_id,
+    communities_chores.community_id,
+    communities_chores.title,
+    communities_chores_tasks.occurrence,
+    communities_chores_tasks.due_on,
+    communities_chores_tasks.assignee_user_id,
+    communities_chores_tasks.completed_at,
+    communities_chores_tasks.completed_by_user_id,
+    communities_chores_tasks.swap_task_id
+FROM
+    communities_chores_tasks
+    INNER JOIN communities_chores ON communities_chores.chore_id = communities_chores_tasks.chore_id
+WHERE
+    communities_chores_tasks.task_id = $1;
+
+
+-- name: GetCommunityChoreTasks :many
+-- Tasks of the chores of a community due from a date on, along with the ones still to do
+SELECT
+    communities_chores_tasks.task_id,
+    communities_chores_tasks.chore_id,
+    communities_chores.community_id,
+    communities_chores.title,
+    communities_chores_tasks.occurrence,
+    communities_chores_tasks.due_on,
+    communities_chores_tasks.assignee_user_id,
+    communities_chores_tasks.completed_at,
+    communities_chores_tasks.completed_by_user_id,
+    communities_chores_tasks.swap_task_id
+FROM
+    communities_chores_tasks
+    INNER JOIN communities_chores ON communities_chores.chore_id = communities_chores_tasks.chore_id
+WHERE
+    communities_chores.community_id = $1
+    AND (
+        communities_chores_tasks.due_on >= $2
+        OR communities_chores_tasks.completed_at IS NULL
+    )
+ORDER BY
+    communities_chores_tasks.due_on,
+    communities_chores.title;
+
+
+-- name: GetCommunityChores :many
+SELECT
+    *
+FROM
+    communities_chores
+WHERE
+    community_id = $1
+ORDER BY
+    title;
+
+
+-- name: GetUserChoreTasks :many
+-- Tasks assigned to a user across their communities due from a date on, along with the ones still to do
+SELECT
+    communities_chores_tasks.task_id,
+    communities_chores_tasks.chore_id,
+    communities_chores.community_id,
+    communities_chores.title,
+    communities_chores_tasks.occurrence,
+    communities_chores_tasks.due_on,
+    communities_chores_tasks.assignee_user_id,
+    communities_chores_tasks.completed_at,
+    communities_chores_tasks.completed_by_user_id,
+    communities_chores_tasks.swap_task_id
+FROM
+    communities_chores_tasks
+    INNER JOIN communities_chores ON communities_chores.chore_id = communities_chores_tasks.chore_id
+WHERE
+    communities_chores_tasks.assignee_user_id = $1
+    AND (
+        communities_chores_tasks.due_on >= $2
+        OR communities_chores_tasks.completed_at IS NULL
+    )
+ORDER BY
+    communities_chores_tasks.due_on,
+    communities_chores.title;
+
+
+-- name: SwapCommunityChoreTaskAssignees :execrows
+-- Swaps the assignees of two tasks still to do, which withdraws the proposals to swap them. The
+-- subqueries see the assignees from before the update, so both tasks are updated or neither is.
+UPDATE communities_chores_tasks
+SET
+    swap_task_id = NULL,
+    assignee_user_id = CASE
+        WHEN task_id = $1 THEN (
+            SELECT
+                other.assignee_user_id
+            FROM
+                communities_chores_tasks AS other
+            WHERE
+                other.task_id = $2
+        )
+        ELSE (
+            SELECT
+                other.assignee_user_id
+            FROM
+                communities_chores_tasks AS other
+            WHERE
+                other.task_id = $1
+        )
+    END
+WHERE
+    task_id IN ($1, $2)
+    AND completed_at IS NULL
+    AND (
+        SELECT
+            count(*)
+        FROM
+            communities_chores_tasks AS pending
+        WHERE
+            pending.task_id IN ($1, $2)
+            AND pending.completed_at IS NULL
+    ) = 2;
+
+
+-- name: UpdateCommunityChoreTaskCompletion :exec
+-- Marks a task done, or to do again when the completion is null
+UPDATE communities_chores_tasks
+SET
+    completed_at = $2,
+    completed_by_user_id = $3
+WHERE
+    task_id = $1;
+
+
+-- name: UpdateCommunityChoreTaskSwap :execrows
+-- Proposes to swap a task still to do with another task, or withdraws the proposal when the other
+-- task is null
+UPDATE communities_chores_tasks
+SET
+    swap_task_id = $2
+WHERE
+    task_id = $1
+    AND completed_at IS NULL;
@@ -0,0 +1,182 @@
+-- name: CreateCommunityDocument :exec
+INSERT INTO
+    communities_documents (
+        document_id,
+        community_id,
+        folder_id,
+        title,
+        visibility,
+        created_by_user_id
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6);
+
+
+-- name: CreateCommunityDocumentFolder :exec
+INSERT INTO
+    communities_documents_folders (
+        folder_id,
+        community_id,
+        parent_folder_id,
+        "name",
+        created_by_user_id
+    )
+VALUES
+    ($1, $2, $3, $4, $5);
+
+
+-- name: CreateCommunityDocumentVersion :one
+-- Adds the next version of a document
+INSERT INTO
+    communities_documents_versions (
+        document_id,
+        "version",
+        file_name,
+        mime_type,
+        "size",
+        content_hash,
+        uploaded_by_user_id,
+        note
+    )
+SELECT
+    $1,
+    coalesce(max("version"), 0) + 1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+FROM
+    communities_documents_versions
+WHERE
+    document_id = $1
+RETURNING
+    "version";
+
+
+-- name: DeleteCommunityDocument :exec
+DELETE FROM communities_documents
+WHERE
+    document_id = $1;
+
+
+-- name: DeleteCommunityDocumentFolder :exec
+DELETE FROM communities_documents_folders
+WHERE
+    folder_id = $1;
+
+
+-- name: GetCommunityDocument :one
+-- Document with the details of its latest version
+SELECT
+    communities_documents.document_id,
+    communities_documents.community_id,
+    communities_documents.folder_id,
+    communities_documents.title,
+    communities_documents.visibility,
+    communities_documents.created_by_user_id,
+    communities_documents.created_at,
+    communities_documents_versions."version",
+    communities_documents_versions.file_name,
+    communities_documents_versions.mime_type,
+    communities_documents_versions."size",
+    communities_documents_versions.created_at AS updated_at
+FROM
+    communities_documents
+    JOIN communities_documents_versions ON communities_documents_versions.document_id = communities_documents.document_id
+WHERE
+    communities_documents.document_id = $1
+ORDER BY
+    communities_documents_versions."version" DESC
+LIMIT
+    1;
+
+
+-- name: GetCommunityDocumentFile :one
+SELECT
+    communities_documents_versions.file_name,
+    communities_documents_versions.mime_type,
+    communities_documents_versions."size",
+    file_blobs."data"
+FROM
+    communities_documents_versions
+    JOIN file_blobs ON communities_documents_versions.content_hash = file_blobs.content_hash
+WHERE
+    communities_documents_versions.document_id = $1
+    AND communities_documents_versions."version" = $2;
+
+
+-- name: GetCommunityDocumentFolder :one
+SELECT
+    *
+FROM
+    communities_documents_folders
+WHERE
+    folder_id = $1;
+
+
+-- name: GetCommunityDocumentFolders :many
+SELECT
+    *
+FROM
+    communities_documents_folders
+WHERE
+    community_id = $1
+ORDER BY
+    "name";
+
+
+-- name: GetCommunityDocumentVersions :many
+-- Versions of a document without their files, latest first
+SELECT
+    document_id,
+    "version",
+    file_name,
+    mime_type,
+    "size",
+    uploaded_by_user_id,
+    note,
+    created_at
+FROM
+    communities_documents_versions
+WHERE
+    document_id = $1
+ORDER BY
+    "version" DESC;
+
+
+-- name: GetCommunityDocuments :many
+-- Documents of a community with the details of their latest version, by title
+SELECT DISTINCT
+    ON (communities_documents.title, communities_documents.document_id) communities_documents.document_id,
+    communities_documents.community_id,
+    communities_documents.folder_id,
+    communities_documents.title,
+    communities_documents.visibility,
+    communities_documents.created_by_user_id,
+    communities_documents.created_at,
+    communities_documents_versions."version",
+    communities_documents_versions.file_name,
+    communities_documents_versions.mime_type,
+    communities_documents_versions."size",
+    communities_documents_versions.created_at AS updated_at
+FROM
+    communities_documents
+    JOIN communities_documents_versions ON communities_documents_versions.document_id = communities_documents.document_id
+WHERE
+    communities_documents.community_id = $1
+ORDER BY
+    communities_documents.title,
+    communities_documents.document_id,
+    communities_documents_versions."version" DESC;
+
+
+-- name: UpdateCommunityDocument :exec
+UPDATE communities_documents
+SET
+    folder_id = $2,
+    title = $3,
+    visibility = $4
+WHERE
+    document_id = $1;
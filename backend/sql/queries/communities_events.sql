@@ -0,0 +1,163 @@
+-- name: CreateCommunityEvent :exec
+INSERT INTO
+    communities_events (
+        event_id,
+        community_id,
+        property_id,
+        created_by_user_id,
+        title,
+        description,
+        location,
+        start_time,
+        end_time,
+        capacity
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+
+-- name: DeleteCommunityEvent :exec
+DELETE FROM communities_events
+WHERE
+    event_id = $1;
+
+
+-- name: DeleteCommunityEventRSVP :exec
+DELETE FROM communities_events_rsvps
+WHERE
+    event_id = $1
+    AND user_id = $2;
+
+
+-- name: GetCommunityEvent :one
+SELECT
+    communities_events.event_id,
+    communities_events.community_id,
+    communities_events.property_id,
+    communities_events.created_by_user_id,
+    communities_events.title,
+    communities_events.description,
+    communities_events.location,
+    communities_events.start_time,
+    communities_events.end_time,
+    communities_events.capacity,
+    (
+        SELECT
+            count(*)
+        FROM
+            communities_events_rsvps
+        WHERE
+            communities_events_rsvps.event_id = communities_events.event_id
+            AND communities_events_rsvps.response = 'yes'
+    ) AS yes_count,
+    (
+        SELECT
+            count(*)
+        FROM
+            communities_events_rsvps
+        WHERE
+            communities_events_rsvps.event_id = communities_events.event_id
+            AND communities_events_rsvps.response = 'maybe'
+    ) AS maybe_count
+FROM
+    communities_events
+WHERE
+    communities_events.event_id = $1;
+
+
+-- name: GetCommunityEventRSVPs :many
+SELECT
+    *
+FROM
+    communities_events_rsvps
+WHERE
+    event_id = $1
+ORDER BY
+    created_at;
+
+
+-- name: GetCommunityEvents :many
+-- Events of a community ending after the given time, soonest first
+SELECT
+    communities_events.event_id,
+    communities_events.community_id,
+    communities_events.property_id,
+    communities_events.created_by_user_id,
+    communities_events.title,
+    communities_events.description,
+    communities_events.location,
+    communities_events.start_time,
+    communities_events.end_time,
+    communities_events.capacity,
+    (
+        SELECT
+            count(*)
+        FROM
+            communities_events_rsvps
+        WHERE
+            communities_events_rsvps.event_id = communities_events.event_id
+            AND communities_events_rsvps.response = 'yes'
+    ) AS yes_count,
+    (
+        SELECT
+            count(*)
+        FROM
+            communities_events_rsvps
+        WHERE
+            communities_events_rsvps.event_id = communities_events.event_id
+            AND communities_events_rsvps.response = 'maybe'
+    ) AS maybe_count
+FROM
+    communities_events
+WHERE
+    communities_events.community_id = $1
+    AND communities_events.end_time > $2
+ORDER BY
+    communities_events.start_time;
+
+
+-- name: LockCommunityEvent :exec
+-- Locks the event until the end of the transaction
+SELECT
+    event_id
+FROM
+    communities_events
+WHERE
+    event_id = $1
+FOR UPDATE;
+
+
+-- name: UpsertCommunityEventRSVP :execrows
+-- RSVPs to the event only if it has not started yet and, for a yes, is not full.
+-- The event must be locked by LockCommunityEvent in the same transaction
+-- beforehand, concurrent RSVPs would otherwise count the same yes RSVPs and
+-- exceed the capacity.
+INSERT INTO
+    communities_events_rsvps (event_id, user_id, response)
+SELECT
+    event_id,
+    $2,
+    $3
+FROM
+    communities_events
+WHERE
+    communities_events.event_id = $1
+    AND communities_events.start_time > CURRENT_TIMESTAMP
+    AND (
+        $3 <> 'yes'
+        OR communities_events.capacity IS NULL
+        OR communities_events.capacity > (
+            SELECT
+                count(*)
+            FROM
+                communities_events_rsvps
+            WHERE
+                communities_events_rsvps.event_id = $1
+                AND communities_events_rsvps.response = 'yes'
+                AND communities_events_rsvps.user_id <> $2
+        )
+    )
+ON CONFLICT (event_id, user_id) DO UPDATE
+SET
+    response = excluded.response,
+    updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,63 @@
+-- name: CreateCommunityExpense :exec
+INSERT INTO
+    communities_expenses (
+        expense_id,
+        community_id,
+        payer_user_id,
+        created_by_user_id,
+        description,
+        category,
+        amount_cents,
+        split_type,
+        spent_on
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+
+-- name: CreateCommunityExpenseSplit :exec
+INSERT INTO
+    communities_expenses_splits (expense_id, user_id, shares, amount_cents)
+VALUES
+    ($1, $2, $3, $4);
+
+
+-- name: DeleteCommunityExpense :exec
+DELETE FROM communities_expenses
+WHERE
+    expense_id = $1;
+
+
+-- name: GetCommunityExpense :one
+SELECT
+    *
+FROM
+    communities_expenses
+WHERE
+    expense_id = $1;
+
+
+-- name: GetCommunityExpenseSplits :many
+-- Splits of all the expenses of a community
+SELECT
+    communities_expenses_splits.*
+FROM
+    communities_expenses_splits
+    INNER JOIN communities_expenses ON communities_expenses.expense_id = communities_expenses_splits.expense_id
+WHERE
+    communities_expenses.community_id = $1
+ORDER BY
+    communities_expenses_splits.id;
+
+
+-- name: GetCommunityExpenses :many
+-- Expenses of a community in the order they were spent
+SELECT
+    *
+FROM
+    communities_expenses
+WHERE
+    community_id = $1
+ORDER BY
+    spent_on,
+    created_at;
@@ -0,0 +1,103 @@
+-- name: CreateCommunityMembershipRequest :exec
+INSERT INTO
+    communities_membership_requests (
+        request_id,
+        community_id,
+        user_id,
+        kind,
+        invited_by_user_id,
+        expires_at
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6);
+
+
+-- name: DeleteCommunityMembershipRequest :exec
+DELETE FROM communities_membership_requests
+WHERE
+    request_id = $1;
+
+
+-- name: DeleteExpiredCommunityMembershipRequests :execrows
+DELETE FROM communities_membership_requests
+WHERE
+    expires_at <= $1;
+
+
+-- name: GetCommunityMembershipRequest :one
+SELECT
+    *
+FROM
+    communities_membership_requests
+WHERE
+    request_id = $1
+    AND (
+        expires_at IS NULL
+        OR expires_at > $2
+    );
+
+
+-- name: GetCommunityMembershipRequestOfUser :one
+SELECT
+    *
+FROM
+    communities_membership_requests
+WHERE
+    community_id = $1
+    AND user_id = $2
+    AND (
+        expires_at IS NULL
+        OR expires_at > $3
+    )
+LIMIT
+    1;
+
+
+-- name: GetCommunityMembershipRequests :many
+SELECT
+    *
+FROM
+    communities_membership_requests
+WHERE
+    community_id = $1
+    AND (
+        expires_at IS NULL
+        OR expires_at > $2
+    )
+ORDER BY
+    created_at;
+
+
+-- name: GetUserCommunityMembershipRequests :many
+-- Pending memberships of the user, as well as of the communities the user has one
+-- of the given roles in
+SELECT
+    communities_membership_requests.id,
+    communities_membership_requests.request_id,
+    communities_membership_requests.community_id,
+    communities_membership_requests.user_id,
+    communities_membership_requests.kind,
+    communities_membership_requests.invited_by_user_id,
+    communities_membership_requests.expires_at,
+    communities_membership_requests.created_at
+FROM
+    communities_membership_requests
+WHERE
+    (
+        communities_membership_requests.user_id = $1
+        OR communities_membership_requests.community_id IN (
+            SELECT
+                communities_users.community_id
+            FROM
+                communities_users
+            WHERE
+                communities_users.user_id = $1
+                AND communities_users.role = ANY ($3::text[])
+        )
+    )
+    AND (
+        communities_membership_requests.expires_at IS NULL
+        OR communities_membership_requests.expires_at > $2
+    )
+ORDER BY
+    communities_membership_requests.created_at DESC;
@@ -0,0 +1,171 @@
+-- name: CastCommunityPollBallot :execrows
+-- Casts the ballot of a member with a vote for each chosen option, only while the poll is open.
+-- The unique ballot of a member per poll rejects a second ballot. Votes of anonymous polls are
+-- not linked to their voter.
+WITH
+    ballot AS (
+        INSERT INTO
+            communities_polls_ballots (poll_id, user_id)
+        SELECT
+            poll_id,
+            $2
+        FROM
+            communities_polls
+        WHERE
+            communities_polls.poll_id = $1
+            AND communities_polls.opens_at <= CURRENT_TIMESTAMP
+            AND communities_polls.closes_at > CURRENT_TIMESTAMP
+        RETURNING
+            poll_id,
+            user_id
+    )
+INSERT INTO
+    communities_polls_votes (poll_id, option_id, user_id)
+SELECT
+    ballot.poll_id,
+    unnest($3::text[]),
+    CASE
+        WHEN communities_polls.anonymous THEN NULL
+        ELSE ballot.user_id
+    END
+FROM
+    ballot
+    INNER JOIN communities_polls ON communities_polls.poll_id = ballot.poll_id;
+
+
+-- name: CheckIsCommunityPollVoter :one
+SELECT
+    count(*)
+FROM
+    communities_polls_ballots
+WHERE
+    poll_id = $1
+    AND user_id = $2;
+
+
+-- name: CreateCommunityPoll :exec
+INSERT INTO
+    communities_polls (
+        poll_id,
+        community_id,
+        created_by_user_id,
+        question,
+        multiple_choice,
+        anonymous,
+        results_visibility,
+        opens_at,
+        closes_at
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+
+-- name: CreateCommunityPollOption :exec
+INSERT INTO
+    communities_polls_options (option_id, poll_id, position, label)
+VALUES
+    ($1, $2, $3, $4);
+
+
+-- name: DeleteCommunityPoll :exec
+DELETE FROM communities_polls
+WHERE
+    poll_id = $1;
+
+
+-- name: GetCommunityPoll :one
+SELECT
+    communities_polls.poll_id,
+    communities_polls.community_id,
+    communities_polls.created_by_user_id,
+    communities_polls.question,
+    communities_polls.multiple_choice,
+    communities_polls.anonymous,
+    communities_polls.results_visibility,
+    communities_polls.opens_at,
+    communities_polls.closes_at,
+    communities_polls.created_at,
+    (
+        SELECT
+            count(*)
+        FROM
+            communities_polls_ballots
+        WHERE
+            communities_polls_ballots.poll_id = communities_polls.poll_id
+    ) AS voter_count
+FROM
+    communities_polls
+WHERE
+    communities_polls.poll_id = $1;
+
+
+-- name: GetCommunityPollOptions :many
+SELECT
+    *
+FROM
+    communities_polls_options
+WHERE
+    poll_id = $1
+ORDER BY
+    position;
+
+
+-- name: GetCommunityPollResults :many
+-- Number of votes for each option of a poll
+SELECT
+    communities_polls_options.option_id,
+    count(communities_polls_votes.vote_id) AS votes
+FROM
+    communities_polls_options
+    LEFT JOIN communities_polls_votes ON communities_polls_votes.option_id = communities_polls_options.option_id
+WHERE
+    communities_polls_options.poll_id = $1
+GROUP BY
+    communities_polls_options.option_id,
+    communities_polls_options.position
+ORDER BY
+    communities_polls_options.position;
+
+
+-- name: GetCommunityPollVoters :many
+-- Voters of each option of a poll, there are none for anonymous polls
+SELECT
+    option_id,
+    user_id
+FROM
+    communities_polls_votes
+WHERE
+    poll_id = $1
+    AND user_id IS NOT NULL
+ORDER BY
+    option_id,
+    user_id;
+
+
+-- name: GetCommunityPolls :many
+-- Polls of a community, newest first
+SELECT
+    communities_polls.poll_id,
+    communities_polls.community_id,
+    communities_polls.created_by_user_id,
+    communities_polls.question,
+    communities_polls.multiple_choice,
+    communities_polls.anonymous,
+    communities_polls.results_visibility,
+    communities_polls.opens_at,
+    communities_polls.closes_at,
+    communities_polls.created_at,
+    (
+        SELECT
+            count(*)
+        FROM
+            communities_polls_ballots
+        WHERE
+            communities_polls_ballots.poll_id = communities_polls.poll_id
+    ) AS voter_count
+FROM
+    communities_polls
+WHERE
+    communities_polls.community_id = $1
+ORDER BY
+    communities_polls.created_at DESC;
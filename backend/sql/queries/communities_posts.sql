@@ -0,0 +1,77 @@
+-- name: CreateCommunityPost :exec
+INSERT INTO
+    communities_posts (
+        post_id,
+        community_id,
+        thread_id,
+        user_id,
+        title,
+        body
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6);
+
+
+-- name: DeleteCommunityPost :exec
+DELETE FROM communities_posts
+WHERE
+    post_id = $1;
+
+
+-- name: GetCommunityPost :one
+SELECT
+    *
+FROM
+    communities_posts
+WHERE
+    post_id = $1;
+
+
+-- name: GetCommunityThreadReplies :many
+SELECT
+    *
+FROM
+    communities_posts
+WHERE
+    thread_id = $1
+ORDER BY
+    created_at,
+    id
+LIMIT
+    $2
+OFFSET
+    $3;
+
+
+-- name: GetCommunityThreads :many
+SELECT
+    *
+FROM
+    communities_posts
+WHERE
+    community_id = $1
+    AND thread_id IS NULL
+ORDER BY
+    pinned DESC,
+    last_activity_at DESC,
+    id DESC
+LIMIT
+    $2
+OFFSET
+    $3;
+
+
+-- name: UpdateCommunityPostPinned :exec
+UPDATE communities_posts
+SET
+    pinned = $2
+WHERE
+    post_id = $1;
+
+
+-- name: UpdateCommunityThreadActivity :exec
+UPDATE communities_posts
+SET
+    last_activity_at = CURRENT_TIMESTAMP
+WHERE
+    post_id = $1;
@@ -0,0 +1,64 @@
+-- name: CreateCommunityPropertyLinkRequest :exec
+INSERT INTO
+    communities_property_link_requests (
+        request_id,
+        community_id,
+        property_id,
+        requested_by_user_id
+    )
+VALUES
+    ($1, $2, $3, $4);
+
+
+-- name: DeleteCommunityPropertyLinkRequest :exec
+DELETE FROM communities_property_link_requests
+WHERE
+    request_id = $1;
+
+
+-- name: GetCommunityPropertyLinkRequest :one
+SELECT
+    *
+FROM
+    communities_property_link_requests
+WHERE
+    request_id = $1;
+
+
+-- name: GetCommunityPropertyLinkRequestOfProperty :one
+SELECT
+    *
+FROM
+    communities_property_link_requests
+WHERE
+    community_id = $1
+    AND property_id = $2;
+
+
+-- name: GetCommunityPropertyLinkRequests :many
+SELECT
+    *
+FROM
+    communities_property_link_requests
+WHERE
+    community_id = $1
+ORDER BY
+    created_at;
+
+
+-- name: GetListerCommunityPropertyLinkRequests :many
+-- Pending requests to link the properties listed by the user
+SELECT
+    communities_property_link_requests.id,
+    communities_property_link_requests.request_id,
+    communities_property_link_requests.community_id,
+    communities_property_link_requests.property_id,
+    communities_property_link_requests.requested_by_user_id,
+    communities_property_link_requests.created_at
+FROM
+    communities_property_link_requests
+    JOIN properties ON communities_property_link_requests.property_id = properties.property_id
+WHERE
+    properties.lister_user_id = $1
+ORDER BY
+    communities_property_link_requests.created_at DESC;
@@ -0,0 +1,31 @@
+-- name: CreateFileBlob :exec
+-- Stores the file content once per unique content hash, reference counts are
+-- maintained by triggers on the tables that reference file_blobs. An existing
+-- blob is touched rather than skipped so that its row stays locked until the
+-- transaction inserting the reference commits, it can then not be released by a
+-- concurrent delete in between.
+INSERT INTO
+    file_blobs (content_hash, "size", "data")
+VALUES
+    ($1, $2, $3)
+ON CONFLICT (content_hash) DO UPDATE
+SET
+    content_hash = EXCLUDED.content_hash;
+
+
+-- name: DeleteUnreferencedFileBlobs :execrows
+-- Deletes the blobs left without any reference that were created before the
+-- given time, e.g. by an upload that failed after storing its blob.
+DELETE FROM file_blobs
+WHERE
+    ref_count <= 0
+    AND created_at < $1;
+
+
+-- name: GetFileBlob :one
+SELECT
+    *
+FROM
+    file_blobs
+WHERE
+    content_hash = $1;
@@ -0,0 +1,48 @@
+-- name: CountUnreadNotifications :one
+SELECT
+    count(*)
+FROM
+    notifications
+WHERE
+    user_id = $1
+    AND is_read = FALSE;
+
+
+-- name: CreateNotification :exec
+INSERT INTO
+    notifications (user_id, "type", message, link)
+VALUES
+    ($1, $2, $3, $4);
+
+
+-- name: GetNotifications :many
+SELECT
+    *
+FROM
+    notifications
+WHERE
+    user_id = $1
+ORDER BY
+    created_at DESC,
+    id DESC
+LIMIT
+    $2
+OFFSET
+    $3;
+
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications
+SET
+    is_read = TRUE
+WHERE
+    user_id = $1;
+
+
+-- name: MarkNotificationRead :exec
+UPDATE notifications
+SET
+    is_read = TRUE
+WHERE
+    id = $1
+    AND user_id = $2;
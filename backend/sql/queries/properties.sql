@@ -17,7 +17,21 @@ INSERT INTO
         num_showers_baths,
         cost_dollars,
         cost_cents,
-        misc_note
+        misc_note,
+        status,
+        price_type,
+        deposit_dollars,
+        deposit_cents,
+        utilities_included,
+        lease_length_months,
+        available_from,
+        rooms_available,
+        pets_policy,
+        smoking_policy,
+        normalized_address,
+        latitude,
+        longitude,
+        expires_at
     )
 VALUES
     (
@@ -37,7 +51,21 @@ VALUES
         $14,
         $15,
         $16,
-        $17
+        $17,
+        $18,
+        $19,
+        $20,
+        $21,
+        $22,
+        $23,
+        $24,
+        $25,
+        $26,
+        $27,
+        $28,
+        $29,
+        $30,
+        $31
     );
 
 
@@ -49,7 +77,7 @@ INSERT INTO
         file_name,
         mime_type,
         "size",
-        "data"
+        content_hash
     )
 VALUES
     ($1, $2, $3, $4, $5, $6);
@@ -75,13 +103,46 @@ ORDER BY
     id;
 
 
+-- name: GetPropertyImage :one
+SELECT
+    properties_images.order_num,
+    properties_images.file_name,
+    properties_images.mime_type,
+    properties_images."size",
+    file_blobs."data"
+FROM
+    properties_images
+    JOIN file_blobs ON properties_images.content_hash = file_blobs.content_hash
+WHERE
+    properties_images.property_id = $1
+    AND properties_images.order_num = $2;
+
+
+-- name: GetPropertyImageOrderNums :many
+SELECT
+    order_num
+FROM
+    properties_images
+WHERE
+    property_id = $1
+ORDER BY
+    order_num;
+
+
 -- name: GetPropertyImages :many
 SELECT
-    *
+    properties_images.order_num,
+    properties_images.file_name,
+    properties_images.mime_type,
+    properties_images."size",
+    file_blobs."data"
 FROM
     properties_images
+    JOIN file_blobs ON properties_images.content_hash = file_blobs.content_hash
 WHERE
-    property_id = $1;
+    properties_images.property_id = $1
+ORDER BY
+    properties_images.order_num;
 
 
 -- name: UpdatePropertyDetails :exec
@@ -103,11 +164,78 @@ SET
     cost_cents = $15,
     misc_note = $16,
     lister_user_id = $17,
+    price_type = $18,
+    deposit_dollars = $19,
+    deposit_cents = $20,
+    utilities_included = $21,
+    lease_length_months = $22,
+    available_from = $23,
+    rooms_available = $24,
+    pets_policy = $25,
+    smoking_policy = $26,
+    normalized_address = $27,
+    latitude = $28,
+    longitude = $29,
     updated_at = CURRENT_TIMESTAMP
 WHERE
     property_id = $1;
 
 
+-- name: UpdatePropertyStatus :exec
+UPDATE properties
+SET
+    status = $2,
+    status_updated_at = CURRENT_TIMESTAMP,
+    expires_at = $3,
+    expiry_reminder_sent_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    property_id = $1;
+
+
+-- name: UpdatePropertyExpiry :exec
+UPDATE properties
+SET
+    expires_at = $2,
+    expiry_reminder_sent_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    property_id = $1;
+
+
+-- name: RemindExpiringProperties :many
+-- Marks published properties expiring by the given time whose lister was not reminded yet
+-- as reminded, returning them to remind their listers
+UPDATE properties
+SET
+    expiry_reminder_sent_at = CURRENT_TIMESTAMP
+WHERE
+    status = 'published'
+    AND expires_at <= $1
+    AND expiry_reminder_sent_at IS NULL
+RETURNING
+    property_id,
+    lister_user_id,
+    name,
+    expires_at;
+
+
+-- name: ExpireProperties :many
+-- Hides published properties whose listing expired by the given time
+UPDATE properties
+SET
+    status = 'expired',
+    status_updated_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    status = 'published'
+    AND expires_at <= $1
+RETURNING
+    property_id,
+    lister_user_id,
+    name;
+
+
 -- name: UpdatePropertyLister :exec
 UPDATE properties
 SET
@@ -164,7 +292,55 @@ WHERE
         AND lower(trim("state")) = lower(trim($4))
         AND lower(trim(zipcode)) = lower(trim($5))
         AND lower(trim(country)) = lower(trim($6))
-    );
+    )
+    AND property_id <> $7;
+
+
+-- name: GetPropertyAddresses :many
+SELECT
+    property_id,
+    address_1,
+    address_2,
+    city,
+    "state",
+    zipcode,
+    country,
+    normalized_address
+FROM
+    properties
+ORDER BY
+    id;
+
+
+-- name: UpdatePropertyNormalizedAddress :exec
+UPDATE properties
+SET
+    normalized_address = $2
+WHERE
+    property_id = $1;
+
+
+-- name: GetSimilarProperties :many
+-- Properties whose normalized address is the same as or similar to the given one by trigram
+-- similarity, most similar first.
+SELECT
+    property_id,
+    similarity (normalized_address, $1::text)::real AS similarity
+FROM
+    properties
+WHERE
+    property_id <> $2
+    AND (
+        normalized_address = $1
+        OR (
+            normalized_address % $1
+            AND similarity (normalized_address, $1) >= $3::real
+        )
+    )
+ORDER BY
+    similarity DESC
+LIMIT
+    5;
 
 
 -- name: GetNextPageProperties :many
@@ -172,6 +348,63 @@ SELECT
     property_id
 FROM
     properties
+WHERE
+    status = $4
+    AND (
+        $5::text = ''
+        OR price_type = $5
+    )
+    AND (
+        $6::bigint = 0
+        OR cost_dollars <= $6
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                properties_rooms
+            WHERE
+                properties_rooms.property_id = properties.property_id
+                AND properties_rooms.is_available
+                AND properties_rooms.cost_dollars <= $6
+        )
+    )
+    AND (
+        $7::text = ''
+        OR available_from IS NULL
+        OR available_from <= $7::date
+    )
+    AND (
+        $8::integer = 0
+        OR $8 = ANY (lease_length_months)
+    )
+    AND (
+        NOT $9::boolean
+        OR utilities_included
+    )
+    AND (
+        NOT $10::boolean
+        OR pets_policy IN ('allowed', 'negotiable')
+    )
+    AND (
+        NOT $11::boolean
+        OR smoking_policy IN ('allowed', 'outside_only')
+    )
+    AND rooms_available >= $12
+    AND (
+        cardinality($13::text[]) = 0
+        OR (
+            SELECT
+                count(DISTINCT amenity_name)
+            FROM
+                properties_amenities
+            WHERE
+                properties_amenities.property_id = properties.property_id
+                AND properties_amenities.amenity_name = ANY ($13::text[])
+        ) >= CASE
+            WHEN $14::boolean THEN cardinality($13::text[])
+            ELSE 1
+        END
+    )
 ORDER BY
     CASE
         WHEN $3 <> '' THEN similarity (
@@ -194,3 +427,33 @@ LIMIT
     $1
 OFFSET
     $2;
+
+
+-- name: GetRecommendationCandidates :many
+-- Properties of the given status and price type closest in price to the given cost,
+-- leaving out the excluded properties and the properties of the excluded lister.
+SELECT
+    property_id,
+    price_type,
+    cost_dollars,
+    cost_cents,
+    square_feet,
+    num_bedrooms,
+    num_showers_baths,
+    city,
+    "state",
+    zipcode,
+    latitude,
+    longitude
+FROM
+    properties
+WHERE
+    status = $1
+    AND price_type = $2
+    AND NOT (property_id = ANY ($3::text[]))
+    AND lister_user_id <> $4
+ORDER BY
+    abs(cost_dollars - $5::bigint),
+    property_id
+LIMIT
+    $6;
@@ -0,0 +1,48 @@
+-- name: CreatePropertyAnalyticsEvent :execrows
+-- Records that the viewer viewed or saved the property today, unless they already did
+INSERT INTO
+    properties_analytics_events (property_id, "day", event_type, viewer_hash)
+VALUES
+    ($1, CURRENT_DATE, $2, $3)
+ON CONFLICT (property_id, "day", event_type, viewer_hash) DO NOTHING;
+
+
+-- name: DeletePropertyAnalyticsEventsBefore :execrows
+-- Viewer events only de-duplicate views and saves within a day, older ones are no longer needed
+DELETE FROM properties_analytics_events
+WHERE
+    "day" < $1;
+
+
+-- name: GetPropertyAnalyticsDaily :many
+SELECT
+    "day",
+    views,
+    saves
+FROM
+    properties_analytics_daily
+WHERE
+    property_id = $1
+    AND "day" >= $2
+ORDER BY
+    "day";
+
+
+-- name: IncrementPropertyAnalyticsSaves :exec
+INSERT INTO
+    properties_analytics_daily (property_id, "day", saves)
+VALUES
+    ($1, CURRENT_DATE, 1)
+ON CONFLICT (property_id, "day") DO UPDATE
+SET
+    saves = properties_analytics_daily.saves + 1;
+
+
+-- name: IncrementPropertyAnalyticsViews :exec
+INSERT INTO
+    properties_analytics_daily (property_id, "day", views)
+VALUES
+    ($1, CURRENT_DATE, 1)
+ON CONFLICT (property_id, "day") DO UPDATE
+SET
+    views = properties_analytics_daily.views + 1;
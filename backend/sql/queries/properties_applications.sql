@@ -0,0 +1,115 @@
+-- name: CreatePropertyApplication :exec
+INSERT INTO
+    properties_applications (
+        application_id,
+        property_id,
+        applicant_user_id,
+        message,
+        move_in_date
+    )
+VALUES
+    ($1, $2, $3, $4, $5);
+
+
+-- name: CreatePropertyApplicationCoApplicant :exec
+INSERT INTO
+    properties_applications_co_applicants (application_id, user_id)
+VALUES
+    ($1, $2);
+
+
+-- name: CreatePropertyApplicationDocument :exec
+INSERT INTO
+    properties_applications_documents (
+        application_id,
+        order_num,
+        file_name,
+        mime_type,
+        "size",
+        content_hash
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6);
+
+
+-- name: DeletePropertyApplication :exec
+DELETE FROM properties_applications
+WHERE
+    application_id = $1;
+
+
+-- name: GetPropertyApplication :one
+SELECT
+    *
+FROM
+    properties_applications
+WHERE
+    application_id = $1;
+
+
+-- name: GetPropertyApplicationCoApplicants :many
+SELECT
+    user_id
+FROM
+    properties_applications_co_applicants
+WHERE
+    application_id = $1;
+
+
+-- name: GetPropertyApplicationDocuments :many
+SELECT
+    properties_applications_documents.order_num,
+    properties_applications_documents.file_name,
+    properties_applications_documents.mime_type,
+    properties_applications_documents."size",
+    file_blobs."data"
+FROM
+    properties_applications_documents
+    JOIN file_blobs ON properties_applications_documents.content_hash = file_blobs.content_hash
+WHERE
+    properties_applications_documents.application_id = $1
+ORDER BY
+    properties_applications_documents.order_num;
+
+
+-- name: GetPropertyApplications :many
+SELECT
+    *
+FROM
+    properties_applications
+WHERE
+    property_id = $1
+ORDER BY
+    created_at;
+
+
+-- name: GetUserPropertyApplications :many
+-- Applications the user submitted or is a co-applicant of, most recent first
+SELECT
+    *
+FROM
+    properties_applications
+WHERE
+    applicant_user_id = $1
+    OR application_id IN (
+        SELECT
+            application_id
+        FROM
+            properties_applications_co_applicants
+        WHERE
+            properties_applications_co_applicants.user_id = $1
+    )
+ORDER BY
+    created_at DESC;
+
+
+-- name: UpdatePropertyApplicationStatus :execrows
+-- Only updates the status if it is still the expected current status
+UPDATE properties_applications
+SET
+    status = $2,
+    decision_note = $3,
+    status_updated_at = CURRENT_TIMESTAMP
+WHERE
+    application_id = $1
+    AND status = $4;
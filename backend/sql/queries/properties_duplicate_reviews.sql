@@ -0,0 +1,44 @@
+-- name: CreatePropertyDuplicateReview :exec
+INSERT INTO
+    properties_duplicate_reviews (
+        property_id,
+        duplicate_of_property_id,
+        similarity
+    )
+VALUES
+    ($1, $2, $3)
+ON CONFLICT (property_id, duplicate_of_property_id) DO NOTHING;
+
+
+-- name: GetPropertyDuplicateReview :one
+SELECT
+    *
+FROM
+    properties_duplicate_reviews
+WHERE
+    id = $1;
+
+
+-- name: GetPropertyDuplicateReviews :many
+SELECT
+    *
+FROM
+    properties_duplicate_reviews
+WHERE
+    status = $1
+ORDER BY
+    created_at
+LIMIT
+    $2
+OFFSET
+    $3;
+
+
+-- name: UpdatePropertyDuplicateReviewStatus :exec
+UPDATE properties_duplicate_reviews
+SET
+    status = $2,
+    reviewed_by_user_id = $3,
+    reviewed_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1;
@@ -0,0 +1,23 @@
+-- name: CreatePropertyHistory :exec
+INSERT INTO
+    properties_history (
+        property_id,
+        changed_by_user_id,
+        field,
+        old_value,
+        new_value
+    )
+VALUES
+    ($1, $2, $3, $4, $5);
+
+
+-- name: GetPropertyHistory :many
+SELECT
+    *
+FROM
+    properties_history
+WHERE
+    property_id = $1
+ORDER BY
+    created_at DESC,
+    id DESC;
@@ -0,0 +1,122 @@
+-- name: CreatePropertyRoom :exec
+INSERT INTO
+    properties_rooms (
+        room_id,
+        property_id,
+        "name",
+        "description",
+        square_feet,
+        cost_dollars,
+        cost_cents,
+        is_available,
+        available_from,
+        occupant_user_id
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+
+-- name: CreatePropertyRoomImage :exec
+INSERT INTO
+    properties_rooms_images (
+        room_id,
+        order_num,
+        file_name,
+        mime_type,
+        "size",
+        content_hash
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6);
+
+
+-- name: GetPropertyRoom :one
+SELECT
+    *
+FROM
+    properties_rooms
+WHERE
+    room_id = $1;
+
+
+-- name: GetPropertyRooms :many
+SELECT
+    *
+FROM
+    properties_rooms
+WHERE
+    property_id = $1
+ORDER BY
+    id;
+
+
+-- name: CountPropertyRooms :one
+SELECT
+    count(*)
+FROM
+    properties_rooms
+WHERE
+    property_id = $1;
+
+
+-- name: GetPropertyRoomImages :many
+SELECT
+    properties_rooms_images.order_num,
+    properties_rooms_images.file_name,
+    properties_rooms_images.mime_type,
+    properties_rooms_images."size",
+    file_blobs."data"
+FROM
+    properties_rooms_images
+    JOIN file_blobs ON properties_rooms_images.content_hash = file_blobs.content_hash
+WHERE
+    properties_rooms_images.room_id = $1
+ORDER BY
+    properties_rooms_images.order_num;
+
+
+-- name: UpdatePropertyRoom :exec
+UPDATE properties_rooms
+SET
+    "name" = $2,
+    "description" = $3,
+    square_feet = $4,
+    cost_dollars = $5,
+    cost_cents = $6,
+    is_available = $7,
+    available_from = $8,
+    occupant_user_id = $9,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    room_id = $1;
+
+
+-- name: UpdatePropertyRoomsAvailable :exec
+-- Keep the property's rooms_available in sync with its rooms so that
+-- room level availability is searchable.
+UPDATE properties
+SET
+    rooms_available = (
+        SELECT
+            count(*)
+        FROM
+            properties_rooms
+        WHERE
+            properties_rooms.property_id = $1
+            AND properties_rooms.is_available
+    ),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    properties.property_id = $1;
+
+
+-- name: DeletePropertyRoom :exec
+DELETE FROM properties_rooms
+WHERE
+    room_id = $1;
+
+
+-- name: DeletePropertyRoomImages :exec
+DELETE FROM properties_rooms_images
+WHERE
+    room_id = $1;
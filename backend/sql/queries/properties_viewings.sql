@@ -0,0 +1,173 @@
+-- name: CreatePropertyViewingBooking :execrows
+-- Books the slot only if it has not started yet and is not full. The slot must be
+-- locked by LockPropertyViewingSlot in the same transaction beforehand, concurrent
+-- bookings would otherwise count the same bookings and overbook the slot.
+INSERT INTO
+    properties_viewing_bookings (slot_id, user_id)
+SELECT
+    slot_id,
+    $2
+FROM
+    properties_viewing_slots
+WHERE
+    properties_viewing_slots.slot_id = $1
+    AND properties_viewing_slots.start_time > CURRENT_TIMESTAMP
+    AND properties_viewing_slots.capacity > (
+        SELECT
+            count(*)
+        FROM
+            properties_viewing_bookings
+        WHERE
+            properties_viewing_bookings.slot_id = $1
+    )
+ON CONFLICT (slot_id, user_id) DO NOTHING;
+
+
+-- name: CreatePropertyViewingSlot :exec
+INSERT INTO
+    properties_viewing_slots (
+        slot_id,
+        property_id,
+        start_time,
+        end_time,
+        capacity,
+        note
+    )
+VALUES
+    ($1, $2, $3, $4, $5, $6);
+
+
+-- name: DeletePropertyViewingBooking :exec
+DELETE FROM properties_viewing_bookings
+WHERE
+    slot_id = $1
+    AND user_id = $2;
+
+
+-- name: DeletePropertyViewingSlot :exec
+DELETE FROM properties_viewing_slots
+WHERE
+    slot_id = $1;
+
+
+-- name: GetPropertyViewingSlot :one
+SELECT
+    properties_viewing_slots.slot_id,
+    properties_viewing_slots.property_id,
+    properties_viewing_slots.start_time,
+    properties_viewing_slots.end_time,
+    properties_viewing_slots.capacity,
+    properties_viewing_slots.note,
+    (
+        SELECT
+            count(*)
+        FROM
+            properties_viewing_bookings
+        WHERE
+            properties_viewing_bookings.slot_id = properties_viewing_slots.slot_id
+    ) AS booked_count
+FROM
+    properties_viewing_slots
+WHERE
+    properties_viewing_slots.slot_id = $1;
+
+
+-- name: GetPropertyViewingSlotBookers :many
+SELECT
+    user_id
+FROM
+    properties_viewing_bookings
+WHERE
+    slot_id = $1
+ORDER BY
+    created_at;
+
+
+-- name: GetPropertyViewingSlots :many
+-- Upcoming viewing slots of a property
+SELECT
+    properties_viewing_slots.slot_id,
+    properties_viewing_slots.property_id,
+    properties_viewing_slots.start_time,
+    properties_viewing_slots.end_time,
+    properties_viewing_slots.capacity,
+    properties_viewing_slots.note,
+    (
+        SELECT
+            count(*)
+        FROM
+            properties_viewing_bookings
+        WHERE
+            properties_viewing_bookings.slot_id = properties_viewing_slots.slot_id
+    ) AS booked_count
+FROM
+    properties_viewing_slots
+WHERE
+    properties_viewing_slots.property_id = $1
+    AND properties_viewing_slots.end_time > CURRENT_TIMESTAMP
+ORDER BY
+    properties_viewing_slots.start_time;
+
+
+-- name: GetUserUpcomingViewingSlots :many
+-- Upcoming viewing slots the user has booked, or that were booked for a property the user lists
+SELECT
+    properties_viewing_slots.slot_id,
+    properties_viewing_slots.property_id,
+    properties_viewing_slots.start_time,
+    properties_viewing_slots.end_time,
+    properties_viewing_slots.capacity,
+    properties_viewing_slots.note,
+    (
+        SELECT
+            count(*)
+        FROM
+            properties_viewing_bookings
+        WHERE
+            properties_viewing_bookings.slot_id = properties_viewing_slots.slot_id
+    ) AS booked_count
+FROM
+    properties_viewing_slots
+WHERE
+    properties_viewing_slots.end_time > CURRENT_TIMESTAMP
+    AND (
+        properties_viewing_slots.slot_id IN (
+            SELECT
+                slot_id
+            FROM
+                properties_viewing_bookings
+            WHERE
+                properties_viewing_bookings.user_id = $1
+        )
+        OR (
+            properties_viewing_slots.property_id IN (
+                SELECT
+                    property_id
+                FROM
+                    properties
+                WHERE
+                    properties.lister_user_id = $1
+            )
+            AND EXISTS (
+                SELECT
+                    1
+                FROM
+                    properties_viewing_bookings
+                WHERE
+                    properties_viewing_bookings.slot_id = properties_viewing_slots.slot_id
+            )
+        )
+    )
+ORDER BY
+    properties_viewing_slots.start_time;
+
+
+-- name: LockPropertyViewingSlot :exec
+-- Locks the slot until the end of the transaction
+SELECT
+    slot_id
+FROM
+    properties_viewing_slots
+WHERE
+    slot_id = $1
+FOR UPDATE;
@@ -0,0 +1,18 @@
+-- name: GetUserIDByCalendarToken :one
+SELECT
+    user_id
+FROM
+    users_calendar_tokens
+WHERE
+    token_hash = $1;
+
+
+-- name: UpsertUserCalendarToken :exec
+INSERT INTO
+    users_calendar_tokens (user_id, token_hash)
+VALUES
+    ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    token_hash = EXCLUDED.token_hash,
+    created_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,190 @@
+-- +goose Up
+-- Content-addressed storage for uploaded files. Each unique file content is stored
+-- once, keyed by the hex encoded sha256 hash of its data, and is shared by every
+-- row that references it. ref_count is maintained by the triggers below so that
+-- cascading deletes (e.g. deleting a user) also release their blobs.
+CREATE TABLE file_blobs (
+    id serial PRIMARY KEY,
+    content_hash text NOT NULL UNIQUE,
+    "size" bigint NOT NULL,
+    "data" bytea NOT NULL,
+    ref_count integer NOT NULL DEFAULT 0,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+
+-- Move existing image data into the blob store
+INSERT INTO
+    file_blobs (content_hash, "size", "data")
+SELECT DISTINCT
+    ON (content_hash) content_hash,
+    "size",
+    "data"
+FROM
+    (
+        SELECT
+            encode(sha256("data"), 'hex') AS content_hash,
+            "size",
+            "data"
+        FROM
+            properties_images
+        UNION ALL
+        SELECT
+            encode(sha256("data"), 'hex') AS content_hash,
+            "size",
+            "data"
+        FROM
+            communities_images
+    ) AS existing_images;
+
+
+ALTER TABLE properties_images
+ADD COLUMN content_hash text;
+
+
+UPDATE properties_images
+SET
+    content_hash = encode(sha256("data"), 'hex');
+
+
+ALTER TABLE properties_images
+ALTER COLUMN content_hash
+SET NOT NULL,
+DROP COLUMN "data",
+ADD CONSTRAINT fk_content_hash_properties_images FOREIGN KEY (content_hash) REFERENCES file_blobs (content_hash);
+
+
+ALTER TABLE communities_images
+ADD COLUMN content_hash text;
+
+
+UPDATE communities_images
+SET
+    content_hash = encode(sha256("data"), 'hex');
+
+
+ALTER TABLE communities_images
+ALTER COLUMN content_hash
+SET NOT NULL,
+DROP COLUMN "data",
+ADD CONSTRAINT fk_content_hash_communities_images FOREIGN KEY (content_hash) REFERENCES file_blobs (content_hash);
+
+
+UPDATE file_blobs
+SET
+    ref_count = (
+        SELECT
+            count(*)
+        FROM
+            properties_images
+        WHERE
+            properties_images.content_hash = file_blobs.content_hash
+    ) + (
+        SELECT
+            count(*)
+        FROM
+            communities_images
+        WHERE
+            communities_images.content_hash = file_blobs.content_hash
+    );
+
+
+-- +goose StatementBegin
+CREATE FUNCTION file_blobs_add_ref () RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE file_blobs SET ref_count = ref_count + 1 WHERE content_hash = NEW.content_hash;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+
+-- +goose StatementBegin
+CREATE FUNCTION file_blobs_remove_ref () RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE file_blobs SET ref_count = ref_count - 1 WHERE content_hash = OLD.content_hash;
+    DELETE FROM file_blobs WHERE content_hash = OLD.content_hash AND ref_count <= 0;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+
+CREATE TRIGGER trg_properties_images_add_ref
+AFTER INSERT ON properties_images FOR EACH ROW
+EXECUTE FUNCTION file_blobs_add_ref ();
+
+
+CREATE TRIGGER trg_properties_images_remove_ref
+AFTER DELETE ON properties_images FOR EACH ROW
+EXECUTE FUNCTION file_blobs_remove_ref ();
+
+
+CREATE TRIGGER trg_communities_images_add_ref
+AFTER INSERT ON communities_images FOR EACH ROW
+EXECUTE FUNCTION file_blobs_add_ref ();
+
+
+CREATE TRIGGER trg_communities_images_remove_ref
+AFTER DELETE ON communities_images FOR EACH ROW
+EXECUTE FUNCTION file_blobs_remove_ref ();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_communities_images_remove_ref ON communities_images;
+
+
+DROP TRIGGER IF EXISTS trg_communities_images_add_ref ON communities_images;
+
+
+DROP TRIGGER IF EXISTS trg_properties_images_remove_ref ON properties_images;
+
+
+DROP TRIGGER IF EXISTS trg_properties_images_add_ref ON properties_images;
+
+
+DROP FUNCTION IF EXISTS file_blobs_remove_ref;
+
+
+DROP FUNCTION IF EXISTS file_blobs_add_ref;
+
+
+ALTER TABLE communities_images
+ADD COLUMN "data" bytea;
+
+
+UPDATE communities_images
+SET
+    "data" = file_blobs."data"
+FROM
+    file_blobs
+WHERE
+    file_blobs.content_hash = communities_images.content_hash;
+
+
+ALTER TABLE communities_images
+ALTER COLUMN "data"
+SET NOT NULL,
+DROP COLUMN content_hash;
+
+
+ALTER TABLE properties_images
+ADD COLUMN "data" bytea;
+
+
+UPDATE properties_images
+SET
+    "data" = file_blobs."data"
+FROM
+    file_blobs
+WHERE
+    file_blobs.content_hash = properties_images.content_hash;
+
+
+ALTER TABLE properties_images
+ALTER COLUMN "data"
+SET NOT NULL,
+DROP COLUMN content_hash;
+
+
+DROP TABLE IF EXISTS file_blobs;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Listing lifecycle of a property. Existing properties were publicly visible so
+-- they start out as published.
+ALTER TABLE properties
+ADD COLUMN status text NOT NULL DEFAULT 'published',
+ADD COLUMN status_updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ADD CONSTRAINT chk_status_properties CHECK (
+    status IN ('draft', 'published', 'paused', 'rented', 'archived')
+);
+
+
+CREATE INDEX idx_status_properties ON properties (status);
+
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_status_properties;
+
+
+ALTER TABLE properties
+DROP CONSTRAINT IF EXISTS chk_status_properties,
+DROP COLUMN IF EXISTS status_updated_at,
+DROP COLUMN IF EXISTS status;
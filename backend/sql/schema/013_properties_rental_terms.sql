@@ -0,0 +1,28 @@
+-- +goose Up
+-- Structured rental terms of a property. cost_dollars/cost_cents are interpreted
+-- as a monthly rent or a sale price depending on price_type.
+ALTER TABLE properties
+ADD COLUMN price_type text NOT NULL DEFAULT 'monthly',
+ADD COLUMN deposit_dollars bigint NOT NULL DEFAULT 0,
+ADD COLUMN deposit_cents smallint NOT NULL DEFAULT 0,
+ADD COLUMN utilities_included boolean NOT NULL DEFAULT FALSE,
+ADD COLUMN lease_length_months integer[] NOT NULL DEFAULT '{}',
+ADD COLUMN available_from date,
+ADD COLUMN rooms_available smallint NOT NULL DEFAULT 0,
+ADD COLUMN pets_policy text NOT NULL DEFAULT '',
+ADD COLUMN smoking_policy text NOT NULL DEFAULT '',
+ADD CONSTRAINT chk_price_type_properties CHECK (price_type IN ('monthly', 'sale'));
+
+
+-- +goose Down
+ALTER TABLE properties
+DROP CONSTRAINT IF EXISTS chk_price_type_properties,
+DROP COLUMN IF EXISTS smoking_policy,
+DROP COLUMN IF EXISTS pets_policy,
+DROP COLUMN IF EXISTS rooms_available,
+DROP COLUMN IF EXISTS available_from,
+DROP COLUMN IF EXISTS lease_length_months,
+DROP COLUMN IF EXISTS utilities_included,
+DROP COLUMN IF EXISTS deposit_cents,
+DROP COLUMN IF EXISTS deposit_dollars,
+DROP COLUMN IF EXISTS price_type;
@@ -0,0 +1,59 @@
+-- +goose Up
+-- Rooms that are rented out individually within a property
+CREATE TABLE properties_rooms (
+    id serial PRIMARY KEY,
+    room_id text NOT NULL UNIQUE,
+    property_id text NOT NULL,
+    "name" text NOT NULL,
+    "description" text,
+    square_feet integer NOT NULL,
+    cost_dollars bigint NOT NULL,
+    cost_cents smallint NOT NULL,
+    is_available boolean NOT NULL DEFAULT TRUE,
+    available_from date,
+    occupant_user_id text,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_property_id_properties_rooms FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT fk_occupant_user_id_properties_rooms FOREIGN KEY (occupant_user_id) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+
+CREATE INDEX idx_property_id_properties_rooms ON properties_rooms (property_id);
+
+
+CREATE TABLE properties_rooms_images (
+    id serial PRIMARY KEY,
+    room_id text NOT NULL,
+    order_num smallint NOT NULL,
+    file_name text NOT NULL,
+    mime_type text NOT NULL,
+    "size" bigint NOT NULL,
+    content_hash text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_room_id_properties_rooms_images FOREIGN KEY (room_id) REFERENCES properties_rooms (room_id) ON DELETE CASCADE,
+    CONSTRAINT fk_content_hash_properties_rooms_images FOREIGN KEY (content_hash) REFERENCES file_blobs (content_hash)
+);
+
+
+CREATE TRIGGER trg_properties_rooms_images_add_ref
+AFTER INSERT ON properties_rooms_images FOR EACH ROW
+EXECUTE FUNCTION file_blobs_add_ref ();
+
+
+CREATE TRIGGER trg_properties_rooms_images_remove_ref
+AFTER DELETE ON properties_rooms_images FOR EACH ROW
+EXECUTE FUNCTION file_blobs_remove_ref ();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_properties_rooms_images_remove_ref ON properties_rooms_images;
+
+
+DROP TRIGGER IF EXISTS trg_properties_rooms_images_add_ref ON properties_rooms_images;
+
+
+DROP TABLE IF EXISTS properties_rooms_images;
+
+
+DROP TABLE IF EXISTS properties_rooms;
@@ -0,0 +1,48 @@
+-- +goose Up
+-- Admin managed catalog of amenities that properties can be tagged with
+CREATE TABLE amenities (
+    id serial PRIMARY KEY,
+    "name" text NOT NULL UNIQUE,
+    category text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+
+INSERT INTO
+    amenities ("name", category)
+VALUES
+    ('in_unit_laundry', 'appliances'),
+    ('shared_laundry', 'appliances'),
+    ('dishwasher', 'appliances'),
+    ('air_conditioning', 'appliances'),
+    ('heating', 'appliances'),
+    ('parking', 'building'),
+    ('bike_storage', 'building'),
+    ('gym', 'building'),
+    ('elevator', 'building'),
+    ('furnished', 'building'),
+    ('wifi', 'services'),
+    ('wheelchair_accessible', 'accessibility'),
+    ('step_free_entrance', 'accessibility'),
+    ('garden', 'outdoor'),
+    ('balcony', 'outdoor');
+
+
+CREATE TABLE properties_amenities (
+    id serial PRIMARY KEY,
+    property_id text NOT NULL,
+    amenity_name text NOT NULL,
+    CONSTRAINT fk_property_id_properties_amenities FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT fk_amenity_name_properties_amenities FOREIGN KEY (amenity_name) REFERENCES amenities ("name") ON DELETE CASCADE,
+    CONSTRAINT unique_property_id_amenity_name_properties_amenities UNIQUE (property_id, amenity_name)
+);
+
+
+CREATE INDEX idx_amenity_name_properties_amenities ON properties_amenities (amenity_name);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS properties_amenities;
+
+
+DROP TABLE IF EXISTS amenities;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Audit log of changes made to a property's key fields, one row per changed field
+CREATE TABLE properties_history (
+    id serial PRIMARY KEY,
+    property_id text NOT NULL,
+    changed_by_user_id text,
+    field text NOT NULL,
+    old_value text NOT NULL,
+    new_value text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_property_id_properties_history FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT fk_changed_by_user_id_properties_history FOREIGN KEY (changed_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+
+CREATE INDEX idx_property_id_properties_history ON properties_history (property_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS properties_history;
@@ -0,0 +1,63 @@
+-- +goose Up
+-- Canonical form of a property's address (see utils.NormalizeAddress) used for
+-- duplicate detection. Existing rows get a simple lowercased approximation that
+-- is replaced by the full normalization by the address normalization job.
+ALTER TABLE properties
+ADD COLUMN normalized_address text NOT NULL DEFAULT '';
+
+
+UPDATE properties
+SET
+    normalized_address = trim(
+        regexp_replace(
+            lower(
+                concat_ws(
+                    ' ',
+                    address_1,
+                    address_2,
+                    city,
+                    "state",
+                    zipcode,
+                    country
+                )
+            ),
+            '[^a-z0-9]+',
+            ' ',
+            'g'
+        )
+    );
+
+
+CREATE INDEX idx_normalized_address_trgm_properties ON properties USING gin (normalized_address gin_trgm_ops);
+
+
+-- Admin review queue of properties suspected to be duplicates of another listing
+CREATE TABLE properties_duplicate_reviews (
+    id serial PRIMARY KEY,
+    property_id text NOT NULL,
+    duplicate_of_property_id text NOT NULL,
+    similarity real NOT NULL,
+    status text NOT NULL DEFAULT 'pending',
+    reviewed_by_user_id text,
+    reviewed_at timestamp,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_property_id_properties_duplicate_reviews FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT fk_duplicate_of_property_id_properties_duplicate_reviews FOREIGN KEY (duplicate_of_property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT fk_reviewed_by_user_id_properties_duplicate_reviews FOREIGN KEY (reviewed_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT unique_property_id_duplicate_of_property_id_properties_duplicate_reviews UNIQUE (property_id, duplicate_of_property_id),
+    CONSTRAINT chk_status_properties_duplicate_reviews CHECK (status IN ('pending', 'confirmed', 'dismissed'))
+);
+
+
+CREATE INDEX idx_status_properties_duplicate_reviews ON properties_duplicate_reviews (status);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS properties_duplicate_reviews;
+
+
+DROP INDEX IF EXISTS idx_normalized_address_trgm_properties;
+
+
+ALTER TABLE properties
+DROP COLUMN IF EXISTS normalized_address;
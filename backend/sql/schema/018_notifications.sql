@@ -0,0 +1,19 @@
+-- +goose Up
+-- In-app notifications for a user, e.g. confirmations of booked viewings
+CREATE TABLE notifications (
+    id serial PRIMARY KEY,
+    user_id text NOT NULL,
+    "type" text NOT NULL,
+    message text NOT NULL,
+    link text NOT NULL DEFAULT '',
+    is_read boolean NOT NULL DEFAULT FALSE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_notifications FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+
+CREATE INDEX idx_user_id_created_at_notifications ON notifications (user_id, created_at);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
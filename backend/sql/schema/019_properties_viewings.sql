@@ -0,0 +1,52 @@
+-- +goose Up
+-- Time slots a lister offers for viewing a property, each can be booked by up to capacity users
+CREATE TABLE properties_viewing_slots (
+    id serial PRIMARY KEY,
+    slot_id text NOT NULL UNIQUE,
+    property_id text NOT NULL,
+    start_time timestamp NOT NULL,
+    end_time timestamp NOT NULL,
+    capacity smallint NOT NULL DEFAULT 1,
+    note text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_property_id_properties_viewing_slots FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT chk_time_properties_viewing_slots CHECK (end_time > start_time),
+    CONSTRAINT chk_capacity_properties_viewing_slots CHECK (capacity > 0)
+);
+
+
+CREATE INDEX idx_property_id_start_time_properties_viewing_slots ON properties_viewing_slots (property_id, start_time);
+
+
+CREATE TABLE properties_viewing_bookings (
+    id serial PRIMARY KEY,
+    slot_id text NOT NULL,
+    user_id text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_slot_id_properties_viewing_bookings FOREIGN KEY (slot_id) REFERENCES properties_viewing_slots (slot_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_properties_viewing_bookings FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT unique_slot_id_user_id_properties_viewing_bookings UNIQUE (slot_id, user_id)
+);
+
+
+CREATE INDEX idx_user_id_properties_viewing_bookings ON properties_viewing_bookings (user_id);
+
+
+-- Secret tokens for subscribing to a user's calendar feed without logging in,
+-- only the sha256 hash of the token is stored
+CREATE TABLE users_calendar_tokens (
+    user_id text PRIMARY KEY,
+    token_hash text NOT NULL UNIQUE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_users_calendar_tokens FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS users_calendar_tokens;
+
+
+DROP TABLE IF EXISTS properties_viewing_bookings;
+
+
+DROP TABLE IF EXISTS properties_viewing_slots;
@@ -0,0 +1,76 @@
+-- +goose Up
+-- Rental applications of users to a property. The applicant may apply together with
+-- co-applicants, e.g. a group of friends looking to rent a place together.
+CREATE TABLE properties_applications (
+    id serial PRIMARY KEY,
+    application_id text NOT NULL UNIQUE,
+    property_id text NOT NULL,
+    applicant_user_id text NOT NULL,
+    message text NOT NULL DEFAULT '',
+    move_in_date date,
+    status text NOT NULL DEFAULT 'submitted',
+    decision_note text NOT NULL DEFAULT '',
+    status_updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_property_id_properties_applications FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT fk_applicant_user_id_properties_applications FOREIGN KEY (applicant_user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT unique_property_id_applicant_user_id_properties_applications UNIQUE (property_id, applicant_user_id),
+    CONSTRAINT chk_status_properties_applications CHECK (status IN ('submitted', 'under_review', 'accepted', 'rejected'))
+);
+
+
+CREATE INDEX idx_applicant_user_id_properties_applications ON properties_applications (applicant_user_id);
+
+
+CREATE TABLE properties_applications_co_applicants (
+    application_id text NOT NULL,
+    user_id text NOT NULL,
+    PRIMARY KEY (application_id, user_id),
+    CONSTRAINT fk_application_id_properties_applications_co_applicants FOREIGN KEY (application_id) REFERENCES properties_applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_properties_applications_co_applicants FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+
+CREATE INDEX idx_user_id_properties_applications_co_applicants ON properties_applications_co_applicants (user_id);
+
+
+-- Supporting documents of an application (e.g. proof of income), file names and data are
+-- encrypted like other personal user files before being stored in file_blobs
+CREATE TABLE properties_applications_documents (
+    id serial PRIMARY KEY,
+    application_id text NOT NULL,
+    order_num smallint NOT NULL,
+    file_name text NOT NULL,
+    mime_type text NOT NULL,
+    "size" bigint NOT NULL,
+    content_hash text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_application_id_properties_applications_documents FOREIGN KEY (application_id) REFERENCES properties_applications (application_id) ON DELETE CASCADE,
+    CONSTRAINT fk_content_hash_properties_applications_documents FOREIGN KEY (content_hash) REFERENCES file_blobs (content_hash)
+);
+
+
+CREATE TRIGGER trg_properties_applications_documents_add_ref
+AFTER INSERT ON properties_applications_documents FOR EACH ROW
+EXECUTE FUNCTION file_blobs_add_ref ();
+
+
+CREATE TRIGGER trg_properties_applications_documents_remove_ref
+AFTER DELETE ON properties_applications_documents FOR EACH ROW
+EXECUTE FUNCTION file_blobs_remove_ref ();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_properties_applications_documents_remove_ref ON properties_applications_documents;
+
+
+DROP TRIGGER IF EXISTS trg_properties_applications_documents_add_ref ON properties_applications_documents;
+
+
+DROP TABLE IF EXISTS properties_applications_documents;
+
+
+DROP TABLE IF EXISTS properties_applications_co_applicants;
+
+
+DROP TABLE IF EXISTS properties_applications;
@@ -0,0 +1,33 @@
+-- +goose Up
+-- Daily view and save counters of each property for the lister's analytics
+CREATE TABLE properties_analytics_daily (
+    property_id text NOT NULL,
+    "day" date NOT NULL,
+    views integer NOT NULL DEFAULT 0,
+    saves integer NOT NULL DEFAULT 0,
+    PRIMARY KEY (property_id, "day"),
+    CONSTRAINT fk_property_id_properties_analytics_daily FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE
+);
+
+
+-- Who already viewed or saved a property on a day, so that each viewer is counted at most
+-- once per day. Viewers are only stored as a hash of their user id or ip address and user agent.
+CREATE TABLE properties_analytics_events (
+    property_id text NOT NULL,
+    "day" date NOT NULL,
+    event_type text NOT NULL,
+    viewer_hash text NOT NULL,
+    PRIMARY KEY (property_id, "day", event_type, viewer_hash),
+    CONSTRAINT fk_property_id_properties_analytics_events FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT chk_event_type_properties_analytics_events CHECK (event_type IN ('view', 'save'))
+);
+
+
+CREATE INDEX idx_day_properties_analytics_events ON properties_analytics_events ("day");
+
+
+-- +goose Down
+DROP TABLE IF EXISTS properties_analytics_events;
+
+
+DROP TABLE IF EXISTS properties_analytics_daily;
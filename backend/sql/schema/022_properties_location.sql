@@ -0,0 +1,22 @@
+-- +goose Up
+-- Optional coordinates of a property, used to rank similar properties by distance
+ALTER TABLE properties
+ADD COLUMN latitude double precision,
+ADD COLUMN longitude double precision,
+ADD CONSTRAINT chk_coordinates_properties CHECK (
+    (
+        latitude IS NULL
+        AND longitude IS NULL
+    )
+    OR (
+        latitude BETWEEN -90 AND 90
+        AND longitude BETWEEN -180 AND 180
+    )
+);
+
+
+-- +goose Down
+ALTER TABLE properties
+DROP CONSTRAINT IF EXISTS chk_coordinates_properties,
+DROP COLUMN IF EXISTS longitude,
+DROP COLUMN IF EXISTS latitude;
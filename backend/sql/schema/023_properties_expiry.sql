@@ -0,0 +1,42 @@
+-- +goose Up
+-- Published listings expire unless their lister renews them. Existing published
+-- listings get the full listing duration from now.
+ALTER TABLE properties
+ADD COLUMN expires_at timestamp,
+ADD COLUMN expiry_reminder_sent_at timestamp,
+DROP CONSTRAINT IF EXISTS chk_status_properties,
+ADD CONSTRAINT chk_status_properties CHECK (
+    status IN ('draft', 'published', 'paused', 'rented', 'archived', 'expired')
+);
+
+
+UPDATE properties
+SET
+    expires_at = CURRENT_TIMESTAMP + INTERVAL '60 days'
+WHERE
+    status = 'published';
+
+
+CREATE INDEX idx_expires_at_properties ON properties (expires_at)
+WHERE
+    status = 'published';
+
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_expires_at_properties;
+
+
+UPDATE properties
+SET
+    status = 'paused'
+WHERE
+    status = 'expired';
+
+
+ALTER TABLE properties
+DROP CONSTRAINT IF EXISTS chk_status_properties,
+ADD CONSTRAINT chk_status_properties CHECK (
+    status IN ('draft', 'published', 'paused', 'rented', 'archived')
+),
+DROP COLUMN IF EXISTS expiry_reminder_sent_at,
+DROP COLUMN IF EXISTS expires_at;
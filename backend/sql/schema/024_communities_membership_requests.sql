@@ -0,0 +1,28 @@
+-- +goose Up
+-- Pending memberships of users to communities. A user requests to join and the community
+-- admin decides, or the admin invites a user who decides before the invite expires. The
+-- row is removed once decided.
+CREATE TABLE communities_membership_requests (
+    id serial PRIMARY KEY,
+    request_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    user_id text NOT NULL,
+    kind text NOT NULL,
+    invited_by_user_id text,
+    expires_at timestamp,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_membership_requests FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_communities_membership_requests FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_invited_by_user_id_communities_membership_requests FOREIGN KEY (invited_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_kind_communities_membership_requests CHECK (kind IN ('request', 'invite'))
+);
+
+
+CREATE INDEX idx_community_id_user_id_communities_membership_requests ON communities_membership_requests (community_id, user_id);
+
+
+CREATE INDEX idx_user_id_communities_membership_requests ON communities_membership_requests (user_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS communities_membership_requests;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Role of each member of a community. The community admin becomes its owner, who can
+-- appoint moderators to help manage the community.
+DELETE FROM communities_users AS duplicate USING communities_users AS original
+WHERE
+    duplicate.id > original.id
+    AND duplicate.community_id = original.community_id
+    AND duplicate.user_id = original.user_id;
+
+
+ALTER TABLE communities_users
+ADD COLUMN role text NOT NULL DEFAULT 'member',
+ADD CONSTRAINT chk_role_communities_users CHECK (role IN ('owner', 'moderator', 'member')),
+ADD CONSTRAINT unique_community_id_user_id_communities_users UNIQUE (community_id, user_id);
+
+
+UPDATE communities_users
+SET
+    role = 'owner'
+FROM
+    communities
+WHERE
+    communities_users.community_id = communities.community_id
+    AND communities_users.user_id = communities.admin_user_id;
+
+
+-- +goose Down
+ALTER TABLE communities_users
+DROP CONSTRAINT IF EXISTS unique_community_id_user_id_communities_users,
+DROP CONSTRAINT IF EXISTS chk_role_communities_users,
+DROP COLUMN IF EXISTS role;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Discussion board of a community. A thread is a post without a thread id and its
+-- replies are posts with the post id of the thread. Threads are listed pinned first,
+-- then by their latest activity.
+CREATE TABLE communities_posts (
+    id serial PRIMARY KEY,
+    post_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    thread_id text,
+    user_id text NOT NULL,
+    title text NOT NULL DEFAULT '',
+    body text NOT NULL,
+    pinned boolean NOT NULL DEFAULT FALSE,
+    last_activity_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_posts FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_thread_id_communities_posts FOREIGN KEY (thread_id) REFERENCES communities_posts (post_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_communities_posts FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE
+);
+
+
+CREATE INDEX idx_community_id_communities_posts ON communities_posts (community_id, pinned, last_activity_at)
+WHERE
+    thread_id IS NULL;
+
+
+CREATE INDEX idx_thread_id_communities_posts ON communities_posts (thread_id, created_at);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS communities_posts;
@@ -0,0 +1,46 @@
+-- +goose Up
+-- Events organized by the members of a community, optionally at one of its properties.
+-- Members RSVP to them and the yes RSVPs are limited to the capacity of the event, if any.
+CREATE TABLE communities_events (
+    id serial PRIMARY KEY,
+    event_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    property_id text,
+    created_by_user_id text,
+    title text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    location text NOT NULL,
+    start_time timestamp NOT NULL,
+    end_time timestamp NOT NULL,
+    capacity smallint,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_events FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_property_id_communities_events FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE SET NULL,
+    CONSTRAINT fk_created_by_user_id_communities_events FOREIGN KEY (created_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_time_communities_events CHECK (end_time > start_time),
+    CONSTRAINT chk_capacity_communities_events CHECK (capacity > 0)
+);
+
+
+CREATE INDEX idx_community_id_start_time_communities_events ON communities_events (community_id, start_time);
+
+
+CREATE TABLE communities_events_rsvps (
+    id serial PRIMARY KEY,
+    event_id text NOT NULL,
+    user_id text NOT NULL,
+    response text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_event_id_communities_events_rsvps FOREIGN KEY (event_id) REFERENCES communities_events (event_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_communities_events_rsvps FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT unique_event_id_user_id_communities_events_rsvps UNIQUE (event_id, user_id),
+    CONSTRAINT chk_response_communities_events_rsvps CHECK (response IN ('yes', 'no', 'maybe'))
+);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS communities_events_rsvps;
+
+
+DROP TABLE IF EXISTS communities_events;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Pending requests of communities to link a property. The property's lister decides and
+-- the row is removed once decided.
+CREATE TABLE communities_property_link_requests (
+    id serial PRIMARY KEY,
+    request_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    property_id text NOT NULL,
+    requested_by_user_id text,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_property_link_requests FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_property_id_communities_property_link_requests FOREIGN KEY (property_id) REFERENCES properties (property_id) ON DELETE CASCADE,
+    CONSTRAINT fk_requested_by_user_id_communities_property_link_requests FOREIGN KEY (requested_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT unique_community_id_property_id_communities_property_link_requests UNIQUE (community_id, property_id)
+);
+
+
+CREATE INDEX idx_property_id_communities_property_link_requests ON communities_property_link_requests (property_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS communities_property_link_requests;
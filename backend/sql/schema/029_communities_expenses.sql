@@ -0,0 +1,47 @@
+-- +goose Up
+-- Shared expenses of the members of a community, e.g. the rent and utilities of a household.
+-- Amounts are in cents and each split is the part of an expense a member owes to its payer.
+-- The expenses and splits of a member who deletes their account are kept without their user
+-- id, dropping them would leave the balances of the other members no longer adding up.
+CREATE TABLE communities_expenses (
+    id serial PRIMARY KEY,
+    expense_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    payer_user_id text,
+    created_by_user_id text,
+    description text NOT NULL,
+    category text NOT NULL,
+    amount_cents integer NOT NULL,
+    split_type text NOT NULL,
+    spent_on date NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_expenses FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_payer_user_id_communities_expenses FOREIGN KEY (payer_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT fk_created_by_user_id_communities_expenses FOREIGN KEY (created_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_amount_cents_communities_expenses CHECK (amount_cents > 0),
+    CONSTRAINT chk_split_type_communities_expenses CHECK (split_type IN ('equal', 'shares', 'exact'))
+);
+
+
+CREATE INDEX idx_community_id_spent_on_communities_expenses ON communities_expenses (community_id, spent_on);
+
+
+CREATE TABLE communities_expenses_splits (
+    id serial PRIMARY KEY,
+    expense_id text NOT NULL,
+    user_id text,
+    shares integer,
+    amount_cents integer NOT NULL,
+    CONSTRAINT fk_expense_id_communities_expenses_splits FOREIGN KEY (expense_id) REFERENCES communities_expenses (expense_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_communities_expenses_splits FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT unique_expense_id_user_id_communities_expenses_splits UNIQUE (expense_id, user_id),
+    CONSTRAINT chk_shares_communities_expenses_splits CHECK (shares > 0),
+    CONSTRAINT chk_amount_cents_communities_expenses_splits CHECK (amount_cents >= 0)
+);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS communities_expenses_splits;
+
+
+DROP TABLE IF EXISTS communities_expenses;
@@ -0,0 +1,74 @@
+-- +goose Up
+-- Polls of a community with single or multiple choice options. A member casts a single ballot per
+-- poll, which records that they voted, and a vote for each option they chose. Votes of anonymous
+-- polls are not linked to their voter. Votes have a random key and no timestamp, so that neither
+-- can be matched with the order or time of the ballots to link a vote back to its voter.
+CREATE TABLE communities_polls (
+    id serial PRIMARY KEY,
+    poll_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    created_by_user_id text,
+    question text NOT NULL,
+    multiple_choice boolean NOT NULL DEFAULT FALSE,
+    anonymous boolean NOT NULL DEFAULT FALSE,
+    results_visibility text NOT NULL,
+    opens_at timestamp NOT NULL,
+    closes_at timestamp NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_polls FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_created_by_user_id_communities_polls FOREIGN KEY (created_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_time_communities_polls CHECK (closes_at > opens_at),
+    CONSTRAINT chk_results_visibility_communities_polls CHECK (results_visibility IN ('after_vote', 'after_close'))
+);
+
+
+CREATE INDEX idx_community_id_communities_polls ON communities_polls (community_id);
+
+
+CREATE TABLE communities_polls_options (
+    id serial PRIMARY KEY,
+    option_id text NOT NULL UNIQUE,
+    poll_id text NOT NULL,
+    position smallint NOT NULL,
+    label text NOT NULL,
+    CONSTRAINT fk_poll_id_communities_polls_options FOREIGN KEY (poll_id) REFERENCES communities_polls (poll_id) ON DELETE CASCADE,
+    CONSTRAINT unique_poll_id_position_communities_polls_options UNIQUE (poll_id, position)
+);
+
+
+CREATE TABLE communities_polls_ballots (
+    id serial PRIMARY KEY,
+    poll_id text NOT NULL,
+    user_id text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_poll_id_communities_polls_ballots FOREIGN KEY (poll_id) REFERENCES communities_polls (poll_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_communities_polls_ballots FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT unique_poll_id_user_id_communities_polls_ballots UNIQUE (poll_id, user_id)
+);
+
+
+CREATE TABLE communities_polls_votes (
+    vote_id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+    poll_id text NOT NULL,
+    option_id text NOT NULL,
+    user_id text,
+    CONSTRAINT fk_poll_id_communities_polls_votes FOREIGN KEY (poll_id) REFERENCES communities_polls (poll_id) ON DELETE CASCADE,
+    CONSTRAINT fk_option_id_communities_polls_votes FOREIGN KEY (option_id) REFERENCES communities_polls_options (option_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_communities_polls_votes FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+
+CREATE INDEX idx_poll_id_communities_polls_votes ON communities_polls_votes (poll_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS communities_polls_votes;
+
+
+DROP TABLE IF EXISTS communities_polls_ballots;
+
+
+DROP TABLE IF EXISTS communities_polls_options;
+
+
+DROP TABLE IF EXISTS communities_polls;
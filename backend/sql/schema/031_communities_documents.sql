@@ -0,0 +1,82 @@
+-- +goose Up
+-- Shared document library of a community, e.g. house rules, leases and meeting minutes.
+-- Documents are kept in folders and every upload of a document is kept as a new version,
+-- the files are stored in file_blobs like the images of a community.
+CREATE TABLE communities_documents_folders (
+    id serial PRIMARY KEY,
+    folder_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    parent_folder_id text,
+    "name" text NOT NULL,
+    created_by_user_id text,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_documents_folders FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_parent_folder_id_communities_documents_folders FOREIGN KEY (parent_folder_id) REFERENCES communities_documents_folders (folder_id) ON DELETE CASCADE,
+    CONSTRAINT fk_created_by_user_id_communities_documents_folders FOREIGN KEY (created_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL
+);
+
+
+CREATE INDEX idx_community_id_communities_documents_folders ON communities_documents_folders (community_id);
+
+
+CREATE TABLE communities_documents (
+    id serial PRIMARY KEY,
+    document_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    folder_id text,
+    title text NOT NULL,
+    visibility text NOT NULL DEFAULT 'members',
+    created_by_user_id text,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_documents FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_folder_id_communities_documents FOREIGN KEY (folder_id) REFERENCES communities_documents_folders (folder_id) ON DELETE CASCADE,
+    CONSTRAINT fk_created_by_user_id_communities_documents FOREIGN KEY (created_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_visibility_communities_documents CHECK (visibility IN ('members', 'moderators', 'public'))
+);
+
+
+CREATE INDEX idx_community_id_communities_documents ON communities_documents (community_id);
+
+
+CREATE TABLE communities_documents_versions (
+    id serial PRIMARY KEY,
+    document_id text NOT NULL,
+    "version" integer NOT NULL,
+    file_name text NOT NULL,
+    mime_type text NOT NULL,
+    "size" bigint NOT NULL,
+    content_hash text NOT NULL,
+    uploaded_by_user_id text,
+    note text NOT NULL DEFAULT '',
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_document_id_communities_documents_versions FOREIGN KEY (document_id) REFERENCES communities_documents (document_id) ON DELETE CASCADE,
+    CONSTRAINT fk_content_hash_communities_documents_versions FOREIGN KEY (content_hash) REFERENCES file_blobs (content_hash),
+    CONSTRAINT fk_uploaded_by_user_id_communities_documents_versions FOREIGN KEY (uploaded_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT unique_document_id_version_communities_documents_versions UNIQUE (document_id, "version")
+);
+
+
+CREATE TRIGGER trg_communities_documents_versions_add_ref
+AFTER INSERT ON communities_documents_versions FOR EACH ROW
+EXECUTE FUNCTION file_blobs_add_ref ();
+
+
+CREATE TRIGGER trg_communities_documents_versions_remove_ref
+AFTER DELETE ON communities_documents_versions FOR EACH ROW
+EXECUTE FUNCTION file_blobs_remove_ref ();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_communities_documents_versions_remove_ref ON communities_documents_versions;
+
+
+DROP TRIGGER IF EXISTS trg_communities_documents_versions_add_ref ON communities_documents_versions;
+
+
+DROP TABLE IF EXISTS communities_documents_versions;
+
+
+DROP TABLE IF EXISTS communities_documents;
+
+
+DROP TABLE IF EXISTS communities_documents_folders;
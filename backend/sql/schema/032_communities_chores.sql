@@ -0,0 +1,69 @@
+-- +goose Up
+-- Recurring chores of a community, e.g. taking out the trash every week. Each occurrence of a chore
+-- is a task assigned to the next member of its rotation, tasks are scheduled ahead by a background job.
+CREATE TABLE communities_chores (
+    id serial PRIMARY KEY,
+    chore_id text NOT NULL UNIQUE,
+    community_id text NOT NULL,
+    title text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    frequency text NOT NULL,
+    interval_count integer NOT NULL DEFAULT 1,
+    starts_on date NOT NULL,
+    created_by_user_id text,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_community_id_communities_chores FOREIGN KEY (community_id) REFERENCES communities (community_id) ON DELETE CASCADE,
+    CONSTRAINT fk_created_by_user_id_communities_chores FOREIGN KEY (created_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT chk_frequency_communities_chores CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+    CONSTRAINT chk_interval_count_communities_chores CHECK (interval_count > 0)
+);
+
+
+CREATE INDEX idx_community_id_communities_chores ON communities_chores (community_id);
+
+
+-- Members taking turns on a chore, in order of position
+CREATE TABLE communities_chores_rotation (
+    id serial PRIMARY KEY,
+    chore_id text NOT NULL,
+    user_id text NOT NULL,
+    position integer NOT NULL,
+    CONSTRAINT fk_chore_id_communities_chores_rotation FOREIGN KEY (chore_id) REFERENCES communities_chores (chore_id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_id_communities_chores_rotation FOREIGN KEY (user_id) REFERENCES users (user_id) ON DELETE CASCADE,
+    CONSTRAINT unique_chore_id_user_id_communities_chores_rotation UNIQUE (chore_id, user_id)
+);
+
+
+-- Occurrences of the chores, the nth occurrence of a chore is due on the nth date of its recurrence.
+-- The assignee of a task can propose to swap it with the task of another member, the task to swap
+-- with is kept until that member accepts by swapping back.
+CREATE TABLE communities_chores_tasks (
+    id serial PRIMARY KEY,
+    task_id text NOT NULL UNIQUE,
+    chore_id text NOT NULL,
+    occurrence integer NOT NULL,
+    due_on date NOT NULL,
+    assignee_user_id text,
+    completed_at timestamp,
+    completed_by_user_id text,
+    swap_task_id text,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_chore_id_communities_chores_tasks FOREIGN KEY (chore_id) REFERENCES communities_chores (chore_id) ON DELETE CASCADE,
+    CONSTRAINT fk_assignee_user_id_communities_chores_tasks FOREIGN KEY (assignee_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT fk_completed_by_user_id_communities_chores_tasks FOREIGN KEY (completed_by_user_id) REFERENCES users (user_id) ON DELETE SET NULL,
+    CONSTRAINT fk_swap_task_id_communities_chores_tasks FOREIGN KEY (swap_task_id) REFERENCES communities_chores_tasks (task_id) ON DELETE SET NULL,
+    CONSTRAINT unique_chore_id_occurrence_communities_chores_tasks UNIQUE (chore_id, occurrence)
+);
+
+
+CREATE INDEX idx_assignee_user_id_due_on_communities_chores_tasks ON communities_chores_tasks (assignee_user_id, due_on);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS communities_chores_tasks;
+
+
+DROP TABLE IF EXISTS communities_chores_rotation;
+
+
+DROP TABLE IF EXISTS communities_chores;
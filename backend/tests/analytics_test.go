@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"backend/internal/analytics"
+	"backend/internal/database"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnalyticsRange(t *testing.T) {
+	from, to := analytics.Range(time.Date(2024, 3, 2, 18, 30, 0, 0, time.UTC), 3)
+	if !from.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected range %s - %s", from, to)
+	}
+}
+
+func TestAnalyticsSummarize(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	details := database.PropertyDetails{PropertyID: "a", Name: "Loft", Status: "published"}
+
+	summary := analytics.Summarize(details, from, to, []database.PropertyAnalyticsDay{
+		{Date: "2024-06-01", Views: 3, Saves: 1},
+		{Date: "2024-06-03", Views: 1},
+	})
+	expectedDaily := []database.PropertyAnalyticsDay{
+		{Date: "2024-06-01", Views: 3, Saves: 1},
+		{Date: "2024-06-02"},
+		{Date: "2024-06-03", Views: 1},
+	}
+	if !reflect.DeepEqual(summary.Daily, expectedDaily) {
+		t.Errorf("expected missing days to be zero filled, got %+v", summary.Daily)
+	}
+	if summary.Views != 4 || summary.Saves != 1 || summary.SaveRate != 0.25 {
+		t.Errorf("unexpected totals %d views, %d saves, %v save rate", summary.Views, summary.Saves, summary.SaveRate)
+	}
+
+	empty := analytics.Summarize(details, from, to, []database.PropertyAnalyticsDay{})
+	if len(empty.Daily) != 3 || empty.SaveRate != 0 {
+		t.Errorf("expected an empty summary of 3 days without a save rate, got %+v", empty)
+	}
+}
+
+func TestAnalyticsPortfolio(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+	quiet := analytics.Summarize(database.PropertyDetails{PropertyID: "a"}, from, to, []database.PropertyAnalyticsDay{
+		{Date: "2024-06-02", Views: 1, Saves: 1},
+	})
+	popular := analytics.Summarize(database.PropertyDetails{PropertyID: "b"}, from, to, []database.PropertyAnalyticsDay{
+		{Date: "2024-06-01", Views: 5},
+		{Date: "2024-06-02", Views: 2, Saves: 1},
+	})
+
+	portfolio := analytics.NewPortfolio(from, to, []analytics.PropertySummary{quiet, popular})
+	if portfolio.From != "2024-06-01" || portfolio.To != "2024-06-02" {
+		t.Errorf("unexpected range %s - %s", portfolio.From, portfolio.To)
+	}
+	if portfolio.Views != 8 || portfolio.Saves != 2 || portfolio.SaveRate != 0.25 {
+		t.Errorf("unexpected totals %d views, %d saves, %v save rate", portfolio.Views, portfolio.Saves, portfolio.SaveRate)
+	}
+	expectedDaily := []database.PropertyAnalyticsDay{
+		{Date: "2024-06-01", Views: 5},
+		{Date: "2024-06-02", Views: 3, Saves: 2},
+	}
+	if !reflect.DeepEqual(portfolio.Daily, expectedDaily) {
+		t.Errorf("expected daily counters totalled across properties, got %+v", portfolio.Daily)
+	}
+	if len(portfolio.Properties) != 2 || portfolio.Properties[0].PropertyID != "b" || portfolio.Properties[0].Daily != nil {
+		t.Errorf("expected most viewed property first without daily counters, got %+v", portfolio.Properties)
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"backend/internal/compare"
+	"backend/internal/database"
+	"reflect"
+	"testing"
+)
+
+func compareTestProperty(id string, costDollars int64, squareFeet int32, bedrooms int16, amenities []string) database.PropertyDetails {
+	return database.PropertyDetails{
+		PropertyID:        id,
+		Square_feet:       squareFeet,
+		Num_bedrooms:      bedrooms,
+		Num_showers_baths: 1,
+		Cost_dollars:      costDollars,
+		Price_type:        "monthly",
+		Amenities:         amenities,
+	}
+}
+
+func TestCompare(t *testing.T) {
+	latitude, longitude := 40.0, -74.0
+	near := compareTestProperty("a", 1000, 500, 1, []string{"gym"})
+	near.Latitude, near.Longitude = &latitude, &longitude
+	roomy := compareTestProperty("b", 1500, 1000, 2, []string{"gym", "pool"})
+	unknownSize := compareTestProperty("c", 900, 0, 2, nil)
+
+	comparison, err := compare.Compare([]database.PropertyDetails{near, roomy, unknownSize}, &compare.Point{Latitude: 40.1, Longitude: -74.0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(comparison.Properties) != 3 {
+		t.Fatalf("expected 3 compared properties, got %d", len(comparison.Properties))
+	}
+	if comparison.Properties[0].PricePerSquareFoot != 2 || comparison.Properties[1].PricePerSquareFoot != 1.5 {
+		t.Errorf("unexpected price per square foot: %v, %v", comparison.Properties[0].PricePerSquareFoot, comparison.Properties[1].PricePerSquareFoot)
+	}
+	if comparison.Properties[0].DistanceKm == nil || comparison.Properties[1].DistanceKm != nil {
+		t.Errorf("expected a distance only for the property with coordinates")
+	}
+
+	expectedBest := map[string][]string{
+		compare.DIMENSION_PRICE:                 {"c"},
+		compare.DIMENSION_PRICE_PER_SQUARE_FOOT: {"b"}, // c has no square footage to compare
+		compare.DIMENSION_SQUARE_FEET:           {"b"},
+		compare.DIMENSION_BEDROOMS:              {"b", "c"},
+		compare.DIMENSION_BATHROOMS:             {"a", "b", "c"},
+		compare.DIMENSION_AMENITIES:             {"b"},
+		compare.DIMENSION_DISTANCE:              {"a"},
+	}
+	if !reflect.DeepEqual(comparison.Best, expectedBest) {
+		t.Errorf("expected best %v, got %v", expectedBest, comparison.Best)
+	}
+	if !reflect.DeepEqual(comparison.Properties[0].BestOn, []string{compare.DIMENSION_BATHROOMS, compare.DIMENSION_DISTANCE}) {
+		t.Errorf("unexpected dimensions a is best on: %v", comparison.Properties[0].BestOn)
+	}
+}
+
+func TestCompareWithoutPoint(t *testing.T) {
+	comparison, err := compare.Compare([]database.PropertyDetails{
+		compareTestProperty("a", 1000, 500, 1, nil),
+		compareTestProperty("b", 1000, 500, 1, nil),
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := comparison.Best[compare.DIMENSION_DISTANCE]; ok {
+		t.Errorf("expected no distance dimension without a point")
+	}
+	if !reflect.DeepEqual(comparison.Best[compare.DIMENSION_PRICE], []string{"a", "b"}) {
+		t.Errorf("expected both properties to tie on price, got %v", comparison.Best[compare.DIMENSION_PRICE])
+	}
+}
+
+func TestCompareMixedPriceTypes(t *testing.T) {
+	forSale := compareTestProperty("b", 300000, 1000, 2, nil)
+	forSale.Price_type = "sale"
+	if _, err := compare.Compare([]database.PropertyDetails{compareTestProperty("a", 1000, 500, 1, nil), forSale}, nil); err == nil {
+		t.Errorf("expected an error comparing a rental with a property for sale")
+	}
+}
@@ -22,6 +22,66 @@ func TestDatabase(t *testing.T) {
 	}
 }
 
+func TestDiffPropertyDetails(t *testing.T) {
+	before := database.PropertyDetails{
+		PropertyID:          "property1",
+		ListerUserID:        "109237874690123193857",
+		Name:                "name",
+		Address_1:           "123 home street",
+		City:                "city",
+		State:               "state",
+		Zipcode:             "12345",
+		Country:             "usa",
+		Square_feet:         900,
+		Num_bedrooms:        3,
+		Cost_dollars:        2400,
+		Cost_cents:          0,
+		Price_type:          "monthly",
+		Lease_length_months: []int32{12, 6},
+		Amenities:           []string{"parking", "gym"},
+		Status:              "published",
+	}
+
+	// No changes, reordering lists is not a change
+	after := before
+	after.Lease_length_months = []int32{6, 12}
+	after.Amenities = []string{"gym", "parking"}
+	if changes := database.DiffPropertyDetails(before, after); len(changes) != 0 {
+		t.Errorf("expected no changes but got %v", changes)
+	}
+
+	// Price drop and a new amenity
+	after = before
+	after.Cost_dollars = 2250
+	after.Cost_cents = 5
+	after.Amenities = []string{"gym", "parking", "wifi"}
+	changes := database.DiffPropertyDetails(before, after)
+	expected := []database.PropertyChange{
+		{Field: database.PROPERTY_HISTORY_FIELD_COST, OldValue: "2400.00", NewValue: "2250.05"},
+		{Field: database.PROPERTY_HISTORY_FIELD_AMENITIES, OldValue: "gym,parking", NewValue: "gym,parking,wifi"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes but got %v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("change #%d - expected %v but got %v", i, expected[i], changes[i])
+		}
+	}
+
+	// Address changes are recorded as a single change
+	after = before
+	after.Address_2 = "apt 2"
+	after.Zipcode = "54321"
+	changes = database.DiffPropertyDetails(before, after)
+	if len(changes) != 1 || changes[0].Field != database.PROPERTY_HISTORY_FIELD_ADDRESS {
+		t.Fatalf("expected a single address change but got %v", changes)
+	}
+	if changes[0].NewValue != "123 home street, apt 2, city, state, 54321, usa" {
+		t.Errorf("unexpected new address: %s", changes[0].NewValue)
+	}
+}
+
 func TestMain(m *testing.M) {
 	config.InitConfig()
 	m.Run()
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"backend/internal/database"
+	"backend/internal/export"
+	"backend/internal/importer"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func exportTestListing(priceType, petsPolicy string) export.Listing {
+	latitude, longitude := 39.7817, -89.6501
+	return export.Listing{
+		Details: database.PropertyDetails{
+			PropertyID:          "7f3c2a4e-3b8a-4f7e-9a41-0d5a6b7c8d9e",
+			ListerUserID:        "123456789012345678901",
+			Name:                "Loft",
+			Address_1:           "1 Main St",
+			Address_2:           "Apt 2",
+			City:                "Springfield",
+			State:               "IL",
+			Zipcode:             "62701",
+			Country:             "United States",
+			Square_feet:         800,
+			Num_bedrooms:        2,
+			Num_showers_baths:   1,
+			Cost_dollars:        1200,
+			Cost_cents:          50,
+			Status:              "published",
+			Price_type:          priceType,
+			Utilities_included:  true,
+			Lease_length_months: []int32{1, 12},
+			Pets_policy:         petsPolicy,
+			Amenities:           []string{"wifi", "parking"},
+			Latitude:            &latitude,
+			Longitude:           &longitude,
+		},
+		URL:       "http://localhost:3000/properties/7f3c2a4e-3b8a-4f7e-9a41-0d5a6b7c8d9e",
+		ImageURLs: []string{"http://localhost:8080/api/v1/properties/7f3c2a4e-3b8a-4f7e-9a41-0d5a6b7c8d9e/images/0"},
+	}
+}
+
+func TestExportRESO(t *testing.T) {
+	monthly := export.ToRESO(exportTestListing("monthly", "negotiable"))
+	if monthly.PropertyType != "Residential Lease" || monthly.LeaseAmountFrequency != "Monthly" {
+		t.Errorf("expected a monthly residential lease, got %s %s", monthly.PropertyType, monthly.LeaseAmountFrequency)
+	}
+	if monthly.ListPrice != 1200.5 {
+		t.Errorf("expected list price 1200.5, got %v", monthly.ListPrice)
+	}
+	if monthly.StandardStatus != "Active" {
+		t.Errorf("expected standard status Active, got %s", monthly.StandardStatus)
+	}
+	if !reflect.DeepEqual(monthly.LeaseTerm, []string{"Month To Month", "12 Months"}) {
+		t.Errorf("unexpected lease terms %v", monthly.LeaseTerm)
+	}
+	if !reflect.DeepEqual(monthly.RentIncludes, []string{"All Utilities"}) || !reflect.DeepEqual(monthly.PetsAllowed, []string{"Call"}) {
+		t.Errorf("unexpected rent includes %v or pets allowed %v", monthly.RentIncludes, monthly.PetsAllowed)
+	}
+	if len(monthly.Media) != 1 || monthly.Media[0].Order != 1 || monthly.Media[0].MediaCategory != "Photo" {
+		t.Errorf("unexpected media %+v", monthly.Media)
+	}
+
+	sale := export.ToRESO(exportTestListing("sale", ""))
+	if sale.PropertyType != "Residential" || sale.LeaseAmountFrequency != "" || sale.LeaseTerm != nil || sale.PetsAllowed != nil {
+		t.Errorf("unexpected lease fields on a sale %+v", sale)
+	}
+
+	feed := export.ToRESOFeed([]export.Listing{})
+	if feed.Value == nil {
+		t.Errorf("expected an empty feed to have an empty value, not null")
+	}
+}
+
+func TestExportJSONLD(t *testing.T) {
+	offer := export.ToJSONLD(exportTestListing("monthly", "allowed"))
+	if offer.Context != export.SCHEMA_ORG_CONTEXT || offer.Type != "Offer" || offer.ItemOffered.Type != "Accommodation" {
+		t.Errorf("expected a schema.org Offer of an Accommodation, got %s %s %s", offer.Context, offer.Type, offer.ItemOffered.Type)
+	}
+	if offer.PriceSpecification.UnitCode != "MON" || !strings.HasSuffix(offer.BusinessFunction, "#LeaseOut") {
+		t.Errorf("expected a monthly lease, got %s %s", offer.PriceSpecification.UnitCode, offer.BusinessFunction)
+	}
+	if offer.ItemOffered.PetsAllowed == nil || !*offer.ItemOffered.PetsAllowed {
+		t.Errorf("expected pets to be allowed")
+	}
+	if offer.ItemOffered.Geo == nil || offer.ItemOffered.Geo.Latitude != 39.7817 {
+		t.Errorf("expected the coordinates of the accommodation, got %+v", offer.ItemOffered.Geo)
+	}
+	if len(offer.ItemOffered.AmenityFeature) != 2 || offer.ItemOffered.Address.StreetAddress != "1 Main St Apt 2" {
+		t.Errorf("unexpected accommodation %+v", offer.ItemOffered)
+	}
+
+	// The number of rooms counts every room, not only the vacant ones
+	listing := exportTestListing("monthly", "")
+	listing.Details.Rooms_available = 1
+	listing.RoomCount = 3
+	if rooms := export.ToJSONLD(listing).ItemOffered.NumberOfRooms; rooms != 3 {
+		t.Errorf("expected 3 rooms, got %d", rooms)
+	}
+
+	sale := export.ToJSONLD(exportTestListing("sale", ""))
+	if sale.PriceSpecification.UnitCode != "" || !strings.HasSuffix(sale.BusinessFunction, "#Sell") || sale.ItemOffered.PetsAllowed != nil {
+		t.Errorf("unexpected sale offer %+v", sale)
+	}
+
+	graph := export.ToJSONLDGraph([]export.Listing{exportTestListing("monthly", "")})
+	if len(graph.Graph) != 1 || graph.Graph[0].Context != "" {
+		t.Errorf("expected offers in a graph to share the graph's context, got %+v", graph.Graph)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	listing := exportTestListing("monthly", "allowed")
+	err := export.WriteCSV(&buf, []export.Listing{listing})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a header and a row, got %d lines", len(lines))
+	}
+	if lines[0] != strings.Join(export.CSV_COLUMNS, ",") {
+		t.Errorf("unexpected header %s", lines[0])
+	}
+
+	// Without the url columns an export can be imported again
+	columns := export.CSV_COLUMNS[:len(export.CSV_COLUMNS)-2]
+	var reimport bytes.Buffer
+	reimport.WriteString(strings.Join(columns, ",") + "\n")
+	fields := strings.Split(lines[1], ",")
+	reimport.WriteString(strings.Join(fields[:len(columns)], ",") + "\n")
+	rows, err := importer.ParseRows(&reimport, importer.FORMAT_CSV)
+	if err != nil {
+		t.Fatalf("unexpected error importing export: %s", err)
+	}
+	if !reflect.DeepEqual(rows[0].PropertyDetails, listing.Details) {
+		t.Errorf("expected %+v, got %+v", listing.Details, rows[0].PropertyDetails)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"backend/internal/ical"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICalCalendar(t *testing.T) {
+	start := time.Date(2024, 6, 1, 15, 0, 0, 0, time.FixedZone("EDT", -4*60*60))
+	calendar := ical.Calendar{
+		Name:   "Coop",
+		Method: "PUBLISH",
+		Events: []ical.Event{{
+			UID:         "viewing-1@coop",
+			Start:       start,
+			End:         start.Add(30 * time.Minute),
+			Summary:     "Viewing: Loft; 2 beds, 1 bath",
+			Description: "Line one\nLine two \\ end",
+			Location:    strings.Repeat("a", 100),
+			Status:      ical.STATUS_CONFIRMED,
+			Stamp:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	output := string(calendar.Bytes())
+
+	expectedLines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:Coop",
+		"BEGIN:VEVENT",
+		"UID:viewing-1@coop",
+		"DTSTAMP:20240501T000000Z",
+		"DTSTART:20240601T190000Z",
+		"DTEND:20240601T193000Z",
+		`SUMMARY:Viewing: Loft\; 2 beds\, 1 bath`,
+		`DESCRIPTION:Line one\nLine two \\ end`,
+		"LOCATION:" + strings.Repeat("a", 75-len("LOCATION:")),
+		" " + strings.Repeat("a", 100-(75-len("LOCATION:"))),
+		"STATUS:CONFIRMED",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(output, line+"\r\n") {
+			t.Errorf("expected line %q in calendar:\n%s", line, output)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+
+	if strings.Contains(output, "URL:") || strings.Contains(output, "SEQUENCE:") {
+		t.Errorf("expected empty optional properties to be omitted:\n%s", output)
+	}
+}
+
+func TestICalFoldMultiByte(t *testing.T) {
+	calendar := ical.Calendar{Name: strings.Repeat("é", 60)}
+	for _, line := range strings.Split(string(calendar.Bytes()), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+		if !strings.HasPrefix(line, " ") && strings.Contains(line, "�") {
+			t.Errorf("multi-byte character split: %q", line)
+		}
+	}
+	if !strings.Contains(strings.ReplaceAll(string(calendar.Bytes()), "\r\n ", ""), "X-WR-CALNAME:"+strings.Repeat("é", 60)) {
+		t.Errorf("expected unfolded name to be intact")
+	}
+}
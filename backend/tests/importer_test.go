@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"archive/zip"
+	"backend/internal/config"
+	"backend/internal/importer"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImporterParseRows(t *testing.T) {
+	type test struct {
+		input       string
+		format      string
+		expected    []importer.PropertyRow
+		expectError bool
+	}
+
+	csvHeader := "name,address1,city,costDollars,utilitiesIncluded,leaseLengthMonths,amenities,images\n"
+
+	tests := []test{
+		{
+			input:  csvHeader + "Loft,1 Main St,Springfield,1200,true,6;12,wifi; parking,a.jpg;b.png\n",
+			format: importer.FORMAT_CSV,
+			expected: func() []importer.PropertyRow {
+				row := importer.PropertyRow{Images: []string{"a.jpg", "b.png"}}
+				row.Name = "Loft"
+				row.Address_1 = "1 Main St"
+				row.City = "Springfield"
+				row.Cost_dollars = 1200
+				row.Utilities_included = true
+				row.Lease_length_months = []int32{6, 12}
+				row.Amenities = []string{"wifi", "parking"}
+				return []importer.PropertyRow{row}
+			}(),
+		},
+		{
+			input:  csvHeader + "Loft,1 Main St,Springfield,,,,,\n",
+			format: importer.FORMAT_CSV,
+			expected: func() []importer.PropertyRow {
+				row := importer.PropertyRow{Images: []string{}}
+				row.Name = "Loft"
+				row.Address_1 = "1 Main St"
+				row.City = "Springfield"
+				row.Lease_length_months = []int32{}
+				row.Amenities = []string{}
+				return []importer.PropertyRow{row}
+			}(),
+		},
+		{input: csvHeader + "Loft,1 Main St,Springfield,lots,,,,\n", format: importer.FORMAT_CSV, expectError: true},
+		{input: csvHeader + "Loft,1 Main St\n", format: importer.FORMAT_CSV, expectError: true},
+		{input: "name,bogus\nLoft,1\n", format: importer.FORMAT_CSV, expectError: true},
+		{input: csvHeader, format: importer.FORMAT_CSV, expectError: true},
+		{
+			input:  `[{"name": "Loft", "address1": "1 Main St", "costDollars": 900, "images": ["a.jpg"]}]`,
+			format: importer.FORMAT_JSON,
+			expected: func() []importer.PropertyRow {
+				row := importer.PropertyRow{Images: []string{"a.jpg"}}
+				row.Name = "Loft"
+				row.Address_1 = "1 Main St"
+				row.Cost_dollars = 900
+				return []importer.PropertyRow{row}
+			}(),
+		},
+		{input: `[{"name": "Loft", "bogus": 1}]`, format: importer.FORMAT_JSON, expectError: true},
+		{input: `{"name": "Loft"}`, format: importer.FORMAT_JSON, expectError: true},
+		{input: `[]`, format: importer.FORMAT_JSON, expectError: true},
+		{input: `[]`, format: "xml", expectError: true},
+	}
+
+	for i, test := range tests {
+		output, err := importer.ParseRows(strings.NewReader(test.input), test.format)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %v", i, output)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected, output)
+		}
+	}
+}
+
+func TestImporterReadImageArchive(t *testing.T) {
+	pngData := []byte("\x89PNG\r\n\x1a\n0000")
+	createArchive := func(files map[string][]byte) []byte {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		for name, data := range files {
+			file, err := archive.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.Write(data)
+		}
+		archive.Close()
+		return buf.Bytes()
+	}
+	rows := []importer.PropertyRow{
+		{Images: []string{"a.png"}},
+		{Images: []string{"units/b.png", "a.png"}},
+	}
+
+	images, err := importer.ReadImageArchive(createArchive(map[string][]byte{
+		"a.png":       pngData,
+		"units/b.png": pngData,
+		"unused.png":  pngData,
+	}), rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(images) != 2 {
+		t.Fatalf("expected only the 2 referenced images, got %d", len(images))
+	}
+	image, ok := images["units/b.png"]
+	if !ok {
+		t.Fatalf("expected image units/b.png in %v", images)
+	}
+	if image.Filename != "b.png" || image.Mimetype != "image/png" || image.Size != int64(len(pngData)) {
+		t.Errorf("unexpected image %+v", image)
+	}
+
+	// Images that decompress past the limit are rejected, unreferenced ones are never read
+	largeData := make([]byte, config.PROPERTY_IMPORT_MAX_IMAGE_SIZE+1)
+	_, err = importer.ReadImageArchive(createArchive(map[string][]byte{"a.png": largeData}), rows)
+	if err == nil {
+		t.Errorf("expected an error for an image larger than the limit")
+	}
+	_, err = importer.ReadImageArchive(createArchive(map[string][]byte{"unused.png": largeData}), rows)
+	if err != nil {
+		t.Errorf("expected an unreferenced large file to be skipped, got %s", err)
+	}
+
+	_, err = importer.ReadImageArchive([]byte("not a zip"), rows)
+	if err == nil {
+		t.Errorf("expected an error for an invalid archive")
+	}
+}
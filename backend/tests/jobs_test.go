@@ -0,0 +1,248 @@
+package tests
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/jobs"
+	"backend/internal/utils"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jobsTestDB fakes the listing expiry methods of the database, other methods are not implemented
+type jobsTestDB struct {
+	database.Service
+	expiring      []database.ExpiringProperty
+	expired       []database.ExpiringProperty
+	remindedBy    time.Time
+	history       map[string][]database.PropertyChange
+	notifications map[string][]string // notification types sent to each user
+}
+
+func newJobsTestDB() *jobsTestDB {
+	return &jobsTestDB{
+		history:       map[string][]database.PropertyChange{},
+		notifications: map[string][]string{},
+	}
+}
+
+func (db *jobsTestDB) RemindExpiringProperties(expiresBy time.Time) ([]database.ExpiringProperty, error) {
+	db.remindedBy = expiresBy
+	return db.expiring, nil
+}
+
+func (db *jobsTestDB) ExpireProperties(now time.Time) ([]database.ExpiringProperty, error) {
+	return db.expired, nil
+}
+
+func (db *jobsTestDB) CreatePropertyHistory(propertyID, changedByUserID string, changes []database.PropertyChange) error {
+	db.history[propertyID] = append(db.history[propertyID], changes...)
+	return nil
+}
+
+func (db *jobsTestDB) CreateNotification(userID, notificationType, message, link string) error {
+	db.notifications[userID] = append(db.notifications[userID], notificationType)
+	return nil
+}
+
+func TestRemindExpiringListings(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	db := newJobsTestDB()
+	db.expiring = []database.ExpiringProperty{
+		{PropertyID: "p1", ListerUserID: "lister1", Name: "Loft", ExpiresAt: now.AddDate(0, 0, 3)},
+	}
+
+	err := jobs.RemindExpiringListings(db, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !db.remindedBy.Equal(now.AddDate(0, 0, config.PROPERTY_EXPIRY_REMINDER_DAYS)) {
+		t.Errorf("expected listings expiring within the reminder period to be reminded, got %v", db.remindedBy)
+	}
+	if len(db.notifications["lister1"]) != 1 || db.notifications["lister1"][0] != config.NOTIFICATION_TYPE_LISTING_EXPIRING {
+		t.Errorf("expected the lister to be reminded once, got %v", db.notifications["lister1"])
+	}
+	if len(db.history) != 0 {
+		t.Errorf("expected reminding to not change any listing")
+	}
+}
+
+func TestExpireListings(t *testing.T) {
+	db := newJobsTestDB()
+	db.expired = []database.ExpiringProperty{
+		{PropertyID: "p1", ListerUserID: "lister1", Name: "Loft"},
+		{PropertyID: "p2", ListerUserID: "lister2", Name: "Cottage"},
+	}
+
+	err := jobs.ExpireListings(db, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, property := range db.expired {
+		changes := db.history[property.PropertyID]
+		if len(changes) != 1 || changes[0].OldValue != config.PROPERTY_STATUS_PUBLISHED || changes[0].NewValue != config.PROPERTY_STATUS_EXPIRED {
+			t.Errorf("expected the expiry of %s to be recorded in its history, got %v", property.PropertyID, changes)
+		}
+		notifications := db.notifications[property.ListerUserID]
+		if len(notifications) != 1 || notifications[0] != config.NOTIFICATION_TYPE_LISTING_EXPIRED {
+			t.Errorf("expected %s to be notified of the expiry, got %v", property.ListerUserID, notifications)
+		}
+	}
+}
+
+// addressesTestDB fakes the normalized address methods of the database, other methods are not implemented
+type addressesTestDB struct {
+	database.Service
+	addresses []database.PropertyAddress
+	updated   map[string]string
+}
+
+func (db *addressesTestDB) GetPropertyAddresses() ([]database.PropertyAddress, error) {
+	return db.addresses, nil
+}
+
+func (db *addressesTestDB) UpdatePropertyNormalizedAddress(propertyID, normalizedAddress string) error {
+	db.updated[propertyID] = normalizedAddress
+	return nil
+}
+
+func TestNormalizePropertyAddresses(t *testing.T) {
+	db := &addressesTestDB{
+		addresses: []database.PropertyAddress{
+			// Backfilled by the migration adding normalized addresses
+			{PropertyID: "p1", Address_1: "123 Main Street", Address_2: "Apt #4", City: "Springfield", State: "IL", Zipcode: "62704", Country: "United States",
+				NormalizedAddress: "123 main street apt 4 springfield il 62704 united states"},
+			// Already normalized when last saved
+			{PropertyID: "p2", Address_1: "9 Oak Ave", City: "Springfield", State: "IL", Zipcode: "62704", Country: "US",
+				NormalizedAddress: "9 oak ave springfield il 62704 us"},
+		},
+		updated: map[string]string{},
+	}
+
+	updated, err := jobs.NormalizePropertyAddresses(db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := utils.NormalizeAddress("123 Main Street", "Apt #4", "Springfield", "IL", "62704", "United States")
+	if expected != "123 main st unit 4 springfield il 62704 us" {
+		t.Fatalf("unexpected normalization of the sample address: %s", expected)
+	}
+	if updated != 1 || len(db.updated) != 1 || db.updated["p1"] != expected {
+		t.Errorf("expected only the backfilled address to be normalized to %q, got %v", expected, db.updated)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	var runs atomic.Int32
+	scheduler := jobs.NewScheduler(jobs.Job{
+		Name:     "test",
+		Interval: 10 * time.Millisecond,
+		Run: func(now time.Time) error {
+			runs.Add(1)
+			return errors.New("failing runs are retried on the next interval")
+		},
+	})
+
+	scheduler.Start()
+	time.Sleep(55 * time.Millisecond)
+	scheduler.Stop()
+
+	stoppedRuns := runs.Load()
+	if stoppedRuns < 2 {
+		t.Errorf("expected the job to run when started and again every interval, got %d runs", stoppedRuns)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != stoppedRuns {
+		t.Errorf("expected the job to not run after the scheduler stopped")
+	}
+}
+
+func TestSchedulerRunsJobWithoutIntervalOnce(t *testing.T) {
+	var runs atomic.Int32
+	scheduler := jobs.NewScheduler(jobs.Job{
+		Name: "test",
+		Run: func(now time.Time) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	scheduler.Start()
+	time.Sleep(30 * time.Millisecond)
+	scheduler.Stop()
+
+	if runs.Load() != 1 {
+		t.Errorf("expected the job without an interval to run once, got %d runs", runs.Load())
+	}
+}
+
+// choresTestDB fakes the chore task methods of the database, other methods are not implemented
+type choresTestDB struct {
+	database.Service
+	lastOccurrence int32
+	tasks          []database.CommunityChoreTask
+}
+
+func (db *choresTestDB) GetCommunityChoreLastOccurrence(choreID string) (int32, error) {
+	return db.lastOccurrence, nil
+}
+
+func (db *choresTestDB) CreateCommunityChoreTask(task database.CommunityChoreTask) error {
+	db.tasks = append(db.tasks, task)
+	return nil
+}
+
+func TestScheduleCommunityChoreTasks(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	chore := database.CommunityChore{
+		ChoreID:   "chore1",
+		Frequency: config.COMMUNITY_CHORE_FREQUENCY_WEEKLY,
+		Interval:  1,
+		StartsOn:  "2024-05-06",
+		Rotation:  []string{"a", "b", "c"},
+	}
+
+	type test struct {
+		lastOccurrence int32
+		expected       []string // due date and assignee of each scheduled task
+	}
+
+	tests := []test{
+		// Past occurrences of a new chore are skipped, the rotation still counts them
+		{lastOccurrence: -1, expected: []string{"2024-06-10 c", "2024-06-17 a", "2024-06-24 b"}},
+		{lastOccurrence: 5, expected: []string{"2024-06-17 a", "2024-06-24 b"}},
+		{lastOccurrence: 7, expected: []string{}},
+	}
+
+	for i, test := range tests {
+		db := &choresTestDB{lastOccurrence: test.lastOccurrence}
+		err := jobs.ScheduleCommunityChoreTasks(db, chore, now)
+		if err != nil {
+			t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			continue
+		}
+
+		scheduled := []string{}
+		for _, task := range db.tasks {
+			scheduled = append(scheduled, task.DueOn+" "+task.AssigneeUserID)
+		}
+		if strings.Join(scheduled, ", ") != strings.Join(test.expected, ", ") {
+			t.Errorf("test #%d - expected %v, got %v", i, test.expected, scheduled)
+		}
+	}
+
+	// A chore without members left in its rotation is not scheduled
+	db := &choresTestDB{lastOccurrence: -1}
+	chore.Rotation = []string{}
+	err := jobs.ScheduleCommunityChoreTasks(db, chore, now)
+	if err != nil || len(db.tasks) != 0 {
+		t.Errorf("expected no tasks for an empty rotation, got %v (%v)", db.tasks, err)
+	}
+}
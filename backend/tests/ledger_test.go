@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"backend/internal/database"
+	"backend/internal/ledger"
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLedgerSplit(t *testing.T) {
+	type test struct {
+		amountCents int64
+		splitType   string
+		splits      []database.CommunityExpenseSplit
+		expected    []int64 // amounts owed by each member
+		expectError bool
+	}
+
+	tests := []test{
+		// Leftover cents of an equal split go to the first members
+		{amountCents: 1000, splitType: "equal", splits: []database.CommunityExpenseSplit{{UserID: "a"}, {UserID: "b"}, {UserID: "c"}}, expected: []int64{334, 333, 333}},
+		{amountCents: 1000, splitType: "shares", splits: []database.CommunityExpenseSplit{{UserID: "a", Shares: 2}, {UserID: "b", Shares: 1}}, expected: []int64{667, 333}},
+		{amountCents: 100, splitType: "shares", splits: []database.CommunityExpenseSplit{{UserID: "a", Shares: 1}, {UserID: "b", Shares: 1}, {UserID: "c", Shares: 1}}, expected: []int64{34, 33, 33}},
+		{amountCents: 1000, splitType: "shares", splits: []database.CommunityExpenseSplit{{UserID: "a", Shares: 0}, {UserID: "b", Shares: 1}}, expectError: true},
+		{amountCents: 1000, splitType: "exact", splits: []database.CommunityExpenseSplit{{UserID: "a", AmountCents: 250}, {UserID: "b", AmountCents: 750}}, expected: []int64{250, 750}},
+		{amountCents: 1000, splitType: "exact", splits: []database.CommunityExpenseSplit{{UserID: "a", AmountCents: 250}, {UserID: "b", AmountCents: 700}}, expectError: true},
+		{amountCents: 1000, splitType: "exact", splits: []database.CommunityExpenseSplit{{UserID: "a", AmountCents: -250}, {UserID: "b", AmountCents: 1250}}, expectError: true},
+		{amountCents: 1000, splitType: "equal", splits: []database.CommunityExpenseSplit{}, expectError: true},
+		{amountCents: 1000, splitType: "percent", splits: []database.CommunityExpenseSplit{{UserID: "a"}}, expectError: true},
+	}
+
+	for i, test := range tests {
+		splits, err := ledger.Split(test.amountCents, test.splitType, test.splits)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			continue
+		}
+
+		amounts := []int64{}
+		for _, split := range splits {
+			amounts = append(amounts, split.AmountCents)
+		}
+		if !reflect.DeepEqual(amounts, test.expected) {
+			t.Errorf("test #%d - expected amounts %v, got %v", i, test.expected, amounts)
+		}
+	}
+}
+
+func ledgerTestExpenses() []database.CommunityExpense {
+	return []database.CommunityExpense{
+		{
+			ExpenseID:   "rent",
+			PayerUserID: "a",
+			Description: "Rent",
+			Category:    "rent",
+			AmountCents: 3000,
+			SplitType:   "equal",
+			SpentOn:     "2024-10-01",
+			Splits: []database.CommunityExpenseSplit{
+				{UserID: "a", AmountCents: 1000},
+				{UserID: "b", AmountCents: 1000},
+				{UserID: "c", AmountCents: 1000},
+			},
+		},
+		{
+			ExpenseID:   "groceries",
+			PayerUserID: "b",
+			Description: "Groceries",
+			Category:    "groceries",
+			AmountCents: 600,
+			SplitType:   "shares",
+			SpentOn:     "2024-10-03",
+			Splits: []database.CommunityExpenseSplit{
+				{UserID: "b", Shares: 1, AmountCents: 300},
+				{UserID: "c", Shares: 1, AmountCents: 300},
+			},
+		},
+	}
+}
+
+func TestLedgerBalancesAndSettle(t *testing.T) {
+	balances := ledger.Balances(ledgerTestExpenses())
+
+	expectedBalances := []ledger.Balance{
+		{UserID: "a", PaidCents: 3000, OwedCents: 1000, BalanceCents: 2000},
+		{UserID: "b", PaidCents: 600, OwedCents: 1300, BalanceCents: -700},
+		{UserID: "c", PaidCents: 0, OwedCents: 1300, BalanceCents: -1300},
+	}
+	if !reflect.DeepEqual(balances, expectedBalances) {
+		t.Fatalf("expected balances %v, got %v", expectedBalances, balances)
+	}
+
+	expectedSettlements := []ledger.Settlement{
+		{FromUserID: "c", ToUserID: "a", AmountCents: 1300},
+		{FromUserID: "b", ToUserID: "a", AmountCents: 700},
+	}
+	if settlements := ledger.Settle(balances); !reflect.DeepEqual(settlements, expectedSettlements) {
+		t.Errorf("expected settlements %v, got %v", expectedSettlements, settlements)
+	}
+
+	if settlements := ledger.Settle([]ledger.Balance{{UserID: "a"}}); len(settlements) != 0 {
+		t.Errorf("expected no settlements of settled balances, got %v", settlements)
+	}
+
+	// Members who deleted their account keep a balance without a user id, balances still add up
+	expenses := ledgerTestExpenses()
+	expenses[0].PayerUserID = ""
+	var totalCents int64
+	for _, balance := range ledger.Balances(expenses) {
+		totalCents += balance.BalanceCents
+	}
+	if totalCents != 0 {
+		t.Errorf("expected balances to add up to 0, got %d", totalCents)
+	}
+}
+
+func TestLedgerStatement(t *testing.T) {
+	statement := ledger.Statement(ledgerTestExpenses(), "b")
+
+	if len(statement) != 2 {
+		t.Fatalf("expected 2 statement entries, got %d", len(statement))
+	}
+	if statement[0].ChangeCents != -1000 || statement[0].BalanceCents != -1000 {
+		t.Errorf("unexpected first entry: %+v", statement[0])
+	}
+	if statement[1].ChangeCents != 300 || statement[1].BalanceCents != -700 {
+		t.Errorf("unexpected second entry: %+v", statement[1])
+	}
+
+	if statement := ledger.Statement(ledgerTestExpenses(), "d"); len(statement) != 0 {
+		t.Errorf("expected an empty statement for a member without expenses, got %v", statement)
+	}
+}
+
+func TestLedgerWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := ledger.WriteCSV(&buf, ledgerTestExpenses())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected a header and 5 split rows, got %d lines", len(lines))
+	}
+	if lines[0] != strings.Join(ledger.CSV_COLUMNS, ",") {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if lines[4] != "groceries,2024-10-03,Groceries,groceries,b,6.00,shares,b,1,3.00" {
+		t.Errorf("unexpected row: %s", lines[4])
+	}
+}
+
+func TestLedgerWriteCSVEscapesFormulas(t *testing.T) {
+	tests := map[string]string{
+		"=HYPERLINK(\"http://example.com\")": "'=HYPERLINK(\"http://example.com\")",
+		"+1":                                 "'+1",
+		"-1":                                 "'-1",
+		"@SUM(A1)":                           "'@SUM(A1)",
+		"\tTab":                              "'\tTab",
+		"Rent = 2 months":                    "Rent = 2 months",
+	}
+
+	for description, expected := range tests {
+		expense := ledgerTestExpenses()[0]
+		expense.Description = description
+
+		var buf bytes.Buffer
+		err := ledger.WriteCSV(&buf, []database.CommunityExpense{expense})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("unexpected error reading the csv: %v", err)
+		}
+		if got := records[1][2]; got != expected {
+			t.Errorf("expected description %q to be written as %q, got %q", description, expected, got)
+		}
+	}
+}
+
+func TestLedgerFormatCents(t *testing.T) {
+	tests := map[int64]string{0: "0.00", 5: "0.05", 1234: "12.34", -1234: "-12.34"}
+	for cents, expected := range tests {
+		if formatted := ledger.FormatCents(cents); formatted != expected {
+			t.Errorf("expected %d cents to format as %s, got %s", cents, expected, formatted)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"backend/internal/database"
+	"backend/internal/recommend"
+	"reflect"
+	"testing"
+)
+
+func recommendTestProperty(id string, costDollars int64, bedrooms int16, zipcode string) database.PropertyDetails {
+	return database.PropertyDetails{
+		PropertyID:        id,
+		City:              "Springfield",
+		State:             "IL",
+		Zipcode:           zipcode,
+		Square_feet:       800,
+		Num_bedrooms:      bedrooms,
+		Num_showers_baths: 1,
+		Cost_dollars:      costDollars,
+		Price_type:        "monthly",
+	}
+}
+
+func TestRecommendSimilarity(t *testing.T) {
+	property := recommendTestProperty("a", 1200, 2, "62701")
+	if similarity := recommend.Similarity(property, property); similarity < 0.9999 || similarity > 1.0001 {
+		t.Errorf("expected a property to be fully similar to itself, got %v", similarity)
+	}
+
+	cheaper := recommendTestProperty("b", 600, 2, "62701")
+	bigger := recommendTestProperty("c", 1200, 5, "62701")
+	elsewhere := recommendTestProperty("d", 1200, 2, "62702")
+	elsewhere.City = "Chicago"
+	for _, other := range []database.PropertyDetails{cheaper, bigger, elsewhere} {
+		similarity := recommend.Similarity(property, other)
+		if similarity <= 0 || similarity >= 1 {
+			t.Errorf("expected a similarity between 0 and 1 for %s, got %v", other.PropertyID, similarity)
+		}
+		if similarity != recommend.Similarity(other, property) {
+			t.Errorf("expected similarity to be symmetric for %s", other.PropertyID)
+		}
+	}
+}
+
+func TestRecommendLocationSimilarity(t *testing.T) {
+	property := recommendTestProperty("a", 1200, 2, "62701")
+	sameCity := recommendTestProperty("b", 1200, 2, "62702")
+	otherCity := recommendTestProperty("c", 1200, 2, "60601")
+	otherCity.City = "Chicago"
+	otherState := recommendTestProperty("d", 1200, 2, "62701")
+	otherState.State = "MO"
+
+	if recommend.LocationSimilarity(property, property) != 1 ||
+		recommend.LocationSimilarity(property, sameCity) != 0.7 ||
+		recommend.LocationSimilarity(property, otherCity) != 0.3 ||
+		recommend.LocationSimilarity(property, otherState) != 0 {
+		t.Errorf("unexpected location similarity without coordinates")
+	}
+
+	// Coordinates take precedence over the address
+	lat1, lon1, lat2, lon2 := 39.78, -89.65, 39.78, -89.65
+	property.Latitude, property.Longitude = &lat1, &lon1
+	otherState.Latitude, otherState.Longitude = &lat2, &lon2
+	if recommend.LocationSimilarity(property, otherState) != 1 {
+		t.Errorf("expected properties at the same coordinates to be fully similar in location")
+	}
+	far := 41.88
+	otherState.Latitude = &far
+	if recommend.LocationSimilarity(property, otherState) != 0 {
+		t.Errorf("expected properties further apart than %v km to not be similar in location", recommend.MAX_DISTANCE_KM)
+	}
+}
+
+func TestRecommendRank(t *testing.T) {
+	property := recommendTestProperty("a", 1200, 2, "62701")
+	candidates := []database.PropertyDetails{
+		recommendTestProperty("far", 3000, 5, "99999"),
+		property,
+		recommendTestProperty("twin", 1200, 2, "62701"),
+		recommendTestProperty("close", 1300, 2, "62701"),
+	}
+
+	matches := recommend.Rank(property, candidates, 2)
+	ids := []string{}
+	for _, match := range matches {
+		ids = append(ids, match.PropertyID)
+	}
+	if !reflect.DeepEqual(ids, []string{"twin", "close"}) {
+		t.Errorf("expected the two most similar other properties, got %v", ids)
+	}
+}
+
+func TestRecommendMerge(t *testing.T) {
+	merged := recommend.Merge([][]recommend.Match{
+		{{PropertyID: "a", Similarity: 0.5}, {PropertyID: "b", Similarity: 0.9}},
+		{{PropertyID: "a", Similarity: 0.8}, {PropertyID: "c", Similarity: 0.8}},
+	}, 10)
+
+	expected := []recommend.Match{
+		{PropertyID: "b", Similarity: 0.9},
+		{PropertyID: "a", Similarity: 0.8},
+		{PropertyID: "c", Similarity: 0.8},
+	}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+
+	if merged := recommend.Merge([][]recommend.Match{}, 10); merged == nil || len(merged) != 0 {
+		t.Errorf("expected an empty list without rankings, got %+v", merged)
+	}
+}
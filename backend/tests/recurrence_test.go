@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"backend/internal/recurrence"
+	"testing"
+	"time"
+)
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrenceOccurrence(t *testing.T) {
+	type test struct {
+		rule     recurrence.Rule
+		n        int
+		expected time.Time
+	}
+
+	tests := []test{
+		{rule: recurrence.Rule{Frequency: "daily", Interval: 1, Start: utcDate(2024, 6, 1)}, n: 0, expected: utcDate(2024, 6, 1)},
+		{rule: recurrence.Rule{Frequency: "daily", Interval: 3, Start: utcDate(2024, 6, 1)}, n: 10, expected: utcDate(2024, 7, 1)},
+		{rule: recurrence.Rule{Frequency: "weekly", Interval: 2, Start: utcDate(2024, 6, 3)}, n: 2, expected: utcDate(2024, 7, 1)},
+		{rule: recurrence.Rule{Frequency: "monthly", Interval: 1, Start: utcDate(2024, 6, 15)}, n: 7, expected: utcDate(2025, 1, 15)},
+		// Monthly occurrences fall on the last day of shorter months and come back after
+		{rule: recurrence.Rule{Frequency: "monthly", Interval: 1, Start: utcDate(2024, 1, 31)}, n: 1, expected: utcDate(2024, 2, 29)},
+		{rule: recurrence.Rule{Frequency: "monthly", Interval: 1, Start: utcDate(2024, 1, 31)}, n: 3, expected: utcDate(2024, 4, 30)},
+		{rule: recurrence.Rule{Frequency: "monthly", Interval: 1, Start: utcDate(2024, 1, 31)}, n: 4, expected: utcDate(2024, 5, 31)},
+		// Time of day of the start is ignored
+		{rule: recurrence.Rule{Frequency: "daily", Interval: 1, Start: time.Date(2024, 6, 1, 18, 30, 0, 0, time.UTC)}, n: 1, expected: utcDate(2024, 6, 2)},
+	}
+
+	for i, test := range tests {
+		occurrence := test.rule.Occurrence(test.n)
+		if !occurrence.Equal(test.expected) {
+			t.Errorf("test #%d - expected %s, got %s", i, test.expected.Format("2006-01-02"), occurrence.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	type test struct {
+		rule     recurrence.Rule
+		date     time.Time
+		expected int
+	}
+
+	tests := []test{
+		{rule: recurrence.Rule{Frequency: "daily", Interval: 1, Start: utcDate(2024, 6, 1)}, date: utcDate(2024, 5, 1), expected: 0},
+		{rule: recurrence.Rule{Frequency: "daily", Interval: 1, Start: utcDate(2024, 6, 1)}, date: utcDate(2024, 6, 1), expected: 0},
+		{rule: recurrence.Rule{Frequency: "daily", Interval: 2, Start: utcDate(2024, 6, 1)}, date: utcDate(2024, 6, 4), expected: 2},
+		{rule: recurrence.Rule{Frequency: "weekly", Interval: 1, Start: utcDate(2024, 6, 3)}, date: utcDate(2024, 6, 10), expected: 1},
+		{rule: recurrence.Rule{Frequency: "weekly", Interval: 1, Start: utcDate(2024, 6, 3)}, date: utcDate(2024, 6, 11), expected: 2},
+		{rule: recurrence.Rule{Frequency: "monthly", Interval: 1, Start: utcDate(2024, 1, 31)}, date: utcDate(2024, 3, 1), expected: 2},
+		{rule: recurrence.Rule{Frequency: "monthly", Interval: 3, Start: utcDate(2024, 1, 15)}, date: utcDate(2025, 1, 15), expected: 4},
+	}
+
+	for i, test := range tests {
+		n := test.rule.Next(test.date)
+		if n != test.expected {
+			t.Errorf("test #%d - expected occurrence %d, got %d", i, test.expected, n)
+		}
+	}
+}
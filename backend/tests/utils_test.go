@@ -5,6 +5,8 @@ import (
 	"bytes"
 	"database/sql"
 	"fmt"
+	"math"
+	"slices"
 	"testing"
 )
 
@@ -399,3 +401,153 @@ func TestCreateSQLNullString(t *testing.T) {
 		}
 	}
 }
+
+func TestContentHash(t *testing.T) {
+	type test struct {
+		input          []byte
+		expectedOutput string
+	}
+
+	tests := []test{
+		{[]byte{}, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{[]byte("abc"), "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for i, test := range tests {
+		if got := utils.ContentHash(test.input); got != test.expectedOutput {
+			t.Errorf("test #%d - expected hash %s but got %s", i, test.expectedOutput, got)
+		}
+	}
+
+	// Same content must always map to the same hash, different content to different hashes
+	if utils.ContentHash([]byte("same photo")) != utils.ContentHash([]byte("same photo")) {
+		t.Error("expected identical content to produce identical hashes")
+	}
+	if utils.ContentHash([]byte("photo a")) == utils.ContentHash([]byte("photo b")) {
+		t.Error("expected different content to produce different hashes")
+	}
+}
+
+func TestSplitCommaSeparated(t *testing.T) {
+	type test struct {
+		input          string
+		expectedOutput []string
+	}
+
+	tests := []test{
+		{"", []string{}},
+		{"wifi", []string{"wifi"}},
+		{"wifi,parking", []string{"wifi", "parking"}},
+		{"wifi,wifi", []string{"wifi"}},
+		{"cooking, hiking ,", []string{"cooking", "hiking"}},
+		{" , ,", []string{}},
+		{"hiking, board games,,cooking ", []string{"hiking", "board games", "cooking"}},
+	}
+
+	for i, test := range tests {
+		if got := utils.SplitCommaSeparated(test.input); !slices.Equal(got, test.expectedOutput) {
+			t.Errorf("test #%d - expected %q but got %q", i, test.expectedOutput, got)
+		}
+	}
+}
+
+func TestCreateSQLNullDate(t *testing.T) {
+	type test struct {
+		input       string
+		expectValid bool
+		expectError bool
+	}
+
+	tests := []test{
+		{"", false, false},
+		{"2024-09-01", true, false},
+		{"2024-02-29", true, false},
+		{"2023-02-29", false, true},
+		{"09/01/2024", false, true},
+		{"tomorrow", false, true},
+	}
+
+	for i, test := range tests {
+		got, err := utils.CreateSQLNullDate(test.input)
+		if (err != nil) != test.expectError {
+			t.Errorf("test #%d - unexpected error result: %v", i, err)
+			continue
+		}
+		if got.Valid != test.expectValid {
+			t.Errorf("test #%d - expected valid to be %v", i, test.expectValid)
+		}
+		// Valid dates must round trip
+		if got.Valid && utils.FormatSQLNullDate(got) != test.input {
+			t.Errorf("test #%d - expected %s but got %s", i, test.input, utils.FormatSQLNullDate(got))
+		}
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	type test struct {
+		a        []string
+		b        []string
+		expected bool // whether a and b are expected to normalize to the same address
+	}
+
+	tests := []test{
+		{
+			[]string{"123 Main Street", "Apt 4", "Springfield", "IL", "62701", "USA"},
+			[]string{"123 main st.", "#4", "springfield", "il", "62701", "United States"},
+			true,
+		},
+		{
+			[]string{"1 North Avenue", "", "Boston", "MA", "02115", "US"},
+			[]string{"1 N. Ave", "", "boston", "ma", "02115", "united states of america"},
+			true,
+		},
+		{
+			[]string{"55 Elm Road", "Suite 200", "Austin", "TX", "73301", "US"},
+			[]string{"55 elm rd", "unit #200", "Austin", "TX", "73301", "US"},
+			true,
+		},
+		{
+			[]string{"123 Main Street", "Apt 4", "Springfield", "IL", "62701", "USA"},
+			[]string{"123 Main Street", "Apt 5", "Springfield", "IL", "62701", "USA"},
+			false,
+		},
+		{
+			[]string{"123 Main Street", "", "Springfield", "IL", "62701", "USA"},
+			[]string{"124 Main Street", "", "Springfield", "IL", "62701", "USA"},
+			false,
+		},
+	}
+
+	for i, test := range tests {
+		a := utils.NormalizeAddress(test.a[0], test.a[1], test.a[2], test.a[3], test.a[4], test.a[5])
+		b := utils.NormalizeAddress(test.b[0], test.b[1], test.b[2], test.b[3], test.b[4], test.b[5])
+		if (a == b) != test.expected {
+			t.Errorf("test #%d - expected equal to be %v, got \"%s\" and \"%s\"", i, test.expected, a, b)
+		}
+	}
+
+	if got := utils.NormalizeAddress("123 Main Street", "Apt. #4", "Springfield", "IL", "62701", "USA"); got != "123 main st unit 4 springfield il 62701 us" {
+		t.Errorf("unexpected normalized address: %s", got)
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	type test struct {
+		lat1, lon1, lat2, lon2 float64
+		expectedKm             float64
+	}
+
+	tests := []test{
+		{40.7128, -74.0060, 40.7128, -74.0060, 0},
+		{40.7128, -74.0060, 34.0522, -118.2437, 3936}, // New York to Los Angeles
+		{51.5074, -0.1278, 48.8566, 2.3522, 344},      // London to Paris
+		{0, 179.5, 0, -179.5, 111},                    // across the antimeridian
+	}
+
+	for i, test := range tests {
+		got := utils.DistanceKm(test.lat1, test.lon1, test.lat2, test.lon2)
+		if math.Abs(got-test.expectedKm) > 2 {
+			t.Errorf("test #%d - expected about %v km but got %v km", i, test.expectedKm, got)
+		}
+	}
+}
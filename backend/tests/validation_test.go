@@ -2,6 +2,7 @@ package tests
 
 import (
 	"backend/internal/database"
+	"backend/internal/utils"
 	"backend/internal/validation"
 	"strings"
 	"testing"
@@ -239,6 +240,79 @@ func TestValidateCommunityDetails(t *testing.T) {
 	}
 }
 
+func TestValidateCommunityMembershipRequest(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.AddDate(0, 0, 14)
+	expiredAt := now.Add(-time.Minute)
+	validRequest := database.CommunityMembershipRequest{
+		RequestID:   uuid.New().String(),
+		CommunityID: uuid.New().String(),
+		UserID:      "123456789012345678901",
+		Kind:        "request",
+	}
+	validInvite := validRequest
+	validInvite.Kind = "invite"
+	validInvite.InvitedByUserID = "123456789012345678902"
+	validInvite.ExpiresAt = &expiresAt
+
+	type test struct {
+		request     database.CommunityMembershipRequest
+		modify      func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest
+		expectError bool
+	}
+
+	unchanged := func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest { return request }
+	tests := []test{
+		{request: validRequest, modify: unchanged, expectError: false},
+		{request: validInvite, modify: unchanged, expectError: false},
+		{request: validRequest, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.CommunityID = "community"
+			return request
+		}, expectError: true},
+		{request: validRequest, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.UserID = "1234"
+			return request
+		}, expectError: true},
+		{request: validRequest, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.Kind = "application"
+			return request
+		}, expectError: true},
+		{request: validRequest, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.ExpiresAt = &expiresAt
+			return request
+		}, expectError: true},
+		{request: validInvite, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.ExpiresAt = nil
+			return request
+		}, expectError: true},
+		{request: validInvite, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.ExpiresAt = &expiredAt
+			return request
+		}, expectError: true},
+		{request: validInvite, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.InvitedByUserID = request.UserID
+			return request
+		}, expectError: true},
+		{request: validInvite, modify: func(request database.CommunityMembershipRequest) database.CommunityMembershipRequest {
+			request.InvitedByUserID = ""
+			return request
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityMembershipRequest(test.modify(test.request), now)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
 func TestValidatePropertyDetails(t *testing.T) {
 	type test struct {
 		input       database.PropertyDetails
@@ -247,6 +321,7 @@ func TestValidatePropertyDetails(t *testing.T) {
 
 	uuid1 := uuid.NewString()
 	listerUserID1 := "109237874690123193857"
+	latitude, longitude, outOfRange := 41.88, -87.63, 123.0
 
 	tests := []test{
 		{
@@ -847,173 +922,1396 @@ func TestValidatePropertyDetails(t *testing.T) {
 			},
 			true,
 		},
-	}
-
-	for i, test := range tests {
-		err := validation.ValidatePropertyDetails(test.input)
-		if test.expectError {
-			if err == nil {
-				t.Errorf("test #%d - expected error but got none", i)
-			}
-		} else {
-			if err != nil {
-				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
-			}
-		}
-	}
-}
-
-func TestValidateUserDetails(t *testing.T) {
-	type test struct {
-		name        string
-		input       database.UserDetails
-		expectError bool
-	}
-
-	userID1 := "109237874690123193857"
-	tests := []test{
 		{
-			name: "regular input 1",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "santa",
-				LastName:  "claus",
-				BirthDate: "0280-12-25",
-				Gender:    "Man",
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// valid draft property
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      123,
+				Num_toilets:       123,
+				Num_showers_baths: 123,
+				Cost_dollars:      123,
+				Cost_cents:        12,
+				Misc_note:         "asdf",
+				Status:            "draft",
 			},
-			expectError: false,
+			false,
 		},
 		{
-			name: "regular input 2",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "frosty",
-				LastName:  "thesnowman",
-				BirthDate: "1999-01-01",
-				Gender:    "Woman",
-				Location:  "Siberia",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// unknown status
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      123,
+				Num_toilets:       123,
+				Num_showers_baths: 123,
+				Cost_dollars:      123,
+				Cost_cents:        12,
+				Misc_note:         "asdf",
+				Status:            "sold",
 			},
-			expectError: false,
+			true,
 		},
 		{
-			name: "no user id",
-			input: database.UserDetails{
-				UserID:    "", // empty user id
-				Email:     "example@example.com",
-				FirstName: "santa",
-				LastName:  "claus",
-				BirthDate: "0280-12-25",
-				Gender:    "Man",
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// valid rental terms
+			database.PropertyDetails{
+				PropertyID:          uuid1,
+				ListerUserID:        listerUserID1,
+				Name:                "name",
+				Description:         "",
+				Address_1:           "123 home street",
+				Address_2:           "",
+				City:                "city",
+				State:               "state",
+				Zipcode:             "12345",
+				Country:             "usa",
+				Square_feet:         123,
+				Num_bedrooms:        4,
+				Num_toilets:         2,
+				Num_showers_baths:   2,
+				Cost_dollars:        1200,
+				Cost_cents:          0,
+				Price_type:          "monthly",
+				Deposit_dollars:     1200,
+				Utilities_included:  true,
+				Lease_length_months: []int32{6, 12},
+				Available_from:      "2024-09-01",
+				Rooms_available:     2,
+				Pets_policy:         "negotiable",
+				Smoking_policy:      "outside_only",
 			},
-			expectError: true,
+			false,
 		},
 		{
-			name: "no email",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "", // empty email
-				FirstName: "santa",
-				LastName:  "claus",
-				BirthDate: "0280-12-25",
-				Gender:    "Man",
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// valid sale
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Price_type:        "sale",
 			},
-			expectError: true,
+			false,
 		},
 		{
-			name: "no first name",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "", // empty first name
-				LastName:  "claus",
-				BirthDate: "0280-12-25",
-				Gender:    "Man",
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// unknown price type
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Price_type:        "weekly",
 			},
-			expectError: true,
+			true,
 		},
 		{
-			name: "no last name",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "santa",
-				LastName:  "", // empty last name
-				BirthDate: "0280-12-25",
-				Gender:    "Man",
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// negative deposit
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Deposit_dollars:   -1,
 			},
-			expectError: true,
+			true,
 		},
 		{
-			name: "no birthdate",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "santa",
-				LastName:  "claus",
-				BirthDate: "", // empty birth date
-				Gender:    "Man",
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// deposit cents out of range
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Deposit_cents:     100,
 			},
-			expectError: true,
+			true,
 		},
 		{
-			name: "user is too young (below 18, 0 years old)",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "santa",
-				LastName:  "claus",
-				BirthDate: strings.Split(time.Now().String(), " ")[0], // invalid birth date (too young)
-				Gender:    "Man",
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// lease lengths on a sale
+			database.PropertyDetails{
+				PropertyID:          uuid1,
+				ListerUserID:        listerUserID1,
+				Name:                "name",
+				Description:         "",
+				Address_1:           "123 home street",
+				Address_2:           "",
+				City:                "city",
+				State:               "state",
+				Zipcode:             "12345",
+				Country:             "usa",
+				Square_feet:         123,
+				Num_bedrooms:        4,
+				Num_toilets:         2,
+				Num_showers_baths:   2,
+				Cost_dollars:        1200,
+				Cost_cents:          0,
+				Price_type:          "sale",
+				Lease_length_months: []int32{12},
 			},
-			expectError: true,
+			true,
 		},
 		{
-			name: "no gender",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "santa",
-				LastName:  "claus",
-				BirthDate: "1999-12-25",
-				Gender:    "", // empty gender
-				Location:  "North Pole, Arctic",
-				Interests: []string{"Reading", "Traveling", "History"},
+			// lease length out of range
+			database.PropertyDetails{
+				PropertyID:          uuid1,
+				ListerUserID:        listerUserID1,
+				Name:                "name",
+				Description:         "",
+				Address_1:           "123 home street",
+				Address_2:           "",
+				City:                "city",
+				State:               "state",
+				Zipcode:             "12345",
+				Country:             "usa",
+				Square_feet:         123,
+				Num_bedrooms:        4,
+				Num_toilets:         2,
+				Num_showers_baths:   2,
+				Cost_dollars:        1200,
+				Cost_cents:          0,
+				Lease_length_months: []int32{0},
 			},
-			expectError: true,
+			true,
 		},
 		{
-			name: "no location",
-			input: database.UserDetails{
-				UserID:    userID1,
-				Email:     "example@example.com",
-				FirstName: "santa",
-				LastName:  "claus",
-				BirthDate: "1999-12-25",
-				Gender:    "Man",
-				Location:  "", // empty location
-				Interests: []string{"Reading", "Traveling", "History"},
+			// lease length too long
+			database.PropertyDetails{
+				PropertyID:          uuid1,
+				ListerUserID:        listerUserID1,
+				Name:                "name",
+				Description:         "",
+				Address_1:           "123 home street",
+				Address_2:           "",
+				City:                "city",
+				State:               "state",
+				Zipcode:             "12345",
+				Country:             "usa",
+				Square_feet:         123,
+				Num_bedrooms:        4,
+				Num_toilets:         2,
+				Num_showers_baths:   2,
+				Cost_dollars:        1200,
+				Cost_cents:          0,
+				Lease_length_months: []int32{61},
 			},
-			expectError: true,
+			true,
 		},
 		{
-			name: "no interests",
+			// duplicate lease lengths
+			database.PropertyDetails{
+				PropertyID:          uuid1,
+				ListerUserID:        listerUserID1,
+				Name:                "name",
+				Description:         "",
+				Address_1:           "123 home street",
+				Address_2:           "",
+				City:                "city",
+				State:               "state",
+				Zipcode:             "12345",
+				Country:             "usa",
+				Square_feet:         123,
+				Num_bedrooms:        4,
+				Num_toilets:         2,
+				Num_showers_baths:   2,
+				Cost_dollars:        1200,
+				Cost_cents:          0,
+				Lease_length_months: []int32{12, 12},
+			},
+			true,
+		},
+		{
+			// bad available from date
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Available_from:    "next week",
+			},
+			true,
+		},
+		{
+			// more rooms available than bedrooms
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Rooms_available:   5,
+			},
+			true,
+		},
+		{
+			// unknown pets policy
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Pets_policy:       "dogs",
+			},
+			true,
+		},
+		{
+			// unknown smoking policy
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Description:       "",
+				Address_1:         "123 home street",
+				Address_2:         "",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Cost_cents:        0,
+				Smoking_policy:    "sometimes",
+			},
+			true,
+		},
+		{
+			// valid coordinates
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Address_1:         "123 home street",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Latitude:          &latitude,
+				Longitude:         &longitude,
+			},
+			false,
+		},
+		{
+			// latitude without longitude
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Address_1:         "123 home street",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Latitude:          &latitude,
+			},
+			true,
+		},
+		{
+			// latitude out of range
+			database.PropertyDetails{
+				PropertyID:        uuid1,
+				ListerUserID:      listerUserID1,
+				Name:              "name",
+				Address_1:         "123 home street",
+				City:              "city",
+				State:             "state",
+				Zipcode:           "12345",
+				Country:           "usa",
+				Square_feet:       123,
+				Num_bedrooms:      4,
+				Num_toilets:       2,
+				Num_showers_baths: 2,
+				Cost_dollars:      1200,
+				Latitude:          &outOfRange,
+				Longitude:         &longitude,
+			},
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidatePropertyDetails(test.input)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidatePropertyAmenities(t *testing.T) {
+	type test struct {
+		input       []string
+		expectError bool
+	}
+
+	catalog := map[string]struct{}{
+		"parking":         {},
+		"gym":             {},
+		"in_unit_laundry": {},
+	}
+
+	tests := []test{
+		{input: nil, expectError: false},
+		{input: []string{}, expectError: false},
+		{input: []string{"parking"}, expectError: false},
+		{input: []string{"parking", "gym", "in_unit_laundry"}, expectError: false},
+		{input: []string{"pool"}, expectError: true},
+		{input: []string{"parking", "Parking"}, expectError: true},
+		{input: []string{"gym", "gym"}, expectError: true},
+		{input: []string{""}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidatePropertyAmenities(test.input, catalog)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateAmenity(t *testing.T) {
+	type test struct {
+		input       database.Amenity
+		expectError bool
+	}
+
+	tests := []test{
+		{input: database.Amenity{Name: "parking", Category: "building"}, expectError: false},
+		{input: database.Amenity{Name: "in_unit_laundry", Category: "appliances"}, expectError: false},
+		{input: database.Amenity{Name: "ev_charger_2", Category: "other"}, expectError: false},
+		{input: database.Amenity{Name: "", Category: "building"}, expectError: true},
+		{input: database.Amenity{Name: "In Unit Laundry", Category: "appliances"}, expectError: true},
+		{input: database.Amenity{Name: "_gym", Category: "building"}, expectError: true},
+		{input: database.Amenity{Name: "gym_", Category: "building"}, expectError: true},
+		{input: database.Amenity{Name: "gym", Category: ""}, expectError: true},
+		{input: database.Amenity{Name: "gym", Category: "fitness"}, expectError: true},
+		{input: database.Amenity{Name: strings.Repeat("a", 65), Category: "other"}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateAmenity(test.input)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidatePropertyImage(t *testing.T) {
+	type test struct {
+		input       database.FileInternal
+		expectError bool
+	}
+
+	tests := []test{
+		{input: database.FileInternal{Filename: "a.png", Mimetype: "image/png", Data: []byte("\x89PNG\r\n\x1a\n0000")}, expectError: false},
+		{input: database.FileInternal{Filename: "a.jpg", Mimetype: "image/jpeg", Data: []byte("\xff\xd8\xff\xe0")}, expectError: false},
+		{input: database.FileInternal{Filename: "a.gif", Mimetype: "image/gif", Data: []byte("GIF89a")}, expectError: false},
+		{input: database.FileInternal{Filename: "a.html", Mimetype: "text/html", Data: []byte("<html><script>alert(1)</script></html>")}, expectError: true},
+		{input: database.FileInternal{Filename: "a.svg", Mimetype: "image/svg+xml", Data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)}, expectError: true},
+		// The type sent by the client is ignored
+		{input: database.FileInternal{Filename: "a.png", Mimetype: "image/png", Data: []byte("<html></html>")}, expectError: true},
+		{input: database.FileInternal{Filename: "a.png", Mimetype: "image/png", Data: []byte{}}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidatePropertyImage(test.input)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateRoomDetails(t *testing.T) {
+	type test struct {
+		input       database.RoomDetails
+		expectError bool
+	}
+
+	roomID := uuid.NewString()
+	propertyID := uuid.NewString()
+	occupantUserID := "109237874690123193857"
+
+	tests := []test{
+		{
+			// valid available room
+			database.RoomDetails{
+				RoomID:       roomID,
+				PropertyID:   propertyID,
+				Name:         "front bedroom",
+				Square_feet:  120,
+				Cost_dollars: 850,
+				Is_available: true,
+			},
+			false,
+		},
+		{
+			// valid occupied room
+			database.RoomDetails{
+				RoomID:           roomID,
+				PropertyID:       propertyID,
+				Name:             "back bedroom",
+				Description:      "faces the garden",
+				Square_feet:      100,
+				Cost_dollars:     700,
+				Cost_cents:       50,
+				Is_available:     false,
+				Occupant_user_id: occupantUserID,
+			},
+			false,
+		},
+		{
+			// occupied room that frees up later
+			database.RoomDetails{
+				RoomID:           roomID,
+				PropertyID:       propertyID,
+				Name:             "back bedroom",
+				Square_feet:      100,
+				Cost_dollars:     700,
+				Is_available:     true,
+				Available_from:   "2024-09-01",
+				Occupant_user_id: occupantUserID,
+			},
+			false,
+		},
+		{
+			// occupied room available without a move out date
+			database.RoomDetails{
+				RoomID:           roomID,
+				PropertyID:       propertyID,
+				Name:             "back bedroom",
+				Square_feet:      100,
+				Cost_dollars:     700,
+				Is_available:     true,
+				Occupant_user_id: occupantUserID,
+			},
+			true,
+		},
+		{
+			// invalid room id
+			database.RoomDetails{
+				RoomID:       "room1",
+				PropertyID:   propertyID,
+				Name:         "front bedroom",
+				Square_feet:  120,
+				Cost_dollars: 850,
+			},
+			true,
+		},
+		{
+			// invalid property id
+			database.RoomDetails{
+				RoomID:       roomID,
+				PropertyID:   "",
+				Name:         "front bedroom",
+				Square_feet:  120,
+				Cost_dollars: 850,
+			},
+			true,
+		},
+		{
+			// missing name
+			database.RoomDetails{
+				RoomID:       roomID,
+				PropertyID:   propertyID,
+				Square_feet:  120,
+				Cost_dollars: 850,
+			},
+			true,
+		},
+		{
+			// no size
+			database.RoomDetails{
+				RoomID:       roomID,
+				PropertyID:   propertyID,
+				Name:         "front bedroom",
+				Cost_dollars: 850,
+			},
+			true,
+		},
+		{
+			// no cost
+			database.RoomDetails{
+				RoomID:      roomID,
+				PropertyID:  propertyID,
+				Name:        "front bedroom",
+				Square_feet: 120,
+			},
+			true,
+		},
+		{
+			// cents out of range
+			database.RoomDetails{
+				RoomID:       roomID,
+				PropertyID:   propertyID,
+				Name:         "front bedroom",
+				Square_feet:  120,
+				Cost_dollars: 850,
+				Cost_cents:   100,
+			},
+			true,
+		},
+		{
+			// bad available from date
+			database.RoomDetails{
+				RoomID:         roomID,
+				PropertyID:     propertyID,
+				Name:           "front bedroom",
+				Square_feet:    120,
+				Cost_dollars:   850,
+				Available_from: "01/09/2024",
+			},
+			true,
+		},
+		{
+			// invalid occupant id
+			database.RoomDetails{
+				RoomID:           roomID,
+				PropertyID:       propertyID,
+				Name:             "front bedroom",
+				Square_feet:      120,
+				Cost_dollars:     850,
+				Occupant_user_id: "someone",
+			},
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateRoomDetails(test.input)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityPermission(t *testing.T) {
+	type test struct {
+		role        string
+		permission  string
+		expectError bool
+	}
+
+	tests := []test{
+		{role: "owner", permission: "edit_details", expectError: false},
+		{role: "owner", permission: "manage_roles", expectError: false},
+		{role: "owner", permission: "transfer_ownership", expectError: false},
+		{role: "owner", permission: "delete_community", expectError: false},
+		{role: "moderator", permission: "edit_details", expectError: false},
+		{role: "moderator", permission: "manage_members", expectError: false},
+		{role: "moderator", permission: "link_properties", expectError: false},
+		{role: "moderator", permission: "manage_roles", expectError: true},
+		{role: "moderator", permission: "transfer_ownership", expectError: true},
+		{role: "moderator", permission: "delete_community", expectError: true},
+		{role: "member", permission: "edit_details", expectError: true},
+		{role: "member", permission: "link_properties", expectError: true},
+		{role: "member", permission: "post", expectError: false},
+		{role: "member", permission: "moderate_posts", expectError: true},
+		{role: "moderator", permission: "moderate_posts", expectError: false},
+		{role: "member", permission: "events", expectError: false},
+		{role: "member", permission: "manage_events", expectError: true},
+		{role: "moderator", permission: "manage_events", expectError: false},
+		{role: "", permission: "edit_details", expectError: true}, // not a member
+		{role: "admin", permission: "edit_details", expectError: true},
+		{role: "owner", permission: "ban_members", expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityPermission(test.role, test.permission)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityPropertyLinkRequest(t *testing.T) {
+	validRequest := database.CommunityPropertyLinkRequest{
+		RequestID:         uuid.New().String(),
+		CommunityID:       uuid.New().String(),
+		PropertyID:        uuid.New().String(),
+		RequestedByUserID: "123456789012345678901",
+	}
+
+	type test struct {
+		modify      func(request database.CommunityPropertyLinkRequest) database.CommunityPropertyLinkRequest
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(request database.CommunityPropertyLinkRequest) database.CommunityPropertyLinkRequest {
+			return request
+		}, expectError: false},
+		{modify: func(request database.CommunityPropertyLinkRequest) database.CommunityPropertyLinkRequest {
+			request.RequestID = "request"
+			return request
+		}, expectError: true},
+		{modify: func(request database.CommunityPropertyLinkRequest) database.CommunityPropertyLinkRequest {
+			request.CommunityID = ""
+			return request
+		}, expectError: true},
+		{modify: func(request database.CommunityPropertyLinkRequest) database.CommunityPropertyLinkRequest {
+			request.PropertyID = "property"
+			return request
+		}, expectError: true},
+		{modify: func(request database.CommunityPropertyLinkRequest) database.CommunityPropertyLinkRequest {
+			request.RequestedByUserID = "1234"
+			return request
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityPropertyLinkRequest(test.modify(validRequest))
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityPost(t *testing.T) {
+	validThread := database.CommunityPost{
+		PostID:      uuid.New().String(),
+		CommunityID: uuid.New().String(),
+		UserID:      "123456789012345678901",
+		Title:       "House meeting on Sunday",
+		Body:        "Let's talk about the new chore rotation.",
+	}
+	validReply := validThread
+	validReply.PostID = uuid.New().String()
+	validReply.ThreadID = validThread.PostID
+	validReply.Title = ""
+	validReply.Body = "Works for me!"
+
+	type test struct {
+		post        database.CommunityPost
+		modify      func(post database.CommunityPost) database.CommunityPost
+		expectError bool
+	}
+
+	unchanged := func(post database.CommunityPost) database.CommunityPost { return post }
+	tests := []test{
+		{post: validThread, modify: unchanged, expectError: false},
+		{post: validReply, modify: unchanged, expectError: false},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Pinned = true
+			return post
+		}, expectError: false},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.PostID = "post"
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.CommunityID = ""
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.UserID = "1234"
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Title = ""
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Title = strings.Repeat("a", 201)
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Body = ""
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Body = strings.Repeat("a", 10001)
+			return post
+		}, expectError: true},
+		{post: validReply, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Title = "Re: House meeting on Sunday"
+			return post
+		}, expectError: true},
+		{post: validReply, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Pinned = true
+			return post
+		}, expectError: true},
+		{post: validReply, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.ThreadID = "thread"
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Title = "fuck this"
+			return post
+		}, expectError: true},
+		{post: validThread, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Body = "fuck"
+			return post
+		}, expectError: true},
+		{post: validReply, modify: func(post database.CommunityPost) database.CommunityPost {
+			post.Body = "fuck that"
+			return post
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityPost(test.modify(test.post))
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityEvent(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	validEvent := database.CommunityEvent{
+		EventID:         uuid.New().String(),
+		CommunityID:     uuid.New().String(),
+		CreatedByUserID: "123456789012345678901",
+		Title:           "Potluck",
+		Description:     "Bring a dish to share.",
+		Location:        "123 Main St, Springfield",
+		StartTime:       now.Add(48 * time.Hour),
+		EndTime:         now.Add(51 * time.Hour),
+		Capacity:        20,
+	}
+
+	type test struct {
+		modify      func(event database.CommunityEvent) database.CommunityEvent
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(event database.CommunityEvent) database.CommunityEvent { return event }, expectError: false},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.Capacity = 0 // no limit
+			return event
+		}, expectError: false},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.PropertyID = uuid.New().String()
+			return event
+		}, expectError: false},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.Description = ""
+			return event
+		}, expectError: false},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.EventID = "event"
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.PropertyID = "property"
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.CreatedByUserID = ""
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.Title = ""
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.Location = ""
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.Description = strings.Repeat("a", 5001)
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.StartTime = now.Add(-time.Hour)
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.EndTime = event.StartTime
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.EndTime = event.StartTime.AddDate(0, 0, 8)
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.Capacity = -1
+			return event
+		}, expectError: true},
+		{modify: func(event database.CommunityEvent) database.CommunityEvent {
+			event.Capacity = 1001
+			return event
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityEvent(test.modify(validEvent), now)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityRoleChange(t *testing.T) {
+	type test struct {
+		from        string
+		to          string
+		expectError bool
+	}
+
+	tests := []test{
+		{from: "member", to: "moderator", expectError: false},
+		{from: "moderator", to: "member", expectError: false},
+		{from: "member", to: "member", expectError: false},
+		{from: "member", to: "owner", expectError: true},
+		{from: "owner", to: "moderator", expectError: true},
+		{from: "member", to: "admin", expectError: true},
+		{from: "member", to: "", expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityRoleChange(test.from, test.to)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidatePropertyStatusTransition(t *testing.T) {
+	type test struct {
+		from        string
+		to          string
+		expectError bool
+	}
+
+	tests := []test{
+		{from: "draft", to: "published", expectError: false},
+		{from: "draft", to: "archived", expectError: false},
+		{from: "draft", to: "rented", expectError: true},
+		{from: "published", to: "paused", expectError: false},
+		{from: "published", to: "rented", expectError: false},
+		{from: "published", to: "archived", expectError: false},
+		{from: "published", to: "draft", expectError: true},
+		{from: "paused", to: "published", expectError: false},
+		{from: "paused", to: "draft", expectError: true},
+		{from: "rented", to: "published", expectError: false},
+		{from: "rented", to: "paused", expectError: true},
+		{from: "archived", to: "draft", expectError: false},
+		{from: "archived", to: "published", expectError: true},
+		{from: "published", to: "expired", expectError: true}, // only the listing expiry job expires listings
+		{from: "expired", to: "published", expectError: false},
+		{from: "expired", to: "archived", expectError: false},
+		{from: "expired", to: "paused", expectError: true},
+		{from: "published", to: "sold", expectError: true},
+		{from: "published", to: "", expectError: true},
+		{from: "", to: "published", expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidatePropertyStatusTransition(test.from, test.to)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateViewingSlot(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	validSlot := database.ViewingSlot{
+		SlotID:     uuid.New().String(),
+		PropertyID: uuid.New().String(),
+		StartTime:  now.Add(24 * time.Hour),
+		EndTime:    now.Add(24*time.Hour + 30*time.Minute),
+		Capacity:   2,
+		Note:       "Ring the bell at the side door",
+	}
+
+	type test struct {
+		modify      func(slot database.ViewingSlot) database.ViewingSlot
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { return slot }, expectError: false},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { slot.SlotID = "slot"; return slot }, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { slot.PropertyID = ""; return slot }, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot {
+			slot.StartTime = now.Add(-time.Hour)
+			return slot
+		}, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { slot.EndTime = slot.StartTime; return slot }, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot {
+			slot.EndTime = slot.StartTime.Add(4 * time.Hour)
+			return slot
+		}, expectError: false},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot {
+			slot.EndTime = slot.StartTime.Add(4*time.Hour + time.Minute)
+			return slot
+		}, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { slot.Capacity = 0; return slot }, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { slot.Capacity = 20; return slot }, expectError: false},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { slot.Capacity = 21; return slot }, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot {
+			slot.Note = strings.Repeat("a", 501)
+			return slot
+		}, expectError: true},
+		{modify: func(slot database.ViewingSlot) database.ViewingSlot { slot.Note = "fuck"; return slot }, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateViewingSlot(test.modify(validSlot), now)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateRentalApplication(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	validApplication := database.RentalApplication{
+		ApplicationID:      uuid.New().String(),
+		PropertyID:         uuid.New().String(),
+		ApplicantUserID:    "123456789012345678901",
+		CoApplicantUserIDs: []string{"123456789012345678902"},
+		Message:            "We are two students looking for a place close to campus",
+		MoveInDate:         "2024-09-01",
+	}
+
+	type test struct {
+		modify      func(application database.RentalApplication) database.RentalApplication
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(application database.RentalApplication) database.RentalApplication { return application }, expectError: false},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.ApplicationID = "application"
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.ApplicantUserID = "1234"
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.CoApplicantUserIDs = []string{}
+			application.MoveInDate = ""
+			application.Message = ""
+			return application
+		}, expectError: false},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.CoApplicantUserIDs = []string{"abc"}
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.CoApplicantUserIDs = []string{application.ApplicantUserID}
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.CoApplicantUserIDs = []string{"123456789012345678902", "123456789012345678902"}
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.CoApplicantUserIDs = []string{
+				"123456789012345678902", "123456789012345678903", "123456789012345678904",
+				"123456789012345678905", "123456789012345678906", "123456789012345678907",
+			}
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.Message = strings.Repeat("a", 2001)
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.Message = "fuck"
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.MoveInDate = "2024-06-01"
+			return application
+		}, expectError: false},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.MoveInDate = "2024-05-31"
+			return application
+		}, expectError: true},
+		{modify: func(application database.RentalApplication) database.RentalApplication {
+			application.MoveInDate = "09/01/2024"
+			return application
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateRentalApplication(test.modify(validApplication), now)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateRentalApplicationDecision(t *testing.T) {
+	type test struct {
+		currentStatus string
+		newStatus     string
+		note          string
+		expectError   bool
+	}
+
+	tests := []test{
+		{currentStatus: "submitted", newStatus: "under_review", expectError: false},
+		{currentStatus: "submitted", newStatus: "accepted", note: "Welcome!", expectError: false},
+		{currentStatus: "under_review", newStatus: "rejected", note: "The place was rented to someone else", expectError: false},
+		{currentStatus: "under_review", newStatus: "submitted", expectError: true},
+		{currentStatus: "accepted", newStatus: "rejected", expectError: true},
+		{currentStatus: "rejected", newStatus: "accepted", expectError: true},
+		{currentStatus: "submitted", newStatus: "approved", expectError: true},
+		{currentStatus: "submitted", newStatus: "rejected", note: strings.Repeat("a", 1001), expectError: true},
+		{currentStatus: "submitted", newStatus: "rejected", note: "fuck", expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateRentalApplicationDecision(test.currentStatus, test.newStatus, test.note)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateUserDetails(t *testing.T) {
+	type test struct {
+		name        string
+		input       database.UserDetails
+		expectError bool
+	}
+
+	userID1 := "109237874690123193857"
+	tests := []test{
+		{
+			name: "regular input 1",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "santa",
+				LastName:  "claus",
+				BirthDate: "0280-12-25",
+				Gender:    "Man",
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: false,
+		},
+		{
+			name: "regular input 2",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "frosty",
+				LastName:  "thesnowman",
+				BirthDate: "1999-01-01",
+				Gender:    "Woman",
+				Location:  "Siberia",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: false,
+		},
+		{
+			name: "no user id",
+			input: database.UserDetails{
+				UserID:    "", // empty user id
+				Email:     "example@example.com",
+				FirstName: "santa",
+				LastName:  "claus",
+				BirthDate: "0280-12-25",
+				Gender:    "Man",
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "no email",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "", // empty email
+				FirstName: "santa",
+				LastName:  "claus",
+				BirthDate: "0280-12-25",
+				Gender:    "Man",
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "no first name",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "", // empty first name
+				LastName:  "claus",
+				BirthDate: "0280-12-25",
+				Gender:    "Man",
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "no last name",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "santa",
+				LastName:  "", // empty last name
+				BirthDate: "0280-12-25",
+				Gender:    "Man",
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "no birthdate",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "santa",
+				LastName:  "claus",
+				BirthDate: "", // empty birth date
+				Gender:    "Man",
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "user is too young (below 18, 0 years old)",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "santa",
+				LastName:  "claus",
+				BirthDate: strings.Split(time.Now().String(), " ")[0], // invalid birth date (too young)
+				Gender:    "Man",
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "no gender",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "santa",
+				LastName:  "claus",
+				BirthDate: "1999-12-25",
+				Gender:    "", // empty gender
+				Location:  "North Pole, Arctic",
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "no location",
+			input: database.UserDetails{
+				UserID:    userID1,
+				Email:     "example@example.com",
+				FirstName: "santa",
+				LastName:  "claus",
+				BirthDate: "1999-12-25",
+				Gender:    "Man",
+				Location:  "", // empty location
+				Interests: []string{"Reading", "Traveling", "History"},
+			},
+			expectError: true,
+		},
+		{
+			name: "no interests",
 			input: database.UserDetails{
 				UserID:    userID1,
 				Email:     "example@example.com",
@@ -1223,3 +2521,605 @@ func TestValidateUserDetails(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateCommunityExpense(t *testing.T) {
+	validExpense := database.CommunityExpense{
+		ExpenseID:       uuid.New().String(),
+		CommunityID:     uuid.New().String(),
+		PayerUserID:     "123456789012345678901",
+		CreatedByUserID: "123456789012345678901",
+		Description:     "October rent",
+		Category:        "rent",
+		AmountCents:     240000,
+		SplitType:       "shares",
+		SpentOn:         "2024-10-01",
+		Splits: []database.CommunityExpenseSplit{
+			{UserID: "123456789012345678901", Shares: 2},
+			{UserID: "123456789012345678902", Shares: 1},
+		},
+	}
+
+	type test struct {
+		modify      func(expense database.CommunityExpense) database.CommunityExpense
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense { return expense }, expectError: false},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.ExpenseID = "expense"
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.PayerUserID = ""
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.Description = ""
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.Description = strings.Repeat("a", 201)
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.Category = "vacation"
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.AmountCents = 0
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.AmountCents = 10000001
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.SpentOn = "10/01/2024"
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.SplitType = "percent"
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.Splits = []database.CommunityExpenseSplit{}
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.Splits = []database.CommunityExpenseSplit{
+				{UserID: "123456789012345678901", Shares: 1},
+				{UserID: "123456789012345678901", Shares: 1},
+			}
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.Splits = []database.CommunityExpenseSplit{
+				{UserID: "123456789012345678901", Shares: 101},
+			}
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.Splits = []database.CommunityExpenseSplit{
+				{UserID: "123456789012345678901", Shares: 2},
+				{UserID: "123456789012345678902", Shares: 0},
+			}
+			return expense
+		}, expectError: true},
+		{modify: func(expense database.CommunityExpense) database.CommunityExpense {
+			expense.SplitType = "equal"
+			expense.Splits = []database.CommunityExpenseSplit{
+				{UserID: "123456789012345678901"},
+				{UserID: "123456789012345678902"},
+			}
+			return expense
+		}, expectError: false},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityExpense(test.modify(validExpense))
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityPoll(t *testing.T) {
+	now := time.Now()
+	validPoll := database.CommunityPoll{
+		PollID:            uuid.New().String(),
+		CommunityID:       uuid.New().String(),
+		CreatedByUserID:   "123456789012345678901",
+		Question:          "Should we get a shared compost bin?",
+		ResultsVisibility: "after_vote",
+		OpensAt:           now,
+		ClosesAt:          now.Add(7 * 24 * time.Hour),
+		Options: []database.CommunityPollOption{
+			{OptionID: uuid.New().String(), Label: "Yes"},
+			{OptionID: uuid.New().String(), Label: "No"},
+		},
+	}
+
+	type test struct {
+		modify      func(poll database.CommunityPoll) database.CommunityPoll
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll { return poll }, expectError: false},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.PollID = "poll"
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.CreatedByUserID = ""
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.Question = ""
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.Question = strings.Repeat("a", 501)
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.ResultsVisibility = "never"
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.Options = poll.Options[:1]
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.Options = []database.CommunityPollOption{
+				{OptionID: uuid.New().String(), Label: "Yes"},
+				{OptionID: uuid.New().String(), Label: " yes "},
+			}
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.Options = []database.CommunityPollOption{
+				{OptionID: uuid.New().String(), Label: "Yes"},
+				{OptionID: uuid.New().String(), Label: ""},
+			}
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.ClosesAt = poll.OpensAt
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.OpensAt = now.Add(-48 * time.Hour)
+			poll.ClosesAt = now.Add(-24 * time.Hour)
+			return poll
+		}, expectError: true},
+		{modify: func(poll database.CommunityPoll) database.CommunityPoll {
+			poll.ClosesAt = now.Add(91 * 24 * time.Hour)
+			return poll
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityPoll(test.modify(validPoll), now)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityPollBallot(t *testing.T) {
+	now := time.Now()
+	poll := database.CommunityPoll{
+		OpensAt:  now.Add(-time.Hour),
+		ClosesAt: now.Add(time.Hour),
+		Options: []database.CommunityPollOption{
+			{OptionID: "a", Label: "Monday"},
+			{OptionID: "b", Label: "Tuesday"},
+		},
+	}
+	multipleChoicePoll := poll
+	multipleChoicePoll.MultipleChoice = true
+
+	type test struct {
+		poll        database.CommunityPoll
+		optionIDs   []string
+		now         time.Time
+		expectError bool
+	}
+
+	tests := []test{
+		{poll: poll, optionIDs: []string{"a"}, now: now, expectError: false},
+		{poll: poll, optionIDs: []string{"a", "b"}, now: now, expectError: true},
+		{poll: multipleChoicePoll, optionIDs: []string{"a", "b"}, now: now, expectError: false},
+		{poll: multipleChoicePoll, optionIDs: []string{"a", "a"}, now: now, expectError: true},
+		{poll: poll, optionIDs: []string{}, now: now, expectError: true},
+		{poll: poll, optionIDs: []string{"c"}, now: now, expectError: true},
+		{poll: poll, optionIDs: []string{"a"}, now: now.Add(-2 * time.Hour), expectError: true},
+		{poll: poll, optionIDs: []string{"a"}, now: now.Add(time.Hour), expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityPollBallot(test.poll, test.optionIDs, test.now)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityPollResultsAccess(t *testing.T) {
+	now := time.Now()
+	openPoll := database.CommunityPoll{ResultsVisibility: "after_vote", ClosesAt: now.Add(time.Hour)}
+	openPollAfterClose := database.CommunityPoll{ResultsVisibility: "after_close", ClosesAt: now.Add(time.Hour)}
+	closedPoll := database.CommunityPoll{ResultsVisibility: "after_close", ClosesAt: now}
+
+	type test struct {
+		poll        database.CommunityPoll
+		hasVoted    bool
+		expectError bool
+	}
+
+	tests := []test{
+		{poll: openPoll, hasVoted: true, expectError: false},
+		{poll: openPoll, hasVoted: false, expectError: true},
+		{poll: openPollAfterClose, hasVoted: true, expectError: true},
+		{poll: closedPoll, hasVoted: false, expectError: false},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityPollResultsAccess(test.poll, test.hasVoted, now)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityDocumentFolder(t *testing.T) {
+	validFolder := database.CommunityDocumentFolder{
+		FolderID:        uuid.New().String(),
+		CommunityID:     uuid.New().String(),
+		Name:            "Leases",
+		CreatedByUserID: "123456789012345678901",
+		CreatedAt:       time.Now(),
+	}
+
+	type test struct {
+		modify      func(folder database.CommunityDocumentFolder) database.CommunityDocumentFolder
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(folder database.CommunityDocumentFolder) database.CommunityDocumentFolder { return folder }, expectError: false},
+		{modify: func(folder database.CommunityDocumentFolder) database.CommunityDocumentFolder {
+			folder.ParentFolderID = uuid.New().String()
+			return folder
+		}, expectError: false},
+		{modify: func(folder database.CommunityDocumentFolder) database.CommunityDocumentFolder {
+			folder.ParentFolderID = "folder"
+			return folder
+		}, expectError: true},
+		{modify: func(folder database.CommunityDocumentFolder) database.CommunityDocumentFolder {
+			folder.CreatedByUserID = ""
+			return folder
+		}, expectError: true},
+		{modify: func(folder database.CommunityDocumentFolder) database.CommunityDocumentFolder {
+			folder.Name = ""
+			return folder
+		}, expectError: true},
+		{modify: func(folder database.CommunityDocumentFolder) database.CommunityDocumentFolder {
+			folder.Name = strings.Repeat("a", 101)
+			return folder
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityDocumentFolder(test.modify(validFolder))
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityDocument(t *testing.T) {
+	validDocument := database.CommunityDocument{
+		DocumentID:      uuid.New().String(),
+		CommunityID:     uuid.New().String(),
+		Title:           "House rules",
+		Visibility:      "members",
+		CreatedByUserID: "123456789012345678901",
+	}
+
+	type test struct {
+		modify      func(document database.CommunityDocument) database.CommunityDocument
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(document database.CommunityDocument) database.CommunityDocument { return document }, expectError: false},
+		{modify: func(document database.CommunityDocument) database.CommunityDocument {
+			document.FolderID = uuid.New().String()
+			document.Visibility = "public"
+			return document
+		}, expectError: false},
+		{modify: func(document database.CommunityDocument) database.CommunityDocument {
+			document.DocumentID = "document"
+			return document
+		}, expectError: true},
+		{modify: func(document database.CommunityDocument) database.CommunityDocument {
+			document.FolderID = "folder"
+			return document
+		}, expectError: true},
+		{modify: func(document database.CommunityDocument) database.CommunityDocument {
+			document.Title = ""
+			return document
+		}, expectError: true},
+		{modify: func(document database.CommunityDocument) database.CommunityDocument {
+			document.Title = strings.Repeat("a", 201)
+			return document
+		}, expectError: true},
+		{modify: func(document database.CommunityDocument) database.CommunityDocument {
+			document.Visibility = "everyone"
+			return document
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityDocument(test.modify(validDocument))
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityDocumentVersion(t *testing.T) {
+	validVersion := database.CommunityDocumentVersion{
+		DocumentID:       uuid.New().String(),
+		FileName:         "lease.pdf",
+		MimeType:         "application/pdf",
+		Size:             1024,
+		UploadedByUserID: "123456789012345678901",
+		Note:             "Signed by everyone",
+	}
+	validFile := database.FileInternal{
+		Filename: "lease.pdf",
+		Mimetype: "application/pdf",
+		Size:     1024,
+		Data:     append([]byte("%PDF-1.7\n"), make([]byte, 1015)...),
+	}
+
+	type test struct {
+		modify      func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal)
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			return version, file
+		}, expectError: false},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			version.UploadedByUserID = ""
+			return version, file
+		}, expectError: true},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			version.Note = strings.Repeat("a", 501)
+			return version, file
+		}, expectError: true},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			file.Filename = ""
+			return version, file
+		}, expectError: true},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			file.Size = 0
+			return version, file
+		}, expectError: true},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			file.Size = 25<<20 + 1
+			return version, file
+		}, expectError: true},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			file.Data = []byte("Rent is due on the first of the month")
+			return version, file
+		}, expectError: false},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			file.Data = []byte("<html><script>alert(1)</script></html>")
+			return version, file
+		}, expectError: true},
+		{modify: func(version database.CommunityDocumentVersion, file database.FileInternal) (database.CommunityDocumentVersion, database.FileInternal) {
+			// The type sent by the client is ignored
+			file.Mimetype = "application/pdf"
+			file.Data = make([]byte, 1024)
+			return version, file
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityDocumentVersion(test.modify(validVersion, validFile))
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityDocumentAccess(t *testing.T) {
+	type test struct {
+		visibility  string
+		role        string
+		expectError bool
+	}
+
+	tests := []test{
+		{visibility: "public", role: "", expectError: false},
+		{visibility: "members", role: "", expectError: true},
+		{visibility: "members", role: "member", expectError: false},
+		{visibility: "moderators", role: "member", expectError: true},
+		{visibility: "moderators", role: "moderator", expectError: false},
+		{visibility: "moderators", role: "owner", expectError: false},
+		{visibility: "everyone", role: "owner", expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityDocumentAccess(test.visibility, test.role)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunityChore(t *testing.T) {
+	validChore := database.CommunityChore{
+		ChoreID:         uuid.New().String(),
+		CommunityID:     uuid.New().String(),
+		Title:           "Take out the trash",
+		Description:     "Bins go out on Sunday evening",
+		Frequency:       "weekly",
+		Interval:        1,
+		StartsOn:        "2024-06-02",
+		CreatedByUserID: "123456789012345678901",
+		Rotation:        []string{"123456789012345678901", "123456789012345678902"},
+	}
+
+	type test struct {
+		modify      func(chore database.CommunityChore) database.CommunityChore
+		expectError bool
+	}
+
+	tests := []test{
+		{modify: func(chore database.CommunityChore) database.CommunityChore { return chore }, expectError: false},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.ChoreID = "chore"
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.CreatedByUserID = ""
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.Title = ""
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.Description = strings.Repeat("a", 2001)
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.Frequency = "yearly"
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.Interval = 0
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.Interval = 53
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.StartsOn = "06/02/2024"
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.Rotation = []string{}
+			return chore
+		}, expectError: true},
+		{modify: func(chore database.CommunityChore) database.CommunityChore {
+			chore.Rotation = []string{"123456789012345678901", "123456789012345678901"}
+			return chore
+		}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunityChore(test.modify(validChore))
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}
+
+func TestValidateCommunitySearchFilters(t *testing.T) {
+	type test struct {
+		filters     database.CommunitySearchFilters
+		expectError bool
+	}
+
+	tests := []test{
+		{filters: database.CommunitySearchFilters{}, expectError: false},
+		{filters: database.CommunitySearchFilters{City: "Boston", Latitude: 42.36, Longitude: -71.06, RadiusKm: 25}, expectError: false},
+		{filters: database.CommunitySearchFilters{MinMembers: 2, MaxMembers: 6, HasVacancy: true, Interests: []string{"hiking", "cooking"}}, expectError: false},
+		{filters: database.CommunitySearchFilters{Interests: utils.SplitCommaSeparated("hiking, cooking,")}, expectError: false},
+		{filters: database.CommunitySearchFilters{MinMembers: 2}, expectError: false},
+		{filters: database.CommunitySearchFilters{Latitude: 42.36, Longitude: -71.06, RadiusKm: 501}, expectError: true},
+		{filters: database.CommunitySearchFilters{Latitude: 91, Longitude: -71.06, RadiusKm: 25}, expectError: true},
+		{filters: database.CommunitySearchFilters{Latitude: 42.36, Longitude: -181, RadiusKm: 25}, expectError: true},
+		{filters: database.CommunitySearchFilters{MinMembers: -1}, expectError: true},
+		{filters: database.CommunitySearchFilters{MinMembers: 6, MaxMembers: 2}, expectError: true},
+		{filters: database.CommunitySearchFilters{Interests: []string{"hiking", ""}}, expectError: true},
+		{filters: database.CommunitySearchFilters{Interests: strings.Split(strings.Repeat("a,", 20)+"a", ",")}, expectError: true},
+	}
+
+	for i, test := range tests {
+		err := validation.ValidateCommunitySearchFilters(test.filters)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("test #%d - expected error but got none", i)
+			}
+		} else {
+			if err != nil {
+				t.Errorf("test #%d - didn't expect error but got one: %s", i, err)
+			}
+		}
+	}
+}